@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE endpoints ADD COLUMN protocol TEXT DEFAULT 'http' NOT NULL;
+ALTER TABLE endpoints ADD COLUMN proto_files TEXT DEFAULT '[]' NOT NULL;
+
+-- +goose Down
+ALTER TABLE endpoints DROP COLUMN proto_files;
+ALTER TABLE endpoints DROP COLUMN protocol;
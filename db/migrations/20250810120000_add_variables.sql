@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE environments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    variables TEXT DEFAULT '{}' NOT NULL,
+    active INTEGER DEFAULT 0 NOT NULL,
+    created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE collections ADD COLUMN variables TEXT DEFAULT '{}' NOT NULL;
+ALTER TABLE endpoints ADD COLUMN extractions TEXT DEFAULT '[]' NOT NULL;
+
+-- +goose Down
+ALTER TABLE endpoints DROP COLUMN extractions;
+ALTER TABLE collections DROP COLUMN variables;
+DROP TABLE IF EXISTS environments;
@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE collections ADD COLUMN pre_request_script TEXT DEFAULT '' NOT NULL;
+ALTER TABLE collections ADD COLUMN post_response_script TEXT DEFAULT '' NOT NULL;
+ALTER TABLE endpoints ADD COLUMN pre_request_script TEXT DEFAULT '' NOT NULL;
+ALTER TABLE endpoints ADD COLUMN post_response_script TEXT DEFAULT '' NOT NULL;
+
+-- +goose Down
+ALTER TABLE endpoints DROP COLUMN post_response_script;
+ALTER TABLE endpoints DROP COLUMN pre_request_script;
+ALTER TABLE collections DROP COLUMN post_response_script;
+ALTER TABLE collections DROP COLUMN pre_request_script;
@@ -0,0 +1,54 @@
+-- +goose Up
+-- FTS4 ships with the default go-sqlite3 build, unlike FTS5 which needs the
+-- sqlite_fts5 build tag
+CREATE VIRTUAL TABLE history_fts USING fts4(
+    content="history",
+    url,
+    query_params,
+    request_headers,
+    request_body,
+    response_headers,
+    response_body
+);
+
+-- +goose StatementBegin
+CREATE TRIGGER history_fts_before_delete BEFORE DELETE ON history
+BEGIN
+    DELETE FROM history_fts WHERE docid = old.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER history_fts_before_update BEFORE UPDATE ON history
+BEGIN
+    DELETE FROM history_fts WHERE docid = old.id;
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER history_fts_after_update AFTER UPDATE ON history
+BEGIN
+    INSERT INTO history_fts (docid, url, query_params, request_headers, request_body, response_headers, response_body)
+    VALUES (new.id, new.url, new.query_params, new.request_headers, new.request_body, new.response_headers, new.response_body);
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER history_fts_after_insert AFTER INSERT ON history
+BEGIN
+    INSERT INTO history_fts (docid, url, query_params, request_headers, request_body, response_headers, response_body)
+    VALUES (new.id, new.url, new.query_params, new.request_headers, new.request_body, new.response_headers, new.response_body);
+END;
+-- +goose StatementEnd
+
+INSERT INTO history_fts (history_fts) VALUES ('rebuild');
+
+CREATE INDEX idx_history_method ON history(method);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_history_method;
+DROP TRIGGER IF EXISTS history_fts_after_insert;
+DROP TRIGGER IF EXISTS history_fts_after_update;
+DROP TRIGGER IF EXISTS history_fts_before_update;
+DROP TRIGGER IF EXISTS history_fts_before_delete;
+DROP TABLE IF EXISTS history_fts;
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE snapshots (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    endpoint_id INTEGER NOT NULL UNIQUE,
+    status_code INTEGER NOT NULL,
+    response_headers TEXT DEFAULT '{}' NOT NULL,
+    response_body TEXT DEFAULT '' NOT NULL,
+    ignored_paths TEXT DEFAULT '[]' NOT NULL,
+    ignored_headers TEXT DEFAULT '[]' NOT NULL,
+    created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (endpoint_id) REFERENCES endpoints(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS snapshots;
@@ -0,0 +1,22 @@
+-- +goose Up
+CREATE TABLE folders (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    collection_id INTEGER NOT NULL,
+    parent_id INTEGER,
+    name TEXT NOT NULL,
+    headers TEXT DEFAULT '{}' NOT NULL,
+    variables TEXT DEFAULT '{}' NOT NULL,
+    created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (collection_id) REFERENCES collections(id) ON DELETE CASCADE,
+    FOREIGN KEY (parent_id) REFERENCES folders(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_folders_collection_id ON folders(collection_id);
+
+ALTER TABLE endpoints ADD COLUMN folder_id INTEGER REFERENCES folders(id) ON DELETE CASCADE;
+
+-- +goose Down
+ALTER TABLE endpoints DROP COLUMN folder_id;
+DROP INDEX IF EXISTS idx_folders_collection_id;
+DROP TABLE IF EXISTS folders;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE endpoints ADD COLUMN sort_order INTEGER DEFAULT 0 NOT NULL;
+-- keep the order endpoints were created in
+UPDATE endpoints SET sort_order = id;
+
+-- +goose Down
+ALTER TABLE endpoints DROP COLUMN sort_order;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- rows with deleted_at set are in the trash until it is emptied
+ALTER TABLE collections ADD COLUMN deleted_at TEXT;
+ALTER TABLE folders ADD COLUMN deleted_at TEXT;
+ALTER TABLE endpoints ADD COLUMN deleted_at TEXT;
+
+-- +goose Down
+ALTER TABLE endpoints DROP COLUMN deleted_at;
+ALTER TABLE folders DROP COLUMN deleted_at;
+ALTER TABLE collections DROP COLUMN deleted_at;
@@ -0,0 +1,24 @@
+-- +goose Up
+CREATE TABLE endpoint_revisions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    endpoint_id INTEGER NOT NULL,
+    name TEXT NOT NULL,
+    method TEXT NOT NULL,
+    url TEXT NOT NULL,
+    headers TEXT DEFAULT '{}' NOT NULL,
+    query_params TEXT DEFAULT '{}' NOT NULL,
+    request_body TEXT DEFAULT '' NOT NULL,
+    protocol TEXT DEFAULT 'http' NOT NULL,
+    proto_files TEXT DEFAULT '[]' NOT NULL,
+    extractions TEXT DEFAULT '[]' NOT NULL,
+    pre_request_script TEXT DEFAULT '' NOT NULL,
+    post_response_script TEXT DEFAULT '' NOT NULL,
+    created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (endpoint_id) REFERENCES endpoints(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_endpoint_revisions_endpoint_id ON endpoint_revisions(endpoint_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_endpoint_revisions_endpoint_id;
+DROP TABLE IF EXISTS endpoint_revisions;
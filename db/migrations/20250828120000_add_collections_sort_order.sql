@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE collections ADD COLUMN sort_order INTEGER DEFAULT 0 NOT NULL;
+-- keep the newest collections first, as they were listed before
+UPDATE collections SET sort_order = -id;
+
+-- +goose Down
+ALTER TABLE collections DROP COLUMN sort_order;
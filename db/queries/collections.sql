@@ -1,17 +1,28 @@
 -- name: CreateCollection :one
-INSERT INTO collections (name) VALUES (?) RETURNING *;
+-- new collections are listed first
+INSERT INTO collections (name, sort_order)
+VALUES (?, (SELECT COALESCE(MIN(sort_order), 0) - 1 FROM collections))
+RETURNING *;
 
 -- name: GetCollectionsPaginated :many
 SELECT * FROM collections
-ORDER BY created_at DESC
+WHERE deleted_at IS NULL
+ORDER BY sort_order, id
 LIMIT ? OFFSET ?;
 
 -- name: GetCollections :many
 SELECT * FROM collections
-ORDER BY created_at DESC;
+WHERE deleted_at IS NULL
+ORDER BY sort_order, id;
 
 -- name: CountCollections :one
-SELECT COUNT(*) FROM collections;
+SELECT COUNT(*) FROM collections
+WHERE deleted_at IS NULL;
+
+-- name: UpdateCollectionSortOrder :exec
+UPDATE collections
+SET sort_order = ?
+WHERE id = ? AND deleted_at IS NULL;
 
 -- name: UpdateCollectionName :one
 UPDATE collections
@@ -25,4 +36,37 @@ WHERE id = ?;
 
 -- name: GetCollection :one
 SELECT * FROM collections
-WHERE id = ?;
+WHERE id = ? AND deleted_at IS NULL;
+
+-- name: UpdateCollectionVariables :one
+UPDATE collections
+SET variables = ?
+WHERE id = ?
+RETURNING *;
+
+-- name: UpdateCollectionScripts :one
+UPDATE collections
+SET pre_request_script = ?,
+    post_response_script = ?
+WHERE id = ?
+RETURNING *;
+
+-- name: TrashCollection :exec
+UPDATE collections
+SET deleted_at = ?
+WHERE id = ? AND deleted_at IS NULL;
+
+-- name: RestoreCollection :one
+UPDATE collections
+SET deleted_at = NULL
+WHERE id = ? AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: ListTrashedCollections :many
+SELECT * FROM collections
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC;
+
+-- name: PurgeCollections :execrows
+DELETE FROM collections
+WHERE deleted_at IS NOT NULL AND deleted_at < sqlc.arg(before);
@@ -6,34 +6,46 @@ INSERT INTO endpoints (
     url,
     headers,
     query_params,
-    request_body
+    request_body,
+    protocol,
+    proto_files,
+    extractions,
+    pre_request_script,
+    post_response_script,
+    folder_id,
+    sort_order
 ) VALUES (
-    ?, ?, ?, ?, ?, ?, ?
+    ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
 )
 RETURNING *;
 
 -- name: GetEndpoint :one
 SELECT * FROM endpoints
-WHERE id = ? LIMIT 1;
+WHERE id = ? AND deleted_at IS NULL LIMIT 1;
 
 -- name: ListEndpointsByCollection :many
 SELECT * FROM endpoints
-WHERE collection_id = ?
-ORDER BY created_at DESC;
+WHERE collection_id = ? AND deleted_at IS NULL
+ORDER BY sort_order, id;
+
+-- name: NextEndpointSortOrder :one
+SELECT CAST(COALESCE(MAX(sort_order), 0) + 1 AS INTEGER) FROM endpoints
+WHERE collection_id = ? AND deleted_at IS NULL;
 
 -- name: ListEndpointsPaginated :many
 SELECT * FROM endpoints
-WHERE collection_id = ?
-ORDER BY name
+WHERE collection_id = ? AND deleted_at IS NULL
+ORDER BY sort_order, id
 LIMIT ? OFFSET ?;
 
 -- name: CountEndpointsByCollection :one
 SELECT COUNT(*) FROM endpoints
-WHERE collection_id = ?;
+WHERE collection_id = ? AND deleted_at IS NULL;
 
 -- name: GetEndpointCountsByCollections :many
 SELECT collection_id, COUNT(*) as count
 FROM endpoints
+WHERE deleted_at IS NULL
 GROUP BY collection_id;
 
 -- name: UpdateEndpointName :one
@@ -52,11 +64,78 @@ SET
     url = ?,
     headers = ?,
     query_params = ?,
-    request_body = ?
+    request_body = ?,
+    protocol = ?,
+    proto_files = ?,
+    extractions = ?,
+    pre_request_script = ?,
+    post_response_script = ?
 WHERE
     id = ?
 RETURNING *;
 
+-- name: UpdateEndpointFolder :one
+UPDATE endpoints
+SET folder_id = ?
+WHERE id = ? AND deleted_at IS NULL
+RETURNING *;
+
+-- name: UpdateEndpointSortOrder :exec
+UPDATE endpoints
+SET sort_order = ?
+WHERE id = ? AND deleted_at IS NULL;
+
+-- name: UpdateEndpointCollection :one
+UPDATE endpoints
+SET collection_id = ?,
+    folder_id = NULL,
+    sort_order = ?
+WHERE id = ? AND deleted_at IS NULL
+RETURNING *;
+
+-- name: DeleteEndpointsByFolder :exec
+DELETE FROM endpoints
+WHERE folder_id = ?;
+
 -- name: DeleteEndpoint :exec
 DELETE FROM endpoints
 WHERE id = ?;
+
+-- name: TrashEndpoint :exec
+UPDATE endpoints
+SET deleted_at = ?
+WHERE id = ? AND deleted_at IS NULL;
+
+-- name: TrashEndpointsByFolder :exec
+UPDATE endpoints
+SET deleted_at = ?
+WHERE folder_id = ? AND deleted_at IS NULL;
+
+-- name: RestoreEndpoint :one
+UPDATE endpoints
+SET deleted_at = NULL,
+    folder_id = ?
+WHERE id = ? AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: RestoreEndpointsByFolder :exec
+UPDATE endpoints
+SET deleted_at = NULL
+WHERE folder_id = ? AND deleted_at = ?;
+
+-- name: GetTrashedEndpoint :one
+SELECT * FROM endpoints
+WHERE id = ? AND deleted_at IS NOT NULL LIMIT 1;
+
+-- name: ListTrashedEndpoints :many
+SELECT * FROM endpoints
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC;
+
+-- name: PurgeEndpoints :execrows
+DELETE FROM endpoints
+WHERE (endpoints.deleted_at IS NOT NULL AND endpoints.deleted_at < sqlc.arg(before))
+   OR collection_id IN (
+       SELECT id FROM collections
+       WHERE collections.deleted_at IS NOT NULL AND collections.deleted_at < sqlc.arg(before)
+   );
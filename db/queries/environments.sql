@@ -0,0 +1,39 @@
+-- name: CreateEnvironment :one
+INSERT INTO environments (name) VALUES (?) RETURNING *;
+
+-- name: GetEnvironment :one
+SELECT * FROM environments
+WHERE id = ?;
+
+-- name: GetEnvironmentByName :one
+SELECT * FROM environments
+WHERE name = ?;
+
+-- name: GetActiveEnvironment :one
+SELECT * FROM environments
+WHERE active = 1
+LIMIT 1;
+
+-- name: ListEnvironments :many
+SELECT * FROM environments
+ORDER BY name;
+
+-- name: UpdateEnvironmentName :one
+UPDATE environments
+SET name = ?
+WHERE id = ?
+RETURNING *;
+
+-- name: UpdateEnvironmentVariables :one
+UPDATE environments
+SET variables = ?
+WHERE id = ?
+RETURNING *;
+
+-- name: SetActiveEnvironment :exec
+UPDATE environments
+SET active = CASE WHEN id = ? THEN 1 ELSE 0 END;
+
+-- name: DeleteEnvironment :exec
+DELETE FROM environments
+WHERE id = ?;
@@ -0,0 +1,64 @@
+-- name: CreateFolder :one
+INSERT INTO folders (collection_id, parent_id, name) VALUES (?, ?, ?) RETURNING *;
+
+-- name: GetFolder :one
+SELECT * FROM folders
+WHERE id = ? AND deleted_at IS NULL LIMIT 1;
+
+-- name: ListFoldersByCollection :many
+SELECT * FROM folders
+WHERE collection_id = ? AND deleted_at IS NULL
+ORDER BY name;
+
+-- name: UpdateFolderName :one
+UPDATE folders
+SET name = ?
+WHERE id = ?
+RETURNING *;
+
+-- name: UpdateFolderParent :one
+UPDATE folders
+SET parent_id = ?
+WHERE id = ?
+RETURNING *;
+
+-- name: UpdateFolderHeaders :one
+UPDATE folders
+SET headers = ?
+WHERE id = ?
+RETURNING *;
+
+-- name: UpdateFolderVariables :one
+UPDATE folders
+SET variables = ?
+WHERE id = ?
+RETURNING *;
+
+-- name: DeleteFolder :exec
+DELETE FROM folders
+WHERE id = ?;
+
+-- name: TrashFolder :exec
+UPDATE folders
+SET deleted_at = ?
+WHERE id = ? AND deleted_at IS NULL;
+
+-- name: RestoreFolder :one
+UPDATE folders
+SET deleted_at = NULL,
+    parent_id = ?
+WHERE id = ? AND deleted_at IS NOT NULL
+RETURNING *;
+
+-- name: ListTrashedFolders :many
+SELECT * FROM folders
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC;
+
+-- name: PurgeFolders :execrows
+DELETE FROM folders
+WHERE (folders.deleted_at IS NOT NULL AND folders.deleted_at < sqlc.arg(before))
+   OR collection_id IN (
+       SELECT id FROM collections
+       WHERE collections.deleted_at IS NOT NULL AND collections.deleted_at < sqlc.arg(before)
+   );
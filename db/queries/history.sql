@@ -25,6 +25,66 @@ WHERE collection_id = ?;
 DELETE FROM history
 WHERE id = ?;
 
--- name: DeleteOldHistory :exec
+-- name: DeleteOldHistory :execrows
 DELETE FROM history
-WHERE executed_at < datetime('now', '-30 days');
\ No newline at end of file
+WHERE datetime(executed_at) < datetime(CAST(sqlc.arg(before) AS TEXT));
+
+-- name: DeleteExcessHistoryPerCollection :execrows
+DELETE FROM history
+WHERE id IN (
+    SELECT id FROM (
+        SELECT id, ROW_NUMBER() OVER (
+            PARTITION BY collection_id
+            ORDER BY datetime(executed_at) DESC, id DESC
+        ) AS position
+        FROM history
+    )
+    WHERE position > CAST(sqlc.arg(max_entries) AS INTEGER)
+);
+
+-- name: DeleteHistoryOverSize :execrows
+-- keeps the newest entries whose combined size fits in max_bytes
+DELETE FROM history
+WHERE id IN (
+    SELECT id FROM (
+        SELECT id, SUM(
+            LENGTH(CAST(url AS BLOB))
+            + LENGTH(CAST(COALESCE(request_headers, '') AS BLOB))
+            + LENGTH(CAST(COALESCE(query_params, '') AS BLOB))
+            + LENGTH(CAST(COALESCE(request_body, '') AS BLOB))
+            + LENGTH(CAST(COALESCE(response_headers, '') AS BLOB))
+            + LENGTH(CAST(COALESCE(response_body, '') AS BLOB))
+        ) OVER (ORDER BY datetime(executed_at) DESC, id DESC) AS running_size
+        FROM history
+    )
+    WHERE running_size > CAST(sqlc.arg(max_bytes) AS INTEGER)
+);
+
+-- name: StripSuccessfulHistoryBodies :execrows
+UPDATE history
+SET request_body = NULL, response_body = NULL
+WHERE status_code < 400
+  AND (request_body IS NOT NULL OR response_body IS NOT NULL);
+
+-- name: GetHistoryStats :one
+SELECT
+    COUNT(*) AS entries,
+    CAST(COALESCE(SUM(
+        LENGTH(CAST(url AS BLOB))
+        + LENGTH(CAST(COALESCE(request_headers, '') AS BLOB))
+        + LENGTH(CAST(COALESCE(query_params, '') AS BLOB))
+        + LENGTH(CAST(COALESCE(request_body, '') AS BLOB))
+        + LENGTH(CAST(COALESCE(response_headers, '') AS BLOB))
+        + LENGTH(CAST(COALESCE(response_body, '') AS BLOB))
+    ), 0) AS INTEGER) AS size
+FROM history;
+
+-- SearchHistory and CountSearchHistory are in
+-- internal/backend/database/history_search.go, since sqlc cannot parse the
+-- FTS4 table they use
+
+-- name: GetLatestSuccessfulHistory :one
+SELECT * FROM history
+WHERE collection_id = ? AND endpoint_name = ? AND status_code < 400
+ORDER BY executed_at DESC, id DESC
+LIMIT 1;
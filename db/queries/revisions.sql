@@ -0,0 +1,31 @@
+-- name: CreateEndpointRevision :one
+INSERT INTO endpoint_revisions (
+    endpoint_id,
+    name,
+    method,
+    url,
+    headers,
+    query_params,
+    request_body,
+    protocol,
+    proto_files,
+    extractions,
+    pre_request_script,
+    post_response_script
+) VALUES (
+    ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+)
+RETURNING *;
+
+-- name: GetEndpointRevision :one
+SELECT * FROM endpoint_revisions
+WHERE id = ? LIMIT 1;
+
+-- name: ListEndpointRevisions :many
+SELECT * FROM endpoint_revisions
+WHERE endpoint_id = ?
+ORDER BY id DESC;
+
+-- name: PurgeOrphanedEndpointRevisions :execrows
+DELETE FROM endpoint_revisions
+WHERE endpoint_id NOT IN (SELECT id FROM endpoints);
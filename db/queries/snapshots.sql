@@ -0,0 +1,31 @@
+-- name: PinSnapshot :one
+INSERT INTO snapshots (endpoint_id, status_code, response_headers, response_body)
+VALUES (?, ?, ?, ?)
+ON CONFLICT (endpoint_id) DO UPDATE SET
+    status_code = excluded.status_code,
+    response_headers = excluded.response_headers,
+    response_body = excluded.response_body,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: GetSnapshotByEndpoint :one
+SELECT * FROM snapshots
+WHERE endpoint_id = ?;
+
+-- name: ListSnapshotsByCollection :many
+SELECT sqlc.embed(snapshots), endpoints.name AS endpoint_name FROM snapshots
+JOIN endpoints ON endpoints.id = snapshots.endpoint_id
+WHERE endpoints.collection_id = ?
+ORDER BY endpoints.name;
+
+-- name: UpdateSnapshotIgnored :one
+UPDATE snapshots
+SET ignored_paths = ?,
+    ignored_headers = ?,
+    updated_at = CURRENT_TIMESTAMP
+WHERE endpoint_id = ?
+RETURNING *;
+
+-- name: DeleteSnapshotByEndpoint :execrows
+DELETE FROM snapshots
+WHERE endpoint_id = ?;
@@ -0,0 +1,151 @@
+package collections
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/maniac-en/req/internal/backend/crud"
+	"github.com/maniac-en/req/internal/backend/database"
+	"github.com/maniac-en/req/internal/log"
+)
+
+// Duplicate copies a collection with its variables, scripts, folders and
+// endpoints. History and snapshots stay with the original. The copy is made
+// in one transaction, so a failure leaves no partial copy behind.
+func (c *CollectionsManager) Duplicate(ctx context.Context, id int64) (CollectionEntity, error) {
+	var duplicated CollectionEntity
+	err := c.DB.InTx(ctx, func(tx *database.Queries) error {
+		var err error
+		duplicated, err = NewCollectionsManager(tx).duplicate(ctx, id)
+		return err
+	})
+	return duplicated, err
+}
+
+func (c *CollectionsManager) duplicate(ctx context.Context, id int64) (CollectionEntity, error) {
+	original, err := c.Read(ctx, id)
+	if err != nil {
+		return CollectionEntity{}, err
+	}
+	all, err := c.List(ctx)
+	if err != nil {
+		return CollectionEntity{}, err
+	}
+	names := make([]string, len(all))
+	for i, collection := range all {
+		names[i] = collection.Name
+	}
+
+	log.Debug("duplicating collection", "id", id)
+	collection, err := c.Create(ctx, crud.CopyName(original.Name, names))
+	if err != nil {
+		return CollectionEntity{}, err
+	}
+	if _, err := c.DB.UpdateCollectionVariables(ctx, database.UpdateCollectionVariablesParams{
+		Variables: original.Variables,
+		ID:        collection.ID,
+	}); err != nil {
+		log.Error("failed to copy collection variables", "id", id, "error", err)
+		return CollectionEntity{}, err
+	}
+	if _, err := c.SetScripts(ctx, collection.ID, original.PreRequestScript, original.PostResponseScript); err != nil {
+		return CollectionEntity{}, err
+	}
+
+	folderIDs, err := c.copyFolders(ctx, id, collection.ID)
+	if err != nil {
+		return CollectionEntity{}, err
+	}
+
+	endpoints, err := c.DB.ListEndpointsByCollection(ctx, id)
+	if err != nil {
+		log.Error("failed to list endpoints to copy", "id", id, "error", err)
+		return CollectionEntity{}, err
+	}
+	for _, endpoint := range endpoints {
+		folderID := sql.NullInt64{}
+		if endpoint.FolderID.Valid {
+			folderID = sql.NullInt64{Int64: folderIDs[endpoint.FolderID.Int64], Valid: true}
+		}
+		if _, err := c.DB.CreateEndpoint(ctx, database.CreateEndpointParams{
+			CollectionID:       collection.ID,
+			Name:               endpoint.Name,
+			Method:             endpoint.Method,
+			Url:                endpoint.Url,
+			Headers:            endpoint.Headers,
+			QueryParams:        endpoint.QueryParams,
+			RequestBody:        endpoint.RequestBody,
+			Protocol:           endpoint.Protocol,
+			ProtoFiles:         endpoint.ProtoFiles,
+			Extractions:        endpoint.Extractions,
+			PreRequestScript:   endpoint.PreRequestScript,
+			PostResponseScript: endpoint.PostResponseScript,
+			FolderID:           folderID,
+			SortOrder:          endpoint.SortOrder,
+		}); err != nil {
+			log.Error("failed to copy endpoint", "id", endpoint.ID, "error", err)
+			return CollectionEntity{}, err
+		}
+	}
+
+	log.Info("duplicated collection", "id", id, "copy_id", collection.ID, "name", collection.Name, "endpoints", len(endpoints))
+	return c.Read(ctx, collection.ID)
+}
+
+// copyFolders copies the folder tree of a collection, parents first, and
+// maps the original folder IDs to the copies
+func (c *CollectionsManager) copyFolders(ctx context.Context, fromID, toID int64) (map[int64]int64, error) {
+	folders, err := c.DB.ListFoldersByCollection(ctx, fromID)
+	if err != nil {
+		log.Error("failed to list folders to copy", "id", fromID, "error", err)
+		return nil, err
+	}
+
+	copied := make(map[int64]int64, len(folders))
+	for len(copied) < len(folders) {
+		progress := false
+		for _, folder := range folders {
+			if _, done := copied[folder.ID]; done {
+				continue
+			}
+			parentID := sql.NullInt64{}
+			if folder.ParentID.Valid {
+				newParentID, ok := copied[folder.ParentID.Int64]
+				if !ok {
+					continue
+				}
+				parentID = sql.NullInt64{Int64: newParentID, Valid: true}
+			}
+
+			created, err := c.DB.CreateFolder(ctx, database.CreateFolderParams{
+				CollectionID: toID,
+				ParentID:     parentID,
+				Name:         folder.Name,
+			})
+			if err != nil {
+				log.Error("failed to copy folder", "id", folder.ID, "error", err)
+				return nil, err
+			}
+			if _, err := c.DB.UpdateFolderHeaders(ctx, database.UpdateFolderHeadersParams{Headers: folder.Headers, ID: created.ID}); err != nil {
+				log.Error("failed to copy folder headers", "id", folder.ID, "error", err)
+				return nil, err
+			}
+			if _, err := c.DB.UpdateFolderVariables(ctx, database.UpdateFolderVariablesParams{Variables: folder.Variables, ID: created.ID}); err != nil {
+				log.Error("failed to copy folder variables", "id", folder.ID, "error", err)
+				return nil, err
+			}
+			copied[folder.ID] = created.ID
+			progress = true
+		}
+		if !progress {
+			// the remaining folders have a missing parent, which the tree
+			// shows at the top
+			for i, folder := range folders {
+				if _, done := copied[folder.ID]; !done {
+					folders[i].ParentID = sql.NullInt64{}
+				}
+			}
+		}
+	}
+	return copied, nil
+}
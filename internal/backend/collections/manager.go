@@ -3,9 +3,12 @@ package collections
 import (
 	"context"
 	"database/sql"
+	"slices"
+	"time"
 
 	"github.com/maniac-en/req/internal/backend/crud"
 	"github.com/maniac-en/req/internal/backend/database"
+	"github.com/maniac-en/req/internal/backend/variables"
 	"github.com/maniac-en/req/internal/log"
 )
 
@@ -78,23 +81,50 @@ func (c *CollectionsManager) Update(ctx context.Context, id int64, name string)
 	return CollectionEntity{Collection: collection}, nil
 }
 
+// Delete moves a collection to the trash. Its folders and endpoints are
+// hidden with it and come back when it is restored.
 func (c *CollectionsManager) Delete(ctx context.Context, id int64) error {
 	if err := crud.ValidateID(id); err != nil {
 		log.Warn("collection delete failed validation", "id", id)
 		return crud.ErrInvalidInput
 	}
 
-	log.Debug("deleting collection", "id", id)
-	err := c.DB.DeleteCollection(ctx, id)
+	log.Debug("trashing collection", "id", id)
+	err := c.DB.TrashCollection(ctx, database.TrashCollectionParams{
+		DeletedAt: sql.NullString{String: crud.FormatTimestamp(time.Now()), Valid: true},
+		ID:        id,
+	})
 	if err != nil {
-		log.Error("failed to delete collection", "id", id, "error", err)
+		log.Error("failed to trash collection", "id", id, "error", err)
 		return err
 	}
 
-	log.Info("deleted collection", "id", id)
+	log.Info("trashed collection", "id", id)
 	return nil
 }
 
+// Restore takes a collection out of the trash
+func (c *CollectionsManager) Restore(ctx context.Context, id int64) (CollectionEntity, error) {
+	if err := crud.ValidateID(id); err != nil {
+		log.Warn("collection restore failed validation", "id", id)
+		return CollectionEntity{}, crud.ErrInvalidInput
+	}
+
+	log.Debug("restoring collection", "id", id)
+	collection, err := c.DB.RestoreCollection(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Debug("collection not found in trash", "id", id)
+			return CollectionEntity{}, crud.ErrNotFound
+		}
+		log.Error("failed to restore collection", "id", id, "error", err)
+		return CollectionEntity{}, err
+	}
+
+	log.Info("restored collection", "id", id)
+	return CollectionEntity{Collection: collection}, nil
+}
+
 func (c *CollectionsManager) List(ctx context.Context) ([]CollectionEntity, error) {
 	collections, err := c.DB.GetCollections(ctx)
 	if err != nil {
@@ -149,3 +179,130 @@ func (c *CollectionsManager) ListPaginated(ctx context.Context, limit, offset in
 	log.Info("retrieved collections", "count", len(entities), "total", pagination.Total, "page", pagination.CurrentPage, "total_pages", pagination.TotalPages)
 	return result, nil
 }
+
+// Shift moves a collection up, for a negative offset, or down in the list.
+// The position is clamped to the ends of the list.
+func (c *CollectionsManager) Shift(ctx context.Context, id int64, offset int) (CollectionEntity, error) {
+	if _, err := c.Read(ctx, id); err != nil {
+		return CollectionEntity{}, err
+	}
+	all, err := c.List(ctx)
+	if err != nil {
+		return CollectionEntity{}, err
+	}
+
+	current := slices.IndexFunc(all, func(other CollectionEntity) bool { return other.ID == id })
+	target := max(0, min(len(all)-1, current+offset))
+	if target != current {
+		moving := all[current]
+		all = slices.Insert(slices.Delete(all, current, current+1), target, moving)
+
+		log.Debug("shifting collection", "id", id, "offset", offset)
+		if err := c.Reorder(ctx, all); err != nil {
+			return CollectionEntity{}, err
+		}
+	}
+	return c.Read(ctx, id)
+}
+
+// Reorder numbers collections in the given order, writing only the ones
+// whose position changed
+func (c *CollectionsManager) Reorder(ctx context.Context, order []CollectionEntity) error {
+	for i, collection := range order {
+		sortOrder := int64(i + 1)
+		if collection.SortOrder == sortOrder {
+			continue
+		}
+		if err := c.DB.UpdateCollectionSortOrder(ctx, database.UpdateCollectionSortOrderParams{
+			SortOrder: sortOrder,
+			ID:        collection.ID,
+		}); err != nil {
+			log.Error("failed to reorder collection", "id", collection.ID, "error", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// SetVariables replaces all variables of the collection
+func (c *CollectionsManager) SetVariables(ctx context.Context, id int64, vars map[string]string) (CollectionEntity, error) {
+	if err := crud.ValidateID(id); err != nil {
+		log.Warn("collection variables update failed ID validation", "id", id)
+		return CollectionEntity{}, crud.ErrInvalidInput
+	}
+	for name := range vars {
+		if !variables.ValidateName(name) {
+			log.Warn("collection variables update failed name validation", "variable", name)
+			return CollectionEntity{}, crud.ErrInvalidInput
+		}
+	}
+
+	data, err := variables.Encode(vars)
+	if err != nil {
+		log.Error("failed to marshal collection variables", "id", id, "error", err)
+		return CollectionEntity{}, err
+	}
+
+	log.Debug("updating collection variables", "id", id, "count", len(vars))
+	collection, err := c.DB.UpdateCollectionVariables(ctx, database.UpdateCollectionVariablesParams{
+		Variables: data,
+		ID:        id,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Debug("collection not found for variables update", "id", id)
+			return CollectionEntity{}, crud.ErrNotFound
+		}
+		log.Error("failed to update collection variables", "id", id, "error", err)
+		return CollectionEntity{}, err
+	}
+	return CollectionEntity{Collection: collection}, nil
+}
+
+// SetVariable sets a single variable, keeping the others
+func (c *CollectionsManager) SetVariable(ctx context.Context, id int64, name, value string) (CollectionEntity, error) {
+	collection, err := c.Read(ctx, id)
+	if err != nil {
+		return CollectionEntity{}, err
+	}
+	vars := collection.GetVariables()
+	vars[name] = value
+	return c.SetVariables(ctx, id, vars)
+}
+
+// UnsetVariable removes a single variable, keeping the others
+func (c *CollectionsManager) UnsetVariable(ctx context.Context, id int64, name string) (CollectionEntity, error) {
+	collection, err := c.Read(ctx, id)
+	if err != nil {
+		return CollectionEntity{}, err
+	}
+	vars := collection.GetVariables()
+	delete(vars, name)
+	return c.SetVariables(ctx, id, vars)
+}
+
+// SetScripts replaces the scripts run around every request of the collection
+func (c *CollectionsManager) SetScripts(ctx context.Context, id int64, preRequest, postResponse string) (CollectionEntity, error) {
+	if err := crud.ValidateID(id); err != nil {
+		log.Warn("collection scripts update failed ID validation", "id", id)
+		return CollectionEntity{}, crud.ErrInvalidInput
+	}
+
+	log.Debug("updating collection scripts", "id", id)
+	collection, err := c.DB.UpdateCollectionScripts(ctx, database.UpdateCollectionScriptsParams{
+		PreRequestScript:   preRequest,
+		PostResponseScript: postResponse,
+		ID:                 id,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Debug("collection not found for scripts update", "id", id)
+			return CollectionEntity{}, crud.ErrNotFound
+		}
+		log.Error("failed to update collection scripts", "id", id, "error", err)
+		return CollectionEntity{}, err
+	}
+
+	log.Info("updated collection scripts", "id", collection.ID)
+	return CollectionEntity{Collection: collection}, nil
+}
@@ -2,10 +2,12 @@ package collections
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"testing"
 
 	"github.com/maniac-en/req/internal/backend/crud"
+	"github.com/maniac-en/req/internal/backend/database"
 	"github.com/maniac-en/req/internal/backend/testutils"
 )
 
@@ -107,6 +109,65 @@ func TestCollectionsManagerCRUD(t *testing.T) {
 	})
 }
 
+func TestCollectionVariables(t *testing.T) {
+	db := testutils.SetupTestDB(t, "collections")
+	manager := NewCollectionsManager(db)
+	ctx := context.Background()
+
+	collection, err := manager.Create(ctx, "Variables Test")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if len(collection.GetVariables()) != 0 {
+		t.Errorf("Expected no variables, got %v", collection.GetVariables())
+	}
+
+	if _, err := manager.SetVariables(ctx, collection.GetID(), map[string]string{"host": "localhost"}); err != nil {
+		t.Fatalf("SetVariables failed: %v", err)
+	}
+	updated, err := manager.SetVariable(ctx, collection.GetID(), "token", "abc")
+	if err != nil {
+		t.Fatalf("SetVariable failed: %v", err)
+	}
+	vars := updated.GetVariables()
+	if vars["host"] != "localhost" || vars["token"] != "abc" {
+		t.Errorf("Expected host and token, got %v", vars)
+	}
+
+	if _, err := manager.SetVariables(ctx, collection.GetID(), map[string]string{"bad name": "x"}); err != crud.ErrInvalidInput {
+		t.Errorf("Expected ErrInvalidInput for invalid variable name, got %v", err)
+	}
+	if _, err := manager.SetVariable(ctx, 99999, "token", "abc"); err != crud.ErrNotFound {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestCollectionScripts(t *testing.T) {
+	db := testutils.SetupTestDB(t, "collections")
+	manager := NewCollectionsManager(db)
+	ctx := context.Background()
+
+	collection, err := manager.Create(ctx, "Scripts Test")
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	updated, err := manager.SetScripts(ctx, collection.GetID(), `console.log("pre")`, `req.test("ok", function () {})`)
+	if err != nil {
+		t.Fatalf("SetScripts failed: %v", err)
+	}
+	if updated.PreRequestScript != `console.log("pre")` {
+		t.Errorf("Expected pre-request script to be stored, got %q", updated.PreRequestScript)
+	}
+	if updated.PostResponseScript != `req.test("ok", function () {})` {
+		t.Errorf("Expected post-response script to be stored, got %q", updated.PostResponseScript)
+	}
+
+	if _, err := manager.SetScripts(ctx, 99999, "", ""); err != crud.ErrNotFound {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
 func TestCollectionsManagerValidation(t *testing.T) {
 	db := testutils.SetupTestDB(t, "collections")
 	manager := NewCollectionsManager(db)
@@ -133,3 +194,114 @@ func TestCollectionsManagerValidation(t *testing.T) {
 		}
 	})
 }
+
+func TestOrdering(t *testing.T) {
+	db := testutils.SetupTestDB(t, "collections")
+	manager := NewCollectionsManager(db)
+	ctx := context.Background()
+
+	created := map[string]CollectionEntity{}
+	for _, name := range []string{"first", "second", "third"} {
+		collection, err := manager.Create(ctx, name)
+		if err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		created[name] = collection
+	}
+	check := func(expected ...string) {
+		t.Helper()
+		all, _ := manager.List(ctx)
+		var got []string
+		for _, collection := range all {
+			got = append(got, collection.Name)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(expected) {
+			t.Errorf("Expected order %v, got %v", expected, got)
+		}
+	}
+	// new collections are listed first
+	check("third", "second", "first")
+
+	if _, err := manager.Shift(ctx, created["first"].ID, -2); err != nil {
+		t.Fatalf("Shift failed: %v", err)
+	}
+	check("first", "third", "second")
+	manager.Shift(ctx, created["third"].ID, 10)
+	check("first", "second", "third")
+	manager.Shift(ctx, created["first"].ID, -1)
+	check("first", "second", "third")
+
+	manager.Create(ctx, "fourth")
+	check("fourth", "first", "second", "third")
+	paginated, err := manager.ListPaginated(ctx, 2, 2)
+	if err != nil {
+		t.Fatalf("ListPaginated failed: %v", err)
+	}
+	if len(paginated.Collections) != 2 || paginated.Collections[0].Name != "second" {
+		t.Errorf("Expected pages to follow the order, got %+v", paginated.Collections)
+	}
+
+	if _, err := manager.Shift(ctx, 99999, 1); err != crud.ErrNotFound {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestDuplicate(t *testing.T) {
+	db := testutils.SetupTestDB(t, "collections", "endpoints", "folders")
+	manager := NewCollectionsManager(db)
+	ctx := context.Background()
+
+	original, _ := manager.Create(ctx, "API")
+	manager.SetVariables(ctx, original.ID, map[string]string{"host": "localhost"})
+	manager.SetScripts(ctx, original.ID, "console.log('pre')", "")
+	users, _ := db.CreateFolder(ctx, database.CreateFolderParams{CollectionID: original.ID, Name: "users"})
+	admin, _ := db.CreateFolder(ctx, database.CreateFolderParams{CollectionID: original.ID, ParentID: sql.NullInt64{Int64: users.ID, Valid: true}, Name: "admin"})
+	db.UpdateFolderVariables(ctx, database.UpdateFolderVariablesParams{Variables: `{"role":"admin"}`, ID: admin.ID})
+	for i, folderID := range []sql.NullInt64{{}, {Int64: admin.ID, Valid: true}} {
+		if _, err := db.CreateEndpoint(ctx, database.CreateEndpointParams{
+			CollectionID: original.ID,
+			Name:         fmt.Sprintf("endpoint %d", i),
+			Method:       "GET",
+			Headers:      "{}",
+			QueryParams:  "{}",
+			Protocol:     "http",
+			ProtoFiles:   "[]",
+			Extractions:  "[]",
+			FolderID:     folderID,
+			SortOrder:    int64(i + 1),
+		}); err != nil {
+			t.Fatalf("CreateEndpoint failed: %v", err)
+		}
+	}
+
+	copied, err := manager.Duplicate(ctx, original.ID)
+	if err != nil {
+		t.Fatalf("Duplicate failed: %v", err)
+	}
+	if copied.Name != "API (copy)" || copied.GetVariables()["host"] != "localhost" || copied.PreRequestScript != "console.log('pre')" {
+		t.Errorf("Expected a copy with the variables and scripts, got %+v", copied.Collection)
+	}
+
+	folders, _ := db.ListFoldersByCollection(ctx, copied.ID)
+	if len(folders) != 2 {
+		t.Fatalf("Expected 2 copied folders, got %d", len(folders))
+	}
+	byName := map[string]database.Folder{}
+	for _, folder := range folders {
+		byName[folder.Name] = folder
+	}
+	if byName["admin"].ParentID.Int64 != byName["users"].ID || byName["admin"].Variables != `{"role":"admin"}` {
+		t.Errorf("Expected admin copied inside users with its variables, got %+v", byName["admin"])
+	}
+
+	endpoints, _ := db.ListEndpointsByCollection(ctx, copied.ID)
+	if len(endpoints) != 2 {
+		t.Fatalf("Expected 2 copied endpoints, got %d", len(endpoints))
+	}
+	if endpoints[0].Name != "endpoint 0" || endpoints[1].FolderID.Int64 != byName["admin"].ID {
+		t.Errorf("Expected endpoints copied in order into the copied folders, got %+v", endpoints)
+	}
+	if original, _ := db.ListEndpointsByCollection(ctx, original.ID); len(original) != 2 {
+		t.Errorf("Expected the original endpoints to remain, got %d", len(original))
+	}
+}
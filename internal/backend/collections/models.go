@@ -5,6 +5,7 @@ import (
 
 	"github.com/maniac-en/req/internal/backend/crud"
 	"github.com/maniac-en/req/internal/backend/database"
+	"github.com/maniac-en/req/internal/backend/variables"
 )
 
 type CollectionEntity struct {
@@ -27,6 +28,10 @@ func (c CollectionEntity) GetUpdatedAt() time.Time {
 	return crud.ParseTimestamp(c.UpdatedAt)
 }
 
+func (c CollectionEntity) GetVariables() map[string]string {
+	return variables.Decode(c.Variables)
+}
+
 type CollectionsManager struct {
 	DB *database.Queries
 }
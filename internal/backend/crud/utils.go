@@ -4,17 +4,21 @@ import (
 	"fmt"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/maniac-en/req/internal/log"
 )
 
+// MaxNameLength is the longest name ValidateName accepts, in bytes
+const MaxNameLength = 100
+
 func ValidateName(name string) error {
 	name = strings.TrimSpace(name)
 	if name == "" {
 		log.Warn("validation failed: empty name")
 		return fmt.Errorf("name cannot be empty")
 	}
-	if len(name) > 100 {
+	if len(name) > MaxNameLength {
 		log.Warn("validation failed: name too long", "length", len(name))
 		return fmt.Errorf("name cannot exceed 100 characters")
 	}
@@ -29,12 +33,56 @@ func ValidateID(id int64) error {
 	return nil
 }
 
-// ParseTimestamp safely parses RFC3339 timestamp strings from database
+// CopyName returns the name for a copy of an entity, "name (copy)", numbered
+// when that is already among taken. Names are compared ignoring case, and
+// long names are shortened so the copy's name still fits MaxNameLength.
+func CopyName(name string, taken []string) string {
+	exists := func(candidate string) bool {
+		for _, other := range taken {
+			if strings.EqualFold(other, candidate) {
+				return true
+			}
+		}
+		return false
+	}
+
+	withSuffix := func(suffix string) string {
+		return Shorten(name, MaxNameLength-len(suffix)) + suffix
+	}
+	candidate := withSuffix(" (copy)")
+	for n := 2; exists(candidate); n++ {
+		candidate = withSuffix(fmt.Sprintf(" (copy %d)", n))
+	}
+	return candidate
+}
+
+// Shorten cuts s to at most n bytes without splitting a character
+func Shorten(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return strings.TrimRight(s[:n], " ")
+}
+
+// FormatTimestamp formats a time for storage. The UTC, fixed width format
+// sorts in time order and ParseTimestamp reads it back.
+func FormatTimestamp(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05.000000")
+}
+
+// ParseTimestamp safely parses timestamp strings from database. It accepts
+// RFC3339 and the UTC format SQLite's CURRENT_TIMESTAMP produces.
 func ParseTimestamp(timestamp string) time.Time {
 	parsed, err := time.Parse(time.RFC3339, timestamp)
-	if err != nil {
-		log.Warn("failed to parse timestamp", "timestamp", timestamp, "error", err)
-		return time.Time{}
+	if err == nil {
+		return parsed
+	}
+	if parsed, sqliteErr := time.Parse(time.DateTime, timestamp); sqliteErr == nil {
+		return parsed
 	}
-	return parsed
+	log.Warn("failed to parse timestamp", "timestamp", timestamp, "error", err)
+	return time.Time{}
 }
@@ -1,6 +1,10 @@
 package crud
 
-import "testing"
+import (
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestValidateName(t *testing.T) {
 	tests := []struct {
@@ -46,3 +50,43 @@ func TestValidateID(t *testing.T) {
 		}
 	}
 }
+
+func TestParseTimestamp(t *testing.T) {
+	tests := map[string]string{
+		"2025-08-01T10:20:30Z":      "2025-08-01T10:20:30Z",
+		"2025-08-01T12:20:30+02:00": "2025-08-01T10:20:30Z",
+		"2025-08-01 10:20:30":       "2025-08-01T10:20:30Z",
+	}
+	for input, expected := range tests {
+		if got := ParseTimestamp(input).UTC().Format(time.RFC3339); got != expected {
+			t.Errorf("ParseTimestamp(%q) = %s, expected %s", input, got, expected)
+		}
+	}
+	if !ParseTimestamp("yesterday").IsZero() {
+		t.Error("expected an unparseable timestamp to give the zero time")
+	}
+}
+
+func TestCopyName(t *testing.T) {
+	tests := []struct {
+		name     string
+		taken    []string
+		expected string
+	}{
+		{"Login", nil, "Login (copy)"},
+		{"Login", []string{"Login", "login (COPY)"}, "Login (copy 2)"},
+		{"Login", []string{"Login (copy)", "Login (copy 2)"}, "Login (copy 3)"},
+		{strings.Repeat("a", 100), nil, strings.Repeat("a", 93) + " (copy)"},
+		{strings.Repeat("é", 50), []string{strings.Repeat("é", 46) + " (copy)"}, strings.Repeat("é", 45) + " (copy 2)"},
+	}
+
+	for _, test := range tests {
+		got := CopyName(test.name, test.taken)
+		if got != test.expected {
+			t.Errorf("expected %q, got %q", test.expected, got)
+		}
+		if err := ValidateName(got); err != nil {
+			t.Errorf("expected %q to be a valid name, got %v", got, err)
+		}
+	}
+}
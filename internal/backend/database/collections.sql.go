@@ -7,10 +7,12 @@ package database
 
 import (
 	"context"
+	"database/sql"
 )
 
 const countCollections = `-- name: CountCollections :one
 SELECT COUNT(*) FROM collections
+WHERE deleted_at IS NULL
 `
 
 func (q *Queries) CountCollections(ctx context.Context) (int64, error) {
@@ -21,9 +23,12 @@ func (q *Queries) CountCollections(ctx context.Context) (int64, error) {
 }
 
 const createCollection = `-- name: CreateCollection :one
-INSERT INTO collections (name) VALUES (?) RETURNING id, name, created_at, updated_at
+INSERT INTO collections (name, sort_order)
+VALUES (?, (SELECT COALESCE(MIN(sort_order), 0) - 1 FROM collections))
+RETURNING id, name, created_at, updated_at, variables, pre_request_script, post_response_script, deleted_at, sort_order
 `
 
+// new collections are listed first
 func (q *Queries) CreateCollection(ctx context.Context, name string) (Collection, error) {
 	row := q.db.QueryRowContext(ctx, createCollection, name)
 	var i Collection
@@ -32,6 +37,11 @@ func (q *Queries) CreateCollection(ctx context.Context, name string) (Collection
 		&i.Name,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Variables,
+		&i.PreRequestScript,
+		&i.PostResponseScript,
+		&i.DeletedAt,
+		&i.SortOrder,
 	)
 	return i, err
 }
@@ -47,8 +57,8 @@ func (q *Queries) DeleteCollection(ctx context.Context, id int64) error {
 }
 
 const getCollection = `-- name: GetCollection :one
-SELECT id, name, created_at, updated_at FROM collections
-WHERE id = ?
+SELECT id, name, created_at, updated_at, variables, pre_request_script, post_response_script, deleted_at, sort_order FROM collections
+WHERE id = ? AND deleted_at IS NULL
 `
 
 func (q *Queries) GetCollection(ctx context.Context, id int64) (Collection, error) {
@@ -59,13 +69,19 @@ func (q *Queries) GetCollection(ctx context.Context, id int64) (Collection, erro
 		&i.Name,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Variables,
+		&i.PreRequestScript,
+		&i.PostResponseScript,
+		&i.DeletedAt,
+		&i.SortOrder,
 	)
 	return i, err
 }
 
 const getCollections = `-- name: GetCollections :many
-SELECT id, name, created_at, updated_at FROM collections
-ORDER BY created_at DESC
+SELECT id, name, created_at, updated_at, variables, pre_request_script, post_response_script, deleted_at, sort_order FROM collections
+WHERE deleted_at IS NULL
+ORDER BY sort_order, id
 `
 
 func (q *Queries) GetCollections(ctx context.Context) ([]Collection, error) {
@@ -82,6 +98,11 @@ func (q *Queries) GetCollections(ctx context.Context) ([]Collection, error) {
 			&i.Name,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Variables,
+			&i.PreRequestScript,
+			&i.PostResponseScript,
+			&i.DeletedAt,
+			&i.SortOrder,
 		); err != nil {
 			return nil, err
 		}
@@ -97,8 +118,9 @@ func (q *Queries) GetCollections(ctx context.Context) ([]Collection, error) {
 }
 
 const getCollectionsPaginated = `-- name: GetCollectionsPaginated :many
-SELECT id, name, created_at, updated_at FROM collections
-ORDER BY created_at DESC
+SELECT id, name, created_at, updated_at, variables, pre_request_script, post_response_script, deleted_at, sort_order FROM collections
+WHERE deleted_at IS NULL
+ORDER BY sort_order, id
 LIMIT ? OFFSET ?
 `
 
@@ -121,6 +143,11 @@ func (q *Queries) GetCollectionsPaginated(ctx context.Context, arg GetCollection
 			&i.Name,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Variables,
+			&i.PreRequestScript,
+			&i.PostResponseScript,
+			&i.DeletedAt,
+			&i.SortOrder,
 		); err != nil {
 			return nil, err
 		}
@@ -135,11 +162,103 @@ func (q *Queries) GetCollectionsPaginated(ctx context.Context, arg GetCollection
 	return items, nil
 }
 
+const listTrashedCollections = `-- name: ListTrashedCollections :many
+SELECT id, name, created_at, updated_at, variables, pre_request_script, post_response_script, deleted_at, sort_order FROM collections
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC
+`
+
+func (q *Queries) ListTrashedCollections(ctx context.Context) ([]Collection, error) {
+	rows, err := q.db.QueryContext(ctx, listTrashedCollections)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Collection
+	for rows.Next() {
+		var i Collection
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Variables,
+			&i.PreRequestScript,
+			&i.PostResponseScript,
+			&i.DeletedAt,
+			&i.SortOrder,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeCollections = `-- name: PurgeCollections :execrows
+DELETE FROM collections
+WHERE deleted_at IS NOT NULL AND deleted_at < ?1
+`
+
+func (q *Queries) PurgeCollections(ctx context.Context, before sql.NullString) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeCollections, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreCollection = `-- name: RestoreCollection :one
+UPDATE collections
+SET deleted_at = NULL
+WHERE id = ? AND deleted_at IS NOT NULL
+RETURNING id, name, created_at, updated_at, variables, pre_request_script, post_response_script, deleted_at, sort_order
+`
+
+func (q *Queries) RestoreCollection(ctx context.Context, id int64) (Collection, error) {
+	row := q.db.QueryRowContext(ctx, restoreCollection, id)
+	var i Collection
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Variables,
+		&i.PreRequestScript,
+		&i.PostResponseScript,
+		&i.DeletedAt,
+		&i.SortOrder,
+	)
+	return i, err
+}
+
+const trashCollection = `-- name: TrashCollection :exec
+UPDATE collections
+SET deleted_at = ?
+WHERE id = ? AND deleted_at IS NULL
+`
+
+type TrashCollectionParams struct {
+	DeletedAt sql.NullString `db:"deleted_at" json:"deleted_at"`
+	ID        int64          `db:"id" json:"id"`
+}
+
+func (q *Queries) TrashCollection(ctx context.Context, arg TrashCollectionParams) error {
+	_, err := q.db.ExecContext(ctx, trashCollection, arg.DeletedAt, arg.ID)
+	return err
+}
+
 const updateCollectionName = `-- name: UpdateCollectionName :one
 UPDATE collections
 SET name = ?
 WHERE id = ?
-RETURNING id, name, created_at, updated_at
+RETURNING id, name, created_at, updated_at, variables, pre_request_script, post_response_script, deleted_at, sort_order
 `
 
 type UpdateCollectionNameParams struct {
@@ -155,6 +274,87 @@ func (q *Queries) UpdateCollectionName(ctx context.Context, arg UpdateCollection
 		&i.Name,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Variables,
+		&i.PreRequestScript,
+		&i.PostResponseScript,
+		&i.DeletedAt,
+		&i.SortOrder,
+	)
+	return i, err
+}
+
+const updateCollectionScripts = `-- name: UpdateCollectionScripts :one
+UPDATE collections
+SET pre_request_script = ?,
+    post_response_script = ?
+WHERE id = ?
+RETURNING id, name, created_at, updated_at, variables, pre_request_script, post_response_script, deleted_at, sort_order
+`
+
+type UpdateCollectionScriptsParams struct {
+	PreRequestScript   string `db:"pre_request_script" json:"pre_request_script"`
+	PostResponseScript string `db:"post_response_script" json:"post_response_script"`
+	ID                 int64  `db:"id" json:"id"`
+}
+
+func (q *Queries) UpdateCollectionScripts(ctx context.Context, arg UpdateCollectionScriptsParams) (Collection, error) {
+	row := q.db.QueryRowContext(ctx, updateCollectionScripts, arg.PreRequestScript, arg.PostResponseScript, arg.ID)
+	var i Collection
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Variables,
+		&i.PreRequestScript,
+		&i.PostResponseScript,
+		&i.DeletedAt,
+		&i.SortOrder,
+	)
+	return i, err
+}
+
+const updateCollectionSortOrder = `-- name: UpdateCollectionSortOrder :exec
+UPDATE collections
+SET sort_order = ?
+WHERE id = ? AND deleted_at IS NULL
+`
+
+type UpdateCollectionSortOrderParams struct {
+	SortOrder int64 `db:"sort_order" json:"sort_order"`
+	ID        int64 `db:"id" json:"id"`
+}
+
+func (q *Queries) UpdateCollectionSortOrder(ctx context.Context, arg UpdateCollectionSortOrderParams) error {
+	_, err := q.db.ExecContext(ctx, updateCollectionSortOrder, arg.SortOrder, arg.ID)
+	return err
+}
+
+const updateCollectionVariables = `-- name: UpdateCollectionVariables :one
+UPDATE collections
+SET variables = ?
+WHERE id = ?
+RETURNING id, name, created_at, updated_at, variables, pre_request_script, post_response_script, deleted_at, sort_order
+`
+
+type UpdateCollectionVariablesParams struct {
+	Variables string `db:"variables" json:"variables"`
+	ID        int64  `db:"id" json:"id"`
+}
+
+func (q *Queries) UpdateCollectionVariables(ctx context.Context, arg UpdateCollectionVariablesParams) (Collection, error) {
+	row := q.db.QueryRowContext(ctx, updateCollectionVariables, arg.Variables, arg.ID)
+	var i Collection
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Variables,
+		&i.PreRequestScript,
+		&i.PostResponseScript,
+		&i.DeletedAt,
+		&i.SortOrder,
 	)
 	return i, err
 }
@@ -7,11 +7,12 @@ package database
 
 import (
 	"context"
+	"database/sql"
 )
 
 const countEndpointsByCollection = `-- name: CountEndpointsByCollection :one
 SELECT COUNT(*) FROM endpoints
-WHERE collection_id = ?
+WHERE collection_id = ? AND deleted_at IS NULL
 `
 
 func (q *Queries) CountEndpointsByCollection(ctx context.Context, collectionID int64) (int64, error) {
@@ -29,21 +30,35 @@ INSERT INTO endpoints (
     url,
     headers,
     query_params,
-    request_body
+    request_body,
+    protocol,
+    proto_files,
+    extractions,
+    pre_request_script,
+    post_response_script,
+    folder_id,
+    sort_order
 ) VALUES (
-    ?, ?, ?, ?, ?, ?, ?
+    ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
 )
-RETURNING id, collection_id, name, method, url, headers, query_params, request_body, created_at, updated_at
+RETURNING id, collection_id, name, method, url, headers, query_params, request_body, created_at, updated_at, protocol, proto_files, extractions, pre_request_script, post_response_script, folder_id, sort_order, deleted_at
 `
 
 type CreateEndpointParams struct {
-	CollectionID int64  `db:"collection_id" json:"collection_id"`
-	Name         string `db:"name" json:"name"`
-	Method       string `db:"method" json:"method"`
-	Url          string `db:"url" json:"url"`
-	Headers      string `db:"headers" json:"headers"`
-	QueryParams  string `db:"query_params" json:"query_params"`
-	RequestBody  string `db:"request_body" json:"request_body"`
+	CollectionID       int64         `db:"collection_id" json:"collection_id"`
+	Name               string        `db:"name" json:"name"`
+	Method             string        `db:"method" json:"method"`
+	Url                string        `db:"url" json:"url"`
+	Headers            string        `db:"headers" json:"headers"`
+	QueryParams        string        `db:"query_params" json:"query_params"`
+	RequestBody        string        `db:"request_body" json:"request_body"`
+	Protocol           string        `db:"protocol" json:"protocol"`
+	ProtoFiles         string        `db:"proto_files" json:"proto_files"`
+	Extractions        string        `db:"extractions" json:"extractions"`
+	PreRequestScript   string        `db:"pre_request_script" json:"pre_request_script"`
+	PostResponseScript string        `db:"post_response_script" json:"post_response_script"`
+	FolderID           sql.NullInt64 `db:"folder_id" json:"folder_id"`
+	SortOrder          int64         `db:"sort_order" json:"sort_order"`
 }
 
 func (q *Queries) CreateEndpoint(ctx context.Context, arg CreateEndpointParams) (Endpoint, error) {
@@ -55,6 +70,13 @@ func (q *Queries) CreateEndpoint(ctx context.Context, arg CreateEndpointParams)
 		arg.Headers,
 		arg.QueryParams,
 		arg.RequestBody,
+		arg.Protocol,
+		arg.ProtoFiles,
+		arg.Extractions,
+		arg.PreRequestScript,
+		arg.PostResponseScript,
+		arg.FolderID,
+		arg.SortOrder,
 	)
 	var i Endpoint
 	err := row.Scan(
@@ -68,6 +90,14 @@ func (q *Queries) CreateEndpoint(ctx context.Context, arg CreateEndpointParams)
 		&i.RequestBody,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Protocol,
+		&i.ProtoFiles,
+		&i.Extractions,
+		&i.PreRequestScript,
+		&i.PostResponseScript,
+		&i.FolderID,
+		&i.SortOrder,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -82,9 +112,19 @@ func (q *Queries) DeleteEndpoint(ctx context.Context, id int64) error {
 	return err
 }
 
+const deleteEndpointsByFolder = `-- name: DeleteEndpointsByFolder :exec
+DELETE FROM endpoints
+WHERE folder_id = ?
+`
+
+func (q *Queries) DeleteEndpointsByFolder(ctx context.Context, folderID sql.NullInt64) error {
+	_, err := q.db.ExecContext(ctx, deleteEndpointsByFolder, folderID)
+	return err
+}
+
 const getEndpoint = `-- name: GetEndpoint :one
-SELECT id, collection_id, name, method, url, headers, query_params, request_body, created_at, updated_at FROM endpoints
-WHERE id = ? LIMIT 1
+SELECT id, collection_id, name, method, url, headers, query_params, request_body, created_at, updated_at, protocol, proto_files, extractions, pre_request_script, post_response_script, folder_id, sort_order, deleted_at FROM endpoints
+WHERE id = ? AND deleted_at IS NULL LIMIT 1
 `
 
 func (q *Queries) GetEndpoint(ctx context.Context, id int64) (Endpoint, error) {
@@ -101,6 +141,14 @@ func (q *Queries) GetEndpoint(ctx context.Context, id int64) (Endpoint, error) {
 		&i.RequestBody,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Protocol,
+		&i.ProtoFiles,
+		&i.Extractions,
+		&i.PreRequestScript,
+		&i.PostResponseScript,
+		&i.FolderID,
+		&i.SortOrder,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -108,6 +156,7 @@ func (q *Queries) GetEndpoint(ctx context.Context, id int64) (Endpoint, error) {
 const getEndpointCountsByCollections = `-- name: GetEndpointCountsByCollections :many
 SELECT collection_id, COUNT(*) as count
 FROM endpoints
+WHERE deleted_at IS NULL
 GROUP BY collection_id
 `
 
@@ -139,10 +188,41 @@ func (q *Queries) GetEndpointCountsByCollections(ctx context.Context) ([]GetEndp
 	return items, nil
 }
 
+const getTrashedEndpoint = `-- name: GetTrashedEndpoint :one
+SELECT id, collection_id, name, method, url, headers, query_params, request_body, created_at, updated_at, protocol, proto_files, extractions, pre_request_script, post_response_script, folder_id, sort_order, deleted_at FROM endpoints
+WHERE id = ? AND deleted_at IS NOT NULL LIMIT 1
+`
+
+func (q *Queries) GetTrashedEndpoint(ctx context.Context, id int64) (Endpoint, error) {
+	row := q.db.QueryRowContext(ctx, getTrashedEndpoint, id)
+	var i Endpoint
+	err := row.Scan(
+		&i.ID,
+		&i.CollectionID,
+		&i.Name,
+		&i.Method,
+		&i.Url,
+		&i.Headers,
+		&i.QueryParams,
+		&i.RequestBody,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Protocol,
+		&i.ProtoFiles,
+		&i.Extractions,
+		&i.PreRequestScript,
+		&i.PostResponseScript,
+		&i.FolderID,
+		&i.SortOrder,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
 const listEndpointsByCollection = `-- name: ListEndpointsByCollection :many
-SELECT id, collection_id, name, method, url, headers, query_params, request_body, created_at, updated_at FROM endpoints
-WHERE collection_id = ?
-ORDER BY created_at DESC
+SELECT id, collection_id, name, method, url, headers, query_params, request_body, created_at, updated_at, protocol, proto_files, extractions, pre_request_script, post_response_script, folder_id, sort_order, deleted_at FROM endpoints
+WHERE collection_id = ? AND deleted_at IS NULL
+ORDER BY sort_order, id
 `
 
 func (q *Queries) ListEndpointsByCollection(ctx context.Context, collectionID int64) ([]Endpoint, error) {
@@ -165,6 +245,14 @@ func (q *Queries) ListEndpointsByCollection(ctx context.Context, collectionID in
 			&i.RequestBody,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Protocol,
+			&i.ProtoFiles,
+			&i.Extractions,
+			&i.PreRequestScript,
+			&i.PostResponseScript,
+			&i.FolderID,
+			&i.SortOrder,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -180,9 +268,9 @@ func (q *Queries) ListEndpointsByCollection(ctx context.Context, collectionID in
 }
 
 const listEndpointsPaginated = `-- name: ListEndpointsPaginated :many
-SELECT id, collection_id, name, method, url, headers, query_params, request_body, created_at, updated_at FROM endpoints
-WHERE collection_id = ?
-ORDER BY name
+SELECT id, collection_id, name, method, url, headers, query_params, request_body, created_at, updated_at, protocol, proto_files, extractions, pre_request_script, post_response_script, folder_id, sort_order, deleted_at FROM endpoints
+WHERE collection_id = ? AND deleted_at IS NULL
+ORDER BY sort_order, id
 LIMIT ? OFFSET ?
 `
 
@@ -212,6 +300,14 @@ func (q *Queries) ListEndpointsPaginated(ctx context.Context, arg ListEndpointsP
 			&i.RequestBody,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Protocol,
+			&i.ProtoFiles,
+			&i.Extractions,
+			&i.PreRequestScript,
+			&i.PostResponseScript,
+			&i.FolderID,
+			&i.SortOrder,
+			&i.DeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -226,6 +322,170 @@ func (q *Queries) ListEndpointsPaginated(ctx context.Context, arg ListEndpointsP
 	return items, nil
 }
 
+const listTrashedEndpoints = `-- name: ListTrashedEndpoints :many
+SELECT id, collection_id, name, method, url, headers, query_params, request_body, created_at, updated_at, protocol, proto_files, extractions, pre_request_script, post_response_script, folder_id, sort_order, deleted_at FROM endpoints
+WHERE deleted_at IS NOT NULL
+ORDER BY deleted_at DESC
+`
+
+func (q *Queries) ListTrashedEndpoints(ctx context.Context) ([]Endpoint, error) {
+	rows, err := q.db.QueryContext(ctx, listTrashedEndpoints)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Endpoint
+	for rows.Next() {
+		var i Endpoint
+		if err := rows.Scan(
+			&i.ID,
+			&i.CollectionID,
+			&i.Name,
+			&i.Method,
+			&i.Url,
+			&i.Headers,
+			&i.QueryParams,
+			&i.RequestBody,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Protocol,
+			&i.ProtoFiles,
+			&i.Extractions,
+			&i.PreRequestScript,
+			&i.PostResponseScript,
+			&i.FolderID,
+			&i.SortOrder,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const nextEndpointSortOrder = `-- name: NextEndpointSortOrder :one
+SELECT CAST(COALESCE(MAX(sort_order), 0) + 1 AS INTEGER) FROM endpoints
+WHERE collection_id = ? AND deleted_at IS NULL
+`
+
+func (q *Queries) NextEndpointSortOrder(ctx context.Context, collectionID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, nextEndpointSortOrder, collectionID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const purgeEndpoints = `-- name: PurgeEndpoints :execrows
+DELETE FROM endpoints
+WHERE (endpoints.deleted_at IS NOT NULL AND endpoints.deleted_at < ?1)
+   OR collection_id IN (
+       SELECT id FROM collections
+       WHERE collections.deleted_at IS NOT NULL AND collections.deleted_at < ?1
+   )
+`
+
+func (q *Queries) PurgeEndpoints(ctx context.Context, before sql.NullString) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeEndpoints, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreEndpoint = `-- name: RestoreEndpoint :one
+UPDATE endpoints
+SET deleted_at = NULL,
+    folder_id = ?
+WHERE id = ? AND deleted_at IS NOT NULL
+RETURNING id, collection_id, name, method, url, headers, query_params, request_body, created_at, updated_at, protocol, proto_files, extractions, pre_request_script, post_response_script, folder_id, sort_order, deleted_at
+`
+
+type RestoreEndpointParams struct {
+	FolderID sql.NullInt64 `db:"folder_id" json:"folder_id"`
+	ID       int64         `db:"id" json:"id"`
+}
+
+func (q *Queries) RestoreEndpoint(ctx context.Context, arg RestoreEndpointParams) (Endpoint, error) {
+	row := q.db.QueryRowContext(ctx, restoreEndpoint, arg.FolderID, arg.ID)
+	var i Endpoint
+	err := row.Scan(
+		&i.ID,
+		&i.CollectionID,
+		&i.Name,
+		&i.Method,
+		&i.Url,
+		&i.Headers,
+		&i.QueryParams,
+		&i.RequestBody,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Protocol,
+		&i.ProtoFiles,
+		&i.Extractions,
+		&i.PreRequestScript,
+		&i.PostResponseScript,
+		&i.FolderID,
+		&i.SortOrder,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const restoreEndpointsByFolder = `-- name: RestoreEndpointsByFolder :exec
+UPDATE endpoints
+SET deleted_at = NULL
+WHERE folder_id = ? AND deleted_at = ?
+`
+
+type RestoreEndpointsByFolderParams struct {
+	FolderID  sql.NullInt64  `db:"folder_id" json:"folder_id"`
+	DeletedAt sql.NullString `db:"deleted_at" json:"deleted_at"`
+}
+
+func (q *Queries) RestoreEndpointsByFolder(ctx context.Context, arg RestoreEndpointsByFolderParams) error {
+	_, err := q.db.ExecContext(ctx, restoreEndpointsByFolder, arg.FolderID, arg.DeletedAt)
+	return err
+}
+
+const trashEndpoint = `-- name: TrashEndpoint :exec
+UPDATE endpoints
+SET deleted_at = ?
+WHERE id = ? AND deleted_at IS NULL
+`
+
+type TrashEndpointParams struct {
+	DeletedAt sql.NullString `db:"deleted_at" json:"deleted_at"`
+	ID        int64          `db:"id" json:"id"`
+}
+
+func (q *Queries) TrashEndpoint(ctx context.Context, arg TrashEndpointParams) error {
+	_, err := q.db.ExecContext(ctx, trashEndpoint, arg.DeletedAt, arg.ID)
+	return err
+}
+
+const trashEndpointsByFolder = `-- name: TrashEndpointsByFolder :exec
+UPDATE endpoints
+SET deleted_at = ?
+WHERE folder_id = ? AND deleted_at IS NULL
+`
+
+type TrashEndpointsByFolderParams struct {
+	DeletedAt sql.NullString `db:"deleted_at" json:"deleted_at"`
+	FolderID  sql.NullInt64  `db:"folder_id" json:"folder_id"`
+}
+
+func (q *Queries) TrashEndpointsByFolder(ctx context.Context, arg TrashEndpointsByFolderParams) error {
+	_, err := q.db.ExecContext(ctx, trashEndpointsByFolder, arg.DeletedAt, arg.FolderID)
+	return err
+}
+
 const updateEndpoint = `-- name: UpdateEndpoint :one
 UPDATE endpoints
 SET
@@ -234,20 +494,30 @@ SET
     url = ?,
     headers = ?,
     query_params = ?,
-    request_body = ?
+    request_body = ?,
+    protocol = ?,
+    proto_files = ?,
+    extractions = ?,
+    pre_request_script = ?,
+    post_response_script = ?
 WHERE
     id = ?
-RETURNING id, collection_id, name, method, url, headers, query_params, request_body, created_at, updated_at
+RETURNING id, collection_id, name, method, url, headers, query_params, request_body, created_at, updated_at, protocol, proto_files, extractions, pre_request_script, post_response_script, folder_id, sort_order, deleted_at
 `
 
 type UpdateEndpointParams struct {
-	Name        string `db:"name" json:"name"`
-	Method      string `db:"method" json:"method"`
-	Url         string `db:"url" json:"url"`
-	Headers     string `db:"headers" json:"headers"`
-	QueryParams string `db:"query_params" json:"query_params"`
-	RequestBody string `db:"request_body" json:"request_body"`
-	ID          int64  `db:"id" json:"id"`
+	Name               string `db:"name" json:"name"`
+	Method             string `db:"method" json:"method"`
+	Url                string `db:"url" json:"url"`
+	Headers            string `db:"headers" json:"headers"`
+	QueryParams        string `db:"query_params" json:"query_params"`
+	RequestBody        string `db:"request_body" json:"request_body"`
+	Protocol           string `db:"protocol" json:"protocol"`
+	ProtoFiles         string `db:"proto_files" json:"proto_files"`
+	Extractions        string `db:"extractions" json:"extractions"`
+	PreRequestScript   string `db:"pre_request_script" json:"pre_request_script"`
+	PostResponseScript string `db:"post_response_script" json:"post_response_script"`
+	ID                 int64  `db:"id" json:"id"`
 }
 
 func (q *Queries) UpdateEndpoint(ctx context.Context, arg UpdateEndpointParams) (Endpoint, error) {
@@ -258,6 +528,11 @@ func (q *Queries) UpdateEndpoint(ctx context.Context, arg UpdateEndpointParams)
 		arg.Headers,
 		arg.QueryParams,
 		arg.RequestBody,
+		arg.Protocol,
+		arg.ProtoFiles,
+		arg.Extractions,
+		arg.PreRequestScript,
+		arg.PostResponseScript,
 		arg.ID,
 	)
 	var i Endpoint
@@ -272,6 +547,93 @@ func (q *Queries) UpdateEndpoint(ctx context.Context, arg UpdateEndpointParams)
 		&i.RequestBody,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Protocol,
+		&i.ProtoFiles,
+		&i.Extractions,
+		&i.PreRequestScript,
+		&i.PostResponseScript,
+		&i.FolderID,
+		&i.SortOrder,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const updateEndpointCollection = `-- name: UpdateEndpointCollection :one
+UPDATE endpoints
+SET collection_id = ?,
+    folder_id = NULL,
+    sort_order = ?
+WHERE id = ? AND deleted_at IS NULL
+RETURNING id, collection_id, name, method, url, headers, query_params, request_body, created_at, updated_at, protocol, proto_files, extractions, pre_request_script, post_response_script, folder_id, sort_order, deleted_at
+`
+
+type UpdateEndpointCollectionParams struct {
+	CollectionID int64 `db:"collection_id" json:"collection_id"`
+	SortOrder    int64 `db:"sort_order" json:"sort_order"`
+	ID           int64 `db:"id" json:"id"`
+}
+
+func (q *Queries) UpdateEndpointCollection(ctx context.Context, arg UpdateEndpointCollectionParams) (Endpoint, error) {
+	row := q.db.QueryRowContext(ctx, updateEndpointCollection, arg.CollectionID, arg.SortOrder, arg.ID)
+	var i Endpoint
+	err := row.Scan(
+		&i.ID,
+		&i.CollectionID,
+		&i.Name,
+		&i.Method,
+		&i.Url,
+		&i.Headers,
+		&i.QueryParams,
+		&i.RequestBody,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Protocol,
+		&i.ProtoFiles,
+		&i.Extractions,
+		&i.PreRequestScript,
+		&i.PostResponseScript,
+		&i.FolderID,
+		&i.SortOrder,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const updateEndpointFolder = `-- name: UpdateEndpointFolder :one
+UPDATE endpoints
+SET folder_id = ?
+WHERE id = ? AND deleted_at IS NULL
+RETURNING id, collection_id, name, method, url, headers, query_params, request_body, created_at, updated_at, protocol, proto_files, extractions, pre_request_script, post_response_script, folder_id, sort_order, deleted_at
+`
+
+type UpdateEndpointFolderParams struct {
+	FolderID sql.NullInt64 `db:"folder_id" json:"folder_id"`
+	ID       int64         `db:"id" json:"id"`
+}
+
+func (q *Queries) UpdateEndpointFolder(ctx context.Context, arg UpdateEndpointFolderParams) (Endpoint, error) {
+	row := q.db.QueryRowContext(ctx, updateEndpointFolder, arg.FolderID, arg.ID)
+	var i Endpoint
+	err := row.Scan(
+		&i.ID,
+		&i.CollectionID,
+		&i.Name,
+		&i.Method,
+		&i.Url,
+		&i.Headers,
+		&i.QueryParams,
+		&i.RequestBody,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Protocol,
+		&i.ProtoFiles,
+		&i.Extractions,
+		&i.PreRequestScript,
+		&i.PostResponseScript,
+		&i.FolderID,
+		&i.SortOrder,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -282,7 +644,7 @@ SET
     name = ?
 WHERE
     id = ?
-RETURNING id, collection_id, name, method, url, headers, query_params, request_body, created_at, updated_at
+RETURNING id, collection_id, name, method, url, headers, query_params, request_body, created_at, updated_at, protocol, proto_files, extractions, pre_request_script, post_response_script, folder_id, sort_order, deleted_at
 `
 
 type UpdateEndpointNameParams struct {
@@ -304,6 +666,30 @@ func (q *Queries) UpdateEndpointName(ctx context.Context, arg UpdateEndpointName
 		&i.RequestBody,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Protocol,
+		&i.ProtoFiles,
+		&i.Extractions,
+		&i.PreRequestScript,
+		&i.PostResponseScript,
+		&i.FolderID,
+		&i.SortOrder,
+		&i.DeletedAt,
 	)
 	return i, err
 }
+
+const updateEndpointSortOrder = `-- name: UpdateEndpointSortOrder :exec
+UPDATE endpoints
+SET sort_order = ?
+WHERE id = ? AND deleted_at IS NULL
+`
+
+type UpdateEndpointSortOrderParams struct {
+	SortOrder int64 `db:"sort_order" json:"sort_order"`
+	ID        int64 `db:"id" json:"id"`
+}
+
+func (q *Queries) UpdateEndpointSortOrder(ctx context.Context, arg UpdateEndpointSortOrderParams) error {
+	_, err := q.db.ExecContext(ctx, updateEndpointSortOrder, arg.SortOrder, arg.ID)
+	return err
+}
@@ -87,6 +87,28 @@ func (q *Queries) CreateHistoryEntry(ctx context.Context, arg CreateHistoryEntry
 	return i, err
 }
 
+const deleteExcessHistoryPerCollection = `-- name: DeleteExcessHistoryPerCollection :execrows
+DELETE FROM history
+WHERE id IN (
+    SELECT id FROM (
+        SELECT id, ROW_NUMBER() OVER (
+            PARTITION BY collection_id
+            ORDER BY datetime(executed_at) DESC, id DESC
+        ) AS position
+        FROM history
+    )
+    WHERE position > CAST(?1 AS INTEGER)
+)
+`
+
+func (q *Queries) DeleteExcessHistoryPerCollection(ctx context.Context, maxEntries int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteExcessHistoryPerCollection, maxEntries)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const deleteHistoryEntry = `-- name: DeleteHistoryEntry :exec
 DELETE FROM history
 WHERE id = ?
@@ -97,14 +119,44 @@ func (q *Queries) DeleteHistoryEntry(ctx context.Context, id int64) error {
 	return err
 }
 
-const deleteOldHistory = `-- name: DeleteOldHistory :exec
+const deleteHistoryOverSize = `-- name: DeleteHistoryOverSize :execrows
 DELETE FROM history
-WHERE executed_at < datetime('now', '-30 days')
+WHERE id IN (
+    SELECT id FROM (
+        SELECT id, SUM(
+            LENGTH(CAST(url AS BLOB))
+            + LENGTH(CAST(COALESCE(request_headers, '') AS BLOB))
+            + LENGTH(CAST(COALESCE(query_params, '') AS BLOB))
+            + LENGTH(CAST(COALESCE(request_body, '') AS BLOB))
+            + LENGTH(CAST(COALESCE(response_headers, '') AS BLOB))
+            + LENGTH(CAST(COALESCE(response_body, '') AS BLOB))
+        ) OVER (ORDER BY datetime(executed_at) DESC, id DESC) AS running_size
+        FROM history
+    )
+    WHERE running_size > CAST(?1 AS INTEGER)
+)
 `
 
-func (q *Queries) DeleteOldHistory(ctx context.Context) error {
-	_, err := q.db.ExecContext(ctx, deleteOldHistory)
-	return err
+// keeps the newest entries whose combined size fits in max_bytes
+func (q *Queries) DeleteHistoryOverSize(ctx context.Context, maxBytes int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteHistoryOverSize, maxBytes)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteOldHistory = `-- name: DeleteOldHistory :execrows
+DELETE FROM history
+WHERE datetime(executed_at) < datetime(CAST(?1 AS TEXT))
+`
+
+func (q *Queries) DeleteOldHistory(ctx context.Context, before string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteOldHistory, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
 const getHistoryByCollection = `-- name: GetHistoryByCollection :many
@@ -186,3 +238,79 @@ func (q *Queries) GetHistoryById(ctx context.Context, id int64) (History, error)
 	)
 	return i, err
 }
+
+const getHistoryStats = `-- name: GetHistoryStats :one
+SELECT
+    COUNT(*) AS entries,
+    CAST(COALESCE(SUM(
+        LENGTH(CAST(url AS BLOB))
+        + LENGTH(CAST(COALESCE(request_headers, '') AS BLOB))
+        + LENGTH(CAST(COALESCE(query_params, '') AS BLOB))
+        + LENGTH(CAST(COALESCE(request_body, '') AS BLOB))
+        + LENGTH(CAST(COALESCE(response_headers, '') AS BLOB))
+        + LENGTH(CAST(COALESCE(response_body, '') AS BLOB))
+    ), 0) AS INTEGER) AS size
+FROM history
+`
+
+type GetHistoryStatsRow struct {
+	Entries int64 `db:"entries" json:"entries"`
+	Size    int64 `db:"size" json:"size"`
+}
+
+func (q *Queries) GetHistoryStats(ctx context.Context) (GetHistoryStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, getHistoryStats)
+	var i GetHistoryStatsRow
+	err := row.Scan(&i.Entries, &i.Size)
+	return i, err
+}
+
+const getLatestSuccessfulHistory = `-- name: GetLatestSuccessfulHistory :one
+SELECT id, collection_id, collection_name, endpoint_name, method, url, status_code, duration, response_size, request_headers, query_params, request_body, response_body, response_headers, executed_at FROM history
+WHERE collection_id = ? AND endpoint_name = ? AND status_code < 400
+ORDER BY executed_at DESC, id DESC
+LIMIT 1
+`
+
+type GetLatestSuccessfulHistoryParams struct {
+	CollectionID sql.NullInt64  `db:"collection_id" json:"collection_id"`
+	EndpointName sql.NullString `db:"endpoint_name" json:"endpoint_name"`
+}
+
+func (q *Queries) GetLatestSuccessfulHistory(ctx context.Context, arg GetLatestSuccessfulHistoryParams) (History, error) {
+	row := q.db.QueryRowContext(ctx, getLatestSuccessfulHistory, arg.CollectionID, arg.EndpointName)
+	var i History
+	err := row.Scan(
+		&i.ID,
+		&i.CollectionID,
+		&i.CollectionName,
+		&i.EndpointName,
+		&i.Method,
+		&i.Url,
+		&i.StatusCode,
+		&i.Duration,
+		&i.ResponseSize,
+		&i.RequestHeaders,
+		&i.QueryParams,
+		&i.RequestBody,
+		&i.ResponseBody,
+		&i.ResponseHeaders,
+		&i.ExecutedAt,
+	)
+	return i, err
+}
+
+const stripSuccessfulHistoryBodies = `-- name: StripSuccessfulHistoryBodies :execrows
+UPDATE history
+SET request_body = NULL, response_body = NULL
+WHERE status_code < 400
+  AND (request_body IS NOT NULL OR response_body IS NOT NULL)
+`
+
+func (q *Queries) StripSuccessfulHistoryBodies(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, stripSuccessfulHistoryBodies)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,123 @@
+package database
+
+// The history search queries are written by hand: sqlc cannot parse the FTS4
+// history_fts table they match against. They follow the code sqlc generates.
+
+import (
+	"context"
+	"database/sql"
+)
+
+const countSearchHistory = `SELECT COUNT(*) FROM history
+WHERE (CAST(?1 AS TEXT) = '' OR id IN (SELECT rowid FROM history_fts WHERE history_fts MATCH ?1))
+  AND (CAST(?2 AS INTEGER) = 0 OR collection_id = ?2)
+  AND (CAST(?3 AS TEXT) = '' OR method = ?3)
+  AND status_code >= CAST(?4 AS INTEGER)
+  AND status_code <= CAST(?5 AS INTEGER)
+  AND (CAST(?6 AS TEXT) = '' OR datetime(executed_at) >= datetime(?6))
+  AND (CAST(?7 AS TEXT) = '' OR datetime(executed_at) < datetime(?7))
+`
+
+type CountSearchHistoryParams struct {
+	Query        string `db:"query" json:"query"`
+	CollectionID int64  `db:"collection_id" json:"collection_id"`
+	Method       string `db:"method" json:"method"`
+	MinStatus    int64  `db:"min_status" json:"min_status"`
+	MaxStatus    int64  `db:"max_status" json:"max_status"`
+	Since        string `db:"since" json:"since"`
+	Until        string `db:"until" json:"until"`
+}
+
+func (q *Queries) CountSearchHistory(ctx context.Context, arg CountSearchHistoryParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSearchHistory,
+		arg.Query,
+		arg.CollectionID,
+		arg.Method,
+		arg.MinStatus,
+		arg.MaxStatus,
+		arg.Since,
+		arg.Until,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const searchHistory = `SELECT id, collection_id, collection_name, endpoint_name, method, url, status_code, duration, executed_at FROM history
+WHERE (CAST(?1 AS TEXT) = '' OR id IN (SELECT rowid FROM history_fts WHERE history_fts MATCH ?1))
+  AND (CAST(?2 AS INTEGER) = 0 OR collection_id = ?2)
+  AND (CAST(?3 AS TEXT) = '' OR method = ?3)
+  AND status_code >= CAST(?4 AS INTEGER)
+  AND status_code <= CAST(?5 AS INTEGER)
+  AND (CAST(?6 AS TEXT) = '' OR datetime(executed_at) >= datetime(?6))
+  AND (CAST(?7 AS TEXT) = '' OR datetime(executed_at) < datetime(?7))
+ORDER BY datetime(executed_at) DESC, id DESC
+LIMIT ?9 OFFSET ?8
+`
+
+type SearchHistoryParams struct {
+	Query        string `db:"query" json:"query"`
+	CollectionID int64  `db:"collection_id" json:"collection_id"`
+	Method       string `db:"method" json:"method"`
+	MinStatus    int64  `db:"min_status" json:"min_status"`
+	MaxStatus    int64  `db:"max_status" json:"max_status"`
+	Since        string `db:"since" json:"since"`
+	Until        string `db:"until" json:"until"`
+	Offset       int64  `db:"offset" json:"offset"`
+	Limit        int64  `db:"limit" json:"limit"`
+}
+
+type SearchHistoryRow struct {
+	ID             int64          `db:"id" json:"id"`
+	CollectionID   sql.NullInt64  `db:"collection_id" json:"collection_id"`
+	CollectionName sql.NullString `db:"collection_name" json:"collection_name"`
+	EndpointName   sql.NullString `db:"endpoint_name" json:"endpoint_name"`
+	Method         string         `db:"method" json:"method"`
+	Url            string         `db:"url" json:"url"`
+	StatusCode     int64          `db:"status_code" json:"status_code"`
+	Duration       int64          `db:"duration" json:"duration"`
+	ExecutedAt     string         `db:"executed_at" json:"executed_at"`
+}
+
+func (q *Queries) SearchHistory(ctx context.Context, arg SearchHistoryParams) ([]SearchHistoryRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchHistory,
+		arg.Query,
+		arg.CollectionID,
+		arg.Method,
+		arg.MinStatus,
+		arg.MaxStatus,
+		arg.Since,
+		arg.Until,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchHistoryRow
+	for rows.Next() {
+		var i SearchHistoryRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CollectionID,
+			&i.CollectionName,
+			&i.EndpointName,
+			&i.Method,
+			&i.Url,
+			&i.StatusCode,
+			&i.Duration,
+			&i.ExecutedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -9,23 +9,74 @@ import (
 )
 
 type Collection struct {
+	ID                 int64          `db:"id" json:"id"`
+	Name               string         `db:"name" json:"name"`
+	CreatedAt          string         `db:"created_at" json:"created_at"`
+	UpdatedAt          string         `db:"updated_at" json:"updated_at"`
+	Variables          string         `db:"variables" json:"variables"`
+	PreRequestScript   string         `db:"pre_request_script" json:"pre_request_script"`
+	PostResponseScript string         `db:"post_response_script" json:"post_response_script"`
+	DeletedAt          sql.NullString `db:"deleted_at" json:"deleted_at"`
+	SortOrder          int64          `db:"sort_order" json:"sort_order"`
+}
+
+type Endpoint struct {
+	ID                 int64          `db:"id" json:"id"`
+	CollectionID       int64          `db:"collection_id" json:"collection_id"`
+	Name               string         `db:"name" json:"name"`
+	Method             string         `db:"method" json:"method"`
+	Url                string         `db:"url" json:"url"`
+	Headers            string         `db:"headers" json:"headers"`
+	QueryParams        string         `db:"query_params" json:"query_params"`
+	RequestBody        string         `db:"request_body" json:"request_body"`
+	CreatedAt          string         `db:"created_at" json:"created_at"`
+	UpdatedAt          string         `db:"updated_at" json:"updated_at"`
+	Protocol           string         `db:"protocol" json:"protocol"`
+	ProtoFiles         string         `db:"proto_files" json:"proto_files"`
+	Extractions        string         `db:"extractions" json:"extractions"`
+	PreRequestScript   string         `db:"pre_request_script" json:"pre_request_script"`
+	PostResponseScript string         `db:"post_response_script" json:"post_response_script"`
+	FolderID           sql.NullInt64  `db:"folder_id" json:"folder_id"`
+	SortOrder          int64          `db:"sort_order" json:"sort_order"`
+	DeletedAt          sql.NullString `db:"deleted_at" json:"deleted_at"`
+}
+
+type EndpointRevision struct {
+	ID                 int64  `db:"id" json:"id"`
+	EndpointID         int64  `db:"endpoint_id" json:"endpoint_id"`
+	Name               string `db:"name" json:"name"`
+	Method             string `db:"method" json:"method"`
+	Url                string `db:"url" json:"url"`
+	Headers            string `db:"headers" json:"headers"`
+	QueryParams        string `db:"query_params" json:"query_params"`
+	RequestBody        string `db:"request_body" json:"request_body"`
+	Protocol           string `db:"protocol" json:"protocol"`
+	ProtoFiles         string `db:"proto_files" json:"proto_files"`
+	Extractions        string `db:"extractions" json:"extractions"`
+	PreRequestScript   string `db:"pre_request_script" json:"pre_request_script"`
+	PostResponseScript string `db:"post_response_script" json:"post_response_script"`
+	CreatedAt          string `db:"created_at" json:"created_at"`
+}
+
+type Environment struct {
 	ID        int64  `db:"id" json:"id"`
 	Name      string `db:"name" json:"name"`
+	Variables string `db:"variables" json:"variables"`
+	Active    int64  `db:"active" json:"active"`
 	CreatedAt string `db:"created_at" json:"created_at"`
 	UpdatedAt string `db:"updated_at" json:"updated_at"`
 }
 
-type Endpoint struct {
-	ID           int64  `db:"id" json:"id"`
-	CollectionID int64  `db:"collection_id" json:"collection_id"`
-	Name         string `db:"name" json:"name"`
-	Method       string `db:"method" json:"method"`
-	Url          string `db:"url" json:"url"`
-	Headers      string `db:"headers" json:"headers"`
-	QueryParams  string `db:"query_params" json:"query_params"`
-	RequestBody  string `db:"request_body" json:"request_body"`
-	CreatedAt    string `db:"created_at" json:"created_at"`
-	UpdatedAt    string `db:"updated_at" json:"updated_at"`
+type Folder struct {
+	ID           int64          `db:"id" json:"id"`
+	CollectionID int64          `db:"collection_id" json:"collection_id"`
+	ParentID     sql.NullInt64  `db:"parent_id" json:"parent_id"`
+	Name         string         `db:"name" json:"name"`
+	Headers      string         `db:"headers" json:"headers"`
+	Variables    string         `db:"variables" json:"variables"`
+	CreatedAt    string         `db:"created_at" json:"created_at"`
+	UpdatedAt    string         `db:"updated_at" json:"updated_at"`
+	DeletedAt    sql.NullString `db:"deleted_at" json:"deleted_at"`
 }
 
 type History struct {
@@ -45,3 +96,15 @@ type History struct {
 	ResponseHeaders sql.NullString `db:"response_headers" json:"response_headers"`
 	ExecutedAt      string         `db:"executed_at" json:"executed_at"`
 }
+
+type Snapshot struct {
+	ID              int64  `db:"id" json:"id"`
+	EndpointID      int64  `db:"endpoint_id" json:"endpoint_id"`
+	StatusCode      int64  `db:"status_code" json:"status_code"`
+	ResponseHeaders string `db:"response_headers" json:"response_headers"`
+	ResponseBody    string `db:"response_body" json:"response_body"`
+	IgnoredPaths    string `db:"ignored_paths" json:"ignored_paths"`
+	IgnoredHeaders  string `db:"ignored_headers" json:"ignored_headers"`
+	CreatedAt       string `db:"created_at" json:"created_at"`
+	UpdatedAt       string `db:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,31 @@
+package database
+
+// InTx is written by hand: sqlc generates WithTx, but nothing that starts the
+// transaction it takes.
+
+import (
+	"context"
+	"database/sql"
+)
+
+// InTx runs fn with queries that share one transaction, committing it when
+// fn succeeds and rolling it back otherwise. Queries that already run in a
+// transaction run fn in it.
+func (q *Queries) InTx(ctx context.Context, fn func(*Queries) error) error {
+	db, ok := q.db.(interface {
+		BeginTx(context.Context, *sql.TxOptions) (*sql.Tx, error)
+	})
+	if !ok {
+		return fn(q)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(q.WithTx(tx)); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,52 @@
+package database_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/maniac-en/req/internal/backend/database"
+	"github.com/maniac-en/req/internal/backend/testutils"
+)
+
+func TestInTx(t *testing.T) {
+	db := testutils.SetupTestDB(t, "collections")
+	ctx := context.Background()
+	failed := errors.New("failed")
+
+	err := db.InTx(ctx, func(tx *database.Queries) error {
+		if _, err := tx.CreateCollection(ctx, "Rolled Back"); err != nil {
+			return err
+		}
+		return failed
+	})
+	if err != failed {
+		t.Errorf("Expected the error of fn, got %v", err)
+	}
+
+	err = db.InTx(ctx, func(tx *database.Queries) error {
+		if _, err := tx.CreateCollection(ctx, "Committed"); err != nil {
+			return err
+		}
+		// nested calls join the outer transaction
+		return tx.InTx(ctx, func(nested *database.Queries) error {
+			_, err := nested.CreateCollection(ctx, "Nested")
+			return err
+		})
+	})
+	if err != nil {
+		t.Fatalf("InTx failed: %v", err)
+	}
+
+	collections, err := db.GetCollections(ctx)
+	if err != nil {
+		t.Fatalf("GetCollections failed: %v", err)
+	}
+	var names []string
+	for _, collection := range collections {
+		names = append(names, collection.Name)
+	}
+	if len(names) != 2 || names[0] != "Nested" || names[1] != "Committed" {
+		t.Errorf("Expected only the committed collections, got %v", names)
+	}
+}
@@ -48,6 +48,10 @@ func (d *DemoGenerator) PopulateDummyData(ctx context.Context) (bool, error) {
 		return false, err
 	}
 
+	if err := d.createGRPCBinCollection(ctx); err != nil {
+		return false, err
+	}
+
 	log.Info("dummy data populated successfully")
 	return true, nil
 }
@@ -117,42 +121,61 @@ func (d *DemoGenerator) createReqresCollection(ctx context.Context) error {
 		return err
 	}
 
+	// the login token is extracted into a collection variable and sent by
+	// the requests after it, so the collection runs end to end
+	if _, err := d.collectionsManager.SetVariables(ctx, collection.ID, map[string]string{"baseUrl": "https://reqres.in/api"}); err != nil {
+		log.Error("failed to set ReqRes collection variables", "error", err)
+		return err
+	}
+
 	endpoints := []endpoints.EndpointData{
 		{
 			CollectionID: collection.ID,
-			Name:         "List Users",
-			Method:       "GET",
-			URL:          "https://reqres.in/api/users",
+			Name:         "Login",
+			Method:       "POST",
+			URL:          "{{baseUrl}}/login",
 			Headers:      `{"Content-Type": "application/json"}`,
-			QueryParams:  map[string]string{"page": "2"},
-			RequestBody:  "",
+			QueryParams:  map[string]string{},
+			RequestBody:  `{"email": "eve.holt@reqres.in", "password": "cityslicka"}`,
+			Extractions: []endpoints.Extraction{
+				{Variable: "token", Source: endpoints.SourceJSON, Expression: "$.token"},
+			},
 		},
 		{
 			CollectionID: collection.ID,
-			Name:         "Single User",
+			Name:         "List Users",
 			Method:       "GET",
-			URL:          "https://reqres.in/api/users/2",
-			Headers:      `{"Content-Type": "application/json"}`,
-			QueryParams:  map[string]string{},
+			URL:          "{{baseUrl}}/users",
+			Headers:      `{"Content-Type": "application/json", "Authorization": "Bearer {{token}}"}`,
+			QueryParams:  map[string]string{"page": "2"},
 			RequestBody:  "",
 		},
 		{
 			CollectionID: collection.ID,
 			Name:         "Create User",
 			Method:       "POST",
-			URL:          "https://reqres.in/api/users",
-			Headers:      `{"Content-Type": "application/json"}`,
+			URL:          "{{baseUrl}}/users",
+			Headers:      `{"Content-Type": "application/json", "Authorization": "Bearer {{token}}"}`,
 			QueryParams:  map[string]string{},
 			RequestBody:  `{"name": "morpheus", "job": "leader"}`,
+			Extractions: []endpoints.Extraction{
+				{Variable: "userId", Source: endpoints.SourceJSON, Expression: "$.id"},
+			},
+			PreRequestScript: `req.request.headers["X-Request-Id"] = req.uuid();`,
+			PostResponseScript: `req.test("user is created", function () {
+  req.expect(req.response.status === 201, "expected 201, got " + req.response.status);
+  req.expect(req.response.json().name === "morpheus", "unexpected name");
+});
+console.log("created user", req.response.json().id);`,
 		},
 		{
 			CollectionID: collection.ID,
-			Name:         "Login",
-			Method:       "POST",
-			URL:          "https://reqres.in/api/login",
-			Headers:      `{"Content-Type": "application/json"}`,
+			Name:         "Single User",
+			Method:       "GET",
+			URL:          "{{baseUrl}}/users/2",
+			Headers:      `{"Content-Type": "application/json", "Authorization": "Bearer {{token}}"}`,
 			QueryParams:  map[string]string{},
-			RequestBody:  `{"email": "eve.holt@reqres.in", "password": "cityslicka"}`,
+			RequestBody:  "",
 		},
 	}
 
@@ -208,6 +231,46 @@ func (d *DemoGenerator) createHTTPBinCollection(ctx context.Context) error {
 	return d.createEndpoints(ctx, endpoints)
 }
 
+func (d *DemoGenerator) createGRPCBinCollection(ctx context.Context) error {
+	collection, err := d.collectionsManager.Create(ctx, "gRPCBin")
+	if err != nil {
+		log.Error("failed to create gRPCBin collection", "error", err)
+		return err
+	}
+
+	endpoints := []endpoints.EndpointData{
+		{
+			CollectionID: collection.ID,
+			Name:         "Say Hello",
+			Protocol:     endpoints.ProtocolGRPC,
+			Method:       "hello.HelloService/SayHello",
+			URL:          "grpc://grpcb.in:9000",
+			Headers:      `{"x-client": "req"}`,
+			RequestBody:  `{"greeting": "req"}`,
+		},
+		{
+			CollectionID: collection.ID,
+			Name:         "Lots Of Replies",
+			Protocol:     endpoints.ProtocolGRPC,
+			Method:       "hello.HelloService/LotsOfReplies",
+			URL:          "grpc://grpcb.in:9000",
+			Headers:      "{}",
+			RequestBody:  `{"greeting": "req"}`,
+		},
+		{
+			CollectionID: collection.ID,
+			Name:         "Anything Over HTTP",
+			Method:       "GET",
+			URL:          "https://httpbin.org/anything",
+			Headers:      `{"Content-Type": "application/json"}`,
+			QueryParams:  map[string]string{},
+			RequestBody:  "",
+		},
+	}
+
+	return d.createEndpoints(ctx, endpoints)
+}
+
 func (d *DemoGenerator) createEndpoints(ctx context.Context, endpointData []endpoints.EndpointData) error {
 	for _, data := range endpointData {
 		_, err := d.endpointsManager.CreateEndpoint(ctx, data)
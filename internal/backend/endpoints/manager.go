@@ -5,10 +5,15 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/maniac-en/req/internal/backend/crud"
 	"github.com/maniac-en/req/internal/backend/database"
+	"github.com/maniac-en/req/internal/backend/variables"
 	"github.com/maniac-en/req/internal/log"
+	"github.com/maniac-en/req/internal/query"
 )
 
 func NewEndpointsManager(db *database.Queries) *EndpointsManager {
@@ -43,23 +48,65 @@ func (e *EndpointsManager) Update(ctx context.Context, id int64, name string) (E
 	return EndpointEntity{}, fmt.Errorf("use UpdateEndpoint to update an endpoint with full data")
 }
 
+// Delete moves an endpoint to the trash
 func (e *EndpointsManager) Delete(ctx context.Context, id int64) error {
 	if err := crud.ValidateID(id); err != nil {
 		log.Warn("endpoint delete failed validation", "id", id)
 		return crud.ErrInvalidInput
 	}
 
-	log.Debug("deleting endpoint", "id", id)
-	err := e.DB.DeleteEndpoint(ctx, id)
+	log.Debug("trashing endpoint", "id", id)
+	err := e.DB.TrashEndpoint(ctx, database.TrashEndpointParams{
+		DeletedAt: sql.NullString{String: crud.FormatTimestamp(time.Now()), Valid: true},
+		ID:        id,
+	})
 	if err != nil {
-		log.Error("failed to delete endpoint", "id", id, "error", err)
+		log.Error("failed to trash endpoint", "id", id, "error", err)
 		return err
 	}
 
-	log.Info("deleted endpoint", "id", id)
+	log.Info("trashed endpoint", "id", id)
 	return nil
 }
 
+// Restore takes an endpoint out of the trash. If its folder is gone, it is
+// restored at the top of its collection.
+func (e *EndpointsManager) Restore(ctx context.Context, id int64) (EndpointEntity, error) {
+	if err := crud.ValidateID(id); err != nil {
+		log.Warn("endpoint restore failed validation", "id", id)
+		return EndpointEntity{}, crud.ErrInvalidInput
+	}
+
+	endpoint, err := e.DB.GetTrashedEndpoint(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Debug("endpoint not found in trash", "id", id)
+			return EndpointEntity{}, crud.ErrNotFound
+		}
+		log.Error("failed to read trashed endpoint", "id", id, "error", err)
+		return EndpointEntity{}, err
+	}
+	folderID := endpoint.FolderID
+	if folderID.Valid {
+		if _, err := e.DB.GetFolder(ctx, folderID.Int64); err != nil {
+			folderID = sql.NullInt64{}
+		}
+	}
+
+	log.Debug("restoring endpoint", "id", id)
+	restored, err := e.DB.RestoreEndpoint(ctx, database.RestoreEndpointParams{
+		FolderID: folderID,
+		ID:       id,
+	})
+	if err != nil {
+		log.Error("failed to restore endpoint", "id", id, "error", err)
+		return EndpointEntity{}, err
+	}
+
+	log.Info("restored endpoint", "id", id)
+	return EndpointEntity{Endpoint: restored}, nil
+}
+
 func (e *EndpointsManager) List(ctx context.Context) ([]EndpointEntity, error) {
 	return nil, fmt.Errorf("use ListByCollection to list endpoints for a specific collection")
 }
@@ -139,6 +186,11 @@ func (e *EndpointsManager) CreateEndpoint(ctx context.Context, data EndpointData
 		log.Warn("endpoint creation failed - method required", "method", data.Method)
 		return EndpointEntity{}, crud.ErrInvalidInput
 	}
+	protocol, err := validateProtocol(data.Protocol)
+	if err != nil {
+		log.Warn("endpoint creation failed protocol validation", "protocol", data.Protocol)
+		return EndpointEntity{}, crud.ErrInvalidInput
+	}
 
 	headersJSON := data.Headers
 	if headersJSON == "" {
@@ -155,15 +207,45 @@ func (e *EndpointsManager) CreateEndpoint(ctx context.Context, data EndpointData
 		queryParamsJSON = string(qpBytes)
 	}
 
-	log.Debug("creating endpoint", "collection_id", data.CollectionID, "name", data.Name, "method", data.Method, "url", data.URL)
+	protoFilesJSON := "[]"
+	if len(data.ProtoFiles) > 0 {
+		pfBytes, err := json.Marshal(data.ProtoFiles)
+		if err != nil {
+			log.Error("failed to marshal proto files", "error", err)
+			return EndpointEntity{}, err
+		}
+		protoFilesJSON = string(pfBytes)
+	}
+
+	extractionsJSON, err := encodeExtractions(data.Extractions)
+	if err != nil {
+		log.Warn("endpoint creation failed extraction validation", "error", err)
+		return EndpointEntity{}, crud.ErrInvalidInput
+	}
+
+	// new endpoints go to the end of the collection
+	sortOrder, err := e.DB.NextEndpointSortOrder(ctx, data.CollectionID)
+	if err != nil {
+		log.Error("failed to get endpoint sort order", "collection_id", data.CollectionID, "error", err)
+		return EndpointEntity{}, err
+	}
+
+	log.Debug("creating endpoint", "collection_id", data.CollectionID, "name", data.Name, "protocol", protocol, "method", data.Method, "url", data.URL)
 	endpoint, err := e.DB.CreateEndpoint(ctx, database.CreateEndpointParams{
-		CollectionID: data.CollectionID,
-		Name:         data.Name,
-		Method:       data.Method,
-		Url:          data.URL,
-		Headers:      headersJSON,
-		QueryParams:  queryParamsJSON,
-		RequestBody:  data.RequestBody,
+		CollectionID:       data.CollectionID,
+		Name:               data.Name,
+		Method:             data.Method,
+		Url:                data.URL,
+		Headers:            headersJSON,
+		QueryParams:        queryParamsJSON,
+		RequestBody:        data.RequestBody,
+		Protocol:           protocol,
+		ProtoFiles:         protoFilesJSON,
+		Extractions:        extractionsJSON,
+		PreRequestScript:   data.PreRequestScript,
+		PostResponseScript: data.PostResponseScript,
+		FolderID:           sql.NullInt64{Int64: data.FolderID, Valid: data.FolderID != 0},
+		SortOrder:          sortOrder,
 	})
 	if err != nil {
 		log.Error("failed to create endpoint", "collection_id", data.CollectionID, "name", data.Name, "error", err)
@@ -185,6 +267,15 @@ func (e *EndpointsManager) UpdateEndpointName(ctx context.Context, id int64, nam
 		return EndpointEntity{}, crud.ErrInvalidInput
 	}
 
+	previous, err := e.DB.GetEndpoint(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Debug("endpoint not found for update", "id", id)
+			return EndpointEntity{}, crud.ErrNotFound
+		}
+		log.Error("failed to read endpoint for update", "id", id, "error", err)
+		return EndpointEntity{}, err
+	}
 	log.Debug("updating endpoint name", "id", id, "name", name)
 
 	endpoint, err := e.DB.UpdateEndpointName(ctx, database.UpdateEndpointNameParams{
@@ -200,11 +291,14 @@ func (e *EndpointsManager) UpdateEndpointName(ctx context.Context, id int64, nam
 		log.Error("failed to update endpoint", "id", id, "name", name, "error", err)
 		return EndpointEntity{}, err
 	}
+	e.recordRevision(ctx, previous, endpoint)
 
 	log.Info("updated endpoint", "id", endpoint.ID, "name", endpoint.Name)
 	return EndpointEntity{Endpoint: endpoint}, nil
 }
 
+// UpdateEndpoint replaces an endpoint's request. The version it replaces is
+// kept as a revision.
 func (e *EndpointsManager) UpdateEndpoint(ctx context.Context, id int64, data EndpointData) (EndpointEntity, error) {
 	if err := crud.ValidateID(id); err != nil {
 		log.Warn("endpoint update failed ID validation", "id", id)
@@ -218,6 +312,11 @@ func (e *EndpointsManager) UpdateEndpoint(ctx context.Context, id int64, data En
 		log.Warn("endpoint update failed - method required", "method", data.Method)
 		return EndpointEntity{}, crud.ErrInvalidInput
 	}
+	protocol, err := validateProtocol(data.Protocol)
+	if err != nil {
+		log.Warn("endpoint update failed protocol validation", "protocol", data.Protocol)
+		return EndpointEntity{}, crud.ErrInvalidInput
+	}
 
 	headersJSON := data.Headers
 	if headersJSON == "" {
@@ -234,15 +333,45 @@ func (e *EndpointsManager) UpdateEndpoint(ctx context.Context, id int64, data En
 		queryParamsJSON = string(qpBytes)
 	}
 
-	log.Debug("updating endpoint", "id", id, "name", data.Name, "method", data.Method, "url", data.URL)
+	protoFilesJSON := "[]"
+	if len(data.ProtoFiles) > 0 {
+		pfBytes, err := json.Marshal(data.ProtoFiles)
+		if err != nil {
+			log.Error("failed to marshal proto files", "error", err)
+			return EndpointEntity{}, err
+		}
+		protoFilesJSON = string(pfBytes)
+	}
+
+	extractionsJSON, err := encodeExtractions(data.Extractions)
+	if err != nil {
+		log.Warn("endpoint update failed extraction validation", "error", err)
+		return EndpointEntity{}, crud.ErrInvalidInput
+	}
+
+	previous, err := e.DB.GetEndpoint(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Debug("endpoint not found for update", "id", id)
+			return EndpointEntity{}, crud.ErrNotFound
+		}
+		log.Error("failed to read endpoint for update", "id", id, "error", err)
+		return EndpointEntity{}, err
+	}
+	log.Debug("updating endpoint", "id", id, "name", data.Name, "protocol", protocol, "method", data.Method, "url", data.URL)
 	endpoint, err := e.DB.UpdateEndpoint(ctx, database.UpdateEndpointParams{
-		Name:        data.Name,
-		Method:      data.Method,
-		Url:         data.URL,
-		Headers:     headersJSON,
-		QueryParams: queryParamsJSON,
-		RequestBody: data.RequestBody,
-		ID:          id,
+		Name:               data.Name,
+		Method:             data.Method,
+		Url:                data.URL,
+		Headers:            headersJSON,
+		QueryParams:        queryParamsJSON,
+		RequestBody:        data.RequestBody,
+		Protocol:           protocol,
+		ProtoFiles:         protoFilesJSON,
+		Extractions:        extractionsJSON,
+		PreRequestScript:   data.PreRequestScript,
+		PostResponseScript: data.PostResponseScript,
+		ID:                 id,
 	})
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -252,11 +381,207 @@ func (e *EndpointsManager) UpdateEndpoint(ctx context.Context, id int64, data En
 		log.Error("failed to update endpoint", "id", id, "name", data.Name, "error", err)
 		return EndpointEntity{}, err
 	}
+	e.recordRevision(ctx, previous, endpoint)
 
 	log.Info("updated endpoint", "id", endpoint.ID, "name", endpoint.Name)
 	return EndpointEntity{Endpoint: endpoint}, nil
 }
 
+// MoveToFolder puts an endpoint in a folder, or at the top of its collection
+// when folderID is 0. The folder must belong to the endpoint's collection.
+func (e *EndpointsManager) MoveToFolder(ctx context.Context, id, folderID int64) (EndpointEntity, error) {
+	endpoint, err := e.Read(ctx, id)
+	if err != nil {
+		return EndpointEntity{}, err
+	}
+	if folderID != 0 {
+		folder, err := e.DB.GetFolder(ctx, folderID)
+		if err == sql.ErrNoRows || (err == nil && folder.CollectionID != endpoint.CollectionID) {
+			log.Warn("endpoint move failed folder validation", "id", id, "folder_id", folderID)
+			return EndpointEntity{}, crud.ErrInvalidInput
+		}
+		if err != nil {
+			log.Error("failed to read folder", "id", folderID, "error", err)
+			return EndpointEntity{}, err
+		}
+	}
+
+	log.Debug("moving endpoint", "id", id, "folder_id", folderID)
+	moved, err := e.DB.UpdateEndpointFolder(ctx, database.UpdateEndpointFolderParams{
+		FolderID: sql.NullInt64{Int64: folderID, Valid: folderID != 0},
+		ID:       id,
+	})
+	if err != nil {
+		log.Error("failed to move endpoint", "id", id, "folder_id", folderID, "error", err)
+		return EndpointEntity{}, err
+	}
+
+	log.Info("moved endpoint", "id", id, "folder_id", folderID)
+	return EndpointEntity{Endpoint: moved}, nil
+}
+
+// Duplicate copies an endpoint, placing the copy right after the original
+func (e *EndpointsManager) Duplicate(ctx context.Context, id int64) (EndpointEntity, error) {
+	endpoint, err := e.Read(ctx, id)
+	if err != nil {
+		return EndpointEntity{}, err
+	}
+	all, err := e.ListByCollection(ctx, endpoint.CollectionID)
+	if err != nil {
+		return EndpointEntity{}, err
+	}
+	names := make([]string, len(all))
+	for i, other := range all {
+		names[i] = other.Name
+	}
+
+	log.Debug("duplicating endpoint", "id", id)
+	copied, err := e.DB.CreateEndpoint(ctx, database.CreateEndpointParams{
+		CollectionID:       endpoint.CollectionID,
+		Name:               crud.CopyName(endpoint.Name, names),
+		Method:             endpoint.Method,
+		Url:                endpoint.Url,
+		Headers:            endpoint.Headers,
+		QueryParams:        endpoint.QueryParams,
+		RequestBody:        endpoint.RequestBody,
+		Protocol:           endpoint.Protocol,
+		ProtoFiles:         endpoint.ProtoFiles,
+		Extractions:        endpoint.Extractions,
+		PreRequestScript:   endpoint.PreRequestScript,
+		PostResponseScript: endpoint.PostResponseScript,
+		FolderID:           endpoint.FolderID,
+	})
+	if err != nil {
+		log.Error("failed to duplicate endpoint", "id", id, "error", err)
+		return EndpointEntity{}, err
+	}
+
+	order := make([]EndpointEntity, 0, len(all)+1)
+	for _, other := range all {
+		order = append(order, other)
+		if other.ID == id {
+			order = append(order, EndpointEntity{Endpoint: copied})
+		}
+	}
+	if err := e.Reorder(ctx, order); err != nil {
+		return EndpointEntity{}, err
+	}
+
+	log.Info("duplicated endpoint", "id", id, "copy_id", copied.ID, "name", copied.Name)
+	return e.Read(ctx, copied.ID)
+}
+
+// MoveToCollection moves an endpoint to the end of another collection, at
+// the top of its folders. Its snapshot and revisions follow it; history
+// stays with the collection the requests were sent from.
+func (e *EndpointsManager) MoveToCollection(ctx context.Context, id, collectionID int64) (EndpointEntity, error) {
+	if err := crud.ValidateID(collectionID); err != nil {
+		log.Warn("endpoint move failed collection validation", "collection_id", collectionID)
+		return EndpointEntity{}, crud.ErrInvalidInput
+	}
+	if _, err := e.Read(ctx, id); err != nil {
+		return EndpointEntity{}, err
+	}
+	if _, err := e.DB.GetCollection(ctx, collectionID); err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn("endpoint move failed - collection not found", "collection_id", collectionID)
+			return EndpointEntity{}, crud.ErrInvalidInput
+		}
+		log.Error("failed to read collection", "id", collectionID, "error", err)
+		return EndpointEntity{}, err
+	}
+	sortOrder, err := e.DB.NextEndpointSortOrder(ctx, collectionID)
+	if err != nil {
+		log.Error("failed to get endpoint sort order", "collection_id", collectionID, "error", err)
+		return EndpointEntity{}, err
+	}
+
+	log.Debug("moving endpoint to collection", "id", id, "collection_id", collectionID)
+	moved, err := e.DB.UpdateEndpointCollection(ctx, database.UpdateEndpointCollectionParams{
+		CollectionID: collectionID,
+		SortOrder:    sortOrder,
+		ID:           id,
+	})
+	if err != nil {
+		log.Error("failed to move endpoint", "id", id, "collection_id", collectionID, "error", err)
+		return EndpointEntity{}, err
+	}
+
+	log.Info("moved endpoint to collection", "id", id, "collection_id", collectionID)
+	return EndpointEntity{Endpoint: moved}, nil
+}
+
+// Shift moves an endpoint up, for a negative offset, or down among the
+// endpoints of its folder. The position is clamped to the folder's ends.
+func (e *EndpointsManager) Shift(ctx context.Context, id int64, offset int) (EndpointEntity, error) {
+	endpoint, err := e.Read(ctx, id)
+	if err != nil {
+		return EndpointEntity{}, err
+	}
+	all, err := e.ListByCollection(ctx, endpoint.CollectionID)
+	if err != nil {
+		return EndpointEntity{}, err
+	}
+
+	// positions in all of the endpoints sharing the folder
+	var siblings []int
+	current := 0
+	for i, other := range all {
+		if other.GetFolderID() != endpoint.GetFolderID() {
+			continue
+		}
+		if other.ID == id {
+			current = len(siblings)
+		}
+		siblings = append(siblings, i)
+	}
+	target := max(0, min(len(siblings)-1, current+offset))
+	if target == current {
+		return endpoint, nil
+	}
+
+	// rotate the endpoint into the target slot, keeping the others in order
+	slots := make([]EndpointEntity, len(siblings))
+	for i, index := range siblings {
+		slots[i] = all[index]
+	}
+	moving := slots[current]
+	if target < current {
+		copy(slots[target+1:current+1], slots[target:current])
+	} else {
+		copy(slots[current:target], slots[current+1:target+1])
+	}
+	slots[target] = moving
+	for i, index := range siblings {
+		all[index] = slots[i]
+	}
+
+	log.Debug("shifting endpoint", "id", id, "offset", offset)
+	if err := e.Reorder(ctx, all); err != nil {
+		return EndpointEntity{}, err
+	}
+	return e.Read(ctx, id)
+}
+
+// Reorder numbers endpoints in the given order, writing only the ones whose
+// position changed
+func (e *EndpointsManager) Reorder(ctx context.Context, order []EndpointEntity) error {
+	for i, endpoint := range order {
+		sortOrder := int64(i + 1)
+		if endpoint.SortOrder == sortOrder {
+			continue
+		}
+		if err := e.DB.UpdateEndpointSortOrder(ctx, database.UpdateEndpointSortOrderParams{
+			SortOrder: sortOrder,
+			ID:        endpoint.ID,
+		}); err != nil {
+			log.Error("failed to reorder endpoint", "id", endpoint.ID, "error", err)
+			return err
+		}
+	}
+	return nil
+}
+
 func (e *EndpointsManager) GetCountsByCollections(ctx context.Context) ([]database.GetEndpointCountsByCollectionsRow, error) {
 	counts, err := e.DB.GetEndpointCountsByCollections(ctx)
 	if err != nil {
@@ -265,3 +590,59 @@ func (e *EndpointsManager) GetCountsByCollections(ctx context.Context) ([]databa
 	}
 	return counts, nil
 }
+
+// validateProtocol normalizes the endpoint protocol, defaulting to HTTP
+func validateProtocol(protocol string) (string, error) {
+	protocol = strings.ToLower(strings.TrimSpace(protocol))
+	switch protocol {
+	case "":
+		return ProtocolHTTP, nil
+	case ProtocolHTTP, ProtocolGRPC:
+		return protocol, nil
+	}
+	return "", fmt.Errorf("unsupported protocol: %s", protocol)
+}
+
+// encodeExtractions validates extraction rules, filling in the default
+// collection scope, and serializes them for storage
+func encodeExtractions(extractions []Extraction) (string, error) {
+	if len(extractions) == 0 {
+		return "[]", nil
+	}
+
+	normalized := make([]Extraction, len(extractions))
+	for i, extraction := range extractions {
+		if !variables.ValidateName(extraction.Variable) {
+			return "", fmt.Errorf("invalid variable name %q", extraction.Variable)
+		}
+		if extraction.Scope == "" {
+			extraction.Scope = ScopeCollection
+		}
+		if extraction.Scope != ScopeCollection && extraction.Scope != ScopeEnvironment {
+			return "", fmt.Errorf("unsupported scope: %s", extraction.Scope)
+		}
+		if extraction.Expression == "" {
+			return "", fmt.Errorf("extraction for %s has no expression", extraction.Variable)
+		}
+		switch extraction.Source {
+		case SourceJSON:
+			if _, err := query.Compile(extraction.Expression); err != nil {
+				return "", err
+			}
+		case SourceRegex:
+			if _, err := regexp.Compile(extraction.Expression); err != nil {
+				return "", err
+			}
+		case SourceHeader:
+		default:
+			return "", fmt.Errorf("unsupported extraction source: %s", extraction.Source)
+		}
+		normalized[i] = extraction
+	}
+
+	data, err := json.Marshal(normalized)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
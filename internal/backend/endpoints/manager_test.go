@@ -2,9 +2,12 @@ package endpoints
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 	"testing"
 
 	"github.com/maniac-en/req/internal/backend/crud"
+	"github.com/maniac-en/req/internal/backend/database"
 	"github.com/maniac-en/req/internal/backend/testutils"
 )
 
@@ -187,10 +190,125 @@ func TestCreateEndpoint(t *testing.T) {
 	})
 }
 
-func TestUpdateEndpoint(t *testing.T) {
+func TestCreateEndpointProtocol(t *testing.T) {
 	db := testutils.SetupTestDB(t, "collections", "endpoints")
 	manager := NewEndpointsManager(db)
 	ctx := context.Background()
+	collectionID := testutils.CreateTestCollection(t, db, "Mixed Collection")
+
+	t.Run("Defaults to HTTP", func(t *testing.T) {
+		endpoint, err := manager.CreateEndpoint(ctx, EndpointData{
+			CollectionID: collectionID,
+			Name:         "REST Endpoint",
+			Method:       "GET",
+			URL:          "https://api.example.com",
+		})
+		if err != nil {
+			t.Fatalf("CreateEndpoint failed: %v", err)
+		}
+		if endpoint.Protocol != ProtocolHTTP {
+			t.Errorf("Expected protocol %q, got %q", ProtocolHTTP, endpoint.Protocol)
+		}
+		if endpoint.IsGRPC() {
+			t.Error("Expected HTTP endpoint not to report gRPC")
+		}
+	})
+
+	t.Run("gRPC endpoint with proto files", func(t *testing.T) {
+		endpoint, err := manager.CreateEndpoint(ctx, EndpointData{
+			CollectionID: collectionID,
+			Name:         "gRPC Endpoint",
+			Method:       "grpc.health.v1.Health/Check",
+			URL:          "grpc://localhost:50051",
+			Protocol:     "GRPC",
+			ProtoFiles:   []string{"protos/health.proto"},
+			RequestBody:  `{"service": ""}`,
+		})
+		if err != nil {
+			t.Fatalf("CreateEndpoint failed: %v", err)
+		}
+		if !endpoint.IsGRPC() {
+			t.Errorf("Expected gRPC endpoint, got protocol %q", endpoint.Protocol)
+		}
+		files := endpoint.GetProtoFiles()
+		if len(files) != 1 || files[0] != "protos/health.proto" {
+			t.Errorf("Expected proto files to round-trip, got %v", files)
+		}
+	})
+
+	t.Run("Unknown protocol", func(t *testing.T) {
+		_, err := manager.CreateEndpoint(ctx, EndpointData{
+			CollectionID: collectionID,
+			Name:         "Socket Endpoint",
+			Method:       "GET",
+			URL:          "ws://localhost",
+			Protocol:     "websocket",
+		})
+		if err != crud.ErrInvalidInput {
+			t.Errorf("Expected ErrInvalidInput, got %v", err)
+		}
+	})
+}
+
+func TestCreateEndpointExtractions(t *testing.T) {
+	db := testutils.SetupTestDB(t, "collections", "endpoints")
+	manager := NewEndpointsManager(db)
+	ctx := context.Background()
+	collectionID := testutils.CreateTestCollection(t, db, "Chained Collection")
+
+	t.Run("Stores rules with default scope", func(t *testing.T) {
+		endpoint, err := manager.CreateEndpoint(ctx, EndpointData{
+			CollectionID: collectionID,
+			Name:         "Login",
+			Method:       "POST",
+			URL:          "https://api.example.com/login",
+			Extractions: []Extraction{
+				{Variable: "token", Source: SourceJSON, Expression: "$.token"},
+				{Variable: "session", Source: SourceHeader, Expression: "X-Session", Scope: ScopeEnvironment},
+			},
+		})
+		if err != nil {
+			t.Fatalf("CreateEndpoint failed: %v", err)
+		}
+		extractions := endpoint.GetExtractions()
+		if len(extractions) != 2 {
+			t.Fatalf("Expected 2 extractions, got %d", len(extractions))
+		}
+		if extractions[0].Scope != ScopeCollection {
+			t.Errorf("Expected default scope %q, got %q", ScopeCollection, extractions[0].Scope)
+		}
+		if extractions[1].Scope != ScopeEnvironment {
+			t.Errorf("Expected scope %q, got %q", ScopeEnvironment, extractions[1].Scope)
+		}
+	})
+
+	invalid := []Extraction{
+		{Variable: "", Source: SourceJSON, Expression: "$.token"},
+		{Variable: "{{token}}", Source: SourceJSON, Expression: "$.token"},
+		{Variable: "token", Source: "cookie", Expression: "session"},
+		{Variable: "token", Source: SourceJSON, Expression: "$.token["},
+		{Variable: "token", Source: SourceRegex, Expression: "(unclosed"},
+		{Variable: "token", Source: SourceHeader, Expression: ""},
+		{Variable: "token", Source: SourceHeader, Expression: "X-Token", Scope: "global"},
+	}
+	for _, extraction := range invalid {
+		_, err := manager.CreateEndpoint(ctx, EndpointData{
+			CollectionID: collectionID,
+			Name:         "Invalid Extraction",
+			Method:       "GET",
+			URL:          "https://api.example.com",
+			Extractions:  []Extraction{extraction},
+		})
+		if err != crud.ErrInvalidInput {
+			t.Errorf("Expected ErrInvalidInput for %+v, got %v", extraction, err)
+		}
+	}
+}
+
+func TestUpdateEndpoint(t *testing.T) {
+	db := testutils.SetupTestDB(t, "collections", "endpoints", "endpoint_revisions")
+	manager := NewEndpointsManager(db)
+	ctx := context.Background()
 	collectionID := testutils.CreateTestCollection(t, db, "Test Collection")
 
 	t.Run("Valid endpoint update", func(t *testing.T) {
@@ -217,6 +335,9 @@ func TestUpdateEndpoint(t *testing.T) {
 			Headers:     `{"Content-Type": "application/json"}`,
 			QueryParams: map[string]string{"updated": "true"},
 			RequestBody: `{"updated": true}`,
+			// scripts are stored verbatim
+			PreRequestScript:   `req.request.headers["X-Trace"] = req.uuid()`,
+			PostResponseScript: `req.test("ok", function () {})`,
 		}
 
 		updated, err := manager.UpdateEndpoint(ctx, created.GetID(), updateData)
@@ -233,6 +354,9 @@ func TestUpdateEndpoint(t *testing.T) {
 		if updated.Url != "https://api.example.com/updated" {
 			t.Errorf("Expected URL 'https://api.example.com/updated', got %s", updated.Url)
 		}
+		if updated.PreRequestScript != updateData.PreRequestScript || updated.PostResponseScript != updateData.PostResponseScript {
+			t.Errorf("Expected scripts to be stored, got %q and %q", updated.PreRequestScript, updated.PostResponseScript)
+		}
 	})
 
 	t.Run("Update non-existent endpoint", func(t *testing.T) {
@@ -405,3 +529,170 @@ func TestEndpointsManagerValidation(t *testing.T) {
 		}
 	})
 }
+
+func TestMoveToFolder(t *testing.T) {
+	db := testutils.SetupTestDB(t, "collections", "endpoints", "folders")
+	manager := NewEndpointsManager(db)
+	ctx := context.Background()
+	collectionID := testutils.CreateTestCollection(t, db, "Test Collection")
+	otherID := testutils.CreateTestCollection(t, db, "Other Collection")
+
+	folder, err := db.CreateFolder(ctx, database.CreateFolderParams{CollectionID: collectionID, Name: "users"})
+	if err != nil {
+		t.Fatalf("CreateFolder failed: %v", err)
+	}
+	otherFolder, err := db.CreateFolder(ctx, database.CreateFolderParams{CollectionID: otherID, Name: "users"})
+	if err != nil {
+		t.Fatalf("CreateFolder failed: %v", err)
+	}
+	endpoint, err := manager.CreateEndpoint(ctx, EndpointData{CollectionID: collectionID, Name: "List", Method: "GET"})
+	if err != nil {
+		t.Fatalf("CreateEndpoint failed: %v", err)
+	}
+	if endpoint.GetFolderID() != 0 {
+		t.Errorf("Expected a new endpoint at the top, got folder %d", endpoint.GetFolderID())
+	}
+
+	moved, err := manager.MoveToFolder(ctx, endpoint.ID, folder.ID)
+	if err != nil {
+		t.Fatalf("MoveToFolder failed: %v", err)
+	}
+	if moved.GetFolderID() != folder.ID {
+		t.Errorf("Expected folder %d, got %d", folder.ID, moved.GetFolderID())
+	}
+	if _, err := manager.MoveToFolder(ctx, endpoint.ID, otherFolder.ID); err != crud.ErrInvalidInput {
+		t.Errorf("Expected a folder of another collection to be rejected, got %v", err)
+	}
+	moved, err = manager.MoveToFolder(ctx, endpoint.ID, 0)
+	if err != nil {
+		t.Fatalf("MoveToFolder failed: %v", err)
+	}
+	if moved.GetFolderID() != 0 {
+		t.Errorf("Expected the endpoint back at the top, got folder %d", moved.GetFolderID())
+	}
+}
+
+func names(all []EndpointEntity) []string {
+	result := make([]string, len(all))
+	for i, endpoint := range all {
+		result[i] = endpoint.Name
+	}
+	return result
+}
+
+func TestOrdering(t *testing.T) {
+	db := testutils.SetupTestDB(t, "collections", "endpoints", "folders")
+	manager := NewEndpointsManager(db)
+	ctx := context.Background()
+	collectionID := testutils.CreateTestCollection(t, db, "Ordering")
+
+	created := map[string]EndpointEntity{}
+	for _, name := range []string{"login", "create", "fetch", "delete"} {
+		endpoint, err := manager.CreateEndpoint(ctx, EndpointData{CollectionID: collectionID, Name: name, Method: "GET"})
+		if err != nil {
+			t.Fatalf("CreateEndpoint failed: %v", err)
+		}
+		created[name] = endpoint
+	}
+	check := func(expected ...string) {
+		t.Helper()
+		all, _ := manager.ListByCollection(ctx, collectionID)
+		if got := names(all); fmt.Sprint(got) != fmt.Sprint(expected) {
+			t.Errorf("Expected order %v, got %v", expected, got)
+		}
+	}
+	check("login", "create", "fetch", "delete")
+
+	if _, err := manager.Shift(ctx, created["delete"].ID, -2); err != nil {
+		t.Fatalf("Shift failed: %v", err)
+	}
+	check("login", "delete", "create", "fetch")
+	manager.Shift(ctx, created["login"].ID, 10)
+	check("delete", "create", "fetch", "login")
+	manager.Shift(ctx, created["delete"].ID, -1)
+	check("delete", "create", "fetch", "login")
+	page, err := manager.ListByCollectionByPage(ctx, collectionID, 2, 2)
+	if err != nil {
+		t.Fatalf("ListByCollectionByPage failed: %v", err)
+	}
+	if got := names(page.Endpoints); fmt.Sprint(got) != "[fetch login]" {
+		t.Errorf("Expected pages to follow the order, got %v", got)
+	}
+
+	copied, err := manager.Duplicate(ctx, created["create"].ID)
+	if err != nil {
+		t.Fatalf("Duplicate failed: %v", err)
+	}
+	if copied.Name != "create (copy)" || copied.Method != "GET" {
+		t.Errorf("Expected a copy named 'create (copy)', got %q", copied.Name)
+	}
+	check("delete", "create", "create (copy)", "fetch", "login")
+	again, _ := manager.Duplicate(ctx, created["create"].ID)
+	if again.Name != "create (copy 2)" {
+		t.Errorf("Expected 'create (copy 2)', got %q", again.Name)
+	}
+
+	// endpoints only shift among the endpoints of their folder
+	folder, _ := db.CreateFolder(ctx, database.CreateFolderParams{CollectionID: collectionID, Name: "users"})
+	manager.MoveToFolder(ctx, created["fetch"].ID, folder.ID)
+	manager.Shift(ctx, created["login"].ID, -1)
+	check("delete", "create", "create (copy 2)", "login", "fetch", "create (copy)")
+}
+
+func TestMoveToCollection(t *testing.T) {
+	db := testutils.SetupTestDB(t, "collections", "endpoints", "folders")
+	manager := NewEndpointsManager(db)
+	ctx := context.Background()
+	fromID := testutils.CreateTestCollection(t, db, "From")
+	toID := testutils.CreateTestCollection(t, db, "To")
+
+	folder, _ := db.CreateFolder(ctx, database.CreateFolderParams{CollectionID: fromID, Name: "users"})
+	endpoint, _ := manager.CreateEndpoint(ctx, EndpointData{CollectionID: fromID, FolderID: folder.ID, Name: "moving", Method: "GET"})
+	manager.CreateEndpoint(ctx, EndpointData{CollectionID: toID, Name: "existing", Method: "GET"})
+
+	moved, err := manager.MoveToCollection(ctx, endpoint.ID, toID)
+	if err != nil {
+		t.Fatalf("MoveToCollection failed: %v", err)
+	}
+	if moved.CollectionID != toID || moved.GetFolderID() != 0 {
+		t.Errorf("Expected the endpoint at the top of collection %d, got collection %d folder %d", toID, moved.CollectionID, moved.GetFolderID())
+	}
+	all, _ := manager.ListByCollection(ctx, toID)
+	if got := names(all); fmt.Sprint(got) != "[existing moving]" {
+		t.Errorf("Expected the moved endpoint last, got %v", got)
+	}
+	if _, err := manager.MoveToCollection(ctx, endpoint.ID, 999); err != crud.ErrInvalidInput {
+		t.Errorf("Expected ErrInvalidInput for a missing collection, got %v", err)
+	}
+}
+
+func TestSortOrderIgnoresTrash(t *testing.T) {
+	db := testutils.SetupTestDB(t, "collections", "endpoints", "folders")
+	manager := NewEndpointsManager(db)
+	ctx := context.Background()
+	collectionID := testutils.CreateTestCollection(t, db, "Trash")
+
+	first, _ := manager.CreateEndpoint(ctx, EndpointData{CollectionID: collectionID, Name: "first", Method: "GET"})
+	trashed, _ := manager.CreateEndpoint(ctx, EndpointData{CollectionID: collectionID, Name: "trashed", Method: "GET"})
+	if err := manager.Delete(ctx, trashed.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	next, err := manager.CreateEndpoint(ctx, EndpointData{CollectionID: collectionID, Name: "next", Method: "GET"})
+	if err != nil {
+		t.Fatalf("CreateEndpoint failed: %v", err)
+	}
+	if next.SortOrder != first.SortOrder+1 {
+		t.Errorf("Expected sort order %d after the trashed endpoint left, got %d", first.SortOrder+1, next.SortOrder)
+	}
+
+	if err := db.UpdateEndpointSortOrder(ctx, database.UpdateEndpointSortOrderParams{SortOrder: 99, ID: trashed.ID}); err != nil {
+		t.Fatalf("UpdateEndpointSortOrder failed: %v", err)
+	}
+	if _, err := db.UpdateEndpointFolder(ctx, database.UpdateEndpointFolderParams{ID: trashed.ID}); err != sql.ErrNoRows {
+		t.Errorf("Expected a trashed endpoint not to move, got %v", err)
+	}
+	if row, _ := db.GetTrashedEndpoint(ctx, trashed.ID); row.SortOrder != trashed.SortOrder {
+		t.Errorf("Expected a trashed endpoint to keep its sort order, got %d", row.SortOrder)
+	}
+}
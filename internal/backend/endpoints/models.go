@@ -1,12 +1,38 @@
 package endpoints
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/maniac-en/req/internal/backend/crud"
 	"github.com/maniac-en/req/internal/backend/database"
 )
 
+const (
+	ProtocolHTTP = "http"
+	ProtocolGRPC = "grpc"
+)
+
+// Extraction sources and the variable scopes they can write to
+const (
+	SourceJSON   = "json"
+	SourceHeader = "header"
+	SourceRegex  = "regex"
+
+	ScopeCollection  = "collection"
+	ScopeEnvironment = "environment"
+)
+
+// Extraction copies a value out of a successful response into a variable.
+// Expression is a JSONPath or jq expression, a header name or a regular
+// expression whose first group (or whole match) is used.
+type Extraction struct {
+	Variable   string `json:"variable"`
+	Source     string `json:"source"`
+	Expression string `json:"expression"`
+	Scope      string `json:"scope"`
+}
+
 type EndpointEntity struct {
 	database.Endpoint
 }
@@ -27,6 +53,55 @@ func (c EndpointEntity) GetUpdatedAt() time.Time {
 	return crud.ParseTimestamp(c.UpdatedAt)
 }
 
+// GetFolderID returns the ID of the folder holding the endpoint, or 0 when it
+// sits at the top of its collection
+func (c EndpointEntity) GetFolderID() int64 {
+	if !c.FolderID.Valid {
+		return 0
+	}
+	return c.FolderID.Int64
+}
+
+func (c EndpointEntity) IsGRPC() bool {
+	return c.Protocol == ProtocolGRPC
+}
+
+// GetHeaders decodes the stored headers JSON, returning an empty map when it is malformed
+func (c EndpointEntity) GetHeaders() map[string]string {
+	headers := map[string]string{}
+	if err := json.Unmarshal([]byte(c.Headers), &headers); err != nil {
+		return map[string]string{}
+	}
+	return headers
+}
+
+// GetQueryParams decodes the stored query params JSON, returning an empty map when it is malformed
+func (c EndpointEntity) GetQueryParams() map[string]string {
+	params := map[string]string{}
+	if err := json.Unmarshal([]byte(c.QueryParams), &params); err != nil {
+		return map[string]string{}
+	}
+	return params
+}
+
+// GetProtoFiles decodes the stored list of .proto files used by gRPC endpoints
+func (c EndpointEntity) GetProtoFiles() []string {
+	var files []string
+	if err := json.Unmarshal([]byte(c.ProtoFiles), &files); err != nil {
+		return nil
+	}
+	return files
+}
+
+// GetExtractions decodes the stored extraction rules
+func (c EndpointEntity) GetExtractions() []Extraction {
+	var extractions []Extraction
+	if err := json.Unmarshal([]byte(c.Extractions), &extractions); err != nil {
+		return nil
+	}
+	return extractions
+}
+
 type EndpointsManager struct {
 	DB *database.Queries
 }
@@ -38,10 +113,20 @@ type PaginatedEndpoints struct {
 
 type EndpointData struct {
 	CollectionID int64
-	Name         string
-	Method       string
-	URL          string
-	Headers      string
-	QueryParams  map[string]string
-	RequestBody  string
+	// FolderID places the endpoint in a folder of the collection, or at the
+	// top when it is 0
+	FolderID    int64
+	Name        string
+	Method      string
+	URL         string
+	Headers     string
+	QueryParams map[string]string
+	RequestBody string
+	Protocol    string
+	ProtoFiles  []string
+	Extractions []Extraction
+	// PreRequestScript and PostResponseScript are JavaScript hooks run
+	// around each request
+	PreRequestScript   string
+	PostResponseScript string
 }
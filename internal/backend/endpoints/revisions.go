@@ -0,0 +1,246 @@
+package endpoints
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/maniac-en/req/internal/backend/crud"
+	"github.com/maniac-en/req/internal/backend/database"
+	"github.com/maniac-en/req/internal/diff"
+	"github.com/maniac-en/req/internal/log"
+)
+
+// Revision is an earlier version of an endpoint, saved when an edit replaced
+// it
+type Revision struct {
+	database.EndpointRevision
+}
+
+func (r Revision) GetCreatedAt() time.Time {
+	return crud.ParseTimestamp(r.CreatedAt)
+}
+
+// Version is the part of an endpoint that revisions keep, so revisions can be
+// compared with each other and with the endpoint as it is now
+type Version struct {
+	Name               string
+	Method             string
+	URL                string
+	Headers            map[string]string
+	QueryParams        map[string]string
+	RequestBody        string
+	Protocol           string
+	ProtoFiles         string
+	Extractions        string
+	PreRequestScript   string
+	PostResponseScript string
+}
+
+func (r Revision) Version() Version {
+	return Version{
+		Name:               r.Name,
+		Method:             r.Method,
+		URL:                r.Url,
+		Headers:            decodeMap(r.Headers),
+		QueryParams:        decodeMap(r.QueryParams),
+		RequestBody:        r.RequestBody,
+		Protocol:           r.Protocol,
+		ProtoFiles:         r.ProtoFiles,
+		Extractions:        r.Extractions,
+		PreRequestScript:   r.PreRequestScript,
+		PostResponseScript: r.PostResponseScript,
+	}
+}
+
+func (c EndpointEntity) Version() Version {
+	return Version{
+		Name:               c.Name,
+		Method:             c.Method,
+		URL:                c.Url,
+		Headers:            c.GetHeaders(),
+		QueryParams:        c.GetQueryParams(),
+		RequestBody:        c.RequestBody,
+		Protocol:           c.Protocol,
+		ProtoFiles:         c.ProtoFiles,
+		Extractions:        c.Extractions,
+		PreRequestScript:   c.PreRequestScript,
+		PostResponseScript: c.PostResponseScript,
+	}
+}
+
+// ListRevisions returns the earlier versions of an endpoint, newest first
+func (e *EndpointsManager) ListRevisions(ctx context.Context, endpointID int64) ([]Revision, error) {
+	if err := crud.ValidateID(endpointID); err != nil {
+		log.Warn("revision list failed validation", "endpoint_id", endpointID)
+		return nil, crud.ErrInvalidInput
+	}
+
+	rows, err := e.DB.ListEndpointRevisions(ctx, endpointID)
+	if err != nil {
+		log.Error("failed to list revisions", "endpoint_id", endpointID, "error", err)
+		return nil, err
+	}
+
+	revisions := make([]Revision, len(rows))
+	for i, row := range rows {
+		revisions[i] = Revision{EndpointRevision: row}
+	}
+	return revisions, nil
+}
+
+func (e *EndpointsManager) GetRevision(ctx context.Context, id int64) (Revision, error) {
+	if err := crud.ValidateID(id); err != nil {
+		log.Warn("revision read failed validation", "id", id)
+		return Revision{}, crud.ErrInvalidInput
+	}
+
+	row, err := e.DB.GetEndpointRevision(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Debug("revision not found", "id", id)
+			return Revision{}, crud.ErrNotFound
+		}
+		log.Error("failed to read revision", "id", id, "error", err)
+		return Revision{}, err
+	}
+	return Revision{EndpointRevision: row}, nil
+}
+
+// RestoreRevision puts an endpoint back the way it was in a revision. Like any
+// edit, the version it replaces is kept as a new revision, so a restore can be
+// undone by restoring that.
+func (e *EndpointsManager) RestoreRevision(ctx context.Context, id int64) (EndpointEntity, error) {
+	revision, err := e.GetRevision(ctx, id)
+	if err != nil {
+		return EndpointEntity{}, err
+	}
+
+	var extractions []Extraction
+	if err := json.Unmarshal([]byte(revision.Extractions), &extractions); err != nil {
+		log.Warn("revision has malformed extractions", "id", id, "error", err)
+	}
+	var protoFiles []string
+	if err := json.Unmarshal([]byte(revision.ProtoFiles), &protoFiles); err != nil {
+		log.Warn("revision has malformed proto files", "id", id, "error", err)
+	}
+
+	log.Debug("restoring revision", "id", id, "endpoint_id", revision.EndpointID)
+	endpoint, err := e.UpdateEndpoint(ctx, revision.EndpointID, EndpointData{
+		Name:               revision.Name,
+		Method:             revision.Method,
+		URL:                revision.Url,
+		Headers:            revision.Headers,
+		QueryParams:        decodeMap(revision.QueryParams),
+		RequestBody:        revision.RequestBody,
+		Protocol:           revision.Protocol,
+		ProtoFiles:         protoFiles,
+		Extractions:        extractions,
+		PreRequestScript:   revision.PreRequestScript,
+		PostResponseScript: revision.PostResponseScript,
+	})
+	if err != nil {
+		return EndpointEntity{}, err
+	}
+
+	log.Info("restored revision", "id", id, "endpoint_id", endpoint.ID)
+	return endpoint, nil
+}
+
+// CompareVersions lists what changed from older to newer. Paths name the
+// field, like "url" or "header content-type", with a line number for bodies
+// and scripts.
+func CompareVersions(older, newer Version) []diff.Change {
+	var changes []diff.Change
+	field := func(path, left, right string) {
+		switch {
+		case left == right:
+		case left == "":
+			changes = append(changes, diff.Change{Path: path, Kind: diff.Added, Right: right})
+		case right == "":
+			changes = append(changes, diff.Change{Path: path, Kind: diff.Removed, Left: left})
+		default:
+			changes = append(changes, diff.Change{Path: path, Kind: diff.Changed, Left: left, Right: right})
+		}
+	}
+	lines := func(prefix, left, right string) {
+		for _, change := range diff.Text(left, right) {
+			change.Path = prefix + " " + change.Path
+			changes = append(changes, change)
+		}
+	}
+	values := func(prefix string, left, right map[string]string) {
+		for _, change := range diff.Headers(wrapValues(left), wrapValues(right), nil) {
+			change.Path = prefix + " " + change.Path
+			changes = append(changes, change)
+		}
+	}
+
+	field("name", older.Name, newer.Name)
+	field("protocol", older.Protocol, newer.Protocol)
+	field("method", older.Method, newer.Method)
+	field("url", older.URL, newer.URL)
+	values("query", older.QueryParams, newer.QueryParams)
+	values("header", older.Headers, newer.Headers)
+	lines("body", older.RequestBody, newer.RequestBody)
+	field("proto files", older.ProtoFiles, newer.ProtoFiles)
+	field("extractions", older.Extractions, newer.Extractions)
+	lines("pre-request script", older.PreRequestScript, newer.PreRequestScript)
+	lines("post-response script", older.PostResponseScript, newer.PostResponseScript)
+	return changes
+}
+
+// recordRevision saves the endpoint as it was before an edit, unless the
+// edit left its request unchanged. It runs once the edit succeeded, so a
+// failed edit leaves no revision behind; a revision that can't be saved is
+// only logged, since the edit itself has been made.
+func (e *EndpointsManager) recordRevision(ctx context.Context, previous, updated database.Endpoint) {
+	params := revisionParams(previous)
+	if params == revisionParams(updated) {
+		return
+	}
+
+	revision, err := e.DB.CreateEndpointRevision(ctx, params)
+	if err != nil {
+		log.Error("failed to record revision", "endpoint_id", previous.ID, "error", err)
+		return
+	}
+	log.Debug("recorded revision", "id", revision.ID, "endpoint_id", previous.ID)
+}
+
+func revisionParams(endpoint database.Endpoint) database.CreateEndpointRevisionParams {
+	return database.CreateEndpointRevisionParams{
+		EndpointID:         endpoint.ID,
+		Name:               endpoint.Name,
+		Method:             endpoint.Method,
+		Url:                endpoint.Url,
+		Headers:            endpoint.Headers,
+		QueryParams:        endpoint.QueryParams,
+		RequestBody:        endpoint.RequestBody,
+		Protocol:           endpoint.Protocol,
+		ProtoFiles:         endpoint.ProtoFiles,
+		Extractions:        endpoint.Extractions,
+		PreRequestScript:   endpoint.PreRequestScript,
+		PostResponseScript: endpoint.PostResponseScript,
+	}
+}
+
+// decodeMap decodes stored headers or query params, returning an empty map
+// when they are malformed
+func decodeMap(raw string) map[string]string {
+	values := map[string]string{}
+	if err := json.Unmarshal([]byte(raw), &values); err != nil {
+		return map[string]string{}
+	}
+	return values
+}
+
+// wrapValues adapts single valued maps for diff.Headers
+func wrapValues(values map[string]string) map[string][]string {
+	wrapped := make(map[string][]string, len(values))
+	for name, value := range values {
+		wrapped[name] = []string{value}
+	}
+	return wrapped
+}
@@ -0,0 +1,133 @@
+package endpoints
+
+import (
+	"context"
+	"testing"
+
+	"github.com/maniac-en/req/internal/backend/database"
+	"github.com/maniac-en/req/internal/backend/testutils"
+	"github.com/maniac-en/req/internal/diff"
+)
+
+func TestRevisions(t *testing.T) {
+	db := testutils.SetupTestDB(t, "collections", "endpoints", "endpoint_revisions")
+	manager := NewEndpointsManager(db)
+	ctx := context.Background()
+	collectionID := testutils.CreateTestCollection(t, db, "Test Collection")
+
+	original := EndpointData{
+		CollectionID: collectionID,
+		Name:         "Get user",
+		Method:       "GET",
+		URL:          "https://api.example.com/users/1",
+		Headers:      `{"Accept": "application/json"}`,
+	}
+	created, err := manager.CreateEndpoint(ctx, original)
+	if err != nil {
+		t.Fatalf("CreateEndpoint failed: %v", err)
+	}
+
+	edited := original
+	edited.URL = "https://api.example.com/users/2"
+	edited.Headers = `{"Accept": "application/json", "X-Trace": "1"}`
+	edited.RequestBody = "{}"
+	if _, err := manager.UpdateEndpoint(ctx, created.ID, edited); err != nil {
+		t.Fatalf("UpdateEndpoint failed: %v", err)
+	}
+
+	t.Run("Unchanged update records nothing", func(t *testing.T) {
+		if _, err := manager.UpdateEndpoint(ctx, created.ID, edited); err != nil {
+			t.Fatalf("UpdateEndpoint failed: %v", err)
+		}
+		revisions, err := manager.ListRevisions(ctx, created.ID)
+		if err != nil {
+			t.Fatalf("ListRevisions failed: %v", err)
+		}
+		if len(revisions) != 1 {
+			t.Fatalf("Expected 1 revision, got %d", len(revisions))
+		}
+		if revisions[0].Url != original.URL || revisions[0].Headers != original.Headers {
+			t.Errorf("Expected the revision to hold the original request, got %s %s", revisions[0].Url, revisions[0].Headers)
+		}
+	})
+
+	t.Run("Compare versions", func(t *testing.T) {
+		revisions, _ := manager.ListRevisions(ctx, created.ID)
+		current, err := manager.Read(ctx, created.ID)
+		if err != nil {
+			t.Fatalf("Read failed: %v", err)
+		}
+
+		changes := CompareVersions(revisions[0].Version(), current.Version())
+		want := []diff.Change{
+			{Path: "url", Kind: diff.Changed, Left: original.URL, Right: edited.URL},
+			{Path: "header x-trace", Kind: diff.Added, Right: "1"},
+			{Path: "body line 1", Kind: diff.Added, Right: "{}"},
+		}
+		if len(changes) != len(want) {
+			t.Fatalf("Expected %d changes, got %v", len(want), changes)
+		}
+		for i := range want {
+			if changes[i] != want[i] {
+				t.Errorf("Expected change %d to be %v, got %v", i, want[i], changes[i])
+			}
+		}
+	})
+
+	t.Run("Rename records a revision", func(t *testing.T) {
+		if _, err := manager.UpdateEndpointName(ctx, created.ID, "Get other user"); err != nil {
+			t.Fatalf("UpdateEndpointName failed: %v", err)
+		}
+		revisions, _ := manager.ListRevisions(ctx, created.ID)
+		if len(revisions) != 2 || revisions[0].Name != "Get user" {
+			t.Errorf("Expected the name before the rename on top, got %d revisions", len(revisions))
+		}
+	})
+
+	t.Run("Restore revision", func(t *testing.T) {
+		revisions, _ := manager.ListRevisions(ctx, created.ID)
+		oldest := revisions[len(revisions)-1]
+
+		restored, err := manager.RestoreRevision(ctx, oldest.ID)
+		if err != nil {
+			t.Fatalf("RestoreRevision failed: %v", err)
+		}
+		if restored.Url != original.URL || restored.Name != original.Name || restored.RequestBody != "" {
+			t.Errorf("Expected the original request back, got %s %s %q", restored.Name, restored.Url, restored.RequestBody)
+		}
+
+		revisions, _ = manager.ListRevisions(ctx, created.ID)
+		if len(revisions) != 3 || revisions[0].Name != "Get other user" {
+			t.Errorf("Expected the restore to keep the replaced version, got %d revisions", len(revisions))
+		}
+	})
+
+	t.Run("Failed update records nothing", func(t *testing.T) {
+		conn := testutils.OpenTestDB(t, "collections", "endpoints", "endpoint_revisions")
+		manager := NewEndpointsManager(database.New(conn))
+		collectionID := testutils.CreateTestCollection(t, manager.DB, "Test Collection")
+		created, err := manager.CreateEndpoint(ctx, EndpointData{CollectionID: collectionID, Name: "Get user", Method: "GET", URL: original.URL})
+		if err != nil {
+			t.Fatalf("CreateEndpoint failed: %v", err)
+		}
+		if _, err := conn.Exec(`CREATE TRIGGER refuse BEFORE UPDATE ON endpoints BEGIN SELECT RAISE(ABORT, 'refused'); END`); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := manager.UpdateEndpoint(ctx, created.ID, edited); err == nil {
+			t.Fatal("Expected the update to fail")
+		}
+		if _, err := manager.UpdateEndpointName(ctx, created.ID, "Renamed"); err == nil {
+			t.Fatal("Expected the rename to fail")
+		}
+		if revisions, _ := manager.ListRevisions(ctx, created.ID); len(revisions) != 0 {
+			t.Errorf("Expected no revisions for failed updates, got %d", len(revisions))
+		}
+	})
+
+	t.Run("Missing revision", func(t *testing.T) {
+		if _, err := manager.RestoreRevision(ctx, 999); err == nil {
+			t.Error("Expected an error restoring a missing revision")
+		}
+	})
+}
@@ -0,0 +1,221 @@
+package environments
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/maniac-en/req/internal/backend/crud"
+	"github.com/maniac-en/req/internal/backend/database"
+	"github.com/maniac-en/req/internal/backend/variables"
+	"github.com/maniac-en/req/internal/log"
+)
+
+func NewEnvironmentsManager(db *database.Queries) *EnvironmentsManager {
+	return &EnvironmentsManager{DB: db}
+}
+
+func (e *EnvironmentsManager) Create(ctx context.Context, name string) (EnvironmentEntity, error) {
+	if err := crud.ValidateName(name); err != nil {
+		log.Warn("environment creation failed validation", "name", name)
+		return EnvironmentEntity{}, crud.ErrInvalidInput
+	}
+
+	log.Debug("creating environment", "name", name)
+	environment, err := e.DB.CreateEnvironment(ctx, name)
+	if err != nil {
+		log.Error("failed to create environment", "name", name, "error", err)
+		return EnvironmentEntity{}, err
+	}
+
+	log.Info("created environment", "id", environment.ID, "name", environment.Name)
+	return EnvironmentEntity{Environment: environment}, nil
+}
+
+func (e *EnvironmentsManager) Read(ctx context.Context, id int64) (EnvironmentEntity, error) {
+	if err := crud.ValidateID(id); err != nil {
+		log.Warn("environment read failed validation", "id", id)
+		return EnvironmentEntity{}, crud.ErrInvalidInput
+	}
+
+	log.Debug("reading environment", "id", id)
+	environment, err := e.DB.GetEnvironment(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Debug("environment not found", "id", id)
+			return EnvironmentEntity{}, crud.ErrNotFound
+		}
+		log.Error("failed to read environment", "id", id, "error", err)
+		return EnvironmentEntity{}, err
+	}
+
+	return EnvironmentEntity{Environment: environment}, nil
+}
+
+func (e *EnvironmentsManager) ReadByName(ctx context.Context, name string) (EnvironmentEntity, error) {
+	log.Debug("reading environment by name", "name", name)
+	environment, err := e.DB.GetEnvironmentByName(ctx, name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Debug("environment not found", "name", name)
+			return EnvironmentEntity{}, crud.ErrNotFound
+		}
+		log.Error("failed to read environment", "name", name, "error", err)
+		return EnvironmentEntity{}, err
+	}
+
+	return EnvironmentEntity{Environment: environment}, nil
+}
+
+func (e *EnvironmentsManager) Update(ctx context.Context, id int64, name string) (EnvironmentEntity, error) {
+	if err := crud.ValidateID(id); err != nil {
+		log.Warn("environment update failed ID validation", "id", id)
+		return EnvironmentEntity{}, crud.ErrInvalidInput
+	}
+	if err := crud.ValidateName(name); err != nil {
+		log.Warn("environment update failed name validation", "name", name)
+		return EnvironmentEntity{}, crud.ErrInvalidInput
+	}
+
+	log.Debug("updating environment", "id", id, "name", name)
+	environment, err := e.DB.UpdateEnvironmentName(ctx, database.UpdateEnvironmentNameParams{
+		Name: name,
+		ID:   id,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Debug("environment not found for update", "id", id)
+			return EnvironmentEntity{}, crud.ErrNotFound
+		}
+		log.Error("failed to update environment", "id", id, "name", name, "error", err)
+		return EnvironmentEntity{}, err
+	}
+
+	log.Info("updated environment", "id", environment.ID, "name", environment.Name)
+	return EnvironmentEntity{Environment: environment}, nil
+}
+
+func (e *EnvironmentsManager) Delete(ctx context.Context, id int64) error {
+	if err := crud.ValidateID(id); err != nil {
+		log.Warn("environment delete failed validation", "id", id)
+		return crud.ErrInvalidInput
+	}
+
+	log.Debug("deleting environment", "id", id)
+	if err := e.DB.DeleteEnvironment(ctx, id); err != nil {
+		log.Error("failed to delete environment", "id", id, "error", err)
+		return err
+	}
+
+	log.Info("deleted environment", "id", id)
+	return nil
+}
+
+func (e *EnvironmentsManager) List(ctx context.Context) ([]EnvironmentEntity, error) {
+	environments, err := e.DB.ListEnvironments(ctx)
+	if err != nil {
+		log.Error("failed to list environments", "error", err)
+		return nil, err
+	}
+
+	entities := make([]EnvironmentEntity, len(environments))
+	for i, environment := range environments {
+		entities[i] = EnvironmentEntity{Environment: environment}
+	}
+	return entities, nil
+}
+
+// GetActive returns the active environment, or crud.ErrNotFound when none is active
+func (e *EnvironmentsManager) GetActive(ctx context.Context) (EnvironmentEntity, error) {
+	environment, err := e.DB.GetActiveEnvironment(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return EnvironmentEntity{}, crud.ErrNotFound
+		}
+		log.Error("failed to read active environment", "error", err)
+		return EnvironmentEntity{}, err
+	}
+	return EnvironmentEntity{Environment: environment}, nil
+}
+
+// Activate makes the environment the only active one
+func (e *EnvironmentsManager) Activate(ctx context.Context, id int64) error {
+	if _, err := e.Read(ctx, id); err != nil {
+		return err
+	}
+
+	log.Debug("activating environment", "id", id)
+	if err := e.DB.SetActiveEnvironment(ctx, id); err != nil {
+		log.Error("failed to activate environment", "id", id, "error", err)
+		return err
+	}
+
+	log.Info("activated environment", "id", id)
+	return nil
+}
+
+// Deactivate leaves no environment active
+func (e *EnvironmentsManager) Deactivate(ctx context.Context) error {
+	if err := e.DB.SetActiveEnvironment(ctx, 0); err != nil {
+		log.Error("failed to deactivate environments", "error", err)
+		return err
+	}
+	log.Info("deactivated environments")
+	return nil
+}
+
+// SetVariables replaces all variables of the environment
+func (e *EnvironmentsManager) SetVariables(ctx context.Context, id int64, vars map[string]string) (EnvironmentEntity, error) {
+	if err := crud.ValidateID(id); err != nil {
+		log.Warn("environment variables update failed ID validation", "id", id)
+		return EnvironmentEntity{}, crud.ErrInvalidInput
+	}
+	for name := range vars {
+		if !variables.ValidateName(name) {
+			log.Warn("environment variables update failed name validation", "variable", name)
+			return EnvironmentEntity{}, crud.ErrInvalidInput
+		}
+	}
+
+	data, err := variables.Encode(vars)
+	if err != nil {
+		log.Error("failed to marshal environment variables", "id", id, "error", err)
+		return EnvironmentEntity{}, err
+	}
+
+	log.Debug("updating environment variables", "id", id, "count", len(vars))
+	environment, err := e.DB.UpdateEnvironmentVariables(ctx, database.UpdateEnvironmentVariablesParams{
+		Variables: data,
+		ID:        id,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Debug("environment not found for variables update", "id", id)
+			return EnvironmentEntity{}, crud.ErrNotFound
+		}
+		log.Error("failed to update environment variables", "id", id, "error", err)
+		return EnvironmentEntity{}, err
+	}
+	return EnvironmentEntity{Environment: environment}, nil
+}
+
+// SetVariable sets a single variable, keeping the others
+func (e *EnvironmentsManager) SetVariable(ctx context.Context, id int64, name, value string) (EnvironmentEntity, error) {
+	environment, err := e.Read(ctx, id)
+	if err != nil {
+		return EnvironmentEntity{}, err
+	}
+	vars := environment.GetVariables()
+	vars[name] = value
+	return e.SetVariables(ctx, id, vars)
+}
+
+// UnsetVariable removes a single variable, keeping the others
+func (e *EnvironmentsManager) UnsetVariable(ctx context.Context, id int64, name string) (EnvironmentEntity, error) {
+	environment, err := e.Read(ctx, id)
+	if err != nil {
+		return EnvironmentEntity{}, err
+	}
+	vars := environment.GetVariables()
+	delete(vars, name)
+	return e.SetVariables(ctx, id, vars)
+}
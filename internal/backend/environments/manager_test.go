@@ -0,0 +1,145 @@
+package environments
+
+import (
+	"context"
+	"testing"
+
+	"github.com/maniac-en/req/internal/backend/crud"
+	"github.com/maniac-en/req/internal/backend/testutils"
+)
+
+func TestEnvironmentsManagerCRUD(t *testing.T) {
+	db := testutils.SetupTestDB(t, "environments")
+	manager := NewEnvironmentsManager(db)
+	ctx := context.Background()
+
+	t.Run("Create", func(t *testing.T) {
+		environment, err := manager.Create(ctx, "staging")
+		if err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		if environment.GetName() != "staging" {
+			t.Errorf("Expected name 'staging', got %s", environment.GetName())
+		}
+		if environment.IsActive() {
+			t.Error("Expected new environment to be inactive")
+		}
+		if _, err := manager.Create(ctx, "staging"); err == nil {
+			t.Error("Expected duplicate environment name to fail")
+		}
+	})
+
+	t.Run("ReadByName", func(t *testing.T) {
+		created, _ := manager.Create(ctx, "by-name")
+		environment, err := manager.ReadByName(ctx, "by-name")
+		if err != nil {
+			t.Fatalf("ReadByName failed: %v", err)
+		}
+		if environment.GetID() != created.GetID() {
+			t.Errorf("Expected ID %d, got %d", created.GetID(), environment.GetID())
+		}
+		if _, err := manager.ReadByName(ctx, "missing"); err != crud.ErrNotFound {
+			t.Errorf("Expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		created, _ := manager.Create(ctx, "update-me")
+		updated, err := manager.Update(ctx, created.GetID(), "updated")
+		if err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		if updated.GetName() != "updated" {
+			t.Errorf("Expected name 'updated', got %s", updated.GetName())
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		created, _ := manager.Create(ctx, "delete-me")
+		if err := manager.Delete(ctx, created.GetID()); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if _, err := manager.Read(ctx, created.GetID()); err != crud.ErrNotFound {
+			t.Errorf("Expected ErrNotFound after delete, got %v", err)
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		environments, err := manager.List(ctx)
+		if err != nil {
+			t.Fatalf("List failed: %v", err)
+		}
+		if len(environments) < 2 {
+			t.Errorf("Expected at least 2 environments, got %d", len(environments))
+		}
+	})
+}
+
+func TestActiveEnvironment(t *testing.T) {
+	db := testutils.SetupTestDB(t, "environments")
+	manager := NewEnvironmentsManager(db)
+	ctx := context.Background()
+
+	if _, err := manager.GetActive(ctx); err != crud.ErrNotFound {
+		t.Errorf("Expected ErrNotFound without an active environment, got %v", err)
+	}
+
+	dev, _ := manager.Create(ctx, "dev")
+	prod, _ := manager.Create(ctx, "prod")
+
+	if err := manager.Activate(ctx, dev.GetID()); err != nil {
+		t.Fatalf("Activate failed: %v", err)
+	}
+	if err := manager.Activate(ctx, prod.GetID()); err != nil {
+		t.Fatalf("Activate failed: %v", err)
+	}
+	active, err := manager.GetActive(ctx)
+	if err != nil {
+		t.Fatalf("GetActive failed: %v", err)
+	}
+	if active.GetID() != prod.GetID() {
+		t.Errorf("Expected prod to be active, got %s", active.GetName())
+	}
+	dev, _ = manager.Read(ctx, dev.GetID())
+	if dev.IsActive() {
+		t.Error("Expected activating prod to deactivate dev")
+	}
+
+	if err := manager.Activate(ctx, 99999); err != crud.ErrNotFound {
+		t.Errorf("Expected ErrNotFound for missing environment, got %v", err)
+	}
+
+	if err := manager.Deactivate(ctx); err != nil {
+		t.Fatalf("Deactivate failed: %v", err)
+	}
+	if _, err := manager.GetActive(ctx); err != crud.ErrNotFound {
+		t.Errorf("Expected no active environment after Deactivate, got %v", err)
+	}
+}
+
+func TestEnvironmentVariables(t *testing.T) {
+	db := testutils.SetupTestDB(t, "environments")
+	manager := NewEnvironmentsManager(db)
+	ctx := context.Background()
+
+	environment, _ := manager.Create(ctx, "vars")
+	if _, err := manager.SetVariables(ctx, environment.GetID(), map[string]string{"host": "localhost", "port": "8080"}); err != nil {
+		t.Fatalf("SetVariables failed: %v", err)
+	}
+	if _, err := manager.SetVariable(ctx, environment.GetID(), "token", "abc"); err != nil {
+		t.Fatalf("SetVariable failed: %v", err)
+	}
+	updated, err := manager.UnsetVariable(ctx, environment.GetID(), "port")
+	if err != nil {
+		t.Fatalf("UnsetVariable failed: %v", err)
+	}
+
+	vars := updated.GetVariables()
+	if len(vars) != 2 || vars["host"] != "localhost" || vars["token"] != "abc" {
+		t.Errorf("Expected host and token, got %v", vars)
+	}
+
+	if _, err := manager.SetVariable(ctx, environment.GetID(), "{{bad}}", "x"); err != crud.ErrInvalidInput {
+		t.Errorf("Expected ErrInvalidInput for invalid variable name, got %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+package environments
+
+import (
+	"time"
+
+	"github.com/maniac-en/req/internal/backend/crud"
+	"github.com/maniac-en/req/internal/backend/database"
+	"github.com/maniac-en/req/internal/backend/variables"
+)
+
+type EnvironmentEntity struct {
+	database.Environment
+}
+
+func (e EnvironmentEntity) GetID() int64 {
+	return e.ID
+}
+
+func (e EnvironmentEntity) GetName() string {
+	return e.Name
+}
+
+func (e EnvironmentEntity) GetCreatedAt() time.Time {
+	return crud.ParseTimestamp(e.CreatedAt)
+}
+
+func (e EnvironmentEntity) GetUpdatedAt() time.Time {
+	return crud.ParseTimestamp(e.UpdatedAt)
+}
+
+func (e EnvironmentEntity) IsActive() bool {
+	return e.Active == 1
+}
+
+func (e EnvironmentEntity) GetVariables() map[string]string {
+	return variables.Decode(e.Variables)
+}
+
+type EnvironmentsManager struct {
+	DB *database.Queries
+}
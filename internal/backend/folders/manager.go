@@ -0,0 +1,484 @@
+package folders
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/maniac-en/req/internal/backend/crud"
+	"github.com/maniac-en/req/internal/backend/database"
+	"github.com/maniac-en/req/internal/backend/variables"
+	"github.com/maniac-en/req/internal/log"
+)
+
+func NewFoldersManager(db *database.Queries) *FoldersManager {
+	return &FoldersManager{DB: db}
+}
+
+func (f *FoldersManager) Create(ctx context.Context, name string) (FolderEntity, error) {
+	return FolderEntity{}, fmt.Errorf("use CreateFolder to create a folder inside a collection")
+}
+
+// CreateFolder adds a folder to a collection, inside parentID or at the top
+// when parentID is 0. Names are unique among the folder's siblings.
+func (f *FoldersManager) CreateFolder(ctx context.Context, collectionID, parentID int64, name string) (FolderEntity, error) {
+	name = strings.TrimSpace(name)
+	if err := crud.ValidateID(collectionID); err != nil {
+		log.Warn("folder creation failed collection validation", "collection_id", collectionID)
+		return FolderEntity{}, crud.ErrInvalidInput
+	}
+	if err := validateName(name); err != nil {
+		log.Warn("folder creation failed name validation", "name", name, "error", err)
+		return FolderEntity{}, crud.ErrInvalidInput
+	}
+
+	tree, err := f.Tree(ctx, collectionID)
+	if err != nil {
+		return FolderEntity{}, err
+	}
+	if parentID != 0 {
+		if _, ok := tree.Get(parentID); !ok {
+			log.Warn("folder creation failed parent validation", "collection_id", collectionID, "parent_id", parentID)
+			return FolderEntity{}, crud.ErrInvalidInput
+		}
+	}
+	if sibling(tree, parentID, name, 0) {
+		log.Warn("folder creation failed, name taken", "collection_id", collectionID, "parent_id", parentID, "name", name)
+		return FolderEntity{}, crud.ErrInvalidInput
+	}
+
+	log.Debug("creating folder", "collection_id", collectionID, "parent_id", parentID, "name", name)
+	folder, err := f.DB.CreateFolder(ctx, database.CreateFolderParams{
+		CollectionID: collectionID,
+		ParentID:     nullID(parentID),
+		Name:         name,
+	})
+	if err != nil {
+		log.Error("failed to create folder", "collection_id", collectionID, "name", name, "error", err)
+		return FolderEntity{}, err
+	}
+
+	log.Info("created folder", "id", folder.ID, "name", folder.Name, "collection_id", folder.CollectionID)
+	return FolderEntity{Folder: folder}, nil
+}
+
+// CreatePath creates every missing folder along a path such as
+// "users/admin" and returns the innermost one
+func (f *FoldersManager) CreatePath(ctx context.Context, collectionID int64, path string) (FolderEntity, error) {
+	names := SplitPath(path)
+	if len(names) == 0 {
+		return FolderEntity{}, crud.ErrInvalidInput
+	}
+
+	tree, err := f.Tree(ctx, collectionID)
+	if err != nil {
+		return FolderEntity{}, err
+	}
+	var folder FolderEntity
+	for i, name := range names {
+		existing, ok := tree.Find(strings.Join(names[:i+1], Separator))
+		if ok {
+			folder = existing
+			continue
+		}
+		folder, err = f.CreateFolder(ctx, collectionID, folder.ID, name)
+		if err != nil {
+			return FolderEntity{}, err
+		}
+	}
+	return folder, nil
+}
+
+func (f *FoldersManager) Read(ctx context.Context, id int64) (FolderEntity, error) {
+	if err := crud.ValidateID(id); err != nil {
+		log.Warn("folder read failed validation", "id", id)
+		return FolderEntity{}, crud.ErrInvalidInput
+	}
+
+	log.Debug("reading folder", "id", id)
+	folder, err := f.DB.GetFolder(ctx, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Debug("folder not found", "id", id)
+			return FolderEntity{}, crud.ErrNotFound
+		}
+		log.Error("failed to read folder", "id", id, "error", err)
+		return FolderEntity{}, err
+	}
+
+	return FolderEntity{Folder: folder}, nil
+}
+
+// Update renames a folder
+func (f *FoldersManager) Update(ctx context.Context, id int64, name string) (FolderEntity, error) {
+	name = strings.TrimSpace(name)
+	if err := validateName(name); err != nil {
+		log.Warn("folder update failed name validation", "name", name, "error", err)
+		return FolderEntity{}, crud.ErrInvalidInput
+	}
+	folder, err := f.Read(ctx, id)
+	if err != nil {
+		return FolderEntity{}, err
+	}
+	tree, err := f.Tree(ctx, folder.CollectionID)
+	if err != nil {
+		return FolderEntity{}, err
+	}
+	if sibling(tree, folder.GetParentID(), name, id) {
+		log.Warn("folder update failed, name taken", "id", id, "name", name)
+		return FolderEntity{}, crud.ErrInvalidInput
+	}
+
+	log.Debug("renaming folder", "id", id, "name", name)
+	updated, err := f.DB.UpdateFolderName(ctx, database.UpdateFolderNameParams{
+		Name: name,
+		ID:   id,
+	})
+	if err != nil {
+		log.Error("failed to rename folder", "id", id, "name", name, "error", err)
+		return FolderEntity{}, err
+	}
+
+	log.Info("renamed folder", "id", updated.ID, "name", updated.Name)
+	return FolderEntity{Folder: updated}, nil
+}
+
+// Delete moves a folder to the trash together with everything inside it:
+// nested folders and their endpoints. It all goes in one transaction.
+func (f *FoldersManager) Delete(ctx context.Context, id int64) error {
+	return f.DB.InTx(ctx, func(tx *database.Queries) error {
+		return NewFoldersManager(tx).delete(ctx, id)
+	})
+}
+
+func (f *FoldersManager) delete(ctx context.Context, id int64) error {
+	folder, err := f.Read(ctx, id)
+	if err != nil {
+		return err
+	}
+	tree, err := f.Tree(ctx, folder.CollectionID)
+	if err != nil {
+		return err
+	}
+
+	// everything shares one timestamp so it can be restored as a whole
+	deletedAt := sql.NullString{String: crud.FormatTimestamp(time.Now()), Valid: true}
+	log.Debug("trashing folder", "id", id)
+	subtree := tree.Subtree(id)
+	for _, nested := range subtree {
+		if err := f.DB.TrashEndpointsByFolder(ctx, database.TrashEndpointsByFolderParams{
+			DeletedAt: deletedAt,
+			FolderID:  nullID(nested.ID),
+		}); err != nil {
+			log.Error("failed to trash folder endpoints", "id", nested.ID, "error", err)
+			return err
+		}
+		if err := f.DB.TrashFolder(ctx, database.TrashFolderParams{
+			DeletedAt: deletedAt,
+			ID:        nested.ID,
+		}); err != nil {
+			log.Error("failed to trash folder", "id", nested.ID, "error", err)
+			return err
+		}
+	}
+
+	log.Info("trashed folder", "id", id, "folders", len(subtree))
+	return nil
+}
+
+// Restore takes a folder out of the trash with the folders and endpoints
+// that were deleted along with it. If its parent is gone, it is restored at
+// the top of its collection, and it is renamed if a sibling took its name.
+func (f *FoldersManager) Restore(ctx context.Context, id int64) (FolderEntity, error) {
+	if err := crud.ValidateID(id); err != nil {
+		log.Warn("folder restore failed validation", "id", id)
+		return FolderEntity{}, crud.ErrInvalidInput
+	}
+
+	trashed, err := f.DB.ListTrashedFolders(ctx)
+	if err != nil {
+		log.Error("failed to list trashed folders", "error", err)
+		return FolderEntity{}, err
+	}
+	var folder database.Folder
+	batch := make(map[int64][]database.Folder)
+	for _, candidate := range trashed {
+		if candidate.ID == id {
+			folder = candidate
+		}
+	}
+	if folder.ID == 0 {
+		log.Debug("folder not found in trash", "id", id)
+		return FolderEntity{}, crud.ErrNotFound
+	}
+	for _, candidate := range trashed {
+		if candidate.CollectionID == folder.CollectionID && candidate.DeletedAt == folder.DeletedAt && candidate.ParentID.Valid {
+			batch[candidate.ParentID.Int64] = append(batch[candidate.ParentID.Int64], candidate)
+		}
+	}
+
+	tree, err := f.Tree(ctx, folder.CollectionID)
+	if err != nil {
+		return FolderEntity{}, err
+	}
+	parentID := folder.ParentID
+	if _, ok := tree.Get(parentID.Int64); !ok {
+		parentID = sql.NullInt64{}
+	}
+
+	log.Debug("restoring folder", "id", id)
+	restored, err := f.DB.RestoreFolder(ctx, database.RestoreFolderParams{ParentID: parentID, ID: id})
+	if err != nil {
+		log.Error("failed to restore folder", "id", id, "error", err)
+		return FolderEntity{}, err
+	}
+	if sibling(tree, parentID.Int64, restored.Name, id) {
+		var names []string
+		for _, child := range tree.Children(parentID.Int64) {
+			names = append(names, child.Name)
+		}
+		restored, err = f.DB.UpdateFolderName(ctx, database.UpdateFolderNameParams{
+			Name: crud.CopyName(restored.Name, names),
+			ID:   id,
+		})
+		if err != nil {
+			log.Error("failed to rename restored folder", "id", id, "error", err)
+			return FolderEntity{}, err
+		}
+	}
+
+	// the folders and endpoints trashed with it, outermost first
+	queue := []int64{id}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if err := f.DB.RestoreEndpointsByFolder(ctx, database.RestoreEndpointsByFolderParams{
+			FolderID:  nullID(current),
+			DeletedAt: folder.DeletedAt,
+		}); err != nil {
+			log.Error("failed to restore folder endpoints", "id", current, "error", err)
+			return FolderEntity{}, err
+		}
+		for _, child := range batch[current] {
+			if _, err := f.DB.RestoreFolder(ctx, database.RestoreFolderParams{ParentID: child.ParentID, ID: child.ID}); err != nil {
+				log.Error("failed to restore folder", "id", child.ID, "error", err)
+				return FolderEntity{}, err
+			}
+			queue = append(queue, child.ID)
+		}
+	}
+
+	log.Info("restored folder", "id", id)
+	return FolderEntity{Folder: restored}, nil
+}
+
+func (f *FoldersManager) List(ctx context.Context) ([]FolderEntity, error) {
+	return nil, fmt.Errorf("use ListByCollection to list the folders of a collection")
+}
+
+func (f *FoldersManager) ListByCollection(ctx context.Context, collectionID int64) ([]FolderEntity, error) {
+	if err := crud.ValidateID(collectionID); err != nil {
+		log.Warn("folder list failed collection validation", "collection_id", collectionID)
+		return nil, crud.ErrInvalidInput
+	}
+
+	folders, err := f.DB.ListFoldersByCollection(ctx, collectionID)
+	if err != nil {
+		log.Error("failed to list folders", "collection_id", collectionID, "error", err)
+		return nil, err
+	}
+
+	entities := make([]FolderEntity, len(folders))
+	for i, folder := range folders {
+		entities[i] = FolderEntity{Folder: folder}
+	}
+	return entities, nil
+}
+
+// Tree loads the folder hierarchy of a collection
+func (f *FoldersManager) Tree(ctx context.Context, collectionID int64) (*Tree, error) {
+	all, err := f.ListByCollection(ctx, collectionID)
+	if err != nil {
+		return nil, err
+	}
+	return NewTree(all), nil
+}
+
+// Move puts a folder inside parentID, or at the top of its collection when
+// parentID is 0. A folder can't be moved into itself or one of its own
+// folders.
+func (f *FoldersManager) Move(ctx context.Context, id, parentID int64) (FolderEntity, error) {
+	folder, err := f.Read(ctx, id)
+	if err != nil {
+		return FolderEntity{}, err
+	}
+	tree, err := f.Tree(ctx, folder.CollectionID)
+	if err != nil {
+		return FolderEntity{}, err
+	}
+	if parentID != 0 {
+		if _, ok := tree.Get(parentID); !ok {
+			log.Warn("folder move failed parent validation", "id", id, "parent_id", parentID)
+			return FolderEntity{}, crud.ErrInvalidInput
+		}
+		if tree.Contains(id, parentID) {
+			log.Warn("folder move failed, target is inside the folder", "id", id, "parent_id", parentID)
+			return FolderEntity{}, fmt.Errorf("can't move %s into itself: %w", tree.Path(id), crud.ErrInvalidInput)
+		}
+	}
+	if sibling(tree, parentID, folder.Name, id) {
+		log.Warn("folder move failed, name taken", "id", id, "parent_id", parentID)
+		return FolderEntity{}, fmt.Errorf("a folder named %s already exists there: %w", folder.Name, crud.ErrInvalidInput)
+	}
+
+	log.Debug("moving folder", "id", id, "parent_id", parentID)
+	moved, err := f.DB.UpdateFolderParent(ctx, database.UpdateFolderParentParams{
+		ParentID: nullID(parentID),
+		ID:       id,
+	})
+	if err != nil {
+		log.Error("failed to move folder", "id", id, "parent_id", parentID, "error", err)
+		return FolderEntity{}, err
+	}
+
+	log.Info("moved folder", "id", id, "parent_id", parentID)
+	return FolderEntity{Folder: moved}, nil
+}
+
+// SetHeaders replaces the headers sent with every request in the folder
+func (f *FoldersManager) SetHeaders(ctx context.Context, id int64, headers map[string]string) (FolderEntity, error) {
+	if err := crud.ValidateID(id); err != nil {
+		log.Warn("folder headers update failed ID validation", "id", id)
+		return FolderEntity{}, crud.ErrInvalidInput
+	}
+	for name := range headers {
+		if strings.TrimSpace(name) == "" {
+			log.Warn("folder headers update failed name validation", "id", id)
+			return FolderEntity{}, crud.ErrInvalidInput
+		}
+	}
+
+	data, err := json.Marshal(headers)
+	if err != nil {
+		log.Error("failed to marshal folder headers", "id", id, "error", err)
+		return FolderEntity{}, err
+	}
+
+	log.Debug("updating folder headers", "id", id, "count", len(headers))
+	folder, err := f.DB.UpdateFolderHeaders(ctx, database.UpdateFolderHeadersParams{
+		Headers: string(data),
+		ID:      id,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Debug("folder not found for headers update", "id", id)
+			return FolderEntity{}, crud.ErrNotFound
+		}
+		log.Error("failed to update folder headers", "id", id, "error", err)
+		return FolderEntity{}, err
+	}
+	return FolderEntity{Folder: folder}, nil
+}
+
+// SetVariables replaces all variables of the folder
+func (f *FoldersManager) SetVariables(ctx context.Context, id int64, vars map[string]string) (FolderEntity, error) {
+	if err := crud.ValidateID(id); err != nil {
+		log.Warn("folder variables update failed ID validation", "id", id)
+		return FolderEntity{}, crud.ErrInvalidInput
+	}
+	for name := range vars {
+		if !variables.ValidateName(name) {
+			log.Warn("folder variables update failed name validation", "variable", name)
+			return FolderEntity{}, crud.ErrInvalidInput
+		}
+	}
+
+	data, err := variables.Encode(vars)
+	if err != nil {
+		log.Error("failed to marshal folder variables", "id", id, "error", err)
+		return FolderEntity{}, err
+	}
+
+	log.Debug("updating folder variables", "id", id, "count", len(vars))
+	folder, err := f.DB.UpdateFolderVariables(ctx, database.UpdateFolderVariablesParams{
+		Variables: data,
+		ID:        id,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Debug("folder not found for variables update", "id", id)
+			return FolderEntity{}, crud.ErrNotFound
+		}
+		log.Error("failed to update folder variables", "id", id, "error", err)
+		return FolderEntity{}, err
+	}
+	return FolderEntity{Folder: folder}, nil
+}
+
+// Inherited returns the headers and variables a request in the folder
+// inherits from it and every folder around it. Inner folders win over outer
+// ones; header names are compared ignoring case.
+func (f *FoldersManager) Inherited(ctx context.Context, id int64) (headers, vars map[string]string, err error) {
+	folder, err := f.Read(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	tree, err := f.Tree(ctx, folder.CollectionID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	headers, vars = map[string]string{}, map[string]string{}
+	for _, ancestor := range tree.Ancestors(id) {
+		headers = MergeHeaders(headers, ancestor.GetHeaders())
+		vars = variables.Merge(vars, ancestor.GetVariables())
+	}
+	return headers, vars, nil
+}
+
+// MergeHeaders returns base with overrides applied on top. A header in
+// overrides replaces one in base with the same name in any case.
+func MergeHeaders(base, overrides map[string]string) map[string]string {
+	merged := make(map[string]string, len(base)+len(overrides))
+	for name, value := range base {
+		merged[name] = value
+	}
+	for name, value := range overrides {
+		for existing := range merged {
+			if http.CanonicalHeaderKey(existing) == http.CanonicalHeaderKey(name) {
+				delete(merged, existing)
+			}
+		}
+		merged[name] = value
+	}
+	return merged
+}
+
+func validateName(name string) error {
+	if err := crud.ValidateName(name); err != nil {
+		return err
+	}
+	if strings.Contains(name, Separator) {
+		return fmt.Errorf("folder names cannot contain %q", Separator)
+	}
+	return nil
+}
+
+// sibling reports whether parentID already holds a folder called name,
+// other than the folder with ID except
+func sibling(tree *Tree, parentID int64, name string, except int64) bool {
+	for _, child := range tree.Children(parentID) {
+		if child.ID != except && strings.EqualFold(child.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func nullID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id != 0}
+}
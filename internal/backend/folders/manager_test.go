@@ -0,0 +1,200 @@
+package folders
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/maniac-en/req/internal/backend/crud"
+	"github.com/maniac-en/req/internal/backend/database"
+	"github.com/maniac-en/req/internal/backend/endpoints"
+	"github.com/maniac-en/req/internal/backend/testutils"
+)
+
+func setupManager(t *testing.T) (*FoldersManager, *database.Queries, int64) {
+	t.Helper()
+	db := testutils.SetupTestDB(t, "collections", "endpoints", "folders")
+	return NewFoldersManager(db), db, testutils.CreateTestCollection(t, db, "Folders Collection")
+}
+
+func TestFoldersManagerCRUD(t *testing.T) {
+	manager, _, collectionID := setupManager(t)
+	ctx := context.Background()
+
+	users, err := manager.CreateFolder(ctx, collectionID, 0, "users")
+	if err != nil {
+		t.Fatalf("CreateFolder failed: %v", err)
+	}
+	admin, err := manager.CreateFolder(ctx, collectionID, users.ID, "admin")
+	if err != nil {
+		t.Fatalf("CreateFolder failed: %v", err)
+	}
+	if admin.GetParentID() != users.ID {
+		t.Errorf("Expected parent %d, got %d", users.ID, admin.GetParentID())
+	}
+
+	if _, err := manager.CreateFolder(ctx, collectionID, 0, "Users"); !errors.Is(err, crud.ErrInvalidInput) {
+		t.Errorf("Expected a duplicate sibling name to fail, got %v", err)
+	}
+	if _, err := manager.CreateFolder(ctx, collectionID, 0, "a/b"); !errors.Is(err, crud.ErrInvalidInput) {
+		t.Errorf("Expected names with a separator to fail, got %v", err)
+	}
+	if _, err := manager.CreateFolder(ctx, collectionID, 999, "orphan"); !errors.Is(err, crud.ErrInvalidInput) {
+		t.Errorf("Expected an unknown parent to fail, got %v", err)
+	}
+	if _, err := manager.CreateFolder(ctx, collectionID, users.ID, "users"); err != nil {
+		t.Errorf("Expected the same name to be allowed at another level, got %v", err)
+	}
+
+	renamed, err := manager.Update(ctx, admin.ID, "staff")
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if renamed.Name != "staff" {
+		t.Errorf("Expected name 'staff', got %s", renamed.Name)
+	}
+	if _, err := manager.Read(ctx, 999); err != crud.ErrNotFound {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestTreePaths(t *testing.T) {
+	manager, _, collectionID := setupManager(t)
+	ctx := context.Background()
+
+	leaf, err := manager.CreatePath(ctx, collectionID, "/billing//invoices/drafts/")
+	if err != nil {
+		t.Fatalf("CreatePath failed: %v", err)
+	}
+	again, err := manager.CreatePath(ctx, collectionID, "Billing/Invoices/drafts")
+	if err != nil {
+		t.Fatalf("CreatePath failed: %v", err)
+	}
+	if again.ID != leaf.ID {
+		t.Errorf("Expected existing folders to be reused, got %d and %d", leaf.ID, again.ID)
+	}
+
+	tree, err := manager.Tree(ctx, collectionID)
+	if err != nil {
+		t.Fatalf("Tree failed: %v", err)
+	}
+	if path := tree.Path(leaf.ID); path != "billing/invoices/drafts" {
+		t.Errorf("Expected path billing/invoices/drafts, got %s", path)
+	}
+	if found, ok := tree.Find("BILLING/invoices/drafts"); !ok || found.ID != leaf.ID {
+		t.Errorf("Expected to find the folder by path, got %v %v", found, ok)
+	}
+	if _, ok := tree.Find("billing/missing"); ok {
+		t.Error("Expected a missing path not to be found")
+	}
+	billing, _ := tree.Find("billing")
+	if !tree.Contains(billing.ID, leaf.ID) || tree.Contains(leaf.ID, billing.ID) {
+		t.Error("Expected billing to contain drafts and not the other way around")
+	}
+	if len(tree.Subtree(billing.ID)) != 3 {
+		t.Errorf("Expected 3 folders in the subtree, got %d", len(tree.Subtree(billing.ID)))
+	}
+}
+
+func TestMove(t *testing.T) {
+	manager, _, collectionID := setupManager(t)
+	ctx := context.Background()
+
+	users, _ := manager.CreateFolder(ctx, collectionID, 0, "users")
+	admin, _ := manager.CreateFolder(ctx, collectionID, users.ID, "admin")
+	billing, _ := manager.CreateFolder(ctx, collectionID, 0, "billing")
+
+	if _, err := manager.Move(ctx, users.ID, admin.ID); !errors.Is(err, crud.ErrInvalidInput) {
+		t.Errorf("Expected moving a folder into its own subfolder to fail, got %v", err)
+	}
+	if _, err := manager.Move(ctx, users.ID, users.ID); !errors.Is(err, crud.ErrInvalidInput) {
+		t.Errorf("Expected moving a folder into itself to fail, got %v", err)
+	}
+
+	moved, err := manager.Move(ctx, admin.ID, billing.ID)
+	if err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	if moved.GetParentID() != billing.ID {
+		t.Errorf("Expected parent %d, got %d", billing.ID, moved.GetParentID())
+	}
+	moved, err = manager.Move(ctx, admin.ID, 0)
+	if err != nil {
+		t.Fatalf("Move failed: %v", err)
+	}
+	if moved.GetParentID() != 0 {
+		t.Errorf("Expected the folder at the top, got parent %d", moved.GetParentID())
+	}
+
+	manager.CreateFolder(ctx, collectionID, users.ID, "admin")
+	if _, err := manager.Move(ctx, admin.ID, users.ID); !errors.Is(err, crud.ErrInvalidInput) {
+		t.Errorf("Expected moving next to a folder with the same name to fail, got %v", err)
+	}
+}
+
+func TestDeleteRemovesContents(t *testing.T) {
+	manager, db, collectionID := setupManager(t)
+	endpointsManager := endpoints.NewEndpointsManager(db)
+	ctx := context.Background()
+
+	users, _ := manager.CreateFolder(ctx, collectionID, 0, "users")
+	admin, _ := manager.CreateFolder(ctx, collectionID, users.ID, "admin")
+	billing, _ := manager.CreateFolder(ctx, collectionID, 0, "billing")
+	for _, folderID := range []int64{0, users.ID, admin.ID, billing.ID} {
+		if _, err := endpointsManager.CreateEndpoint(ctx, endpoints.EndpointData{
+			CollectionID: collectionID,
+			FolderID:     folderID,
+			Name:         "endpoint",
+			Method:       "GET",
+		}); err != nil {
+			t.Fatalf("CreateEndpoint failed: %v", err)
+		}
+	}
+
+	if err := manager.Delete(ctx, users.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	remaining, _ := manager.ListByCollection(ctx, collectionID)
+	if len(remaining) != 1 || remaining[0].ID != billing.ID {
+		t.Errorf("Expected only billing to remain, got %v", remaining)
+	}
+	left, _ := endpointsManager.ListByCollection(ctx, collectionID)
+	if len(left) != 2 {
+		t.Errorf("Expected the endpoints of deleted folders to be deleted, got %d left", len(left))
+	}
+}
+
+func TestInherited(t *testing.T) {
+	manager, _, collectionID := setupManager(t)
+	ctx := context.Background()
+
+	users, _ := manager.CreateFolder(ctx, collectionID, 0, "users")
+	admin, _ := manager.CreateFolder(ctx, collectionID, users.ID, "admin")
+	if _, err := manager.SetHeaders(ctx, users.ID, map[string]string{"Authorization": "Bearer {{token}}", "X-Team": "users"}); err != nil {
+		t.Fatalf("SetHeaders failed: %v", err)
+	}
+	if _, err := manager.SetHeaders(ctx, admin.ID, map[string]string{"authorization": "Bearer {{adminToken}}"}); err != nil {
+		t.Fatalf("SetHeaders failed: %v", err)
+	}
+	if _, err := manager.SetVariables(ctx, users.ID, map[string]string{"base": "/users", "page": "1"}); err != nil {
+		t.Fatalf("SetVariables failed: %v", err)
+	}
+	if _, err := manager.SetVariables(ctx, admin.ID, map[string]string{"base": "/admin"}); err != nil {
+		t.Fatalf("SetVariables failed: %v", err)
+	}
+	if _, err := manager.SetVariables(ctx, admin.ID, map[string]string{"not valid": "x"}); !errors.Is(err, crud.ErrInvalidInput) {
+		t.Errorf("Expected invalid variable names to fail, got %v", err)
+	}
+
+	headers, vars, err := manager.Inherited(ctx, admin.ID)
+	if err != nil {
+		t.Fatalf("Inherited failed: %v", err)
+	}
+	expectedHeaders := map[string]string{"authorization": "Bearer {{adminToken}}", "X-Team": "users"}
+	if len(headers) != len(expectedHeaders) || headers["authorization"] != expectedHeaders["authorization"] || headers["X-Team"] != "users" {
+		t.Errorf("Expected %v, got %v", expectedHeaders, headers)
+	}
+	if vars["base"] != "/admin" || vars["page"] != "1" {
+		t.Errorf("Expected inner variables to win, got %v", vars)
+	}
+}
@@ -0,0 +1,63 @@
+// Package folders groups the endpoints of a collection into a hierarchy.
+// Folders carry headers and variables that every request inside them
+// inherits, so shared auth can be set once per folder.
+package folders
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/maniac-en/req/internal/backend/crud"
+	"github.com/maniac-en/req/internal/backend/database"
+	"github.com/maniac-en/req/internal/backend/variables"
+)
+
+// Separator joins folder names into paths such as "users/admin"
+const Separator = "/"
+
+type FolderEntity struct {
+	database.Folder
+}
+
+func (f FolderEntity) GetID() int64 {
+	return f.ID
+}
+
+func (f FolderEntity) GetName() string {
+	return f.Name
+}
+
+func (f FolderEntity) GetCreatedAt() time.Time {
+	return crud.ParseTimestamp(f.CreatedAt)
+}
+
+func (f FolderEntity) GetUpdatedAt() time.Time {
+	return crud.ParseTimestamp(f.UpdatedAt)
+}
+
+// GetParentID returns the ID of the enclosing folder, or 0 for folders at
+// the top of their collection
+func (f FolderEntity) GetParentID() int64 {
+	if !f.ParentID.Valid {
+		return 0
+	}
+	return f.ParentID.Int64
+}
+
+// GetHeaders decodes the headers sent with every request in the folder,
+// returning an empty map when they are malformed
+func (f FolderEntity) GetHeaders() map[string]string {
+	headers := map[string]string{}
+	if err := json.Unmarshal([]byte(f.Headers), &headers); err != nil {
+		return map[string]string{}
+	}
+	return headers
+}
+
+func (f FolderEntity) GetVariables() map[string]string {
+	return variables.Decode(f.Variables)
+}
+
+type FoldersManager struct {
+	DB *database.Queries
+}
@@ -0,0 +1,132 @@
+package folders
+
+import (
+	"sort"
+	"strings"
+)
+
+// Tree indexes the folders of a collection for walking the hierarchy
+type Tree struct {
+	folders  map[int64]FolderEntity
+	children map[int64][]FolderEntity
+}
+
+func NewTree(all []FolderEntity) *Tree {
+	tree := &Tree{
+		folders:  make(map[int64]FolderEntity, len(all)),
+		children: map[int64][]FolderEntity{},
+	}
+	for _, folder := range all {
+		tree.folders[folder.ID] = folder
+	}
+	for _, folder := range all {
+		parentID := folder.GetParentID()
+		// folders whose parent is gone are shown at the top
+		if _, ok := tree.folders[parentID]; !ok {
+			parentID = 0
+		}
+		tree.children[parentID] = append(tree.children[parentID], folder)
+	}
+	for _, children := range tree.children {
+		sort.Slice(children, func(i, j int) bool {
+			return strings.ToLower(children[i].Name) < strings.ToLower(children[j].Name)
+		})
+	}
+	return tree
+}
+
+func (t *Tree) Get(id int64) (FolderEntity, bool) {
+	folder, ok := t.folders[id]
+	return folder, ok
+}
+
+// Children returns the folders directly inside parentID, sorted by name.
+// A parentID of 0 returns the top-level folders.
+func (t *Tree) Children(parentID int64) []FolderEntity {
+	return t.children[parentID]
+}
+
+// Ancestors returns the chain of folders from the top of the collection
+// down to and including id
+func (t *Tree) Ancestors(id int64) []FolderEntity {
+	var chain []FolderEntity
+	seen := map[int64]bool{}
+	for folder, ok := t.folders[id]; ok && !seen[folder.ID]; folder, ok = t.folders[folder.GetParentID()] {
+		seen[folder.ID] = true
+		chain = append([]FolderEntity{folder}, chain...)
+	}
+	return chain
+}
+
+// Path returns the folder's names joined from the top, such as
+// "users/admin". The top of the collection has an empty path.
+func (t *Tree) Path(id int64) string {
+	ancestors := t.Ancestors(id)
+	names := make([]string, len(ancestors))
+	for i, folder := range ancestors {
+		names[i] = folder.Name
+	}
+	return strings.Join(names, Separator)
+}
+
+// Find looks a folder up by path, ignoring case. An empty path is the top of
+// the collection and returns false.
+func (t *Tree) Find(path string) (FolderEntity, bool) {
+	var folder FolderEntity
+	var parentID int64
+	names := SplitPath(path)
+	for _, name := range names {
+		found := false
+		for _, child := range t.children[parentID] {
+			if strings.EqualFold(child.Name, name) {
+				folder, parentID, found = child, child.ID, true
+				break
+			}
+		}
+		if !found {
+			return FolderEntity{}, false
+		}
+	}
+	return folder, len(names) > 0
+}
+
+// Contains reports whether id is ancestorID or nested anywhere below it
+func (t *Tree) Contains(ancestorID, id int64) bool {
+	for _, folder := range t.Ancestors(id) {
+		if folder.ID == ancestorID {
+			return true
+		}
+	}
+	return false
+}
+
+// Subtree returns the folder and every folder nested below it
+func (t *Tree) Subtree(id int64) []FolderEntity {
+	folder, ok := t.folders[id]
+	if !ok {
+		return nil
+	}
+	subtree := []FolderEntity{folder}
+	seen := map[int64]bool{id: true}
+	for i := 0; i < len(subtree); i++ {
+		for _, child := range t.children[subtree[i].ID] {
+			if !seen[child.ID] {
+				seen[child.ID] = true
+				subtree = append(subtree, child)
+			}
+		}
+	}
+	return subtree
+}
+
+// SplitPath splits a folder path into names, ignoring empty segments so
+// "/users//admin/" is the same as "users/admin"
+func SplitPath(path string) []string {
+	var names []string
+	for _, name := range strings.Split(path, Separator) {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,251 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/grpc"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const reflectionServiceName = "grpc.reflection.v1.ServerReflection"
+
+// descriptorSource resolves service definitions either from local .proto
+// files or from a running server via reflection.
+type descriptorSource interface {
+	ListServices() ([]protoreflect.ServiceDescriptor, error)
+	FindMethod(fullName string) (protoreflect.MethodDescriptor, error)
+}
+
+type fileSource struct {
+	files []protoreflect.FileDescriptor
+}
+
+func (f *fileSource) ListServices() ([]protoreflect.ServiceDescriptor, error) {
+	var services []protoreflect.ServiceDescriptor
+	for _, file := range f.files {
+		for i := 0; i < file.Services().Len(); i++ {
+			services = append(services, file.Services().Get(i))
+		}
+	}
+	return services, nil
+}
+
+func (f *fileSource) FindMethod(fullName string) (protoreflect.MethodDescriptor, error) {
+	serviceName, methodName, err := splitMethodName(fullName)
+	if err != nil {
+		return nil, err
+	}
+	services, err := f.ListServices()
+	if err != nil {
+		return nil, err
+	}
+	for _, service := range services {
+		if string(service.FullName()) != serviceName {
+			continue
+		}
+		if method := service.Methods().ByName(protoreflect.Name(methodName)); method != nil {
+			return method, nil
+		}
+		return nil, fmt.Errorf("method %q not found in service %q", methodName, serviceName)
+	}
+	return nil, fmt.Errorf("service %q not found", serviceName)
+}
+
+// newProtoFileSource compiles the given .proto files. When no import paths
+// are provided the directory of each file is used.
+func newProtoFileSource(ctx context.Context, protoFiles, importPaths []string) (*fileSource, error) {
+	names := make([]string, len(protoFiles))
+	paths := slices.Clone(importPaths)
+	for i, file := range protoFiles {
+		if len(importPaths) > 0 {
+			names[i] = file
+			continue
+		}
+		dir := filepath.Dir(file)
+		if !slices.Contains(paths, dir) {
+			paths = append(paths, dir)
+		}
+		names[i] = filepath.Base(file)
+	}
+
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			ImportPaths: paths,
+		}),
+	}
+	results, err := compiler.Compile(ctx, names...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile proto files: %w", err)
+	}
+
+	files := make([]protoreflect.FileDescriptor, len(results))
+	for i, result := range results {
+		files[i] = result
+	}
+	return &fileSource{files: files}, nil
+}
+
+// newReflectionSource downloads the descriptors of every service exposed by
+// the server through the gRPC reflection service.
+func newReflectionSource(ctx context.Context, conn *grpc.ClientConn) (*fileSource, error) {
+	stream, err := rpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open reflection stream: %w", err)
+	}
+	defer func() {
+		_ = stream.CloseSend()
+	}()
+
+	call := func(req *rpb.ServerReflectionRequest) (*rpb.ServerReflectionResponse, error) {
+		if err := stream.Send(req); err != nil {
+			return nil, fmt.Errorf("reflection request failed: %w", err)
+		}
+		resp, err := stream.Recv()
+		if err != nil {
+			return nil, fmt.Errorf("reflection request failed: %w", err)
+		}
+		if errResp := resp.GetErrorResponse(); errResp != nil {
+			return nil, fmt.Errorf("reflection error: %s", errResp.GetErrorMessage())
+		}
+		return resp, nil
+	}
+
+	resp, err := call(&rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_ListServices{},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	protos := map[string]*descriptorpb.FileDescriptorProto{}
+	collect := func(resp *rpb.ServerReflectionResponse) ([]string, error) {
+		var names []string
+		for _, raw := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+			fdp := &descriptorpb.FileDescriptorProto{}
+			if err := proto.Unmarshal(raw, fdp); err != nil {
+				return nil, fmt.Errorf("failed to decode file descriptor: %w", err)
+			}
+			protos[fdp.GetName()] = fdp
+			names = append(names, fdp.GetName())
+		}
+		return names, nil
+	}
+
+	var roots []string
+	for _, service := range resp.GetListServicesResponse().GetService() {
+		if service.GetName() == reflectionServiceName || strings.HasPrefix(service.GetName(), "grpc.reflection.") {
+			continue
+		}
+		fileResp, err := call(&rpb.ServerReflectionRequest{
+			MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: service.GetName()},
+		})
+		if err != nil {
+			return nil, err
+		}
+		names, err := collect(fileResp)
+		if err != nil {
+			return nil, err
+		}
+		if len(names) > 0 {
+			roots = append(roots, names[0])
+		}
+	}
+
+	// fetch any dependency the server did not send along with the service files
+	pending := slices.Clone(roots)
+	for len(pending) > 0 {
+		name := pending[0]
+		pending = pending[1:]
+		fdp, ok := protos[name]
+		if !ok {
+			if _, err := protoregistry.GlobalFiles.FindFileByPath(name); err == nil {
+				continue
+			}
+			fileResp, err := call(&rpb.ServerReflectionRequest{
+				MessageRequest: &rpb.ServerReflectionRequest_FileByFilename{FileByFilename: name},
+			})
+			if err != nil {
+				return nil, err
+			}
+			if _, err := collect(fileResp); err != nil {
+				return nil, err
+			}
+			if fdp, ok = protos[name]; !ok {
+				return nil, fmt.Errorf("server did not return descriptor for %q", name)
+			}
+		}
+		pending = append(pending, fdp.GetDependency()...)
+	}
+
+	registry, err := buildRegistry(protos)
+	if err != nil {
+		return nil, err
+	}
+
+	source := &fileSource{}
+	for _, name := range slices.Compact(slices.Sorted(slices.Values(roots))) {
+		file, err := registry.FindFileByPath(name)
+		if err != nil {
+			return nil, err
+		}
+		source.files = append(source.files, file)
+	}
+	return source, nil
+}
+
+// buildRegistry links raw file descriptors in dependency order, falling back
+// to the well-known types compiled into the binary.
+func buildRegistry(protos map[string]*descriptorpb.FileDescriptorProto) (*protoregistry.Files, error) {
+	files := new(protoregistry.Files)
+
+	var register func(name string) error
+	register = func(name string) error {
+		if _, err := files.FindFileByPath(name); err == nil {
+			return nil
+		}
+		fdp, ok := protos[name]
+		if !ok {
+			global, err := protoregistry.GlobalFiles.FindFileByPath(name)
+			if err != nil {
+				return fmt.Errorf("missing descriptor for %q", name)
+			}
+			return files.RegisterFile(global)
+		}
+		for _, dep := range fdp.GetDependency() {
+			if err := register(dep); err != nil {
+				return err
+			}
+		}
+		file, err := protodesc.NewFile(fdp, files)
+		if err != nil {
+			return fmt.Errorf("failed to link %q: %w", name, err)
+		}
+		return files.RegisterFile(file)
+	}
+
+	for name := range protos {
+		if err := register(name); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+func splitMethodName(fullName string) (string, string, error) {
+	fullName = strings.TrimPrefix(strings.TrimSpace(fullName), "/")
+	idx := strings.LastIndex(fullName, "/")
+	if idx <= 0 || idx == len(fullName)-1 {
+		return "", "", fmt.Errorf("invalid method name %q, expected package.Service/Method", fullName)
+	}
+	return fullName[:idx], fullName[idx+1:], nil
+}
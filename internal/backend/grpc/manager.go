@@ -0,0 +1,344 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/maniac-en/req/internal/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func NewGRPCManager() *GRPCManager {
+	return &GRPCManager{
+		Timeout: 30 * time.Second,
+	}
+}
+
+// ParseTarget converts an endpoint URL into a dial target. The grpc:// scheme
+// selects a plaintext connection, grpcs:// or no scheme selects TLS.
+func ParseTarget(rawURL string) (string, bool) {
+	rawURL = strings.TrimSpace(rawURL)
+	switch {
+	case strings.HasPrefix(rawURL, "grpc://"):
+		return strings.TrimSuffix(strings.TrimPrefix(rawURL, "grpc://"), "/"), true
+	case strings.HasPrefix(rawURL, "grpcs://"):
+		return strings.TrimSuffix(strings.TrimPrefix(rawURL, "grpcs://"), "/"), false
+	}
+	return strings.TrimSuffix(rawURL, "/"), false
+}
+
+func (g *GRPCManager) ValidateRequest(req *Request) error {
+	if strings.TrimSpace(req.Target) == "" {
+		log.Error("invalid gRPC target", "target", req.Target)
+		return fmt.Errorf("target cannot be empty")
+	}
+	if _, _, err := splitMethodName(req.Method); err != nil {
+		log.Error("invalid gRPC method", "method", req.Method, "error", err)
+		return err
+	}
+	return nil
+}
+
+// ListMethods returns every method exposed by the target, using the request's
+// proto files when present and server reflection otherwise.
+func (g *GRPCManager) ListMethods(ctx context.Context, req *Request) ([]MethodInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.Timeout)
+	defer cancel()
+
+	source, closeConn, err := g.resolveSource(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer closeConn()
+
+	services, err := source.ListServices()
+	if err != nil {
+		return nil, err
+	}
+
+	var methods []MethodInfo
+	for _, service := range services {
+		for i := 0; i < service.Methods().Len(); i++ {
+			methods = append(methods, newMethodInfo(service.Methods().Get(i)))
+		}
+	}
+	log.Debug("listed gRPC methods", "target", req.Target, "count", len(methods))
+	return methods, nil
+}
+
+// RequestTemplate returns a JSON skeleton of the method's input message with
+// every field present, ready to be filled in.
+func (g *GRPCManager) RequestTemplate(ctx context.Context, req *Request) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.Timeout)
+	defer cancel()
+
+	source, closeConn, err := g.resolveSource(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	defer closeConn()
+
+	method, err := source.FindMethod(req.Method)
+	if err != nil {
+		return "", err
+	}
+
+	template, err := protojson.MarshalOptions{
+		Multiline:       true,
+		Indent:          "  ",
+		EmitUnpopulated: true,
+	}.Marshal(dynamicpb.NewMessage(method.Input()))
+	if err != nil {
+		return "", fmt.Errorf("failed to build request template: %w", err)
+	}
+	return string(template), nil
+}
+
+func (g *GRPCManager) ExecuteRequest(ctx context.Context, req *Request) (*Response, error) {
+	if err := g.ValidateRequest(req); err != nil {
+		return nil, err
+	}
+
+	log.Debug("executing gRPC request", "target", req.Target, "method", req.Method)
+
+	ctx, cancel := context.WithTimeout(ctx, g.Timeout)
+	defer cancel()
+
+	conn, err := g.dial(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := conn.Close(); closeErr != nil {
+			log.Error("failed to close gRPC connection", "error", closeErr)
+		}
+	}()
+
+	source, err := loadSource(ctx, req, conn)
+	if err != nil {
+		log.Error("failed to resolve gRPC descriptors", "target", req.Target, "error", err)
+		return nil, err
+	}
+
+	method, err := source.FindMethod(req.Method)
+	if err != nil {
+		log.Error("gRPC method not found", "method", req.Method, "error", err)
+		return nil, err
+	}
+	if method.IsStreamingClient() {
+		return nil, fmt.Errorf("client streaming method %q is not supported", req.Method)
+	}
+
+	input := dynamicpb.NewMessage(method.Input())
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		body = "{}"
+	}
+	if err := protojson.Unmarshal([]byte(body), input); err != nil {
+		log.Error("failed to parse gRPC request message", "error", err)
+		return nil, fmt.Errorf("invalid request message: %w", err)
+	}
+
+	if len(req.Metadata) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(req.Metadata))
+	}
+
+	fullMethod := fmt.Sprintf("/%s/%s", method.Parent().FullName(), method.Name())
+	var header, trailer metadata.MD
+	var outputs []*dynamicpb.Message
+
+	start := time.Now()
+	if method.IsStreamingServer() {
+		outputs, err = invokeServerStream(ctx, conn, fullMethod, method, input, &header, &trailer)
+	} else {
+		output := dynamicpb.NewMessage(method.Output())
+		err = conn.Invoke(ctx, fullMethod, input, output, grpc.Header(&header), grpc.Trailer(&trailer))
+		if err == nil {
+			outputs = append(outputs, output)
+		}
+	}
+	duration := time.Since(start)
+
+	st, ok := status.FromError(err)
+	if !ok {
+		log.Error("gRPC request failed", "error", err)
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	messages := make([]string, len(outputs))
+	for i, output := range outputs {
+		encoded, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(output)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode response message: %w", err)
+		}
+		messages[i] = string(encoded)
+	}
+
+	if st.Code() != codes.OK {
+		log.Warn("gRPC request returned error status", "code", st.Code().String(), "method", req.Method)
+	}
+	if duration > 5*time.Second {
+		log.Warn("slow gRPC request", "duration", duration, "method", req.Method)
+	}
+
+	response := &Response{
+		StatusCode: int(st.Code()),
+		Status:     formatStatus(st),
+		Headers:    header,
+		Trailers:   trailer,
+		Messages:   messages,
+		Body:       joinMessages(messages, method.IsStreamingServer()),
+		Duration:   duration,
+	}
+
+	log.Info("gRPC request completed", "code", st.Code().String(), "messages", len(messages), "duration", duration)
+	return response, nil
+}
+
+// HTTPStatus maps a gRPC status code onto the closest HTTP status code so
+// gRPC calls can be recorded alongside REST requests.
+func HTTPStatus(code int) int {
+	switch codes.Code(code) {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+func (g *GRPCManager) dial(req *Request) (*grpc.ClientConn, error) {
+	creds := insecure.NewCredentials()
+	if !req.Plaintext {
+		creds = credentials.NewTLS(&tls.Config{})
+	}
+	conn, err := grpc.NewClient(req.Target, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		log.Error("failed to create gRPC client", "target", req.Target, "error", err)
+		return nil, fmt.Errorf("failed to connect: %w", err)
+	}
+	return conn, nil
+}
+
+// resolveSource returns the descriptor source for a request along with a
+// cleanup function for any connection opened to reach the server.
+func (g *GRPCManager) resolveSource(ctx context.Context, req *Request) (descriptorSource, func(), error) {
+	if len(req.ProtoFiles) > 0 {
+		source, err := newProtoFileSource(ctx, req.ProtoFiles, req.ImportPaths)
+		return source, func() {}, err
+	}
+	if strings.TrimSpace(req.Target) == "" {
+		return nil, nil, fmt.Errorf("target cannot be empty")
+	}
+
+	conn, err := g.dial(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	closeConn := func() {
+		if closeErr := conn.Close(); closeErr != nil {
+			log.Error("failed to close gRPC connection", "error", closeErr)
+		}
+	}
+	source, err := loadSource(ctx, req, conn)
+	if err != nil {
+		closeConn()
+		return nil, nil, err
+	}
+	return source, closeConn, nil
+}
+
+func loadSource(ctx context.Context, req *Request, conn *grpc.ClientConn) (descriptorSource, error) {
+	if len(req.ProtoFiles) > 0 {
+		return newProtoFileSource(ctx, req.ProtoFiles, req.ImportPaths)
+	}
+	return newReflectionSource(ctx, conn)
+}
+
+func invokeServerStream(ctx context.Context, conn *grpc.ClientConn, fullMethod string, method protoreflect.MethodDescriptor, input *dynamicpb.Message, header, trailer *metadata.MD) ([]*dynamicpb.Message, error) {
+	stream, err := conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, fullMethod, grpc.Header(header), grpc.Trailer(trailer))
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.SendMsg(input); err != nil {
+		return nil, err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+
+	var outputs []*dynamicpb.Message
+	for {
+		output := dynamicpb.NewMessage(method.Output())
+		err := stream.RecvMsg(output)
+		if errors.Is(err, io.EOF) {
+			return outputs, nil
+		}
+		if err != nil {
+			return outputs, err
+		}
+		outputs = append(outputs, output)
+	}
+}
+
+func newMethodInfo(method protoreflect.MethodDescriptor) MethodInfo {
+	return MethodInfo{
+		Service:         string(method.Parent().FullName()),
+		Name:            string(method.Name()),
+		InputType:       string(method.Input().FullName()),
+		OutputType:      string(method.Output().FullName()),
+		ClientStreaming: method.IsStreamingClient(),
+		ServerStreaming: method.IsStreamingServer(),
+	}
+}
+
+func formatStatus(st *status.Status) string {
+	if st.Message() == "" {
+		return st.Code().String()
+	}
+	return fmt.Sprintf("%s: %s", st.Code().String(), st.Message())
+}
+
+// joinMessages renders streamed responses as a JSON array so the body stays
+// valid JSON regardless of how many messages were received.
+func joinMessages(messages []string, streaming bool) string {
+	if !streaming {
+		if len(messages) == 0 {
+			return ""
+		}
+		return messages[0]
+	}
+	return "[" + strings.Join(messages, ",\n") + "]"
+}
@@ -0,0 +1,312 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+const healthProto = `syntax = "proto3";
+
+package grpc.health.v1;
+
+message HealthCheckRequest {
+  string service = 1;
+}
+
+message HealthCheckResponse {
+  enum ServingStatus {
+    UNKNOWN = 0;
+    SERVING = 1;
+    NOT_SERVING = 2;
+    SERVICE_UNKNOWN = 3;
+  }
+  ServingStatus status = 1;
+}
+
+service Health {
+  rpc Check(HealthCheckRequest) returns (HealthCheckResponse);
+  rpc Watch(HealthCheckRequest) returns (stream HealthCheckResponse);
+}
+`
+
+func startTestServer(t *testing.T) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	server := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("req", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String()
+}
+
+func writeHealthProto(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "health.proto")
+	if err := os.WriteFile(path, []byte(healthProto), 0o644); err != nil {
+		t.Fatalf("failed to write proto file: %v", err)
+	}
+	return path
+}
+
+func TestNewGRPCManager(t *testing.T) {
+	manager := NewGRPCManager()
+	if manager == nil {
+		t.Fatal("NewGRPCManager returned nil")
+	}
+	if manager.Timeout != 30*time.Second {
+		t.Errorf("expected timeout 30s, got %v", manager.Timeout)
+	}
+}
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		url       string
+		target    string
+		plaintext bool
+	}{
+		{"grpc://localhost:50051", "localhost:50051", true},
+		{"grpcs://api.example.com:443", "api.example.com:443", false},
+		{"api.example.com:443", "api.example.com:443", false},
+		{" grpc://localhost:9000/ ", "localhost:9000", true},
+	}
+
+	for _, test := range tests {
+		target, plaintext := ParseTarget(test.url)
+		if target != test.target || plaintext != test.plaintext {
+			t.Errorf("ParseTarget(%q) = (%q, %v), expected (%q, %v)", test.url, target, plaintext, test.target, test.plaintext)
+		}
+	}
+}
+
+func TestValidateRequest(t *testing.T) {
+	manager := NewGRPCManager()
+
+	tests := []struct {
+		name  string
+		req   Request
+		valid bool
+	}{
+		{"valid", Request{Target: "localhost:50051", Method: "pkg.Service/Method"}, true},
+		{"leading slash", Request{Target: "localhost:50051", Method: "/pkg.Service/Method"}, true},
+		{"empty target", Request{Method: "pkg.Service/Method"}, false},
+		{"missing method", Request{Target: "localhost:50051", Method: "pkg.Service"}, false},
+		{"trailing slash", Request{Target: "localhost:50051", Method: "pkg.Service/"}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := manager.ValidateRequest(&test.req)
+			if test.valid && err != nil {
+				t.Errorf("expected request to be valid, got: %v", err)
+			}
+			if !test.valid && err == nil {
+				t.Error("expected request to be invalid")
+			}
+		})
+	}
+}
+
+func TestHTTPStatus(t *testing.T) {
+	tests := []struct {
+		code     int
+		expected int
+	}{
+		{0, 200},
+		{3, 400},
+		{5, 404},
+		{14, 503},
+		{13, 500},
+	}
+
+	for _, test := range tests {
+		if got := HTTPStatus(test.code); got != test.expected {
+			t.Errorf("HTTPStatus(%d) = %d, expected %d", test.code, got, test.expected)
+		}
+	}
+}
+
+func TestListMethods(t *testing.T) {
+	ctx := context.Background()
+	manager := NewGRPCManager()
+
+	t.Run("server reflection", func(t *testing.T) {
+		target := startTestServer(t)
+		methods, err := manager.ListMethods(ctx, &Request{Target: target, Plaintext: true})
+		if err != nil {
+			t.Fatalf("ListMethods failed: %v", err)
+		}
+
+		found := map[string]MethodInfo{}
+		for _, method := range methods {
+			found[method.FullName()] = method
+		}
+		if _, ok := found["grpc.health.v1.Health/Check"]; !ok {
+			t.Errorf("expected Health/Check in %v", methods)
+		}
+		if watch, ok := found["grpc.health.v1.Health/Watch"]; !ok || !watch.ServerStreaming {
+			t.Errorf("expected server streaming Health/Watch in %v", methods)
+		}
+		for name := range found {
+			if strings.HasPrefix(name, "grpc.reflection.") {
+				t.Errorf("expected reflection service to be hidden, got %s", name)
+			}
+		}
+	})
+
+	t.Run("proto files", func(t *testing.T) {
+		methods, err := manager.ListMethods(ctx, &Request{ProtoFiles: []string{writeHealthProto(t)}})
+		if err != nil {
+			t.Fatalf("ListMethods failed: %v", err)
+		}
+		if len(methods) != 2 {
+			t.Fatalf("expected 2 methods, got %d", len(methods))
+		}
+		if methods[0].InputType != "grpc.health.v1.HealthCheckRequest" {
+			t.Errorf("unexpected input type %s", methods[0].InputType)
+		}
+	})
+}
+
+func TestRequestTemplate(t *testing.T) {
+	manager := NewGRPCManager()
+	template, err := manager.RequestTemplate(context.Background(), &Request{
+		Method:     "grpc.health.v1.Health/Check",
+		ProtoFiles: []string{writeHealthProto(t)},
+	})
+	if err != nil {
+		t.Fatalf("RequestTemplate failed: %v", err)
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(template), &fields); err != nil {
+		t.Fatalf("template is not valid JSON: %v", err)
+	}
+	if _, ok := fields["service"]; !ok {
+		t.Errorf("expected template to contain service field, got %s", template)
+	}
+}
+
+func TestExecuteRequest(t *testing.T) {
+	ctx := context.Background()
+	manager := NewGRPCManager()
+	target := startTestServer(t)
+
+	t.Run("unary via reflection", func(t *testing.T) {
+		resp, err := manager.ExecuteRequest(ctx, &Request{
+			Target:    target,
+			Method:    "grpc.health.v1.Health/Check",
+			Body:      `{"service": "req"}`,
+			Metadata:  map[string]string{"x-request-id": "abc"},
+			Plaintext: true,
+		})
+		if err != nil {
+			t.Fatalf("ExecuteRequest failed: %v", err)
+		}
+		if resp.StatusCode != 0 {
+			t.Errorf("expected OK status, got %s", resp.Status)
+		}
+		if !strings.Contains(resp.Body, "SERVING") {
+			t.Errorf("expected SERVING in body, got %s", resp.Body)
+		}
+	})
+
+	t.Run("unary via proto files", func(t *testing.T) {
+		resp, err := manager.ExecuteRequest(ctx, &Request{
+			Target:     target,
+			Method:     "grpc.health.v1.Health/Check",
+			Body:       `{"service": "req"}`,
+			ProtoFiles: []string{writeHealthProto(t)},
+			Plaintext:  true,
+		})
+		if err != nil {
+			t.Fatalf("ExecuteRequest failed: %v", err)
+		}
+		if !strings.Contains(resp.Body, "SERVING") {
+			t.Errorf("expected SERVING in body, got %s", resp.Body)
+		}
+	})
+
+	t.Run("error status is returned as response", func(t *testing.T) {
+		resp, err := manager.ExecuteRequest(ctx, &Request{
+			Target:    target,
+			Method:    "grpc.health.v1.Health/Check",
+			Body:      `{"service": "unknown"}`,
+			Plaintext: true,
+		})
+		if err != nil {
+			t.Fatalf("ExecuteRequest failed: %v", err)
+		}
+		if resp.StatusCode != 5 {
+			t.Errorf("expected NotFound status, got %s", resp.Status)
+		}
+		if !strings.HasPrefix(resp.Status, "NotFound") {
+			t.Errorf("unexpected status text %q", resp.Status)
+		}
+	})
+
+	t.Run("server streaming", func(t *testing.T) {
+		manager := &GRPCManager{Timeout: 500 * time.Millisecond}
+		resp, err := manager.ExecuteRequest(ctx, &Request{
+			Target:    target,
+			Method:    "grpc.health.v1.Health/Watch",
+			Body:      `{"service": "req"}`,
+			Plaintext: true,
+		})
+		if err != nil {
+			t.Fatalf("ExecuteRequest failed: %v", err)
+		}
+		if len(resp.Messages) == 0 {
+			t.Fatal("expected at least one streamed message")
+		}
+		var messages []map[string]any
+		if err := json.Unmarshal([]byte(resp.Body), &messages); err != nil {
+			t.Errorf("expected streamed body to be a JSON array: %v", err)
+		}
+	})
+
+	t.Run("invalid message", func(t *testing.T) {
+		_, err := manager.ExecuteRequest(ctx, &Request{
+			Target:    target,
+			Method:    "grpc.health.v1.Health/Check",
+			Body:      `{"unknown_field": true}`,
+			Plaintext: true,
+		})
+		if err == nil {
+			t.Error("expected invalid request message to fail")
+		}
+	})
+
+	t.Run("unknown method", func(t *testing.T) {
+		_, err := manager.ExecuteRequest(ctx, &Request{
+			Target:    target,
+			Method:    "grpc.health.v1.Health/Missing",
+			Plaintext: true,
+		})
+		if err == nil {
+			t.Error("expected unknown method to fail")
+		}
+	})
+}
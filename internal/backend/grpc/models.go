@@ -0,0 +1,47 @@
+// Package grpc provides gRPC client functionality backed by dynamic protobuf messages.
+package grpc
+
+import (
+	"time"
+)
+
+type GRPCManager struct {
+	Timeout time.Duration
+}
+
+// Request describes a gRPC call. Method is the fully-qualified method name in
+// the form "package.Service/Method". When ProtoFiles is empty the service
+// definitions are discovered through server reflection.
+type Request struct {
+	Target      string
+	Method      string
+	Metadata    map[string]string
+	Body        string
+	ProtoFiles  []string
+	ImportPaths []string
+	Plaintext   bool
+}
+
+type Response struct {
+	StatusCode int
+	Status     string
+	Headers    map[string][]string
+	Trailers   map[string][]string
+	Messages   []string
+	Body       string
+	Duration   time.Duration
+}
+
+type MethodInfo struct {
+	Service         string
+	Name            string
+	InputType       string
+	OutputType      string
+	ClientStreaming bool
+	ServerStreaming bool
+}
+
+// FullName returns the method name in the "package.Service/Method" form used by Request
+func (m MethodInfo) FullName() string {
+	return m.Service + "/" + m.Name
+}
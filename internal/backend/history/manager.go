@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/maniac-en/req/internal/backend/crud"
@@ -13,7 +14,7 @@ import (
 )
 
 func NewHistoryManager(db *database.Queries) *HistoryManager {
-	return &HistoryManager{DB: db}
+	return &HistoryManager{DB: db, Retention: DefaultRetention()}
 }
 
 func (h *HistoryManager) Create(ctx context.Context, name string) (HistoryEntity, error) {
@@ -58,6 +59,27 @@ func (h *HistoryManager) List(ctx context.Context) ([]HistoryEntity, error) {
 	return nil, fmt.Errorf("use ListByCollection to list history entries")
 }
 
+// LatestSuccessful returns the most recent entry of an endpoint that got a
+// non-error response, or crud.ErrNotFound when there is none
+func (h *HistoryManager) LatestSuccessful(ctx context.Context, collectionID int64, endpointName string) (HistoryEntity, error) {
+	if err := crud.ValidateID(collectionID); err != nil {
+		return HistoryEntity{}, err
+	}
+
+	entry, err := h.DB.GetLatestSuccessfulHistory(ctx, database.GetLatestSuccessfulHistoryParams{
+		CollectionID: sql.NullInt64{Int64: collectionID, Valid: true},
+		EndpointName: sql.NullString{String: endpointName, Valid: true},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return HistoryEntity{}, crud.ErrNotFound
+		}
+		log.Error("failed to read latest history entry", "collection_id", collectionID, "endpoint", endpointName, "error", err)
+		return HistoryEntity{}, err
+	}
+	return HistoryEntity{History: entry}, nil
+}
+
 func (h *HistoryManager) ListByCollection(ctx context.Context, collectionID int64, limit, offset int) (PaginatedHistory, error) {
 	if err := crud.ValidateID(collectionID); err != nil {
 		return PaginatedHistory{}, err
@@ -105,6 +127,72 @@ func (h *HistoryManager) ListByCollection(ctx context.Context, collectionID int6
 	return result, nil
 }
 
+// Search lists executions matching the filters, newest first
+func (h *HistoryManager) Search(ctx context.Context, filters SearchFilters, limit, offset int) (PaginatedHistory, error) {
+	if filters.CollectionID < 0 {
+		return PaginatedHistory{}, crud.ErrInvalidInput
+	}
+	maxStatus := filters.MaxStatus
+	if maxStatus == 0 {
+		maxStatus = 999
+	}
+	if filters.MinStatus > maxStatus {
+		log.Warn("history search has an empty status range", "min_status", filters.MinStatus, "max_status", maxStatus)
+		return PaginatedHistory{}, crud.ErrInvalidInput
+	}
+
+	params := database.SearchHistoryParams{
+		Query:        matchExpression(filters.Text),
+		CollectionID: filters.CollectionID,
+		Method:       strings.ToUpper(filters.Method),
+		MinStatus:    int64(filters.MinStatus),
+		MaxStatus:    int64(maxStatus),
+		Since:        formatTime(filters.Since),
+		Until:        formatTime(filters.Until),
+		Limit:        int64(limit),
+		Offset:       int64(offset),
+	}
+
+	total, err := h.DB.CountSearchHistory(ctx, database.CountSearchHistoryParams{
+		Query:        params.Query,
+		CollectionID: params.CollectionID,
+		Method:       params.Method,
+		MinStatus:    params.MinStatus,
+		MaxStatus:    params.MaxStatus,
+		Since:        params.Since,
+		Until:        params.Until,
+	})
+	if err != nil {
+		log.Error("failed to count history search results", "query", params.Query, "error", err)
+		return PaginatedHistory{}, err
+	}
+
+	rows, err := h.DB.SearchHistory(ctx, params)
+	if err != nil {
+		log.Error("failed to search history", "query", params.Query, "error", err)
+		return PaginatedHistory{}, err
+	}
+
+	entities := make([]HistoryEntity, len(rows))
+	for i, row := range rows {
+		entities[i] = HistoryEntity{History: database.History{
+			ID:             row.ID,
+			CollectionID:   row.CollectionID,
+			CollectionName: row.CollectionName,
+			EndpointName:   row.EndpointName,
+			Method:         row.Method,
+			Url:            row.Url,
+			StatusCode:     row.StatusCode,
+			Duration:       row.Duration,
+			ExecutedAt:     row.ExecutedAt,
+		}}
+	}
+
+	pagination := crud.CalculatePagination(total, limit, offset)
+	log.Info("searched history", "query", params.Query, "count", len(entities), "total", pagination.Total)
+	return PaginatedHistory{Items: entities, PaginationMetadata: pagination}, nil
+}
+
 func (h *HistoryManager) RecordExecution(ctx context.Context, data ExecutionData) (HistoryEntity, error) {
 	if err := validateExecutionData(data); err != nil {
 		log.Error("invalid execution data", "error", err)
@@ -129,6 +217,11 @@ func (h *HistoryManager) RecordExecution(ctx context.Context, data ExecutionData
 		return HistoryEntity{}, fmt.Errorf("failed to marshal response headers: %w", err)
 	}
 
+	requestBody, responseBody := data.RequestBody, data.ResponseBody
+	if h.Retention.FailedBodiesOnly && data.StatusCode < 400 {
+		requestBody, responseBody = "", ""
+	}
+
 	params := database.CreateHistoryEntryParams{
 		CollectionID:    sql.NullInt64{Int64: data.CollectionID, Valid: data.CollectionID > 0},
 		CollectionName:  sql.NullString{String: data.CollectionName, Valid: data.CollectionName != ""},
@@ -140,8 +233,8 @@ func (h *HistoryManager) RecordExecution(ctx context.Context, data ExecutionData
 		ResponseSize:    sql.NullInt64{Int64: data.ResponseSize, Valid: data.ResponseSize > 0},
 		RequestHeaders:  sql.NullString{String: string(requestHeaders), Valid: true},
 		QueryParams:     sql.NullString{String: string(queryParams), Valid: true},
-		RequestBody:     sql.NullString{String: data.RequestBody, Valid: data.RequestBody != ""},
-		ResponseBody:    sql.NullString{String: data.ResponseBody, Valid: data.ResponseBody != ""},
+		RequestBody:     sql.NullString{String: requestBody, Valid: requestBody != ""},
+		ResponseBody:    sql.NullString{String: responseBody, Valid: responseBody != ""},
 		ResponseHeaders: sql.NullString{String: string(responseHeaders), Valid: true},
 		ExecutedAt:      time.Now().Format(time.RFC3339),
 	}
@@ -156,15 +249,72 @@ func (h *HistoryManager) RecordExecution(ctx context.Context, data ExecutionData
 	return HistoryEntity{History: history}, nil
 }
 
+// Prune enforces a retention policy: it deletes entries that are too old,
+// beyond the per collection limit or over the size budget, oldest first, and
+// strips bodies of successful entries when asked to
+func (h *HistoryManager) Prune(ctx context.Context, retention Retention) (PruneResult, error) {
+	if retention.MaxAge < 0 || retention.MaxEntriesPerCollection < 0 || retention.MaxTotalBytes < 0 {
+		return PruneResult{}, crud.ErrInvalidInput
+	}
+
+	var result PruneResult
+	if retention.MaxAge > 0 {
+		before := time.Now().Add(-retention.MaxAge).UTC().Format(time.DateTime)
+		deleted, err := h.DB.DeleteOldHistory(ctx, before)
+		if err != nil {
+			log.Error("failed to delete old history", "before", before, "error", err)
+			return result, err
+		}
+		result.Deleted += deleted
+	}
+	if retention.MaxEntriesPerCollection > 0 {
+		deleted, err := h.DB.DeleteExcessHistoryPerCollection(ctx, int64(retention.MaxEntriesPerCollection))
+		if err != nil {
+			log.Error("failed to delete excess history", "max_entries", retention.MaxEntriesPerCollection, "error", err)
+			return result, err
+		}
+		result.Deleted += deleted
+	}
+	if retention.FailedBodiesOnly {
+		stripped, err := h.DB.StripSuccessfulHistoryBodies(ctx)
+		if err != nil {
+			log.Error("failed to strip history bodies", "error", err)
+			return result, err
+		}
+		result.Stripped = stripped
+	}
+	// the size budget goes last so it counts what the other limits kept
+	if retention.MaxTotalBytes > 0 {
+		deleted, err := h.DB.DeleteHistoryOverSize(ctx, retention.MaxTotalBytes)
+		if err != nil {
+			log.Error("failed to delete history over size", "max_bytes", retention.MaxTotalBytes, "error", err)
+			return result, err
+		}
+		result.Deleted += deleted
+	}
+
+	stats, err := h.DB.GetHistoryStats(ctx)
+	if err != nil {
+		log.Error("failed to read history stats", "error", err)
+		return result, err
+	}
+	result.Entries = stats.Entries
+	result.Size = stats.Size
+
+	log.Info("pruned history", "deleted", result.Deleted, "stripped", result.Stripped, "entries", result.Entries, "size", result.Size)
+	return result, nil
+}
+
 func validateExecutionData(data ExecutionData) error {
 	if err := crud.ValidateName(data.Method); err != nil {
 		log.Warn("execution validation failed: invalid method", "method", data.Method)
 		return fmt.Errorf("invalid method: %w", err)
 	}
 
-	if err := crud.ValidateName(data.URL); err != nil {
-		log.Warn("execution validation failed: invalid URL", "url", data.URL)
-		return fmt.Errorf("invalid URL: %w", err)
+	// URLs are often longer than a name may be, so only require one
+	if strings.TrimSpace(data.URL) == "" {
+		log.Warn("execution validation failed: empty URL")
+		return fmt.Errorf("invalid URL: URL cannot be empty")
 	}
 
 	if data.StatusCode < 100 || data.StatusCode > 599 {
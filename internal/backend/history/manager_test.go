@@ -2,9 +2,11 @@ package history
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/maniac-en/req/internal/backend/crud"
 	"github.com/maniac-en/req/internal/backend/testutils"
 )
 
@@ -123,6 +125,17 @@ func TestRecordExecution(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("long URL", func(t *testing.T) {
+		url := "https://api.example.com/" + strings.Repeat("segment/", 20)
+		entity, err := manager.RecordExecution(ctx, ExecutionData{CollectionID: 1, Method: "GET", URL: url, StatusCode: 200})
+		if err != nil {
+			t.Fatalf("expected a long URL to be recorded, got %v", err)
+		}
+		if entity.Url != url {
+			t.Errorf("expected URL %q, got %q", url, entity.Url)
+		}
+	})
 }
 
 func TestListByCollection(t *testing.T) {
@@ -252,3 +265,33 @@ func TestDelete(t *testing.T) {
 		t.Error("expected Read to fail after Delete")
 	}
 }
+
+func TestLatestSuccessful(t *testing.T) {
+	ctx := context.Background()
+	db := testutils.SetupTestDB(t, "history")
+	manager := NewHistoryManager(db)
+
+	testData := []ExecutionData{
+		{CollectionID: 1, EndpointName: "Get User", Method: "GET", URL: "https://api.example.com/users/1", StatusCode: 200, ResponseBody: "first"},
+		{CollectionID: 1, EndpointName: "Get User", Method: "GET", URL: "https://api.example.com/users/1", StatusCode: 200, ResponseBody: "second"},
+		{CollectionID: 1, EndpointName: "Get User", Method: "GET", URL: "https://api.example.com/users/1", StatusCode: 500, ResponseBody: "failed"},
+		{CollectionID: 2, EndpointName: "Get User", Method: "GET", URL: "https://api.other.com/users/1", StatusCode: 200, ResponseBody: "other"},
+	}
+	for _, data := range testData {
+		if _, err := manager.RecordExecution(ctx, data); err != nil {
+			t.Fatalf("failed to create test data: %v", err)
+		}
+	}
+
+	entry, err := manager.LatestSuccessful(ctx, 1, "Get User")
+	if err != nil {
+		t.Fatalf("LatestSuccessful failed: %v", err)
+	}
+	if entry.ResponseBody.String != "second" {
+		t.Errorf("expected the latest successful entry, got %q", entry.ResponseBody.String)
+	}
+
+	if _, err := manager.LatestSuccessful(ctx, 1, "Missing"); err != crud.ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
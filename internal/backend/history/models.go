@@ -2,14 +2,20 @@
 package history
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/maniac-en/req/internal/backend/crud"
 	"github.com/maniac-en/req/internal/backend/database"
+	"github.com/maniac-en/req/internal/diff"
+	"github.com/maniac-en/req/internal/log"
 )
 
 type HistoryManager struct {
 	DB *database.Queries
+	// Retention is the configured policy. Its FailedBodiesOnly setting is
+	// also applied as executions are recorded.
+	Retention Retention
 }
 
 type HistoryEntity struct {
@@ -53,3 +59,18 @@ type ExecutionData struct {
 	Duration        time.Duration
 	ResponseSize    int64
 }
+
+// Response returns the recorded response for comparison with another one
+func (h HistoryEntity) Response() diff.Response {
+	var headers map[string][]string
+	if h.ResponseHeaders.Valid && h.ResponseHeaders.String != "" {
+		if err := json.Unmarshal([]byte(h.ResponseHeaders.String), &headers); err != nil {
+			log.Warn("failed to decode recorded response headers", "id", h.ID, "error", err)
+		}
+	}
+	return diff.Response{
+		Status:  int(h.StatusCode),
+		Headers: headers,
+		Body:    h.ResponseBody.String,
+	}
+}
@@ -0,0 +1,95 @@
+package history
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Retention limits how much history is kept. Zero values disable a limit.
+type Retention struct {
+	MaxAge                  time.Duration
+	MaxEntriesPerCollection int
+	MaxTotalBytes           int64
+	// FailedBodiesOnly drops request and response bodies of successful
+	// executions, keeping them only where they help debugging
+	FailedBodiesOnly bool
+}
+
+// DefaultRetention keeps all history. Limits are opt-in, so upgrading never
+// deletes entries the user may still want, such as those mocks serve.
+func DefaultRetention() Retention {
+	return Retention{}
+}
+
+// PruneResult reports what Prune removed and what is left
+type PruneResult struct {
+	Deleted  int64
+	Stripped int64
+	Entries  int64
+	Size     int64
+}
+
+var ageUnits = map[byte]time.Duration{
+	'm': time.Minute,
+	'h': time.Hour,
+	'd': 24 * time.Hour,
+	'w': 7 * 24 * time.Hour,
+}
+
+// ParseAge reads an age like 30m, 12h, 7d or 2w. "0" means no limit.
+func ParseAge(value string) (time.Duration, error) {
+	if value == "0" {
+		return 0, nil
+	}
+	if value == "" {
+		return 0, fmt.Errorf("expected an age like 12h, 7d or 2w")
+	}
+	unit, ok := ageUnits[value[len(value)-1]]
+	count, err := strconv.Atoi(value[:len(value)-1])
+	if !ok || err != nil || count < 0 {
+		return 0, fmt.Errorf("expected an age like 12h, 7d or 2w, got %q", value)
+	}
+	return time.Duration(count) * unit, nil
+}
+
+var sizeUnits = []struct {
+	suffix string
+	size   int64
+}{
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"B", 1},
+}
+
+// ParseSize reads a size like 500KB, 100MB or 1GB, or a plain number of
+// bytes. "0" means no limit.
+func ParseSize(value string) (int64, error) {
+	upper := strings.ToUpper(strings.TrimSpace(value))
+	for _, unit := range sizeUnits {
+		if number, ok := strings.CutSuffix(upper, unit.suffix); ok {
+			count, err := strconv.ParseInt(strings.TrimSpace(number), 10, 64)
+			if err != nil || count < 0 {
+				return 0, fmt.Errorf("expected a size like 500KB or 100MB, got %q", value)
+			}
+			return count * unit.size, nil
+		}
+	}
+	count, err := strconv.ParseInt(upper, 10, 64)
+	if err != nil || count < 0 {
+		return 0, fmt.Errorf("expected a size like 500KB or 100MB, got %q", value)
+	}
+	return count, nil
+}
+
+// FormatSize renders a byte count with the largest fitting unit
+func FormatSize(size int64) string {
+	for _, unit := range sizeUnits[:len(sizeUnits)-1] {
+		if size >= unit.size {
+			return fmt.Sprintf("%.1f %s", float64(size)/float64(unit.size), unit.suffix)
+		}
+	}
+	return fmt.Sprintf("%d B", size)
+}
@@ -0,0 +1,158 @@
+package history
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maniac-en/req/internal/backend/crud"
+	"github.com/maniac-en/req/internal/backend/database"
+	"github.com/maniac-en/req/internal/backend/testutils"
+)
+
+// insertEntry records an execution with a chosen age and body
+func insertEntry(t *testing.T, manager *HistoryManager, collectionID int64, status int, body string, age time.Duration) int64 {
+	t.Helper()
+	entry, err := manager.DB.CreateHistoryEntry(context.Background(), database.CreateHistoryEntryParams{
+		CollectionID:    sql.NullInt64{Int64: collectionID, Valid: collectionID > 0},
+		Method:          "GET",
+		Url:             "https://example.com",
+		StatusCode:      int64(status),
+		RequestHeaders:  sql.NullString{String: "{}", Valid: true},
+		QueryParams:     sql.NullString{String: "{}", Valid: true},
+		ResponseBody:    sql.NullString{String: body, Valid: body != ""},
+		ResponseHeaders: sql.NullString{String: "{}", Valid: true},
+		ExecutedAt:      time.Now().Add(-age).Format(time.RFC3339),
+	})
+	if err != nil {
+		t.Fatalf("failed to insert history entry: %v", err)
+	}
+	return entry.ID
+}
+
+func exists(manager *HistoryManager, id int64) bool {
+	_, err := manager.Read(context.Background(), id)
+	return err == nil
+}
+
+func TestPrune(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("max age", func(t *testing.T) {
+		manager := NewHistoryManager(testutils.SetupTestDB(t, "history"))
+		old := insertEntry(t, manager, 1, 200, "", 40*24*time.Hour)
+		recent := insertEntry(t, manager, 1, 200, "", time.Hour)
+
+		result, err := manager.Prune(ctx, Retention{MaxAge: 30 * 24 * time.Hour})
+		if err != nil {
+			t.Fatalf("Prune failed: %v", err)
+		}
+		if result.Deleted != 1 || result.Entries != 1 {
+			t.Errorf("expected 1 deleted and 1 left, got %+v", result)
+		}
+		if exists(manager, old) || !exists(manager, recent) {
+			t.Error("expected only the old entry to be deleted")
+		}
+	})
+
+	t.Run("max entries per collection", func(t *testing.T) {
+		manager := NewHistoryManager(testutils.SetupTestDB(t, "history"))
+		oldest := insertEntry(t, manager, 1, 200, "", 3*time.Hour)
+		insertEntry(t, manager, 1, 200, "", 2*time.Hour)
+		insertEntry(t, manager, 1, 200, "", time.Hour)
+		other := insertEntry(t, manager, 2, 200, "", 5*time.Hour)
+
+		result, err := manager.Prune(ctx, Retention{MaxEntriesPerCollection: 2})
+		if err != nil {
+			t.Fatalf("Prune failed: %v", err)
+		}
+		if result.Deleted != 1 || result.Entries != 3 {
+			t.Errorf("expected 1 deleted and 3 left, got %+v", result)
+		}
+		if exists(manager, oldest) || !exists(manager, other) {
+			t.Error("expected only the oldest entry of the full collection to be deleted")
+		}
+	})
+
+	t.Run("max total bytes", func(t *testing.T) {
+		manager := NewHistoryManager(testutils.SetupTestDB(t, "history"))
+		oldest := insertEntry(t, manager, 1, 200, strings.Repeat("a", 1000), 3*time.Hour)
+		middle := insertEntry(t, manager, 1, 200, strings.Repeat("b", 1000), 2*time.Hour)
+		newest := insertEntry(t, manager, 1, 200, strings.Repeat("c", 1000), time.Hour)
+
+		result, err := manager.Prune(ctx, Retention{MaxTotalBytes: 2200})
+		if err != nil {
+			t.Fatalf("Prune failed: %v", err)
+		}
+		if result.Deleted != 1 || result.Size > 2200 {
+			t.Errorf("expected the oldest entry to go to fit the budget, got %+v", result)
+		}
+		if exists(manager, oldest) || !exists(manager, middle) || !exists(manager, newest) {
+			t.Error("expected newest entries to be kept")
+		}
+	})
+
+	t.Run("failed bodies only", func(t *testing.T) {
+		manager := NewHistoryManager(testutils.SetupTestDB(t, "history"))
+		success := insertEntry(t, manager, 1, 200, "ok", time.Hour)
+		failure := insertEntry(t, manager, 1, 500, "boom", time.Hour)
+
+		result, err := manager.Prune(ctx, Retention{FailedBodiesOnly: true})
+		if err != nil {
+			t.Fatalf("Prune failed: %v", err)
+		}
+		if result.Stripped != 1 || result.Deleted != 0 {
+			t.Errorf("expected one stripped entry, got %+v", result)
+		}
+		if entry, _ := manager.Read(ctx, success); entry.ResponseBody.Valid {
+			t.Errorf("expected successful body to be dropped, got %q", entry.ResponseBody.String)
+		}
+		if entry, _ := manager.Read(ctx, failure); entry.ResponseBody.String != "boom" {
+			t.Errorf("expected failed body to be kept, got %q", entry.ResponseBody.String)
+		}
+
+		manager.Retention.FailedBodiesOnly = true
+		recorded, err := manager.RecordExecution(ctx, ExecutionData{Method: "GET", URL: "https://example.com", StatusCode: 201, RequestBody: "{}", ResponseBody: "created"})
+		if err != nil {
+			t.Fatalf("RecordExecution failed: %v", err)
+		}
+		if recorded.RequestBody.Valid || recorded.ResponseBody.Valid {
+			t.Error("expected bodies of successful executions not to be recorded")
+		}
+	})
+
+	t.Run("invalid retention", func(t *testing.T) {
+		manager := NewHistoryManager(testutils.SetupTestDB(t, "history"))
+		if _, err := manager.Prune(ctx, Retention{MaxEntriesPerCollection: -1}); err != crud.ErrInvalidInput {
+			t.Errorf("expected ErrInvalidInput, got %v", err)
+		}
+	})
+}
+
+func TestParseSizeAndAge(t *testing.T) {
+	sizes := map[string]int64{"0": 0, "512": 512, "500KB": 500 << 10, "100mb": 100 << 20, "1 GB": 1 << 30}
+	for input, expected := range sizes {
+		if got, err := ParseSize(input); err != nil || got != expected {
+			t.Errorf("ParseSize(%q) = %d, %v, expected %d", input, got, err, expected)
+		}
+	}
+	if _, err := ParseSize("lots"); err == nil {
+		t.Error("expected ParseSize to reject an invalid size")
+	}
+
+	ages := map[string]time.Duration{"0": 0, "90m": 90 * time.Minute, "12h": 12 * time.Hour, "30d": 30 * 24 * time.Hour, "2w": 14 * 24 * time.Hour}
+	for input, expected := range ages {
+		if got, err := ParseAge(input); err != nil || got != expected {
+			t.Errorf("ParseAge(%q) = %v, %v, expected %v", input, got, err, expected)
+		}
+	}
+	if _, err := ParseAge("1y"); err == nil {
+		t.Error("expected ParseAge to reject an unknown unit")
+	}
+
+	if got := FormatSize(1536); got != "1.5 KB" {
+		t.Errorf("FormatSize(1536) = %q, expected 1.5 KB", got)
+	}
+}
@@ -0,0 +1,116 @@
+package history
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SearchFilters narrows a history search. Zero values match everything.
+type SearchFilters struct {
+	// Text must match the URL, query params, headers or bodies. Every word
+	// has to appear, as a prefix of an indexed word.
+	Text         string
+	CollectionID int64
+	Method       string
+	MinStatus    int
+	MaxStatus    int
+	// Since and Until bound the execution time, Until being exclusive
+	Since time.Time
+	Until time.Time
+}
+
+// ParseQuery reads filters typed into a search box. Words of the form
+// key:value set filters and everything else is searched as text:
+//
+//	status:404, status:4xx or status:400-499
+//	method:POST
+//	since:7d, since:12h or since:2025-08-01 (until: takes the same values)
+func ParseQuery(input string, now time.Time) (SearchFilters, error) {
+	var filters SearchFilters
+	var words []string
+	for _, word := range strings.Fields(input) {
+		key, value, ok := strings.Cut(word, ":")
+		if !ok || value == "" {
+			words = append(words, word)
+			continue
+		}
+
+		var err error
+		switch strings.ToLower(key) {
+		case "status":
+			filters.MinStatus, filters.MaxStatus, err = parseStatus(value)
+		case "method":
+			filters.Method = strings.ToUpper(value)
+		case "since":
+			filters.Since, err = parseTime(value, now)
+		case "until":
+			filters.Until, err = parseTime(value, now)
+		default:
+			words = append(words, word)
+		}
+		if err != nil {
+			return SearchFilters{}, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	filters.Text = strings.Join(words, " ")
+	return filters, nil
+}
+
+func parseStatus(value string) (int, int, error) {
+	if len(value) == 3 && strings.HasSuffix(strings.ToLower(value), "xx") {
+		class, err := strconv.Atoi(value[:1])
+		if err != nil || class < 1 || class > 5 {
+			return 0, 0, fmt.Errorf("invalid status class %q", value)
+		}
+		return class * 100, class*100 + 99, nil
+	}
+	if low, high, ok := strings.Cut(value, "-"); ok {
+		minStatus, err := strconv.Atoi(low)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid status %q", low)
+		}
+		maxStatus, err := strconv.Atoi(high)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid status %q", high)
+		}
+		return minStatus, maxStatus, nil
+	}
+	status, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid status %q", value)
+	}
+	return status, status, nil
+}
+
+// parseTime accepts a date or an age in minutes, hours, days or weeks
+func parseTime(value string, now time.Time) (time.Time, error) {
+	if date, err := time.ParseInLocation(time.DateOnly, value, now.Location()); err == nil {
+		return date, nil
+	}
+
+	age, err := ParseAge(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected a date like 2025-08-01 or an age like 7d, got %q", value)
+	}
+	return now.Add(-age), nil
+}
+
+// matchExpression turns free text into a full-text query. Words are quoted so
+// punctuation in URLs and bodies cannot break the query syntax.
+func matchExpression(text string) string {
+	words := strings.Fields(text)
+	for i, word := range words {
+		words[i] = `"` + strings.ReplaceAll(word, `"`, `""`) + `*"`
+	}
+	return strings.Join(words, " ")
+}
+
+// formatTime matches the format SQLite's datetime() compares against
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.DateTime)
+}
@@ -0,0 +1,138 @@
+package history
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maniac-en/req/internal/backend/crud"
+	"github.com/maniac-en/req/internal/backend/testutils"
+)
+
+func TestSearch(t *testing.T) {
+	ctx := context.Background()
+	db := testutils.SetupTestDB(t, "history", "history_fts")
+	manager := NewHistoryManager(db)
+
+	executions := []ExecutionData{
+		{CollectionID: 1, Method: "GET", URL: "https://api.example.com/users", StatusCode: 200, ResponseBody: `{"users": []}`},
+		{CollectionID: 1, Method: "POST", URL: "https://api.example.com/orders", StatusCode: 500, ResponseBody: `{"error": "flux capacitor overheated"}`},
+		{CollectionID: 2, Method: "GET", URL: "https://other.example.com/health", StatusCode: 503, Headers: map[string]string{"X-Trace": "abc-123"}},
+	}
+	for _, data := range executions {
+		if _, err := manager.RecordExecution(ctx, data); err != nil {
+			t.Fatalf("RecordExecution failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		filters  SearchFilters
+		expected []string
+	}{
+		{"everything", SearchFilters{}, []string{"/health", "/orders", "/users"}},
+		{"response body", SearchFilters{Text: "capacitor"}, []string{"/orders"}},
+		{"word prefix", SearchFilters{Text: "overheat"}, []string{"/orders"}},
+		{"request header", SearchFilters{Text: "abc-123"}, []string{"/health"}},
+		{"url", SearchFilters{Text: "api.example.com"}, []string{"/orders", "/users"}},
+		{"query syntax is escaped", SearchFilters{Text: `"flux OR (`}, nil},
+		{"status range", SearchFilters{MinStatus: 500, MaxStatus: 599}, []string{"/health", "/orders"}},
+		{"method", SearchFilters{Method: "post"}, []string{"/orders"}},
+		{"collection", SearchFilters{CollectionID: 2}, []string{"/health"}},
+		{"text and status", SearchFilters{Text: "example", MinStatus: 500, MaxStatus: 599, CollectionID: 1}, []string{"/orders"}},
+		{"since", SearchFilters{Since: time.Now().Add(-time.Hour)}, []string{"/health", "/orders", "/users"}},
+		{"until", SearchFilters{Until: time.Now().Add(-time.Hour)}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := manager.Search(ctx, test.filters, 10, 0)
+			if err != nil {
+				t.Fatalf("Search failed: %v", err)
+			}
+			if result.Total != int64(len(test.expected)) || len(result.Items) != len(test.expected) {
+				t.Fatalf("expected %d results, got %d (total %d)", len(test.expected), len(result.Items), result.Total)
+			}
+
+			found := map[string]bool{}
+			for _, item := range result.Items {
+				for _, suffix := range test.expected {
+					if strings.HasSuffix(item.Url, suffix) {
+						found[suffix] = true
+					}
+				}
+			}
+			if len(found) != len(test.expected) {
+				t.Errorf("expected results %v, got %v", test.expected, result.Items)
+			}
+		})
+	}
+
+	t.Run("deleted entries are not found", func(t *testing.T) {
+		result, err := manager.Search(ctx, SearchFilters{Text: "capacitor"}, 10, 0)
+		if err != nil || len(result.Items) != 1 {
+			t.Fatalf("expected one result, got %v (%v)", result.Items, err)
+		}
+		if err := manager.Delete(ctx, result.Items[0].ID); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		result, err = manager.Search(ctx, SearchFilters{Text: "capacitor"}, 10, 0)
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		if len(result.Items) != 0 {
+			t.Errorf("expected deleted entry to leave the index, got %v", result.Items)
+		}
+	})
+
+	t.Run("invalid filters", func(t *testing.T) {
+		if _, err := manager.Search(ctx, SearchFilters{MinStatus: 500, MaxStatus: 400}, 10, 0); err != crud.ErrInvalidInput {
+			t.Errorf("expected ErrInvalidInput for empty status range, got %v", err)
+		}
+		if _, err := manager.Search(ctx, SearchFilters{CollectionID: -1}, 10, 0); err != crud.ErrInvalidInput {
+			t.Errorf("expected ErrInvalidInput for negative collection ID, got %v", err)
+		}
+	})
+}
+
+func TestParseQuery(t *testing.T) {
+	now := time.Date(2025, 8, 15, 12, 0, 0, 0, time.UTC)
+
+	filters, err := ParseQuery("weird error status:5xx method:post since:7d", now)
+	if err != nil {
+		t.Fatalf("ParseQuery failed: %v", err)
+	}
+	if filters.Text != "weird error" {
+		t.Errorf("expected text %q, got %q", "weird error", filters.Text)
+	}
+	if filters.MinStatus != 500 || filters.MaxStatus != 599 {
+		t.Errorf("expected status 500-599, got %d-%d", filters.MinStatus, filters.MaxStatus)
+	}
+	if filters.Method != "POST" {
+		t.Errorf("expected method POST, got %q", filters.Method)
+	}
+	if !filters.Since.Equal(now.AddDate(0, 0, -7)) {
+		t.Errorf("expected since a week ago, got %v", filters.Since)
+	}
+
+	filters, err = ParseQuery("status:400-404 until:2025-08-01 http://localhost:8080", now)
+	if err != nil {
+		t.Fatalf("ParseQuery failed: %v", err)
+	}
+	if filters.MinStatus != 400 || filters.MaxStatus != 404 {
+		t.Errorf("expected status 400-404, got %d-%d", filters.MinStatus, filters.MaxStatus)
+	}
+	if !filters.Until.Equal(time.Date(2025, 8, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected until 2025-08-01, got %v", filters.Until)
+	}
+	if filters.Text != "http://localhost:8080" {
+		t.Errorf("expected unknown keys to stay in the text, got %q", filters.Text)
+	}
+
+	for _, input := range []string{"status:abc", "status:9xx", "since:soon", "until:3y"} {
+		if _, err := ParseQuery(input, now); err == nil {
+			t.Errorf("expected ParseQuery(%q) to fail", input)
+		}
+	}
+}
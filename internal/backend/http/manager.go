@@ -61,7 +61,7 @@ func (h *HTTPManager) ExecuteRequest(req *Request) (*Response, error) {
 
 	log.Debug("executing HTTP request", "method", req.Method, "url", req.URL)
 
-	requestURL, err := h.buildURL(req.URL, req.QueryParams)
+	requestURL, err := BuildURL(req.URL, req.QueryParams)
 	if err != nil {
 		log.Error("failed to build URL", "error", err)
 		return nil, fmt.Errorf("failed to build URL: %w", err)
@@ -70,7 +70,7 @@ func (h *HTTPManager) ExecuteRequest(req *Request) (*Response, error) {
 	start := time.Now()
 
 	var body io.Reader
-	if req.Body != "" && (strings.ToUpper(req.Method) == "POST" || strings.ToUpper(req.Method) == "PUT" || strings.ToUpper(req.Method) == "PATCH") {
+	if req.Body != "" && SendsBody(req.Method) {
 		body = strings.NewReader(req.Body)
 	}
 
@@ -133,7 +133,19 @@ func (h *HTTPManager) ExecuteRequest(req *Request) (*Response, error) {
 	return response, nil
 }
 
-func (h *HTTPManager) buildURL(baseURL string, queryParams map[string]string) (string, error) {
+// SendsBody reports whether requests with the method carry their body.
+// Bodies of other methods are dropped.
+func SendsBody(method string) bool {
+	switch strings.ToUpper(method) {
+	case "POST", "PUT", "PATCH":
+		return true
+	}
+	return false
+}
+
+// BuildURL adds query parameters to a URL, replacing any it already has
+// with the same name
+func BuildURL(baseURL string, queryParams map[string]string) (string, error) {
 	if len(queryParams) == 0 {
 		return baseURL, nil
 	}
@@ -166,14 +178,17 @@ func (h *HTTPManager) setContentType(req *http.Request, body string) {
 	if req.Header.Get("Content-Type") != "" {
 		return
 	}
+	req.Header.Set("Content-Type", ContentType(body))
+}
 
+// ContentType is the Content-Type sent with a body when the request doesn't
+// set one
+func ContentType(body string) string {
 	body = strings.TrimSpace(body)
 	if strings.HasPrefix(body, "{") || strings.HasPrefix(body, "[") {
 		if json.Valid([]byte(body)) {
-			req.Header.Set("Content-Type", "application/json")
-			return
+			return "application/json"
 		}
 	}
-
-	req.Header.Set("Content-Type", "text/plain")
+	return "text/plain"
 }
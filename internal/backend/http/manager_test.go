@@ -89,8 +89,6 @@ func TestValidateRequest(t *testing.T) {
 }
 
 func TestBuildURL(t *testing.T) {
-	manager := NewHTTPManager()
-
 	tests := []struct {
 		baseURL     string
 		queryParams map[string]string
@@ -102,9 +100,9 @@ func TestBuildURL(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		result, err := manager.buildURL(test.baseURL, test.queryParams)
+		result, err := BuildURL(test.baseURL, test.queryParams)
 		if err != nil {
-			t.Errorf("buildURL failed: %v", err)
+			t.Errorf("BuildURL failed: %v", err)
 		}
 		if result != test.expected {
 			t.Errorf("expected %s, got %s", test.expected, result)
@@ -0,0 +1,343 @@
+// Package load sends a request repeatedly, at a fixed concurrency or rate,
+// and reports throughput, errors and latency percentiles. It is meant for
+// quick capacity checks rather than full load tests.
+package load
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ProgressInterval is how often Run reports progress
+const ProgressInterval = 250 * time.Millisecond
+
+type Options struct {
+	// Requests stops the test after this many requests
+	Requests int
+	// Duration stops the test after this long
+	Duration time.Duration
+	// Concurrency is the number of requests in flight at once
+	Concurrency int
+	// Rate caps the requests started per second across all workers. Zero
+	// sends as fast as the workers allow.
+	Rate float64
+}
+
+// Validate checks the options. A test needs a request count, a duration or
+// both, in which case it stops at whichever comes first.
+func (o Options) Validate() error {
+	switch {
+	case o.Requests < 0:
+		return errors.New("requests must not be negative")
+	case o.Duration < 0:
+		return errors.New("duration must not be negative")
+	case o.Requests == 0 && o.Duration == 0:
+		return errors.New("set a number of requests or a duration")
+	case o.Concurrency < 1:
+		return errors.New("concurrency must be at least 1")
+	case o.Rate < 0:
+		return errors.New("rate must not be negative")
+	}
+	return nil
+}
+
+// Sample is the outcome of one request. Err is set when no response was
+// received.
+type Sample struct {
+	Status   int
+	Duration time.Duration
+	Err      error
+}
+
+// Failed reports whether the request errored or got an error status
+func (s Sample) Failed() bool {
+	return s.Err != nil || s.Status >= 400
+}
+
+// Send performs one request
+type Send func(ctx context.Context) Sample
+
+// Latency summarizes response times
+type Latency struct {
+	Min  time.Duration
+	Mean time.Duration
+	P50  time.Duration
+	P90  time.Duration
+	P99  time.Duration
+	Max  time.Duration
+}
+
+type Report struct {
+	Options   Options
+	Requests  int
+	Failed    int
+	Elapsed   time.Duration
+	Latency   Latency
+	Statuses  map[int]int
+	Errors    map[string]int
+	Completed bool
+}
+
+// Throughput is the number of completed requests per second
+func (r Report) Throughput() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Requests) / r.Elapsed.Seconds()
+}
+
+// ErrorRate is the share of requests that failed, between 0 and 1
+func (r Report) ErrorRate() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return float64(r.Failed) / float64(r.Requests)
+}
+
+// MarshalJSON writes durations in milliseconds so reports are easy to read
+// and to compare in other tools
+func (r Report) MarshalJSON() ([]byte, error) {
+	ms := func(d time.Duration) float64 {
+		return math.Round(float64(d)/float64(time.Microsecond)) / 1000
+	}
+	type latency struct {
+		Min  float64 `json:"min"`
+		Mean float64 `json:"mean"`
+		P50  float64 `json:"p50"`
+		P90  float64 `json:"p90"`
+		P99  float64 `json:"p99"`
+		Max  float64 `json:"max"`
+	}
+	statuses := make(map[string]int, len(r.Statuses))
+	for status, count := range r.Statuses {
+		statuses[fmt.Sprint(status)] = count
+	}
+	return json.Marshal(struct {
+		Requests    int            `json:"requests"`
+		Failed      int            `json:"failed"`
+		ErrorRate   float64        `json:"errorRate"`
+		ElapsedMs   float64        `json:"elapsedMs"`
+		Throughput  float64        `json:"requestsPerSecond"`
+		Concurrency int            `json:"concurrency"`
+		Rate        float64        `json:"rate,omitempty"`
+		Latency     latency        `json:"latencyMs"`
+		Statuses    map[string]int `json:"statuses"`
+		Errors      map[string]int `json:"errors,omitempty"`
+		Completed   bool           `json:"completed"`
+	}{
+		Requests:    r.Requests,
+		Failed:      r.Failed,
+		ErrorRate:   math.Round(r.ErrorRate()*10000) / 10000,
+		ElapsedMs:   ms(r.Elapsed),
+		Throughput:  math.Round(r.Throughput()*100) / 100,
+		Concurrency: r.Options.Concurrency,
+		Rate:        r.Options.Rate,
+		Latency: latency{
+			Min:  ms(r.Latency.Min),
+			Mean: ms(r.Latency.Mean),
+			P50:  ms(r.Latency.P50),
+			P90:  ms(r.Latency.P90),
+			P99:  ms(r.Latency.P99),
+			Max:  ms(r.Latency.Max),
+		},
+		Statuses:  statuses,
+		Errors:    r.Errors,
+		Completed: r.Completed,
+	})
+}
+
+// WriteFile exports the report as indented JSON
+func (r Report) WriteFile(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// FileName suggests a file name for an endpoint's report
+func FileName(endpointName string, at time.Time) string {
+	name := strings.Trim(unsafeFileChars.ReplaceAllString(strings.ToLower(endpointName), "-"), "-")
+	if name == "" {
+		name = "endpoint"
+	}
+	return fmt.Sprintf("load-%s-%s.json", name, at.Format("20060102-150405"))
+}
+
+// Run sends requests until the options say to stop or ctx is cancelled.
+// onProgress, when set, receives a report every ProgressInterval while the
+// test runs. The final report is returned; Completed is false when the test
+// was cancelled.
+func Run(ctx context.Context, options Options, send Send, onProgress func(Report)) (Report, error) {
+	if err := options.Validate(); err != nil {
+		return Report{}, err
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if options.Duration > 0 {
+		runCtx, cancel = context.WithTimeout(runCtx, options.Duration)
+		defer cancel()
+	}
+
+	stats := newCollector(options)
+	jobs := make(chan struct{})
+	go dispatch(runCtx, options, jobs)
+
+	var workers sync.WaitGroup
+	for range options.Concurrency {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for range jobs {
+				sample := send(runCtx)
+				// requests cut short by the end of the test don't count
+				if sample.Err != nil && runCtx.Err() != nil {
+					continue
+				}
+				stats.add(sample)
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(done)
+	}()
+
+	ticker := time.NewTicker(ProgressInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			report := stats.report()
+			report.Completed = ctx.Err() == nil
+			return report, nil
+		case <-ticker.C:
+			if onProgress != nil {
+				onProgress(stats.report())
+			}
+		}
+	}
+}
+
+// dispatch hands out one job per request, paced by the rate, and closes jobs
+// when the test is over
+func dispatch(ctx context.Context, options Options, jobs chan<- struct{}) {
+	defer close(jobs)
+	var tick <-chan time.Time
+	if options.Rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / options.Rate))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for sent := 0; options.Requests == 0 || sent < options.Requests; sent++ {
+		if tick != nil && sent > 0 {
+			select {
+			case <-tick:
+			case <-ctx.Done():
+				return
+			}
+		}
+		select {
+		case jobs <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+type collector struct {
+	mu        sync.Mutex
+	options   Options
+	start     time.Time
+	durations []time.Duration
+	failed    int
+	statuses  map[int]int
+	errors    map[string]int
+}
+
+func newCollector(options Options) *collector {
+	return &collector{
+		options:  options,
+		start:    time.Now(),
+		statuses: map[int]int{},
+		errors:   map[string]int{},
+	}
+}
+
+func (c *collector) add(sample Sample) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if sample.Failed() {
+		c.failed++
+	}
+	if sample.Err != nil {
+		c.errors[sample.Err.Error()]++
+		return
+	}
+	c.statuses[sample.Status]++
+	c.durations = append(c.durations, sample.Duration)
+}
+
+func (c *collector) report() Report {
+	c.mu.Lock()
+	durations := append([]time.Duration(nil), c.durations...)
+	report := Report{
+		Options:  c.options,
+		Requests: len(c.durations),
+		Failed:   c.failed,
+		Elapsed:  time.Since(c.start),
+		Statuses: make(map[int]int, len(c.statuses)),
+		Errors:   make(map[string]int, len(c.errors)),
+	}
+	for status, count := range c.statuses {
+		report.Statuses[status] = count
+	}
+	for message, count := range c.errors {
+		report.Errors[message] = count
+		report.Requests += count
+	}
+	c.mu.Unlock()
+
+	report.Latency = summarize(durations)
+	return report
+}
+
+// summarize computes latency statistics, sorting durations in place
+func summarize(durations []time.Duration) Latency {
+	if len(durations) == 0 {
+		return Latency{}
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+	return Latency{
+		Min:  durations[0],
+		Mean: total / time.Duration(len(durations)),
+		P50:  percentile(durations, 50),
+		P90:  percentile(durations, 90),
+		P99:  percentile(durations, 99),
+		Max:  durations[len(durations)-1],
+	}
+}
+
+// percentile uses the nearest-rank method on sorted durations
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank-1, 0)]
+}
@@ -0,0 +1,162 @@
+package load
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunRequests(t *testing.T) {
+	var sent, inFlight, peak atomic.Int64
+	send := func(context.Context) Sample {
+		n := sent.Add(1)
+		current := inFlight.Add(1)
+		for {
+			seen := peak.Load()
+			if current <= seen || peak.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+		time.Sleep(2 * time.Millisecond)
+		inFlight.Add(-1)
+		switch {
+		case n%10 == 0:
+			return Sample{Err: errors.New("connection refused")}
+		case n%5 == 0:
+			return Sample{Status: 500, Duration: 20 * time.Millisecond}
+		}
+		return Sample{Status: 200, Duration: time.Duration(n) * time.Millisecond}
+	}
+
+	report, err := Run(context.Background(), Options{Requests: 100, Concurrency: 4}, send, nil)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if sent.Load() != 100 || report.Requests != 100 {
+		t.Errorf("Expected exactly 100 requests, sent %d and reported %d", sent.Load(), report.Requests)
+	}
+	if peak.Load() > 4 {
+		t.Errorf("Expected at most 4 requests in flight, saw %d", peak.Load())
+	}
+	if report.Failed != 20 || report.Errors["connection refused"] != 10 || report.Statuses[500] != 10 || report.Statuses[200] != 80 {
+		t.Errorf("Unexpected outcome counts: failed %d, statuses %v, errors %v", report.Failed, report.Statuses, report.Errors)
+	}
+	if report.ErrorRate() != 0.2 {
+		t.Errorf("Expected a 20%% error rate, got %v", report.ErrorRate())
+	}
+	if !report.Completed || report.Throughput() <= 0 {
+		t.Errorf("Expected a completed report with throughput, got %+v", report)
+	}
+	if report.Latency.Min != time.Millisecond || report.Latency.Max != 99*time.Millisecond {
+		t.Errorf("Unexpected latency range %v - %v", report.Latency.Min, report.Latency.Max)
+	}
+}
+
+func TestRunDurationAndRate(t *testing.T) {
+	send := func(context.Context) Sample {
+		return Sample{Status: 204, Duration: time.Millisecond}
+	}
+	var progress int
+	start := time.Now()
+	report, err := Run(context.Background(), Options{Duration: 600 * time.Millisecond, Concurrency: 2, Rate: 50}, send, func(Report) { progress++ })
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 600*time.Millisecond || elapsed > 2*time.Second {
+		t.Errorf("Expected the test to stop after its duration, took %v", elapsed)
+	}
+	// 50 per second for 0.6 seconds, with the first request sent at once
+	if report.Requests < 20 || report.Requests > 40 {
+		t.Errorf("Expected the rate to pace requests, got %d", report.Requests)
+	}
+	if progress == 0 {
+		t.Error("Expected progress reports while running")
+	}
+}
+
+func TestRunCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	send := func(ctx context.Context) Sample {
+		<-ctx.Done()
+		return Sample{Err: ctx.Err()}
+	}
+	time.AfterFunc(50*time.Millisecond, cancel)
+	report, err := Run(ctx, Options{Requests: 10, Concurrency: 2}, send, nil)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if report.Completed || report.Requests != 0 {
+		t.Errorf("Expected an incomplete report without cut-short requests, got %+v", report)
+	}
+}
+
+func TestOptionsValidate(t *testing.T) {
+	invalid := []Options{
+		{Concurrency: 1},
+		{Requests: 10},
+		{Requests: -1, Concurrency: 1},
+		{Duration: time.Second, Concurrency: 1, Rate: -1},
+	}
+	for _, options := range invalid {
+		if err := options.Validate(); err == nil {
+			t.Errorf("Expected %+v to be invalid", options)
+		}
+	}
+	if err := (Options{Duration: time.Second, Concurrency: 1}).Validate(); err != nil {
+		t.Errorf("Expected a duration alone to be valid, got %v", err)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	durations := make([]time.Duration, 100)
+	for i := range durations {
+		durations[i] = time.Duration(100-i) * time.Millisecond
+	}
+	latency := summarize(durations)
+	if latency.P50 != 50*time.Millisecond || latency.P90 != 90*time.Millisecond || latency.P99 != 99*time.Millisecond {
+		t.Errorf("Unexpected percentiles %+v", latency)
+	}
+	if latency.Mean != 50500*time.Microsecond {
+		t.Errorf("Expected a mean of 50.5ms, got %v", latency.Mean)
+	}
+	if (summarize(nil) != Latency{}) {
+		t.Error("Expected no samples to give zero latency")
+	}
+}
+
+func TestReportWriteFile(t *testing.T) {
+	report := Report{
+		Options:   Options{Requests: 2, Concurrency: 1},
+		Requests:  2,
+		Failed:    1,
+		Elapsed:   time.Second,
+		Latency:   Latency{P50: 1500 * time.Microsecond},
+		Statuses:  map[int]int{200: 1, 503: 1},
+		Completed: true,
+	}
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := report.WriteFile(path); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		ErrorRate  float64            `json:"errorRate"`
+		Throughput float64            `json:"requestsPerSecond"`
+		Latency    map[string]float64 `json:"latencyMs"`
+		Statuses   map[string]int     `json:"statuses"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Expected valid JSON: %v", err)
+	}
+	if decoded.ErrorRate != 0.5 || decoded.Throughput != 2 || decoded.Latency["p50"] != 1.5 || decoded.Statuses["503"] != 1 {
+		t.Errorf("Unexpected report JSON %s", data)
+	}
+}
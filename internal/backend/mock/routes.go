@@ -0,0 +1,287 @@
+package mock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/maniac-en/req/internal/backend/crud"
+	"github.com/maniac-en/req/internal/backend/endpoints"
+	"github.com/maniac-en/req/internal/backend/history"
+	"github.com/maniac-en/req/internal/backend/snapshots"
+	"github.com/maniac-en/req/internal/diff"
+	"github.com/maniac-en/req/internal/log"
+)
+
+// Where a route's response comes from
+const (
+	SourceDefinition = "definition"
+	SourceSnapshot   = "snapshot"
+	SourceHistory    = "history"
+	SourceMissing    = "missing"
+)
+
+// Config is a routes file. It sets server-wide overrides and static
+// definitions.
+type Config struct {
+	Latency string       `json:"latency,omitempty"`
+	Status  int          `json:"status,omitempty"`
+	Routes  []Definition `json:"routes"`
+}
+
+// Definition is a static response. Naming an endpoint overrides parts of
+// that endpoint's response; giving a method and path adds a new route.
+type Definition struct {
+	Endpoint string            `json:"endpoint,omitempty"`
+	Method   string            `json:"method,omitempty"`
+	Path     string            `json:"path,omitempty"`
+	Status   int               `json:"status,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	// Body is written as is when it is a JSON string and as JSON otherwise
+	Body    json.RawMessage `json:"body,omitempty"`
+	Latency string          `json:"latency,omitempty"`
+}
+
+// ReadConfig reads a routes file
+func ReadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return config, nil
+}
+
+// ParseLatency reads a latency like 250ms or 2s. An empty value is zero.
+func ParseLatency(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	latency, err := time.ParseDuration(value)
+	if err != nil || latency < 0 {
+		return 0, fmt.Errorf("invalid latency %q, expected a duration like 250ms", value)
+	}
+	return latency, nil
+}
+
+type Loader struct {
+	Endpoints *endpoints.EndpointsManager
+	Snapshots *snapshots.SnapshotsManager
+	History   *history.HistoryManager
+}
+
+func NewLoader(endpointsManager *endpoints.EndpointsManager, snapshotsManager *snapshots.SnapshotsManager, historyManager *history.HistoryManager) *Loader {
+	return &Loader{
+		Endpoints: endpointsManager,
+		Snapshots: snapshotsManager,
+		History:   historyManager,
+	}
+}
+
+// Load builds a route for every HTTP endpoint of a collection. Responses
+// come from the endpoint's definition, its pinned snapshot or its latest
+// successful history entry, in that order. Endpoints with none of them
+// answer 501 so clients see what is missing.
+func (l *Loader) Load(ctx context.Context, collectionID int64, definitions []Definition) ([]Route, error) {
+	all, err := l.Endpoints.ListByCollection(ctx, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	byEndpoint := map[string]Definition{}
+	var static []Definition
+	for _, definition := range definitions {
+		if definition.Endpoint == "" {
+			if definition.Method == "" || definition.Path == "" {
+				return nil, errors.New("a route needs an endpoint name, or a method and a path")
+			}
+			static = append(static, definition)
+			continue
+		}
+		byEndpoint[definition.Endpoint] = definition
+	}
+
+	var routes []Route
+	for i := len(all) - 1; i >= 0; i-- {
+		endpoint := all[i]
+		if endpoint.IsGRPC() {
+			log.Info("skipping gRPC endpoint in mock server", "endpoint", endpoint.Name)
+			continue
+		}
+
+		route := Route{
+			Method:  endpoint.Method,
+			Pattern: RoutePath(endpoint.Url),
+			Name:    endpoint.Name,
+		}
+		route.Response, route.Source, err = l.recorded(ctx, endpoint)
+		if err != nil {
+			return nil, err
+		}
+		if definition, ok := byEndpoint[endpoint.Name]; ok {
+			delete(byEndpoint, endpoint.Name)
+			if route.Response, err = apply(route.Response, definition); err != nil {
+				return nil, fmt.Errorf("%s: %w", endpoint.Name, err)
+			}
+			if len(definition.Body) > 0 || route.Source == SourceMissing {
+				route.Source = SourceDefinition
+			}
+		}
+		routes = append(routes, route)
+	}
+	for name := range byEndpoint {
+		return nil, fmt.Errorf("no endpoint named %q in the collection", name)
+	}
+
+	for _, definition := range static {
+		response, err := apply(Response{Status: http.StatusOK}, definition)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", definition.Method, definition.Path, err)
+		}
+		routes = append(routes, Route{
+			Method:   definition.Method,
+			Pattern:  definition.Path,
+			Source:   SourceDefinition,
+			Response: response,
+		})
+	}
+	return routes, nil
+}
+
+// recorded finds a response the endpoint really returned
+func (l *Loader) recorded(ctx context.Context, endpoint endpoints.EndpointEntity) (Response, string, error) {
+	if l.Snapshots != nil {
+		snapshot, err := l.Snapshots.ReadByEndpoint(ctx, endpoint.ID)
+		if err == nil {
+			return fromRecorded(snapshot.Response()), SourceSnapshot, nil
+		}
+		if !errors.Is(err, crud.ErrNotFound) {
+			return Response{}, "", err
+		}
+	}
+	if l.History != nil {
+		entry, err := l.History.LatestSuccessful(ctx, endpoint.CollectionID, endpoint.Name)
+		if err == nil {
+			return fromRecorded(entry.Response()), SourceHistory, nil
+		}
+		if !errors.Is(err, crud.ErrNotFound) {
+			return Response{}, "", err
+		}
+	}
+
+	body, _ := json.Marshal(map[string]string{
+		"error": fmt.Sprintf("no example for %q: send it once, pin a snapshot or define a response", endpoint.Name),
+	})
+	return Response{
+		Status:  http.StatusNotImplemented,
+		Headers: map[string][]string{"Content-Type": {"application/json"}},
+		Body:    string(body),
+	}, SourceMissing, nil
+}
+
+// skippedHeaders describe the original transfer rather than the response,
+// so they are not replayed
+var skippedHeaders = map[string]bool{
+	"content-length":    true,
+	"content-encoding":  true,
+	"transfer-encoding": true,
+	"connection":        true,
+	"keep-alive":        true,
+	"date":              true,
+}
+
+func fromRecorded(recorded diff.Response) Response {
+	headers := map[string][]string{}
+	for name, values := range recorded.Headers {
+		if !skippedHeaders[strings.ToLower(name)] {
+			headers[name] = values
+		}
+	}
+	return Response{Status: recorded.Status, Headers: headers, Body: recorded.Body}
+}
+
+// apply overrides the parts of a response a definition sets
+func apply(response Response, definition Definition) (Response, error) {
+	latency, err := ParseLatency(definition.Latency)
+	if err != nil {
+		return response, err
+	}
+	response.Latency = latency
+	if definition.Status != 0 {
+		response.Status = definition.Status
+	}
+
+	headers := map[string][]string{}
+	for name, values := range response.Headers {
+		headers[name] = values
+	}
+	if len(definition.Body) > 0 {
+		var text string
+		if err := json.Unmarshal(definition.Body, &text); err == nil {
+			response.Body = text
+		} else {
+			response.Body = string(definition.Body)
+			if len(definition.Headers) == 0 || !hasHeader(definition.Headers, "Content-Type") {
+				deleteHeader(headers, "Content-Type")
+				headers["Content-Type"] = []string{"application/json"}
+			}
+		}
+	}
+	for name, value := range definition.Headers {
+		deleteHeader(headers, name)
+		headers[name] = []string{value}
+	}
+	response.Headers = headers
+	return response, nil
+}
+
+func hasHeader(headers map[string]string, name string) bool {
+	for header := range headers {
+		if strings.EqualFold(header, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func deleteHeader(headers map[string][]string, name string) {
+	for header := range headers {
+		if strings.EqualFold(header, name) {
+			delete(headers, header)
+		}
+	}
+}
+
+// RoutePath returns the path of an endpoint URL. A leading {{variable}},
+// usually a base URL, is dropped along with the scheme, host and query.
+func RoutePath(rawURL string) string {
+	path := strings.TrimSpace(rawURL)
+	if strings.HasPrefix(path, "{{") {
+		if end := strings.Index(path, "}}"); end >= 0 {
+			path = path[end+2:]
+		}
+	}
+	if scheme := strings.Index(path, "://"); scheme >= 0 {
+		path = path[scheme+3:]
+		if slash := strings.Index(path, "/"); slash >= 0 {
+			path = path[slash:]
+		} else {
+			path = "/"
+		}
+	}
+	if end := strings.IndexAny(path, "?#"); end >= 0 {
+		path = path[:end]
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return path
+}
@@ -0,0 +1,138 @@
+package mock
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/maniac-en/req/internal/backend/database"
+	"github.com/maniac-en/req/internal/backend/endpoints"
+	"github.com/maniac-en/req/internal/backend/history"
+	"github.com/maniac-en/req/internal/backend/snapshots"
+	"github.com/maniac-en/req/internal/backend/testutils"
+	"github.com/maniac-en/req/internal/diff"
+)
+
+func createEndpoint(t *testing.T, db *database.Queries, collectionID int64, name, method, url, protocol string) int64 {
+	t.Helper()
+	endpoint, err := db.CreateEndpoint(context.Background(), database.CreateEndpointParams{
+		CollectionID: collectionID,
+		Name:         name,
+		Method:       method,
+		Url:          url,
+		Headers:      "{}",
+		QueryParams:  "{}",
+		Protocol:     protocol,
+		ProtoFiles:   "[]",
+		Extractions:  "[]",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test endpoint: %v", err)
+	}
+	return endpoint.ID
+}
+
+func TestRoutePath(t *testing.T) {
+	tests := map[string]string{
+		"{{baseUrl}}/users/{{id}}":          "/users/{{id}}",
+		"https://api.example.com/users?x=1": "/users",
+		"https://api.example.com":           "/",
+		"users/1#top":                       "/users/1",
+		"{{host}}":                          "/",
+	}
+	for url, expected := range tests {
+		if path := RoutePath(url); path != expected {
+			t.Errorf("RoutePath(%q): expected %q, got %q", url, expected, path)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	ctx := context.Background()
+	db := testutils.SetupTestDB(t, "collections", "endpoints", "snapshots", "history")
+	collectionID := testutils.CreateTestCollection(t, db, "Mock")
+	snapshotsManager := snapshots.NewSnapshotsManager(db)
+	historyManager := history.NewHistoryManager(db)
+	loader := NewLoader(endpoints.NewEndpointsManager(db), snapshotsManager, historyManager)
+
+	pinnedID := createEndpoint(t, db, collectionID, "Get User", "GET", "{{baseUrl}}/users/{{id}}", "http")
+	createEndpoint(t, db, collectionID, "List Users", "GET", "https://api.example.com/users", "http")
+	createEndpoint(t, db, collectionID, "Delete User", "DELETE", "{{baseUrl}}/users/{{id}}", "http")
+	createEndpoint(t, db, collectionID, "Say Hello", "", "localhost:50051", "grpc")
+
+	if _, err := snapshotsManager.Pin(ctx, pinnedID, diff.Response{
+		Status:  200,
+		Headers: map[string][]string{"Content-Type": {"application/json"}, "Date": {"Mon"}, "Content-Length": {"9"}},
+		Body:    `{"id": 1}`,
+	}); err != nil {
+		t.Fatalf("Pin failed: %v", err)
+	}
+	if _, err := historyManager.RecordExecution(ctx, history.ExecutionData{
+		CollectionID: collectionID, EndpointName: "List Users", Method: "GET", URL: "https://api.example.com/users",
+		StatusCode: 200, ResponseBody: "[]",
+	}); err != nil {
+		t.Fatalf("RecordExecution failed: %v", err)
+	}
+
+	t.Run("Sources", func(t *testing.T) {
+		routes, err := loader.Load(ctx, collectionID, nil)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if len(routes) != 3 {
+			t.Fatalf("Expected gRPC endpoints to be skipped, got %d routes", len(routes))
+		}
+		byName := map[string]Route{}
+		for _, route := range routes {
+			byName[route.Name] = route
+		}
+
+		user := byName["Get User"]
+		if user.Source != SourceSnapshot || user.Pattern != "/users/{{id}}" || user.Response.Body != `{"id": 1}` {
+			t.Errorf("Expected the snapshot to be served, got %+v", user)
+		}
+		if _, ok := user.Response.Headers["Date"]; ok {
+			t.Errorf("Expected transfer headers to be dropped, got %v", user.Response.Headers)
+		}
+		if list := byName["List Users"]; list.Source != SourceHistory || list.Response.Body != "[]" {
+			t.Errorf("Expected the latest history entry to be served, got %+v", list)
+		}
+		if missing := byName["Delete User"]; missing.Source != SourceMissing || missing.Response.Status != 501 {
+			t.Errorf("Expected a 501 without an example, got %+v", missing)
+		}
+	})
+
+	t.Run("Definitions", func(t *testing.T) {
+		routes, err := loader.Load(ctx, collectionID, []Definition{
+			{Endpoint: "Get User", Status: 404, Latency: "10ms"},
+			{Endpoint: "Delete User", Status: 204, Body: json.RawMessage(`""`)},
+			{Method: "GET", Path: "/health", Body: json.RawMessage(`{"ok": true}`)},
+		})
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		byName := map[string]Route{}
+		for _, route := range routes {
+			byName[route.Name+route.Pattern] = route
+		}
+
+		user := byName["Get User/users/{{id}}"]
+		if user.Source != SourceSnapshot || user.Response.Status != 404 || user.Response.Body != `{"id": 1}` || user.Response.Latency == 0 {
+			t.Errorf("Expected the status and latency to override the snapshot, got %+v", user)
+		}
+		if deleted := byName["Delete User/users/{{id}}"]; deleted.Source != SourceDefinition || deleted.Response.Status != 204 {
+			t.Errorf("Expected the definition to be served, got %+v", deleted)
+		}
+		health := byName["/health"]
+		if health.Response.Status != 200 || health.Response.Body != `{"ok": true}` || health.Response.Headers["Content-Type"][0] != "application/json" {
+			t.Errorf("Expected a static JSON route, got %+v", health)
+		}
+
+		if _, err := loader.Load(ctx, collectionID, []Definition{{Endpoint: "Nope"}}); err == nil {
+			t.Error("Expected an error for an unknown endpoint")
+		}
+		if _, err := loader.Load(ctx, collectionID, []Definition{{Path: "/x"}}); err == nil {
+			t.Error("Expected an error for a route without a method")
+		}
+	})
+}
@@ -0,0 +1,225 @@
+// Package mock serves a collection's endpoints from a local HTTP server so
+// clients can be built before the real API exists.
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/maniac-en/req/internal/log"
+)
+
+// StatusHeader lets a client ask for a specific status on a single request
+const StatusHeader = "X-Mock-Status"
+
+// Response is what a route answers with
+type Response struct {
+	Status  int
+	Headers map[string][]string
+	Body    string
+	// Latency delays the response, on top of the server's latency
+	Latency time.Duration
+}
+
+// Route maps a method and path pattern to a response. Pattern segments
+// written as {{name}}, {name} or :name match any single path segment, and
+// their values replace {{name}} in the response body.
+type Route struct {
+	Method   string
+	Pattern  string
+	Name     string
+	Source   string
+	Response Response
+	segments []string
+}
+
+// Params returns the path parameters when the route matches a request path
+func (r Route) Params(path string) (map[string]string, bool) {
+	segments := splitPath(path)
+	if len(segments) != len(r.segments) {
+		return nil, false
+	}
+	params := map[string]string{}
+	for i, segment := range r.segments {
+		if name, ok := paramName(segment); ok {
+			if segments[i] == "" {
+				return nil, false
+			}
+			params[name] = segments[i]
+			continue
+		}
+		if segment != segments[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// literals counts the segments that must match exactly. Routes with more of
+// them are preferred.
+func (r Route) literals() int {
+	count := 0
+	for _, segment := range r.segments {
+		if _, ok := paramName(segment); !ok {
+			count++
+		}
+	}
+	return count
+}
+
+type Server struct {
+	routes []Route
+	// Latency delays every response
+	Latency time.Duration
+	// Status overrides the status of every response when set
+	Status int
+	// OnRequest is called after each request is answered
+	OnRequest func(Hit)
+}
+
+// Hit describes an answered request. Route is nil when nothing matched.
+type Hit struct {
+	Method string
+	Path   string
+	Status int
+	Route  *Route
+}
+
+func NewServer(routes []Route) *Server {
+	sorted := make([]Route, len(routes))
+	for i, route := range routes {
+		route.Method = strings.ToUpper(route.Method)
+		route.segments = splitPath(route.Pattern)
+		sorted[i] = route
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].literals() > sorted[j].literals()
+	})
+	return &Server{routes: sorted}
+}
+
+// Routes returns the routes in the order they are matched
+func (s *Server) Routes() []Route {
+	return s.routes
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// browsers preflight cross-origin requests, so allow them all
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, HEAD, OPTIONS")
+		if headers := r.Header.Get("Access-Control-Request-Headers"); headers != "" {
+			w.Header().Set("Access-Control-Allow-Headers", headers)
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	route, params := s.match(r.Method, r.URL.Path)
+	if route == nil {
+		s.notFound(w, r)
+		return
+	}
+
+	response := route.Response
+	if !s.wait(r, s.Latency+response.Latency) {
+		return
+	}
+
+	status := response.Status
+	if s.Status != 0 {
+		status = s.Status
+	}
+	if requested, err := strconv.Atoi(r.Header.Get(StatusHeader)); err == nil && requested >= 100 && requested <= 999 {
+		status = requested
+	}
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	for name, values := range response.Headers {
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
+	body := response.Body
+	for name, value := range params {
+		body = strings.ReplaceAll(body, "{{"+name+"}}", value)
+	}
+	w.WriteHeader(status)
+	if r.Method != http.MethodHead {
+		fmt.Fprint(w, body)
+	}
+	s.hit(Hit{Method: r.Method, Path: r.URL.Path, Status: status, Route: route})
+}
+
+func (s *Server) match(method, path string) (*Route, map[string]string) {
+	for i := range s.routes {
+		route := &s.routes[i]
+		if route.Method != strings.ToUpper(method) {
+			continue
+		}
+		if params, ok := route.Params(path); ok {
+			return route, params
+		}
+	}
+	return nil, nil
+}
+
+// wait sleeps for the latency and reports whether the client is still there
+func (s *Server) wait(r *http.Request, latency time.Duration) bool {
+	if latency <= 0 {
+		return true
+	}
+	timer := time.NewTimer(latency)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-r.Context().Done():
+		log.Debug("mock client went away while waiting", "path", r.URL.Path)
+		return false
+	}
+}
+
+// notFound lists the available routes so a typo is easy to spot
+func (s *Server) notFound(w http.ResponseWriter, r *http.Request) {
+	routes := make([]string, len(s.routes))
+	for i, route := range s.routes {
+		routes[i] = route.Method + " " + route.Pattern
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotFound)
+	json.NewEncoder(w).Encode(map[string]any{
+		"error":  fmt.Sprintf("no mock for %s %s", r.Method, r.URL.Path),
+		"routes": routes,
+	})
+	s.hit(Hit{Method: r.Method, Path: r.URL.Path, Status: http.StatusNotFound})
+}
+
+func (s *Server) hit(hit Hit) {
+	if s.OnRequest != nil {
+		s.OnRequest(hit)
+	}
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+func paramName(segment string) (string, bool) {
+	switch {
+	case strings.HasPrefix(segment, "{{") && strings.HasSuffix(segment, "}}"):
+		return strings.TrimSpace(segment[2 : len(segment)-2]), true
+	case strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}"):
+		return segment[1 : len(segment)-1], true
+	case strings.HasPrefix(segment, ":") && len(segment) > 1:
+		return segment[1:], true
+	}
+	return "", false
+}
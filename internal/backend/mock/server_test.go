@@ -0,0 +1,116 @@
+package mock
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func serve(server *Server, method, path string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestServer(t *testing.T) {
+	server := NewServer([]Route{
+		{Method: "get", Pattern: "/users/{{id}}", Name: "Get User", Response: Response{
+			Status:  200,
+			Headers: map[string][]string{"Content-Type": {"application/json"}},
+			Body:    `{"id": "{{id}}"}`,
+		}},
+		{Method: "GET", Pattern: "/users/me", Name: "Me", Response: Response{Status: 200, Body: "me"}},
+		{Method: "POST", Pattern: "/users/:id/posts/{post}", Name: "Create Post", Response: Response{Status: 201, Body: "{{id}}/{{post}}"}},
+	})
+
+	var hits []Hit
+	server.OnRequest = func(hit Hit) { hits = append(hits, hit) }
+
+	t.Run("Params", func(t *testing.T) {
+		response := serve(server, "GET", "/users/42", nil)
+		if response.Code != 200 || response.Body.String() != `{"id": "42"}` {
+			t.Errorf("Expected the route body with the param filled in, got %d %q", response.Code, response.Body.String())
+		}
+		if response.Header().Get("Content-Type") != "application/json" {
+			t.Errorf("Expected the route headers, got %v", response.Header())
+		}
+
+		response = serve(server, "POST", "/users/7/posts/3", nil)
+		if response.Code != 201 || response.Body.String() != "7/3" {
+			t.Errorf("Expected both params, got %d %q", response.Code, response.Body.String())
+		}
+	})
+
+	t.Run("LiteralsWin", func(t *testing.T) {
+		response := serve(server, "GET", "/users/me", nil)
+		if response.Body.String() != "me" {
+			t.Errorf("Expected the literal route to win over the param route, got %q", response.Body.String())
+		}
+	})
+
+	t.Run("StatusOverrides", func(t *testing.T) {
+		server.Status = 503
+		defer func() { server.Status = 0 }()
+
+		if response := serve(server, "GET", "/users/1", nil); response.Code != 503 {
+			t.Errorf("Expected the server status, got %d", response.Code)
+		}
+		if response := serve(server, "GET", "/users/1", map[string]string{StatusHeader: "418"}); response.Code != 418 {
+			t.Errorf("Expected the header to win over the server status, got %d", response.Code)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		response := serve(server, "DELETE", "/users/1", nil)
+		if response.Code != 404 {
+			t.Fatalf("Expected 404, got %d", response.Code)
+		}
+		var body struct {
+			Routes []string `json:"routes"`
+		}
+		if err := json.Unmarshal(response.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Expected a JSON body: %v", err)
+		}
+		if len(body.Routes) != 3 || body.Routes[2] != "GET /users/{{id}}" {
+			t.Errorf("Expected the routes to be listed, got %v", body.Routes)
+		}
+	})
+
+	t.Run("CORS", func(t *testing.T) {
+		response := serve(server, "OPTIONS", "/users/1", map[string]string{
+			"Origin":                         "http://localhost:3000",
+			"Access-Control-Request-Method":  "GET",
+			"Access-Control-Request-Headers": "Authorization",
+		})
+		if response.Code != http.StatusNoContent {
+			t.Errorf("Expected the preflight to succeed, got %d", response.Code)
+		}
+		if response.Header().Get("Access-Control-Allow-Headers") != "Authorization" {
+			t.Errorf("Expected the requested headers to be allowed, got %v", response.Header())
+		}
+		if serve(server, "GET", "/users/1", nil).Header().Get("Access-Control-Allow-Origin") != "*" {
+			t.Error("Expected responses to allow any origin")
+		}
+	})
+
+	t.Run("Head", func(t *testing.T) {
+		server := NewServer([]Route{{Method: "HEAD", Pattern: "/", Response: Response{Body: "body"}}})
+		response := serve(server, "HEAD", "/", nil)
+		if response.Code != 200 || response.Body.Len() != 0 {
+			t.Errorf("Expected 200 without a body, got %d %q", response.Code, response.Body.String())
+		}
+	})
+
+	if len(hits) == 0 || hits[0].Route == nil || hits[0].Route.Name != "Get User" {
+		t.Errorf("Expected requests to be reported, got %+v", hits)
+	}
+	if last := hits[len(hits)-1]; !strings.HasPrefix(last.Path, "/users") {
+		t.Errorf("Unexpected last hit %+v", last)
+	}
+}
@@ -0,0 +1,300 @@
+// Package proxy implements a recording forward proxy. Requests sent through
+// it are forwarded unchanged and saved into a collection as endpoints and
+// history entries. HTTPS is tunnelled as is unless a local CA is given, in
+// which case connections are intercepted so they can be recorded too.
+package proxy
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/maniac-en/req/internal/log"
+)
+
+// DefaultMaxBodySize caps the bodies kept in memory for a single exchange
+const DefaultMaxBodySize = 10 << 20
+
+// hopHeaders only apply to a single connection and are never forwarded
+var hopHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Proxy-Connection",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+type Proxy struct {
+	Recorder *Recorder
+	// CA intercepts HTTPS when set. Without it HTTPS is tunnelled unrecorded.
+	CA *CA
+	// Transport sends requests upstream, http.DefaultTransport when nil
+	Transport   http.RoundTripper
+	MaxBodySize int64
+	// OnExchange is called after each exchange is answered. Recording errors
+	// are passed along rather than failing the request.
+	OnExchange func(Exchange, Recorded, error)
+}
+
+func NewProxy(recorder *Recorder, ca *CA) *Proxy {
+	return &Proxy{Recorder: recorder, CA: ca, MaxBodySize: DefaultMaxBodySize}
+}
+
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodConnect {
+		p.connect(w, r)
+		return
+	}
+	if !r.URL.IsAbs() {
+		http.Error(w, "req record is a proxy: send requests through it with an absolute URL", http.StatusBadRequest)
+		return
+	}
+
+	response, exchange, err := p.forward(r)
+	if err != nil {
+		log.Warn("proxy request failed", "url", r.URL.String(), "error", err)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	copyHeaders(w.Header(), response.Header)
+	w.WriteHeader(response.StatusCode)
+	w.Write(exchange.ResponseBody)
+	p.record(r.Context(), exchange)
+}
+
+// forward sends a request upstream and reads the whole response
+func (p *Proxy) forward(r *http.Request) (*http.Response, Exchange, error) {
+	requestBody, err := p.readBody(r.Body)
+	if err != nil {
+		return nil, Exchange{}, fmt.Errorf("reading request body: %w", err)
+	}
+
+	outgoing, err := http.NewRequestWithContext(r.Context(), r.Method, r.URL.String(), bytes.NewReader(requestBody))
+	if err != nil {
+		return nil, Exchange{}, err
+	}
+	copyHeaders(outgoing.Header, r.Header)
+	removeHopHeaders(outgoing.Header)
+	// let the transport negotiate compression so recorded bodies are readable
+	outgoing.Header.Del("Accept-Encoding")
+
+	transport := p.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	start := time.Now()
+	response, err := transport.RoundTrip(outgoing)
+	if err != nil {
+		return nil, Exchange{}, err
+	}
+	defer response.Body.Close()
+	responseBody, err := p.readBody(response.Body)
+	if err != nil {
+		return nil, Exchange{}, fmt.Errorf("reading response body: %w", err)
+	}
+	removeHopHeaders(response.Header)
+	response.Header.Del("Content-Length")
+
+	exchange := Exchange{
+		Method:          r.Method,
+		URL:             r.URL,
+		RequestHeaders:  r.Header,
+		RequestBody:     requestBody,
+		Status:          response.StatusCode,
+		ResponseHeaders: response.Header,
+		ResponseBody:    responseBody,
+		Duration:        time.Since(start),
+	}
+	return response, exchange, nil
+}
+
+func (p *Proxy) readBody(body io.ReadCloser) ([]byte, error) {
+	if body == nil {
+		return nil, nil
+	}
+	limit := p.MaxBodySize
+	if limit <= 0 {
+		limit = DefaultMaxBodySize
+	}
+	data, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("body is larger than %d bytes", limit)
+	}
+	return data, nil
+}
+
+func (p *Proxy) record(ctx context.Context, exchange Exchange) {
+	var recorded Recorded
+	var err error
+	if p.Recorder != nil {
+		recorded, err = p.Recorder.Record(context.WithoutCancel(ctx), exchange)
+		if err != nil {
+			log.Warn("failed to record proxied request", "url", exchange.URL.String(), "error", err)
+		}
+	}
+	if p.OnExchange != nil {
+		p.OnExchange(exchange, recorded, err)
+	}
+}
+
+// connect handles an HTTPS CONNECT request by tunnelling or intercepting it
+func (p *Proxy) connect(w http.ResponseWriter, r *http.Request) {
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "connection can't be taken over", http.StatusInternalServerError)
+		return
+	}
+
+	var upstream net.Conn
+	if p.CA == nil {
+		var err error
+		upstream, err = net.DialTimeout("tcp", r.Host, 10*time.Second)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+	}
+
+	client, buffered, err := hijacker.Hijack()
+	if err != nil {
+		if upstream != nil {
+			upstream.Close()
+		}
+		log.Warn("failed to take over proxy connection", "error", err)
+		return
+	}
+	defer client.Close()
+	if _, err := io.WriteString(client, "HTTP/1.1 200 Connection Established\r\n\r\n"); err != nil {
+		if upstream != nil {
+			upstream.Close()
+		}
+		return
+	}
+
+	if upstream != nil {
+		defer upstream.Close()
+		tunnel(client, buffered, upstream)
+		return
+	}
+	p.intercept(r.Context(), client, r.Host)
+}
+
+// tunnel copies bytes both ways until either side closes
+func tunnel(client net.Conn, buffered *bufio.ReadWriter, upstream net.Conn) {
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(upstream, buffered)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(client, upstream)
+		done <- struct{}{}
+	}()
+	<-done
+}
+
+// intercept terminates TLS with a certificate for the host and serves the
+// requests sent over the connection
+func (p *Proxy) intercept(ctx context.Context, client net.Conn, hostPort string) {
+	host, _, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		host = hostPort
+	}
+	conn := tls.Server(client, &tls.Config{
+		GetCertificate: func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+			name := hello.ServerName
+			if name == "" {
+				name = host
+			}
+			return p.CA.Certificate(name)
+		},
+		NextProtos: []string{"http/1.1"},
+	})
+	if err := conn.HandshakeContext(ctx); err != nil {
+		log.Warn("TLS handshake with proxy client failed", "host", host, "error", err)
+		return
+	}
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	for {
+		request, err := http.ReadRequest(reader)
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				log.Debug("stopped reading intercepted connection", "host", host, "error", err)
+			}
+			return
+		}
+		request.URL.Scheme = "https"
+		request.URL.Host = hostPort
+		if strings.HasSuffix(hostPort, ":443") {
+			request.URL.Host = host
+		}
+		request = request.WithContext(ctx)
+
+		response, exchange, err := p.forward(request)
+		if err != nil {
+			log.Warn("proxy request failed", "url", request.URL.String(), "error", err)
+			writeError(conn, http.StatusBadGateway, err.Error())
+			return
+		}
+		response.Body = io.NopCloser(bytes.NewReader(exchange.ResponseBody))
+		response.ContentLength = int64(len(exchange.ResponseBody))
+		response.TransferEncoding = nil
+		response.Close = request.Close
+		if err := response.Write(conn); err != nil {
+			return
+		}
+		p.record(ctx, exchange)
+		if request.Close {
+			return
+		}
+	}
+}
+
+func writeError(conn net.Conn, status int, message string) {
+	response := &http.Response{
+		StatusCode:    status,
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"text/plain; charset=utf-8"}},
+		Body:          io.NopCloser(strings.NewReader(message)),
+		ContentLength: int64(len(message)),
+		Close:         true,
+	}
+	response.Write(conn)
+}
+
+func copyHeaders(dst, src http.Header) {
+	for name, values := range src {
+		for _, value := range values {
+			dst.Add(name, value)
+		}
+	}
+}
+
+func removeHopHeaders(header http.Header) {
+	for _, field := range header["Connection"] {
+		for _, name := range strings.Split(field, ",") {
+			header.Del(strings.TrimSpace(name))
+		}
+	}
+	for _, name := range hopHeaders {
+		header.Del(name)
+	}
+}
@@ -0,0 +1,227 @@
+package proxy
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/maniac-en/req/internal/backend/crud"
+	"github.com/maniac-en/req/internal/backend/endpoints"
+	"github.com/maniac-en/req/internal/backend/history"
+	"github.com/maniac-en/req/internal/backend/testutils"
+)
+
+func newRecorder(t *testing.T) (*Recorder, *endpoints.EndpointsManager, *history.HistoryManager) {
+	t.Helper()
+	db := testutils.SetupTestDB(t, "collections", "endpoints", "history")
+	collectionID := testutils.CreateTestCollection(t, db, "Recorded")
+	endpointsManager := endpoints.NewEndpointsManager(db)
+	historyManager := history.NewHistoryManager(db)
+	recorder, err := NewRecorder(context.Background(), endpointsManager, historyManager, collectionID, "Recorded")
+	if err != nil {
+		t.Fatalf("NewRecorder failed: %v", err)
+	}
+	return recorder, endpointsManager, historyManager
+}
+
+func upstream() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Seen-Auth", r.Header.Get("Authorization"))
+		fmt.Fprintf(w, `{"path": %q, "query": %q, "body": %q}`, r.URL.Path, r.URL.RawQuery, body)
+	}
+}
+
+func TestProxyHTTP(t *testing.T) {
+	ctx := context.Background()
+	recorder, endpointsManager, historyManager := newRecorder(t)
+	server := httptest.NewServer(upstream())
+	defer server.Close()
+
+	proxy := NewProxy(recorder, nil)
+	var exchanges []Recorded
+	proxy.OnExchange = func(_ Exchange, recorded Recorded, err error) {
+		if err != nil {
+			t.Errorf("Unexpected recording error: %v", err)
+		}
+		exchanges = append(exchanges, recorded)
+	}
+	proxyServer := httptest.NewServer(proxy)
+	defer proxyServer.Close()
+	proxyURL, _ := url.Parse(proxyServer.URL)
+	client := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)}}
+
+	for _, query := range []string{"page=1", "page=2"} {
+		req, _ := http.NewRequest("POST", server.URL+"/users?"+query, strings.NewReader(`{"name": "Ada"}`))
+		req.Header.Set("Authorization", "Bearer token")
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Request through proxy failed: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != 200 || resp.Header.Get("X-Seen-Auth") != "Bearer token" || !strings.Contains(string(body), `"path": "/users"`) {
+			t.Errorf("Expected the request to be forwarded unchanged, got %d %v %s", resp.StatusCode, resp.Header, body)
+		}
+	}
+
+	if len(exchanges) != 2 || !exchanges[0].Created || exchanges[1].Created {
+		t.Fatalf("Expected one endpoint for both requests, got %+v", exchanges)
+	}
+
+	all, err := endpointsManager.ListByCollection(ctx, recorder.CollectionID)
+	if err != nil || len(all) != 1 {
+		t.Fatalf("Expected one recorded endpoint, got %d (%v)", len(all), err)
+	}
+	endpoint := all[0]
+	if endpoint.Name != "POST /users" || endpoint.Method != "POST" || endpoint.Url != server.URL+"/users" {
+		t.Errorf("Unexpected endpoint %+v", endpoint.Endpoint)
+	}
+	if endpoint.GetHeaders()["Authorization"] != "Bearer token" || endpoint.GetQueryParams()["page"] != "1" || endpoint.RequestBody != `{"name": "Ada"}` {
+		t.Errorf("Expected the first request to be saved, got headers %v, query %v, body %q", endpoint.GetHeaders(), endpoint.GetQueryParams(), endpoint.RequestBody)
+	}
+	if _, ok := endpoint.GetHeaders()["Proxy-Connection"]; ok {
+		t.Errorf("Expected proxy headers to be dropped, got %v", endpoint.GetHeaders())
+	}
+
+	entries, err := historyManager.ListByCollection(ctx, recorder.CollectionID, 10, 0)
+	if err != nil || len(entries.Items) != 2 {
+		t.Fatalf("Expected a history entry per request, got %+v (%v)", entries, err)
+	}
+	if entries.Items[0].EndpointName.String != "POST /users" || entries.Items[0].StatusCode != 200 {
+		t.Errorf("Unexpected history entry %+v", entries.Items[0].History)
+	}
+
+	t.Run("ExistingEndpoints", func(t *testing.T) {
+		again, err := NewRecorder(ctx, endpointsManager, historyManager, recorder.CollectionID, "Recorded")
+		if err != nil {
+			t.Fatalf("NewRecorder failed: %v", err)
+		}
+		recorded, err := again.Record(ctx, Exchange{Method: "POST", URL: mustParse(server.URL + "/users"), Status: 201})
+		if err != nil || recorded.Created || recorded.EndpointName != "POST /users" {
+			t.Errorf("Expected the existing endpoint to be reused, got %+v (%v)", recorded, err)
+		}
+
+		other := mustParse("http://other.example.com/users")
+		recorded, err = again.Record(ctx, Exchange{Method: "POST", URL: other, Status: 200})
+		if err != nil || recorded.EndpointName != "POST other.example.com/users" {
+			t.Errorf("Expected the host to tell the endpoints apart, got %+v (%v)", recorded, err)
+		}
+	})
+
+	t.Run("LongPaths", func(t *testing.T) {
+		long := "/" + strings.Repeat("segment/", 15)
+		var names []string
+		for _, last := range []string{"a", "b", "c"} {
+			recorded, err := recorder.Record(ctx, Exchange{Method: "GET", URL: mustParse(server.URL + long + last), Status: 200})
+			if err != nil {
+				t.Fatalf("Record failed: %v", err)
+			}
+			if len(recorded.EndpointName) > crud.MaxNameLength {
+				t.Errorf("Expected the name to fit %d bytes, got %d: %q", crud.MaxNameLength, len(recorded.EndpointName), recorded.EndpointName)
+			}
+			names = append(names, recorded.EndpointName)
+		}
+		if names[0] == names[1] || names[1] == names[2] || !strings.HasSuffix(names[2], " 2") {
+			t.Errorf("Expected distinct names keeping the number, got %q", names)
+		}
+	})
+
+	t.Run("RelativeURL", func(t *testing.T) {
+		resp, err := http.Get(proxyServer.URL + "/users")
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected direct requests to be refused, got %d", resp.StatusCode)
+		}
+	})
+}
+
+func TestProxyHTTPS(t *testing.T) {
+	recorder, endpointsManager, _ := newRecorder(t)
+	server := httptest.NewTLSServer(upstream())
+	defer server.Close()
+
+	ca, err := LoadOrCreateCA(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadOrCreateCA failed: %v", err)
+	}
+	proxy := NewProxy(recorder, ca)
+	proxy.Transport = server.Client().Transport
+	proxyServer := httptest.NewServer(proxy)
+	defer proxyServer.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Cert)
+	proxyURL, _ := url.Parse(proxyServer.URL)
+	transport := &http.Transport{Proxy: http.ProxyURL(proxyURL)}
+	transport.TLSClientConfig = server.Client().Transport.(*http.Transport).TLSClientConfig.Clone()
+	transport.TLSClientConfig.RootCAs = roots
+	client := &http.Client{Transport: transport}
+
+	for range 2 {
+		resp, err := client.Get(server.URL + "/secure")
+		if err != nil {
+			t.Fatalf("HTTPS request through proxy failed: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if !strings.Contains(string(body), `"path": "/secure"`) {
+			t.Errorf("Expected the intercepted request to be forwarded, got %s", body)
+		}
+	}
+
+	all, err := endpointsManager.ListByCollection(context.Background(), recorder.CollectionID)
+	if err != nil || len(all) != 1 {
+		t.Fatalf("Expected one recorded endpoint, got %d (%v)", len(all), err)
+	}
+	if all[0].Url != server.URL+"/secure" {
+		t.Errorf("Expected the HTTPS URL to be recorded, got %q", all[0].Url)
+	}
+}
+
+func TestLoadOrCreateCA(t *testing.T) {
+	dir := t.TempDir()
+	created, err := LoadOrCreateCA(dir)
+	if err != nil {
+		t.Fatalf("LoadOrCreateCA failed: %v", err)
+	}
+	loaded, err := LoadOrCreateCA(dir)
+	if err != nil {
+		t.Fatalf("LoadOrCreateCA failed: %v", err)
+	}
+	if !created.Cert.Equal(loaded.Cert) {
+		t.Error("Expected the stored CA to be reused")
+	}
+
+	leaf, err := loaded.Certificate("api.example.com")
+	if err != nil {
+		t.Fatalf("Certificate failed: %v", err)
+	}
+	cert, err := x509.ParseCertificate(leaf.Certificate[0])
+	if err != nil {
+		t.Fatalf("Invalid leaf certificate: %v", err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(created.Cert)
+	if _, err := cert.Verify(x509.VerifyOptions{DNSName: "api.example.com", Roots: roots}); err != nil {
+		t.Errorf("Expected the leaf to be signed by the CA: %v", err)
+	}
+}
+
+func mustParse(raw string) *url.URL {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}
@@ -0,0 +1,220 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/maniac-en/req/internal/backend/crud"
+	"github.com/maniac-en/req/internal/backend/endpoints"
+	"github.com/maniac-en/req/internal/backend/history"
+	"github.com/maniac-en/req/internal/log"
+)
+
+// Exchange is a request that passed through the proxy and its response
+type Exchange struct {
+	Method          string
+	URL             *url.URL
+	RequestHeaders  http.Header
+	RequestBody     []byte
+	Status          int
+	ResponseHeaders http.Header
+	ResponseBody    []byte
+	Duration        time.Duration
+}
+
+// Recorded says where an exchange was stored
+type Recorded struct {
+	EndpointName string
+	// Created is set when the exchange added a new endpoint
+	Created   bool
+	HistoryID int64
+}
+
+// skippedRequestHeaders belong to the connection or the proxy rather than the
+// request, so they are not saved on endpoints
+var skippedRequestHeaders = map[string]bool{
+	"Accept-Encoding":     true,
+	"Connection":          true,
+	"Content-Length":      true,
+	"Keep-Alive":          true,
+	"Proxy-Authorization": true,
+	"Proxy-Connection":    true,
+	"Te":                  true,
+	"Trailer":             true,
+	"Transfer-Encoding":   true,
+	"Upgrade":             true,
+}
+
+// Recorder saves exchanges into a collection. Each distinct method and URL
+// becomes one endpoint, and every exchange becomes a history entry.
+type Recorder struct {
+	Endpoints      *endpoints.EndpointsManager
+	History        *history.HistoryManager
+	CollectionID   int64
+	CollectionName string
+
+	mu sync.Mutex
+	// names maps a method and URL to the endpoint recorded for it
+	names map[string]string
+	taken map[string]bool
+}
+
+// NewRecorder returns a recorder that adds to the collection. Endpoints the
+// collection already has are reused rather than duplicated.
+func NewRecorder(ctx context.Context, endpointsManager *endpoints.EndpointsManager, historyManager *history.HistoryManager, collectionID int64, collectionName string) (*Recorder, error) {
+	r := &Recorder{
+		Endpoints:      endpointsManager,
+		History:        historyManager,
+		CollectionID:   collectionID,
+		CollectionName: collectionName,
+		names:          map[string]string{},
+		taken:          map[string]bool{},
+	}
+	existing, err := endpointsManager.ListByCollection(ctx, collectionID)
+	if err != nil {
+		return nil, err
+	}
+	for _, endpoint := range existing {
+		r.names[endpointKey(endpoint.Method, endpoint.Url)] = endpoint.Name
+		r.taken[endpoint.Name] = true
+	}
+	return r, nil
+}
+
+// Record stores an exchange, creating its endpoint the first time it is seen
+func (r *Recorder) Record(ctx context.Context, exchange Exchange) (Recorded, error) {
+	target := baseURL(exchange.URL)
+	headers := requestHeaders(exchange.RequestHeaders)
+	query := queryParams(exchange.URL)
+	requestBody := textBody(exchange.RequestBody)
+
+	r.mu.Lock()
+	key := endpointKey(exchange.Method, target)
+	name, known := r.names[key]
+	recorded := Recorded{EndpointName: name}
+	if !known {
+		headersJSON, err := json.Marshal(headers)
+		if err != nil {
+			r.mu.Unlock()
+			return Recorded{}, err
+		}
+		name = r.endpointName(exchange.Method, exchange.URL)
+		_, err = r.Endpoints.CreateEndpoint(ctx, endpoints.EndpointData{
+			CollectionID: r.CollectionID,
+			Name:         name,
+			Method:       exchange.Method,
+			URL:          target,
+			Headers:      string(headersJSON),
+			QueryParams:  query,
+			RequestBody:  requestBody,
+			Protocol:     endpoints.ProtocolHTTP,
+		})
+		if err != nil {
+			r.mu.Unlock()
+			return Recorded{}, err
+		}
+		r.names[key] = name
+		r.taken[name] = true
+		recorded = Recorded{EndpointName: name, Created: true}
+		log.Info("recorded new endpoint", "collection_id", r.CollectionID, "name", name)
+	}
+	r.mu.Unlock()
+
+	if r.History == nil {
+		return recorded, nil
+	}
+	entry, err := r.History.RecordExecution(ctx, history.ExecutionData{
+		CollectionID:    r.CollectionID,
+		CollectionName:  r.CollectionName,
+		EndpointName:    name,
+		Method:          exchange.Method,
+		URL:             target,
+		Headers:         headers,
+		QueryParams:     query,
+		RequestBody:     requestBody,
+		StatusCode:      exchange.Status,
+		ResponseBody:    textBody(exchange.ResponseBody),
+		ResponseHeaders: exchange.ResponseHeaders,
+		Duration:        exchange.Duration,
+		ResponseSize:    int64(len(exchange.ResponseBody)),
+	})
+	if err != nil {
+		return recorded, err
+	}
+	recorded.HistoryID = entry.ID
+	return recorded, nil
+}
+
+// endpointName names an endpoint after its method and path, adding the host
+// and then a number when that name is already used. Long paths are
+// shortened so the name, number included, fits crud.MaxNameLength.
+func (r *Recorder) endpointName(method string, target *url.URL) string {
+	path := target.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	withSuffix := func(name, suffix string) string {
+		return crud.Shorten(name, crud.MaxNameLength-len(suffix)) + suffix
+	}
+	candidates := []string{method + " " + path, method + " " + target.Host + path}
+	for _, name := range candidates {
+		if name = withSuffix(name, ""); !r.taken[name] {
+			return name
+		}
+	}
+	for i := 2; ; i++ {
+		name := withSuffix(candidates[1], " "+strconv.Itoa(i))
+		if !r.taken[name] {
+			return name
+		}
+	}
+}
+
+func endpointKey(method, target string) string {
+	return strings.ToUpper(method) + " " + target
+}
+
+// baseURL is the URL without its query, which is stored separately
+func baseURL(target *url.URL) string {
+	base := *target
+	base.RawQuery = ""
+	base.Fragment = ""
+	return base.String()
+}
+
+func requestHeaders(header http.Header) map[string]string {
+	headers := map[string]string{}
+	for name, values := range header {
+		if skippedRequestHeaders[http.CanonicalHeaderKey(name)] {
+			continue
+		}
+		headers[name] = strings.Join(values, ", ")
+	}
+	return headers
+}
+
+func queryParams(target *url.URL) map[string]string {
+	params := map[string]string{}
+	for name, values := range target.Query() {
+		if len(values) > 0 {
+			params[name] = values[0]
+		}
+	}
+	return params
+}
+
+// textBody keeps text bodies. Binary ones can't be edited or replayed as
+// endpoint bodies, so they are left out.
+func textBody(body []byte) string {
+	if !utf8.Valid(body) {
+		return ""
+	}
+	return string(body)
+}
@@ -0,0 +1,62 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/maniac-en/req/internal/backend/endpoints"
+	"github.com/maniac-en/req/internal/backend/http"
+	"github.com/maniac-en/req/internal/backend/variables"
+	"github.com/maniac-en/req/internal/codegen"
+)
+
+// ClientRequest returns what an HTTP endpoint would send right now, for
+// generating client code. Variables and template functions are resolved so
+// the snippet works outside req, and the body and Content-Type match what
+// the HTTP manager sends. Scripts are not run.
+func (r *Runner) ClientRequest(ctx context.Context, endpoint endpoints.EndpointEntity) (codegen.Request, error) {
+	if endpoint.IsGRPC() {
+		return codegen.Request{}, errors.New("code generation only supports HTTP endpoints")
+	}
+
+	endpoint, folderVars := r.inherit(ctx, endpoint)
+	collectionVars, environmentVars := r.variableSets(ctx, endpoint.CollectionID)
+	resolved, _, err := resolve(endpoint, variables.Merge(collectionVars, folderVars, environmentVars))
+	if err != nil {
+		return codegen.Request{}, err
+	}
+	manager := r.HTTP
+	if manager == nil {
+		manager = http.NewHTTPManager()
+	}
+	if err := manager.ValidateRequest(&http.Request{Method: resolved.Method, URL: resolved.Url}); err != nil {
+		return codegen.Request{}, err
+	}
+	url, err := http.BuildURL(resolved.Url, resolved.GetQueryParams())
+	if err != nil {
+		return codegen.Request{}, err
+	}
+
+	request := codegen.Request{
+		Method:  resolved.Method,
+		URL:     url,
+		Headers: resolved.GetHeaders(),
+	}
+	if resolved.RequestBody != "" && http.SendsBody(resolved.Method) {
+		request.Body = resolved.RequestBody
+		if !hasHeader(request.Headers, "Content-Type") {
+			request.Headers["Content-Type"] = http.ContentType(resolved.RequestBody)
+		}
+	}
+	return request, nil
+}
+
+func hasHeader(headers map[string]string, name string) bool {
+	for key := range headers {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package runner
+
+import (
+	"context"
+	"testing"
+
+	"github.com/maniac-en/req/internal/backend/endpoints"
+)
+
+func TestClientRequest(t *testing.T) {
+	ctx := context.Background()
+	runner, collectionID := setupRunner(t)
+
+	if _, err := runner.Collections.SetVariables(ctx, collectionID, map[string]string{"host": "https://api.example.com", "token": "secret"}); err != nil {
+		t.Fatalf("SetVariables failed: %v", err)
+	}
+	endpoint, err := runner.Endpoints.CreateEndpoint(ctx, endpoints.EndpointData{
+		CollectionID: collectionID,
+		Name:         "Create User",
+		Method:       "post",
+		URL:          "{{host}}/users",
+		Headers:      `{"Authorization": "Bearer {{token}}"}`,
+		QueryParams:  map[string]string{"notify": "true"},
+		RequestBody:  `{"name": "ann"}`,
+	})
+	if err != nil {
+		t.Fatalf("CreateEndpoint failed: %v", err)
+	}
+
+	request, err := runner.ClientRequest(ctx, endpoint)
+	if err != nil {
+		t.Fatalf("ClientRequest failed: %v", err)
+	}
+	if request.URL != "https://api.example.com/users?notify=true" {
+		t.Errorf("Expected resolved URL with query, got %s", request.URL)
+	}
+	if request.Headers["Authorization"] != "Bearer secret" {
+		t.Errorf("Expected resolved headers, got %v", request.Headers)
+	}
+	if request.Headers["Content-Type"] != "application/json" || request.Body != `{"name": "ann"}` {
+		t.Errorf("Expected the body with a detected Content-Type, got %v %q", request.Headers, request.Body)
+	}
+
+	endpoint.Method = "GET"
+	request, err = runner.ClientRequest(ctx, endpoint)
+	if err != nil {
+		t.Fatalf("ClientRequest failed: %v", err)
+	}
+	if request.Body != "" || request.Headers["Content-Type"] != "" {
+		t.Errorf("Expected GET requests to drop the body like the HTTP manager does, got %v %q", request.Headers, request.Body)
+	}
+
+	endpoint.Protocol = endpoints.ProtocolGRPC
+	if _, err := runner.ClientRequest(ctx, endpoint); err == nil {
+		t.Error("Expected gRPC endpoints to be rejected")
+	}
+}
@@ -0,0 +1,45 @@
+package runner
+
+import (
+	"context"
+	"time"
+
+	"github.com/maniac-en/req/internal/log"
+)
+
+// RunCollection runs every endpoint of a collection in its sort order. Values
+// extracted by one step are available to the steps after it, so flows like
+// login, create and fetch can run end to end.
+func (r *Runner) RunCollection(ctx context.Context, collectionID int64, opts RunOptions) (*CollectionRun, error) {
+	collection, err := r.Collections.Read(ctx, collectionID)
+	if err != nil {
+		return nil, err
+	}
+	endpoints, err := r.Endpoints.ListByCollection(ctx, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("running collection", "id", collectionID, "endpoints", len(endpoints))
+	run := &CollectionRun{Collection: collection}
+	start := time.Now()
+	for _, endpoint := range endpoints {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		result, err := r.Run(ctx, endpoint)
+		step := Step{Endpoint: endpoint, Result: result, Err: err}
+		run.Steps = append(run.Steps, step)
+		if opts.OnStep != nil {
+			opts.OnStep(step)
+		}
+		if !step.Passed() && opts.StopOnFailure {
+			break
+		}
+	}
+	run.Duration = time.Since(start)
+
+	log.Info("collection run finished", "id", collectionID, "steps", len(run.Steps), "failed", run.Failed())
+	return run, nil
+}
@@ -0,0 +1,222 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maniac-en/req/internal/backend/endpoints"
+)
+
+// newFlowServer serves a login -> create -> fetch flow that requires the
+// token from the login response and returns the created ID in a header.
+func newFlowServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := nethttp.NewServeMux()
+	mux.HandleFunc("POST /login", func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"session": {"token": "tok-123"}}`)
+	})
+	mux.HandleFunc("POST /items", func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if r.Header.Get("Authorization") != "Bearer tok-123" {
+			w.WriteHeader(nethttp.StatusUnauthorized)
+			return
+		}
+		var item map[string]string
+		json.NewDecoder(r.Body).Decode(&item)
+		w.Header().Set("Location", "/items/7")
+		w.WriteHeader(nethttp.StatusCreated)
+		fmt.Fprintf(w, `<created id="7" name=%q/>`, item["name"])
+	})
+	mux.HandleFunc("GET /items/7", func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if r.Header.Get("Authorization") != "Bearer tok-123" {
+			w.WriteHeader(nethttp.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"id": 7, "name": "widget"}`)
+	})
+	return httptest.NewServer(mux)
+}
+
+func TestRunCollectionChaining(t *testing.T) {
+	ctx := context.Background()
+	runner, collectionID := setupRunner(t)
+	server := newFlowServer(t)
+	defer server.Close()
+
+	environment, err := runner.Environments.Create(ctx, "local")
+	if err != nil {
+		t.Fatalf("Create environment failed: %v", err)
+	}
+	if _, err := runner.Environments.SetVariables(ctx, environment.ID, map[string]string{"base": server.URL}); err != nil {
+		t.Fatalf("SetVariables failed: %v", err)
+	}
+	if err := runner.Environments.Activate(ctx, environment.ID); err != nil {
+		t.Fatalf("Activate failed: %v", err)
+	}
+	if _, err := runner.Collections.SetVariables(ctx, collectionID, map[string]string{"name": "widget"}); err != nil {
+		t.Fatalf("SetVariables failed: %v", err)
+	}
+
+	steps := []endpoints.EndpointData{
+		{
+			Name: "Login", Method: "POST", URL: "{{base}}/login",
+			Extractions: []endpoints.Extraction{
+				{Variable: "token", Source: endpoints.SourceJSON, Expression: "$.session.token", Scope: endpoints.ScopeEnvironment},
+			},
+		},
+		{
+			Name: "Create", Method: "POST", URL: "{{base}}/items",
+			Headers:     `{"Authorization": "Bearer {{token}}"}`,
+			RequestBody: `{"name": "{{name}}"}`,
+			Extractions: []endpoints.Extraction{
+				{Variable: "location", Source: endpoints.SourceHeader, Expression: "location"},
+				{Variable: "itemID", Source: endpoints.SourceRegex, Expression: `id="(\d+)"`},
+			},
+		},
+		{
+			Name: "Fetch", Method: "GET", URL: "{{base}}/items/{{itemID}}",
+			Headers: `{"Authorization": "Bearer {{token}}"}`,
+		},
+	}
+	for _, data := range steps {
+		data.CollectionID = collectionID
+		if _, err := runner.Endpoints.CreateEndpoint(ctx, data); err != nil {
+			t.Fatalf("CreateEndpoint %s failed: %v", data.Name, err)
+		}
+	}
+
+	var seen []string
+	run, err := runner.RunCollection(ctx, collectionID, RunOptions{
+		OnStep: func(step Step) { seen = append(seen, step.Endpoint.Name) },
+	})
+	if err != nil {
+		t.Fatalf("RunCollection failed: %v", err)
+	}
+	if len(seen) != 3 || seen[0] != "Login" || seen[2] != "Fetch" {
+		t.Errorf("expected steps to run in order, got %v", seen)
+	}
+	for _, step := range run.Steps {
+		if !step.Passed() {
+			t.Errorf("step %s failed: err=%v result=%+v", step.Endpoint.Name, step.Err, step.Result)
+		}
+	}
+	if !run.Passed() {
+		t.Fatalf("expected run to pass, %d steps failed", run.Failed())
+	}
+
+	if got := run.Steps[2].Result.Endpoint.Url; got != server.URL+"/items/7" {
+		t.Errorf("expected resolved URL in result, got %s", got)
+	}
+	environment, _ = runner.Environments.Read(ctx, environment.ID)
+	if environment.GetVariables()["token"] != "tok-123" {
+		t.Errorf("expected token in environment, got %v", environment.GetVariables())
+	}
+	collection, _ := runner.Collections.Read(ctx, collectionID)
+	if vars := collection.GetVariables(); vars["location"] != "/items/7" || vars["itemID"] != "7" {
+		t.Errorf("expected extracted values in collection, got %v", vars)
+	}
+
+	entry, err := runner.History.Read(ctx, run.Steps[2].Result.HistoryID)
+	if err != nil {
+		t.Fatalf("failed to read history entry: %v", err)
+	}
+	if entry.Url != server.URL+"/items/7" {
+		t.Errorf("expected history to record the resolved URL, got %s", entry.Url)
+	}
+}
+
+func TestRunCollectionStopOnFailure(t *testing.T) {
+	ctx := context.Background()
+	runner, collectionID := setupRunner(t)
+	server := newFlowServer(t)
+	defer server.Close()
+
+	for _, name := range []string{"Unauthorized", "Never Runs"} {
+		_, err := runner.Endpoints.CreateEndpoint(ctx, endpoints.EndpointData{
+			CollectionID: collectionID,
+			Name:         name,
+			Method:       "GET",
+			URL:          server.URL + "/items/7",
+		})
+		if err != nil {
+			t.Fatalf("CreateEndpoint failed: %v", err)
+		}
+	}
+
+	run, err := runner.RunCollection(ctx, collectionID, RunOptions{StopOnFailure: true})
+	if err != nil {
+		t.Fatalf("RunCollection failed: %v", err)
+	}
+	if len(run.Steps) != 1 || run.Passed() {
+		t.Errorf("expected run to stop after the first failure, got %d steps", len(run.Steps))
+	}
+}
+
+func TestExtract(t *testing.T) {
+	result := &Result{
+		Protocol:   endpoints.ProtocolHTTP,
+		StatusCode: 200,
+		Headers:    map[string][]string{"X-Request-Id": {"abc"}},
+		Body:       `{"data": {"items": [{"id": 1}, {"id": 2}], "owner": {"name": "ada"}}}`,
+	}
+
+	tests := []struct {
+		rule     endpoints.Extraction
+		expected string
+		valid    bool
+	}{
+		{endpoints.Extraction{Source: endpoints.SourceJSON, Expression: "$.data.items[1].id"}, "2", true},
+		{endpoints.Extraction{Source: endpoints.SourceJSON, Expression: ".data.owner"}, `{"name":"ada"}`, true},
+		{endpoints.Extraction{Source: endpoints.SourceJSON, Expression: "$.missing"}, "", false},
+		{endpoints.Extraction{Source: endpoints.SourceHeader, Expression: "x-request-id"}, "abc", true},
+		{endpoints.Extraction{Source: endpoints.SourceHeader, Expression: "X-Missing"}, "", false},
+		{endpoints.Extraction{Source: endpoints.SourceRegex, Expression: `"name": "(\w+)"`}, "ada", true},
+		{endpoints.Extraction{Source: endpoints.SourceRegex, Expression: `items`}, "items", true},
+		{endpoints.Extraction{Source: "cookie", Expression: "a"}, "", false},
+	}
+
+	for _, test := range tests {
+		value, err := Extract(test.rule, result)
+		if test.valid && err != nil {
+			t.Errorf("Extract(%s %s) failed: %v", test.rule.Source, test.rule.Expression, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("expected Extract(%s %s) to fail", test.rule.Source, test.rule.Expression)
+		}
+		if value != test.expected {
+			t.Errorf("Extract(%s %s) = %q, expected %q", test.rule.Source, test.rule.Expression, value, test.expected)
+		}
+	}
+}
+
+func TestExtractionRequiresEnvironment(t *testing.T) {
+	ctx := context.Background()
+	runner, collectionID := setupRunner(t)
+	server := newFlowServer(t)
+	defer server.Close()
+
+	endpoint, err := runner.Endpoints.CreateEndpoint(ctx, endpoints.EndpointData{
+		CollectionID: collectionID,
+		Name:         "Login",
+		Method:       "POST",
+		URL:          server.URL + "/login",
+		Extractions: []endpoints.Extraction{
+			{Variable: "token", Source: endpoints.SourceJSON, Expression: "$.session.token", Scope: endpoints.ScopeEnvironment},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateEndpoint failed: %v", err)
+	}
+
+	result, err := runner.Run(ctx, endpoint)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(result.ExtractionErrors) != 1 {
+		t.Errorf("expected an extraction error without an active environment, got %v", result.ExtractionErrors)
+	}
+}
@@ -0,0 +1,33 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/maniac-en/req/internal/backend/endpoints"
+	"github.com/maniac-en/req/internal/backend/folders"
+	"github.com/maniac-en/req/internal/log"
+)
+
+// inherit applies the headers of the folders around an endpoint to it and
+// returns their variables. The endpoint's own headers win over its
+// folders', and inner folders win over outer ones.
+func (r *Runner) inherit(ctx context.Context, endpoint endpoints.EndpointEntity) (endpoints.EndpointEntity, map[string]string) {
+	if r.Folders == nil || endpoint.GetFolderID() == 0 {
+		return endpoint, nil
+	}
+	headers, vars, err := r.Folders.Inherited(ctx, endpoint.GetFolderID())
+	if err != nil {
+		log.Warn("failed to load folder settings", "endpoint_id", endpoint.ID, "folder_id", endpoint.GetFolderID(), "error", err)
+		return endpoint, nil
+	}
+	if len(headers) > 0 {
+		encoded, err := json.Marshal(folders.MergeHeaders(headers, endpoint.GetHeaders()))
+		if err != nil {
+			log.Warn("failed to encode inherited headers", "endpoint_id", endpoint.ID, "error", err)
+			return endpoint, vars
+		}
+		endpoint.Headers = string(encoded)
+	}
+	return endpoint, vars
+}
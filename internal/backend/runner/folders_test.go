@@ -0,0 +1,77 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maniac-en/req/internal/backend/endpoints"
+)
+
+func TestFolderInheritance(t *testing.T) {
+	ctx := context.Background()
+	runner, collectionID := setupRunner(t)
+
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"path":%q,"auth":%q,"team":%q}`, r.URL.Path, r.Header.Get("Authorization"), r.Header.Get("X-Team"))
+	}))
+	defer server.Close()
+
+	if _, err := runner.Collections.SetVariables(ctx, collectionID, map[string]string{"host": server.URL, "base": "/collection", "token": "collection-token"}); err != nil {
+		t.Fatalf("SetVariables failed: %v", err)
+	}
+	users, err := runner.Folders.CreateFolder(ctx, collectionID, 0, "users")
+	if err != nil {
+		t.Fatalf("CreateFolder failed: %v", err)
+	}
+	admin, err := runner.Folders.CreateFolder(ctx, collectionID, users.ID, "admin")
+	if err != nil {
+		t.Fatalf("CreateFolder failed: %v", err)
+	}
+	runner.Folders.SetHeaders(ctx, users.ID, map[string]string{"Authorization": "Bearer {{token}}", "X-Team": "users"})
+	runner.Folders.SetVariables(ctx, users.ID, map[string]string{"base": "/users"})
+	runner.Folders.SetVariables(ctx, admin.ID, map[string]string{"token": "admin-token"})
+
+	endpoint, err := runner.Endpoints.CreateEndpoint(ctx, endpoints.EndpointData{
+		CollectionID: collectionID,
+		FolderID:     admin.ID,
+		Name:         "List Admins",
+		Method:       "GET",
+		URL:          "{{host}}{{base}}",
+		Headers:      `{"x-team": "admins"}`,
+	})
+	if err != nil {
+		t.Fatalf("CreateEndpoint failed: %v", err)
+	}
+
+	result, err := runner.Run(ctx, endpoint)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	var body map[string]string
+	if err := json.Unmarshal([]byte(result.Body), &body); err != nil {
+		t.Fatalf("Unexpected body %s: %v", result.Body, err)
+	}
+	expected := map[string]string{"path": "/users", "auth": "Bearer admin-token", "team": "admins"}
+	for key, value := range expected {
+		if body[key] != value {
+			t.Errorf("Expected %s %q, got %q", key, value, body[key])
+		}
+	}
+
+	// the environment still wins over folders
+	environment, _ := runner.Environments.Create(ctx, "staging")
+	runner.Environments.SetVariables(ctx, environment.ID, map[string]string{"token": "env-token"})
+	runner.Environments.Activate(ctx, environment.ID)
+	request, err := runner.ClientRequest(ctx, endpoint)
+	if err != nil {
+		t.Fatalf("ClientRequest failed: %v", err)
+	}
+	if request.Headers["Authorization"] != "Bearer env-token" {
+		t.Errorf("Expected the environment token, got %v", request.Headers)
+	}
+}
@@ -0,0 +1,71 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	nethttp "net/http"
+
+	"github.com/maniac-en/req/internal/backend/endpoints"
+	"github.com/maniac-en/req/internal/backend/http"
+	"github.com/maniac-en/req/internal/backend/load"
+	"github.com/maniac-en/req/internal/backend/variables"
+)
+
+// Load sends an HTTP endpoint repeatedly and reports how it held up.
+// Variables and template functions are resolved for every request, while
+// scripts, extractions, snapshots and history are skipped so the numbers
+// reflect the server rather than req.
+func (r *Runner) Load(ctx context.Context, endpoint endpoints.EndpointEntity, options load.Options, onProgress func(load.Report)) (load.Report, error) {
+	if endpoint.IsGRPC() {
+		return load.Report{}, errors.New("load tests only support HTTP endpoints")
+	}
+	if err := options.Validate(); err != nil {
+		return load.Report{}, err
+	}
+
+	endpoint, folderVars := r.inherit(ctx, endpoint)
+	collectionVars, environmentVars := r.variableSets(ctx, endpoint.CollectionID)
+	vars := variables.Merge(collectionVars, folderVars, environmentVars)
+	// fail before starting when the endpoint can't be sent at all
+	resolved, _, err := resolve(endpoint, vars)
+	if err != nil {
+		return load.Report{}, err
+	}
+	manager := r.loadClient(options.Concurrency)
+	if err := manager.ValidateRequest(&http.Request{Method: resolved.Method, URL: resolved.Url}); err != nil {
+		return load.Report{}, err
+	}
+
+	send := func(context.Context) load.Sample {
+		resolved, _, err := resolve(endpoint, vars)
+		if err != nil {
+			return load.Sample{Err: err}
+		}
+		resp, err := manager.ExecuteRequest(&http.Request{
+			Method:      resolved.Method,
+			URL:         resolved.Url,
+			Headers:     resolved.GetHeaders(),
+			QueryParams: resolved.GetQueryParams(),
+			Body:        resolved.RequestBody,
+		})
+		if err != nil {
+			return load.Sample{Err: err}
+		}
+		return load.Sample{Status: resp.StatusCode, Duration: resp.Duration}
+	}
+	return load.Run(ctx, options, send, onProgress)
+}
+
+// loadClient returns an HTTP manager that keeps a connection open per
+// worker. The default client keeps only two, so most requests would pay for
+// a new connection and the test would measure that instead.
+func (r *Runner) loadClient(concurrency int) *http.HTTPManager {
+	manager := http.NewHTTPManager()
+	if r.HTTP != nil && r.HTTP.Client != nil {
+		manager.Client.Timeout = r.HTTP.Client.Timeout
+	}
+	transport := nethttp.DefaultTransport.(*nethttp.Transport).Clone()
+	transport.MaxIdleConnsPerHost = concurrency
+	manager.Client.Transport = transport
+	return manager
+}
@@ -0,0 +1,68 @@
+package runner
+
+import (
+	"context"
+	nethttp "net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/maniac-en/req/internal/backend/endpoints"
+	"github.com/maniac-en/req/internal/backend/load"
+)
+
+func TestLoad(t *testing.T) {
+	ctx := context.Background()
+	runner, collectionID := setupRunner(t)
+
+	var mu sync.Mutex
+	keys := map[string]bool{}
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		mu.Lock()
+		keys[r.Header.Get("Idempotency-Key")] = true
+		mu.Unlock()
+		w.WriteHeader(nethttp.StatusAccepted)
+	}))
+	defer server.Close()
+
+	endpoint, err := runner.Endpoints.CreateEndpoint(ctx, endpoints.EndpointData{
+		CollectionID: collectionID,
+		Name:         "Enqueue",
+		Method:       "POST",
+		URL:          server.URL,
+		Headers:      `{"Idempotency-Key": "{{$uuid}}"}`,
+	})
+	if err != nil {
+		t.Fatalf("CreateEndpoint failed: %v", err)
+	}
+
+	report, err := runner.Load(ctx, endpoint, load.Options{Requests: 20, Concurrency: 4}, nil)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if report.Requests != 20 || report.Statuses[nethttp.StatusAccepted] != 20 || report.Failed != 0 {
+		t.Errorf("Unexpected report %+v", report)
+	}
+	if len(keys) != 20 {
+		t.Errorf("Expected template functions to be resolved per request, got %d distinct keys", len(keys))
+	}
+
+	entries, err := runner.History.ListByCollection(ctx, collectionID, 10, 0)
+	if err != nil {
+		t.Fatalf("ListByCollection failed: %v", err)
+	}
+	if entries.Total != 0 {
+		t.Errorf("Expected load tests to stay out of history, got %d entries", entries.Total)
+	}
+
+	grpcEndpoint := endpoint
+	grpcEndpoint.Protocol = endpoints.ProtocolGRPC
+	if _, err := runner.Load(ctx, grpcEndpoint, load.Options{Requests: 1, Concurrency: 1}, nil); err == nil {
+		t.Error("Expected gRPC endpoints to be refused")
+	}
+	broken := endpoint
+	broken.Url = "{{$unknown}}"
+	if _, err := runner.Load(ctx, broken, load.Options{Requests: 1, Concurrency: 1}, nil); err == nil {
+		t.Error("Expected an endpoint that can't be resolved to fail before starting")
+	}
+}
@@ -0,0 +1,123 @@
+// Package runner executes saved endpoints and records their results in history.
+package runner
+
+import (
+	"time"
+
+	"github.com/maniac-en/req/internal/backend/collections"
+	"github.com/maniac-en/req/internal/backend/endpoints"
+	"github.com/maniac-en/req/internal/backend/environments"
+	"github.com/maniac-en/req/internal/backend/folders"
+	"github.com/maniac-en/req/internal/backend/grpc"
+	"github.com/maniac-en/req/internal/backend/history"
+	"github.com/maniac-en/req/internal/backend/http"
+	"github.com/maniac-en/req/internal/backend/scripting"
+	"github.com/maniac-en/req/internal/backend/snapshots"
+	"github.com/maniac-en/req/internal/diff"
+)
+
+type Runner struct {
+	Collections  *collections.CollectionsManager
+	Endpoints    *endpoints.EndpointsManager
+	Folders      *folders.FoldersManager
+	Environments *environments.EnvironmentsManager
+	HTTP         *http.HTTPManager
+	GRPC         *grpc.GRPCManager
+	Scripts      *scripting.ScriptManager
+	Snapshots    *snapshots.SnapshotsManager
+	History      *history.HistoryManager
+
+	// EnvironmentID overrides the active environment when set
+	EnvironmentID int64
+}
+
+// Result is the protocol independent outcome of running an endpoint. For gRPC
+// endpoints StatusCode holds the gRPC status code. Endpoint is the endpoint as
+// sent, with variables substituted.
+type Result struct {
+	Endpoint   endpoints.EndpointEntity
+	Protocol   string
+	StatusCode int
+	Status     string
+	Headers    map[string][]string
+	Trailers   map[string][]string
+	Body       string
+	Duration   time.Duration
+	HistoryID  int64
+	// Generated holds the values of template functions such as {{$uuid}},
+	// keyed by expression
+	Generated        map[string]string
+	Extracted        map[string]string
+	ExtractionErrors []error
+	Logs             []scripting.LogEntry
+	Tests            []scripting.TestResult
+	ScriptErrors     []error
+	// Snapshot is the comparison with the endpoint's pinned snapshot, nil
+	// when none is pinned
+	Snapshot *diff.Result
+}
+
+// Succeeded reports whether the call completed with a non-error status
+func (r Result) Succeeded() bool {
+	if r.Protocol == endpoints.ProtocolGRPC {
+		return r.StatusCode == 0
+	}
+	return r.StatusCode >= 200 && r.StatusCode < 400
+}
+
+// Verified reports whether extractions, post-response scripts and their
+// tests all ran without failures and the response matches its snapshot
+func (r Result) Verified() bool {
+	if len(r.ExtractionErrors) > 0 || len(r.ScriptErrors) > 0 {
+		return false
+	}
+	if r.Snapshot != nil && !r.Snapshot.Equal() {
+		return false
+	}
+	for _, test := range r.Tests {
+		if !test.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+type RunOptions struct {
+	// StopOnFailure ends a collection run at the first failed step
+	StopOnFailure bool
+	// OnStep is called after each step, as soon as it completes
+	OnStep func(Step)
+}
+
+// Step is the outcome of one endpoint in a collection run. Err is set when
+// the request could not be sent at all.
+type Step struct {
+	Endpoint endpoints.EndpointEntity
+	Result   *Result
+	Err      error
+}
+
+// Passed reports whether the request succeeded and was verified
+func (s Step) Passed() bool {
+	return s.Err == nil && s.Result != nil && s.Result.Succeeded() && s.Result.Verified()
+}
+
+type CollectionRun struct {
+	Collection collections.CollectionEntity
+	Steps      []Step
+	Duration   time.Duration
+}
+
+func (c CollectionRun) Failed() int {
+	failed := 0
+	for _, step := range c.Steps {
+		if !step.Passed() {
+			failed++
+		}
+	}
+	return failed
+}
+
+func (c CollectionRun) Passed() bool {
+	return c.Failed() == 0
+}
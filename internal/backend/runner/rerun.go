@@ -0,0 +1,57 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/maniac-en/req/internal/backend/crud"
+	"github.com/maniac-en/req/internal/backend/endpoints"
+	"github.com/maniac-en/req/internal/backend/grpc"
+	"github.com/maniac-en/req/internal/backend/history"
+	"github.com/maniac-en/req/internal/diff"
+)
+
+// Rerun runs the endpoint that produced a history entry again
+func (r *Runner) Rerun(ctx context.Context, entry history.HistoryEntity) (*Result, error) {
+	endpoint, err := r.EndpointFor(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+	return r.Run(ctx, endpoint)
+}
+
+// EndpointFor returns the endpoint that produced a history entry. Entries
+// only record the collection and endpoint name, so the endpoint is looked up
+// by name within the collection.
+func (r *Runner) EndpointFor(ctx context.Context, entry history.HistoryEntity) (endpoints.EndpointEntity, error) {
+	if !entry.CollectionID.Valid || !entry.EndpointName.Valid {
+		return endpoints.EndpointEntity{}, fmt.Errorf("history entry %d is not linked to an endpoint: %w", entry.ID, crud.ErrNotFound)
+	}
+
+	all, err := r.Endpoints.ListByCollection(ctx, entry.CollectionID.Int64)
+	if err != nil {
+		return endpoints.EndpointEntity{}, err
+	}
+	for _, endpoint := range all {
+		if endpoint.Name == entry.EndpointName.String {
+			return endpoint, nil
+		}
+	}
+	return endpoints.EndpointEntity{}, fmt.Errorf("endpoint %q: %w", entry.EndpointName.String, crud.ErrNotFound)
+}
+
+// Response returns the result the way history records it, with gRPC
+// statuses mapped to HTTP ones and trailers merged into the headers, so it
+// can be compared with history entries
+func (r Result) Response() diff.Response {
+	response := diff.Response{
+		Status:  r.StatusCode,
+		Headers: r.Headers,
+		Body:    r.Body,
+	}
+	if r.Protocol == endpoints.ProtocolGRPC {
+		response.Status = grpc.HTTPStatus(r.StatusCode)
+		response.Headers = mergeMetadata(r.Headers, r.Trailers)
+	}
+	return response
+}
@@ -0,0 +1,93 @@
+package runner
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maniac-en/req/internal/backend/crud"
+	"github.com/maniac-en/req/internal/backend/endpoints"
+	"github.com/maniac-en/req/internal/backend/history"
+	"github.com/maniac-en/req/internal/diff"
+)
+
+func TestRerun(t *testing.T) {
+	ctx := context.Background()
+	runner, collectionID := setupRunner(t)
+
+	calls := 0
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"call": %d, "ok": true}`, calls)
+	}))
+	defer server.Close()
+
+	endpoint, err := runner.Endpoints.CreateEndpoint(ctx, endpoints.EndpointData{
+		CollectionID: collectionID,
+		Name:         "Counter",
+		Method:       "GET",
+		URL:          server.URL,
+	})
+	if err != nil {
+		t.Fatalf("CreateEndpoint failed: %v", err)
+	}
+
+	first, err := runner.Run(ctx, endpoint)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	entry, err := runner.History.Read(ctx, first.HistoryID)
+	if err != nil {
+		t.Fatalf("failed to read history entry: %v", err)
+	}
+
+	second, err := runner.Rerun(ctx, entry)
+	if err != nil {
+		t.Fatalf("Rerun failed: %v", err)
+	}
+	if second.HistoryID == entry.ID {
+		t.Error("expected the rerun to be recorded as a new entry")
+	}
+
+	result := diff.Compare(entry.Response(), second.Response(), diff.Options{IgnoreHeaders: diff.DefaultIgnoredHeaders})
+	if result.Count() != 1 || len(result.Body) != 1 || result.Body[0].Path != "$.call" {
+		t.Errorf("expected only $.call to differ, got %+v", result)
+	}
+
+	t.Run("unlinked entry", func(t *testing.T) {
+		_, err := runner.Rerun(ctx, history.HistoryEntity{})
+		if !errors.Is(err, crud.ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("deleted endpoint", func(t *testing.T) {
+		entry.EndpointName = sql.NullString{String: "Gone", Valid: true}
+		_, err := runner.Rerun(ctx, entry)
+		if !errors.Is(err, crud.ErrNotFound) {
+			t.Errorf("expected ErrNotFound, got %v", err)
+		}
+	})
+}
+
+func TestResultResponse(t *testing.T) {
+	result := Result{
+		Protocol:   endpoints.ProtocolGRPC,
+		StatusCode: 5,
+		Headers:    map[string][]string{"content-type": {"application/grpc"}},
+		Trailers:   map[string][]string{"grpc-status": {"5"}},
+		Body:       "{}",
+	}
+	response := result.Response()
+	if response.Status != 404 {
+		t.Errorf("expected NOT_FOUND to map to 404, got %d", response.Status)
+	}
+	if len(response.Headers) != 2 {
+		t.Errorf("expected trailers to be merged into headers, got %v", response.Headers)
+	}
+}
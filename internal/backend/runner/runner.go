@@ -0,0 +1,212 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/maniac-en/req/internal/backend/collections"
+	"github.com/maniac-en/req/internal/backend/crud"
+	"github.com/maniac-en/req/internal/backend/endpoints"
+	"github.com/maniac-en/req/internal/backend/environments"
+	"github.com/maniac-en/req/internal/backend/folders"
+	"github.com/maniac-en/req/internal/backend/grpc"
+	"github.com/maniac-en/req/internal/backend/history"
+	"github.com/maniac-en/req/internal/backend/http"
+	"github.com/maniac-en/req/internal/backend/scripting"
+	"github.com/maniac-en/req/internal/backend/snapshots"
+	"github.com/maniac-en/req/internal/log"
+)
+
+func NewRunner(
+	collectionsManager *collections.CollectionsManager,
+	endpointsManager *endpoints.EndpointsManager,
+	environmentsManager *environments.EnvironmentsManager,
+	httpManager *http.HTTPManager,
+	grpcManager *grpc.GRPCManager,
+	scriptManager *scripting.ScriptManager,
+	snapshotsManager *snapshots.SnapshotsManager,
+	historyManager *history.HistoryManager,
+	foldersManager *folders.FoldersManager,
+) *Runner {
+	return &Runner{
+		Collections:  collectionsManager,
+		Endpoints:    endpointsManager,
+		Environments: environmentsManager,
+		HTTP:         httpManager,
+		GRPC:         grpcManager,
+		Scripts:      scriptManager,
+		Snapshots:    snapshotsManager,
+		History:      historyManager,
+		Folders:      foldersManager,
+	}
+}
+
+// RunEndpoint loads an endpoint, runs its pre-request scripts, executes it
+// with the matching protocol manager, applies its extraction rules and
+// post-response scripts, compares the response with the endpoint's snapshot
+// and records the execution in history.
+func (r *Runner) RunEndpoint(ctx context.Context, endpointID int64) (*Result, error) {
+	endpoint, err := r.Endpoints.Read(ctx, endpointID)
+	if err != nil {
+		return nil, err
+	}
+	return r.Run(ctx, endpoint)
+}
+
+func (r *Runner) Run(ctx context.Context, endpoint endpoints.EndpointEntity) (*Result, error) {
+	log.Debug("running endpoint", "id", endpoint.ID, "protocol", endpoint.Protocol)
+
+	endpoint, folderVars := r.inherit(ctx, endpoint)
+	preRequest, postResponse := r.scripts(ctx, endpoint)
+	scriptCtx := r.scriptContext(ctx, endpoint, folderVars)
+	pre, err := r.runScripts(ctx, endpoint.CollectionID, preRequest, scriptCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved, generated, err := resolve(withRequest(endpoint, scriptCtx.Request), scriptCtx.Variables)
+	if err != nil {
+		log.Warn("failed to resolve endpoint", "id", endpoint.ID, "error", err)
+		return nil, err
+	}
+
+	var result *Result
+	if resolved.IsGRPC() {
+		result, err = r.runGRPC(ctx, resolved)
+	} else {
+		result, err = r.runHTTP(resolved)
+	}
+	if err != nil {
+		log.Error("endpoint run failed", "id", endpoint.ID, "error", err)
+		return nil, err
+	}
+
+	result.Generated = generated
+	result.Logs = pre.Logs
+	result.Tests = pre.Tests
+	r.extract(ctx, endpoint, result)
+	r.postResponse(ctx, endpoint, folderVars, postResponse, result)
+	r.compareSnapshot(ctx, endpoint, result)
+	r.record(ctx, resolved, result)
+	return result, nil
+}
+
+func (r *Runner) runHTTP(endpoint endpoints.EndpointEntity) (*Result, error) {
+	resp, err := r.HTTP.ExecuteRequest(&http.Request{
+		Method:      endpoint.Method,
+		URL:         endpoint.Url,
+		Headers:     endpoint.GetHeaders(),
+		QueryParams: endpoint.GetQueryParams(),
+		Body:        endpoint.RequestBody,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Result{
+		Endpoint:   endpoint,
+		Protocol:   endpoints.ProtocolHTTP,
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Headers:    resp.Headers,
+		Body:       resp.Body,
+		Duration:   resp.Duration,
+	}, nil
+}
+
+func (r *Runner) runGRPC(ctx context.Context, endpoint endpoints.EndpointEntity) (*Result, error) {
+	target, plaintext := grpc.ParseTarget(endpoint.Url)
+	resp, err := r.GRPC.ExecuteRequest(ctx, &grpc.Request{
+		Target:     target,
+		Method:     endpoint.Method,
+		Metadata:   endpoint.GetHeaders(),
+		Body:       endpoint.RequestBody,
+		ProtoFiles: endpoint.GetProtoFiles(),
+		Plaintext:  plaintext,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Result{
+		Endpoint:   endpoint,
+		Protocol:   endpoints.ProtocolGRPC,
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Headers:    resp.Headers,
+		Trailers:   resp.Trailers,
+		Body:       resp.Body,
+		Duration:   resp.Duration,
+	}, nil
+}
+
+// compareSnapshot diffs the result against the endpoint's pinned snapshot,
+// if it has one
+func (r *Runner) compareSnapshot(ctx context.Context, endpoint endpoints.EndpointEntity, result *Result) {
+	if r.Snapshots == nil || endpoint.ID == 0 {
+		return
+	}
+	snapshot, err := r.Snapshots.ReadByEndpoint(ctx, endpoint.ID)
+	if err != nil {
+		if !errors.Is(err, crud.ErrNotFound) {
+			log.Warn("failed to load snapshot", "endpoint_id", endpoint.ID, "error", err)
+		}
+		return
+	}
+	drift := snapshot.Compare(result.Response())
+	result.Snapshot = &drift
+}
+
+// record stores the execution in history. Failures are logged but never fail
+// the run itself since the response has already been received.
+func (r *Runner) record(ctx context.Context, endpoint endpoints.EndpointEntity, result *Result) {
+	if r.History == nil {
+		return
+	}
+
+	collectionName := ""
+	if r.Collections != nil {
+		if collection, err := r.Collections.Read(ctx, endpoint.CollectionID); err == nil {
+			collectionName = collection.GetName()
+		}
+	}
+
+	data := history.ExecutionData{
+		CollectionID:    endpoint.CollectionID,
+		CollectionName:  collectionName,
+		EndpointName:    endpoint.Name,
+		Method:          endpoint.Method,
+		URL:             endpoint.Url,
+		Headers:         endpoint.GetHeaders(),
+		QueryParams:     endpoint.GetQueryParams(),
+		RequestBody:     endpoint.RequestBody,
+		StatusCode:      result.StatusCode,
+		ResponseBody:    result.Body,
+		ResponseHeaders: result.Headers,
+		Duration:        result.Duration,
+		ResponseSize:    int64(len(result.Body)),
+	}
+	if result.Protocol == endpoints.ProtocolGRPC {
+		data.Method = "GRPC"
+		data.URL = fmt.Sprintf("%s/%s", endpoint.Url, endpoint.Method)
+		data.StatusCode = grpc.HTTPStatus(result.StatusCode)
+		data.ResponseHeaders = mergeMetadata(result.Headers, result.Trailers)
+	}
+
+	entry, err := r.History.RecordExecution(ctx, data)
+	if err != nil {
+		log.Warn("failed to record endpoint run in history", "id", endpoint.ID, "error", err)
+		return
+	}
+	result.HistoryID = entry.ID
+}
+
+func mergeMetadata(headers, trailers map[string][]string) map[string][]string {
+	merged := make(map[string][]string, len(headers)+len(trailers))
+	for key, values := range headers {
+		merged[key] = values
+	}
+	for key, values := range trailers {
+		merged[key] = append(merged[key], values...)
+	}
+	return merged
+}
@@ -0,0 +1,236 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	"io"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maniac-en/req/internal/backend/collections"
+	"github.com/maniac-en/req/internal/backend/endpoints"
+	"github.com/maniac-en/req/internal/backend/environments"
+	"github.com/maniac-en/req/internal/backend/folders"
+	"github.com/maniac-en/req/internal/backend/grpc"
+	"github.com/maniac-en/req/internal/backend/history"
+	"github.com/maniac-en/req/internal/backend/http"
+	"github.com/maniac-en/req/internal/backend/scripting"
+	"github.com/maniac-en/req/internal/backend/snapshots"
+	"github.com/maniac-en/req/internal/backend/testutils"
+)
+
+func setupRunner(t *testing.T) (*Runner, int64) {
+	t.Helper()
+	db := testutils.SetupTestDB(t, "collections", "endpoints", "environments", "history", "snapshots", "folders")
+	runner := NewRunner(
+		collections.NewCollectionsManager(db),
+		endpoints.NewEndpointsManager(db),
+		environments.NewEnvironmentsManager(db),
+		http.NewHTTPManager(),
+		grpc.NewGRPCManager(),
+		scripting.NewScriptManager(),
+		snapshots.NewSnapshotsManager(db),
+		history.NewHistoryManager(db),
+		folders.NewFoldersManager(db),
+	)
+	return runner, testutils.CreateTestCollection(t, db, "Runner Collection")
+}
+
+func TestRunEndpoint(t *testing.T) {
+	ctx := context.Background()
+	runner, collectionID := setupRunner(t)
+
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(nethttp.StatusCreated)
+		fmt.Fprintf(w, `{"token":%q,"echo":%q}`, r.Header.Get("X-Token"), string(body))
+	}))
+	defer server.Close()
+
+	endpoint, err := runner.Endpoints.CreateEndpoint(ctx, endpoints.EndpointData{
+		CollectionID: collectionID,
+		Name:         "Create Thing",
+		Method:       "POST",
+		URL:          server.URL,
+		Headers:      `{"X-Token": "secret"}`,
+		RequestBody:  `{"name": "thing"}`,
+	})
+	if err != nil {
+		t.Fatalf("CreateEndpoint failed: %v", err)
+	}
+
+	result, err := runner.RunEndpoint(ctx, endpoint.ID)
+	if err != nil {
+		t.Fatalf("RunEndpoint failed: %v", err)
+	}
+
+	if result.StatusCode != nethttp.StatusCreated {
+		t.Errorf("expected status 201, got %d", result.StatusCode)
+	}
+	if !result.Succeeded() {
+		t.Error("expected result to be successful")
+	}
+	if result.Body != `{"token":"secret","echo":"{\"name\": \"thing\"}"}` {
+		t.Errorf("unexpected body %s", result.Body)
+	}
+	if result.HistoryID == 0 {
+		t.Fatal("expected run to be recorded in history")
+	}
+
+	entry, err := runner.History.Read(ctx, result.HistoryID)
+	if err != nil {
+		t.Fatalf("failed to read history entry: %v", err)
+	}
+	if entry.CollectionName.String != "Runner Collection" {
+		t.Errorf("expected collection name in history, got %q", entry.CollectionName.String)
+	}
+	if entry.EndpointName.String != "Create Thing" {
+		t.Errorf("expected endpoint name in history, got %q", entry.EndpointName.String)
+	}
+}
+
+func TestRunEndpointDynamicVariables(t *testing.T) {
+	ctx := context.Background()
+	runner, collectionID := setupRunner(t)
+
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		fmt.Fprint(w, r.Header.Get("Idempotency-Key"))
+	}))
+	defer server.Close()
+
+	endpoint, err := runner.Endpoints.CreateEndpoint(ctx, endpoints.EndpointData{
+		CollectionID: collectionID,
+		Name:         "Idempotent",
+		Method:       "POST",
+		URL:          server.URL,
+		Headers:      `{"Idempotency-Key": "{{$uuid}}"}`,
+		RequestBody:  `{"key": "{{$uuid}}"}`,
+	})
+	if err != nil {
+		t.Fatalf("CreateEndpoint failed: %v", err)
+	}
+
+	result, err := runner.RunEndpoint(ctx, endpoint.ID)
+	if err != nil {
+		t.Fatalf("RunEndpoint failed: %v", err)
+	}
+	key := result.Generated["$uuid"]
+	if key == "" || result.Body != key {
+		t.Fatalf("expected generated key to be sent, got %q and %v", result.Body, result.Generated)
+	}
+
+	entry, err := runner.History.Read(ctx, result.HistoryID)
+	if err != nil {
+		t.Fatalf("failed to read history entry: %v", err)
+	}
+	if entry.RequestBody.String != `{"key": "`+key+`"}` {
+		t.Errorf("expected history to record the resolved body, got %q", entry.RequestBody.String)
+	}
+
+	broken := endpoint
+	broken.RequestBody = "{{$unknown}}"
+	if _, err := runner.Run(ctx, broken); err == nil {
+		t.Error("expected unknown template function to fail")
+	}
+}
+
+func TestRunEndpointErrors(t *testing.T) {
+	ctx := context.Background()
+	runner, collectionID := setupRunner(t)
+
+	t.Run("missing endpoint", func(t *testing.T) {
+		_, err := runner.RunEndpoint(ctx, 99999)
+		if err == nil {
+			t.Error("expected missing endpoint to fail")
+		}
+	})
+
+	t.Run("invalid URL", func(t *testing.T) {
+		endpoint, err := runner.Endpoints.CreateEndpoint(ctx, endpoints.EndpointData{
+			CollectionID: collectionID,
+			Name:         "No URL",
+			Method:       "GET",
+		})
+		if err != nil {
+			t.Fatalf("CreateEndpoint failed: %v", err)
+		}
+		if _, err := runner.Run(ctx, endpoint); err == nil {
+			t.Error("expected endpoint without URL to fail")
+		}
+	})
+}
+
+func TestResultSucceeded(t *testing.T) {
+	tests := []struct {
+		result   Result
+		expected bool
+	}{
+		{Result{Protocol: endpoints.ProtocolHTTP, StatusCode: 200}, true},
+		{Result{Protocol: endpoints.ProtocolHTTP, StatusCode: 302}, true},
+		{Result{Protocol: endpoints.ProtocolHTTP, StatusCode: 404}, false},
+		{Result{Protocol: endpoints.ProtocolGRPC, StatusCode: 0}, true},
+		{Result{Protocol: endpoints.ProtocolGRPC, StatusCode: 5}, false},
+	}
+
+	for _, test := range tests {
+		if got := test.result.Succeeded(); got != test.expected {
+			t.Errorf("Succeeded() for %s %d = %v, expected %v", test.result.Protocol, test.result.StatusCode, got, test.expected)
+		}
+	}
+}
+
+func TestRunEndpointSnapshot(t *testing.T) {
+	ctx := context.Background()
+	runner, collectionID := setupRunner(t)
+
+	version := "1"
+	server := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"version": %q, "requestId": %q}`, version, r.URL.RawQuery)
+	}))
+	defer server.Close()
+
+	endpoint, err := runner.Endpoints.CreateEndpoint(ctx, endpoints.EndpointData{
+		CollectionID: collectionID,
+		Name:         "Version",
+		Method:       "GET",
+		URL:          server.URL,
+		QueryParams:  map[string]string{"id": "{{$uuid}}"},
+	})
+	if err != nil {
+		t.Fatalf("CreateEndpoint failed: %v", err)
+	}
+
+	first, err := runner.Run(ctx, endpoint)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if first.Snapshot != nil {
+		t.Error("expected no comparison without a pinned snapshot")
+	}
+	if _, err := runner.Snapshots.Pin(ctx, endpoint.ID, first.Response()); err != nil {
+		t.Fatalf("Pin failed: %v", err)
+	}
+	if _, err := runner.Snapshots.SetIgnored(ctx, endpoint.ID, []string{"$.requestId"}, nil); err != nil {
+		t.Fatalf("SetIgnored failed: %v", err)
+	}
+
+	same, err := runner.Run(ctx, endpoint)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if same.Snapshot == nil || !same.Snapshot.Equal() || !same.Verified() {
+		t.Errorf("expected the response to match its snapshot, got %+v", same.Snapshot)
+	}
+
+	version = "2"
+	drifted, err := runner.Run(ctx, endpoint)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if drifted.Snapshot == nil || drifted.Snapshot.Equal() || drifted.Verified() {
+		t.Errorf("expected drift to fail verification, got %+v", drifted.Snapshot)
+	}
+}
@@ -0,0 +1,111 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/maniac-en/req/internal/backend/endpoints"
+	"github.com/maniac-en/req/internal/backend/scripting"
+	"github.com/maniac-en/req/internal/backend/variables"
+	"github.com/maniac-en/req/internal/log"
+)
+
+// scripts returns the hooks to run around an endpoint. Collection scripts
+// run before the endpoint's own.
+func (r *Runner) scripts(ctx context.Context, endpoint endpoints.EndpointEntity) (preRequest, postResponse []scripting.Script) {
+	if r.Scripts == nil {
+		return nil, nil
+	}
+
+	add := func(name, pre, post string) {
+		if pre != "" {
+			preRequest = append(preRequest, scripting.Script{Name: name, Phase: scripting.PhasePreRequest, Source: pre})
+		}
+		if post != "" {
+			postResponse = append(postResponse, scripting.Script{Name: name, Phase: scripting.PhasePostResponse, Source: post})
+		}
+	}
+	if r.Collections != nil {
+		if collection, err := r.Collections.Read(ctx, endpoint.CollectionID); err == nil {
+			add(collection.Name, collection.PreRequestScript, collection.PostResponseScript)
+		}
+	}
+	add(endpoint.Name, endpoint.PreRequestScript, endpoint.PostResponseScript)
+	return preRequest, postResponse
+}
+
+// scriptContext exposes the endpoint's request, before variables are
+// substituted, and the current variables to scripts. Folder variables sit
+// between the collection's and the environment's.
+func (r *Runner) scriptContext(ctx context.Context, endpoint endpoints.EndpointEntity, folderVars map[string]string) *scripting.Context {
+	collectionVars, environmentVars := r.variableSets(ctx, endpoint.CollectionID)
+	return &scripting.Context{
+		Request: &scripting.Request{
+			Method:      endpoint.Method,
+			URL:         endpoint.Url,
+			Headers:     endpoint.GetHeaders(),
+			QueryParams: endpoint.GetQueryParams(),
+			Body:        endpoint.RequestBody,
+		},
+		Variables:   variables.Merge(collectionVars, folderVars, environmentVars),
+		Collection:  collectionVars,
+		Environment: environmentVars,
+	}
+}
+
+// runScripts runs the scripts and persists the variables they changed
+func (r *Runner) runScripts(ctx context.Context, collectionID int64, scripts []scripting.Script, scriptCtx *scripting.Context) (*scripting.Output, error) {
+	if len(scripts) == 0 {
+		return &scripting.Output{}, nil
+	}
+
+	output, err := r.Scripts.Run(scripts, scriptCtx)
+	for _, change := range output.Changes {
+		if changeErr := r.setVariable(ctx, collectionID, change.Scope, change.Name, change.Value, change.Unset); changeErr != nil {
+			log.Warn("failed to store script variable", "variable", change.Name, "scope", change.Scope, "error", changeErr)
+			if err == nil {
+				err = fmt.Errorf("%s: %w", change.Name, changeErr)
+			}
+		}
+	}
+	return output, err
+}
+
+// postResponse runs the post-response scripts with the variables as they are
+// after extraction. Failures are collected on the result.
+func (r *Runner) postResponse(ctx context.Context, endpoint endpoints.EndpointEntity, folderVars map[string]string, scripts []scripting.Script, result *Result) {
+	if len(scripts) == 0 {
+		return
+	}
+
+	scriptCtx := r.scriptContext(ctx, result.Endpoint, folderVars)
+	scriptCtx.Response = &scripting.Response{
+		StatusCode: result.StatusCode,
+		Status:     result.Status,
+		Headers:    mergeMetadata(result.Headers, result.Trailers),
+		Body:       result.Body,
+		Duration:   result.Duration,
+	}
+
+	output, err := r.runScripts(ctx, endpoint.CollectionID, scripts, scriptCtx)
+	result.Logs = append(result.Logs, output.Logs...)
+	result.Tests = append(result.Tests, output.Tests...)
+	if err != nil {
+		result.ScriptErrors = append(result.ScriptErrors, err)
+	}
+}
+
+// withRequest returns a copy of the endpoint carrying the request as changed
+// by pre-request scripts
+func withRequest(endpoint endpoints.EndpointEntity, request *scripting.Request) endpoints.EndpointEntity {
+	headers, _ := json.Marshal(request.Headers)
+	params, _ := json.Marshal(request.QueryParams)
+
+	endpoint.Method = request.Method
+	endpoint.Url = request.URL
+	endpoint.Headers = string(headers)
+	endpoint.QueryParams = string(params)
+	endpoint.RequestBody = request.Body
+	return endpoint
+}
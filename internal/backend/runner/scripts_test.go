@@ -0,0 +1,150 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/maniac-en/req/internal/backend/endpoints"
+)
+
+func newEchoServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"signature": %q, "page": %q}`, r.Header.Get("X-Signature"), r.URL.Query().Get("page"))
+	}))
+}
+
+func TestRunScripts(t *testing.T) {
+	ctx := context.Background()
+	runner, collectionID := setupRunner(t)
+	server := newEchoServer(t)
+	defer server.Close()
+
+	if _, err := runner.Collections.SetVariables(ctx, collectionID, map[string]string{"page": "1"}); err != nil {
+		t.Fatalf("SetVariables failed: %v", err)
+	}
+	if _, err := runner.Collections.SetScripts(ctx, collectionID, `console.log("collection hook")`, ""); err != nil {
+		t.Fatalf("SetScripts failed: %v", err)
+	}
+
+	endpoint, err := runner.Endpoints.CreateEndpoint(ctx, endpoints.EndpointData{
+		CollectionID: collectionID,
+		Name:         "Signed",
+		Method:       "GET",
+		URL:          server.URL,
+		QueryParams:  map[string]string{"page": "{{page}}"},
+		PreRequestScript: `
+			req.request.headers["X-Signature"] = req.crypto.sha256("secret");
+			req.collection.set("page", "2");
+		`,
+		PostResponseScript: `
+			req.test("signed", function () {
+				req.expect(req.response.json().signature === req.crypto.sha256("secret"), "signature missing");
+			});
+			req.collection.set("seen", req.response.json().page);
+		`,
+	})
+	if err != nil {
+		t.Fatalf("CreateEndpoint failed: %v", err)
+	}
+
+	result, err := runner.RunEndpoint(ctx, endpoint.ID)
+	if err != nil {
+		t.Fatalf("RunEndpoint failed: %v", err)
+	}
+	if len(result.ScriptErrors) != 0 {
+		t.Fatalf("unexpected script errors: %v", result.ScriptErrors)
+	}
+	if len(result.Tests) != 1 || !result.Tests[0].Passed {
+		t.Errorf("expected one passing test, got %+v", result.Tests)
+	}
+	if len(result.Logs) != 1 || result.Logs[0].Message != "collection hook" {
+		t.Errorf("expected collection script log, got %+v", result.Logs)
+	}
+	if !result.Verified() {
+		t.Error("expected result to be verified")
+	}
+
+	collection, err := runner.Collections.Read(ctx, collectionID)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	// the pre-request change is applied before variables are substituted
+	if seen := collection.GetVariables()["seen"]; seen != "2" {
+		t.Errorf("expected request to use page 2, got %q", seen)
+	}
+}
+
+func TestRunScriptFailures(t *testing.T) {
+	ctx := context.Background()
+	runner, collectionID := setupRunner(t)
+	server := newEchoServer(t)
+	defer server.Close()
+
+	t.Run("failing test", func(t *testing.T) {
+		endpoint, err := runner.Endpoints.CreateEndpoint(ctx, endpoints.EndpointData{
+			CollectionID:       collectionID,
+			Name:               "Failing Test",
+			Method:             "GET",
+			URL:                server.URL,
+			PostResponseScript: `req.test("status", function () { req.expect(req.response.status === 404, "expected 404"); });`,
+		})
+		if err != nil {
+			t.Fatalf("CreateEndpoint failed: %v", err)
+		}
+
+		result, err := runner.Run(ctx, endpoint)
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		if result.Verified() {
+			t.Error("expected failing test to fail verification")
+		}
+		step := Step{Endpoint: endpoint, Result: result}
+		if step.Passed() {
+			t.Error("expected step with a failing test to fail")
+		}
+	})
+
+	t.Run("pre-request error", func(t *testing.T) {
+		endpoint, err := runner.Endpoints.CreateEndpoint(ctx, endpoints.EndpointData{
+			CollectionID:     collectionID,
+			Name:             "Broken Script",
+			Method:           "GET",
+			URL:              server.URL,
+			PreRequestScript: `throw new Error("boom")`,
+		})
+		if err != nil {
+			t.Fatalf("CreateEndpoint failed: %v", err)
+		}
+
+		if _, err := runner.Run(ctx, endpoint); err == nil {
+			t.Error("expected pre-request error to abort the request")
+		}
+	})
+
+	t.Run("post-response error", func(t *testing.T) {
+		endpoint, err := runner.Endpoints.CreateEndpoint(ctx, endpoints.EndpointData{
+			CollectionID:       collectionID,
+			Name:               "Broken Post Script",
+			Method:             "GET",
+			URL:                server.URL,
+			PostResponseScript: `req.environment.set("token", "x")`,
+		})
+		if err != nil {
+			t.Fatalf("CreateEndpoint failed: %v", err)
+		}
+
+		result, err := runner.Run(ctx, endpoint)
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+		if len(result.ScriptErrors) != 1 {
+			t.Errorf("expected environment change without an active environment to fail, got %v", result.ScriptErrors)
+		}
+	})
+}
@@ -0,0 +1,182 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/maniac-en/req/internal/backend/crud"
+	"github.com/maniac-en/req/internal/backend/endpoints"
+	"github.com/maniac-en/req/internal/backend/environments"
+	"github.com/maniac-en/req/internal/backend/variables"
+	"github.com/maniac-en/req/internal/log"
+	"github.com/maniac-en/req/internal/query"
+)
+
+var errNoEnvironment = errors.New("no active environment")
+
+// environment returns the environment variables are read from and written to
+func (r *Runner) environment(ctx context.Context) (environments.EnvironmentEntity, error) {
+	if r.Environments == nil {
+		return environments.EnvironmentEntity{}, errNoEnvironment
+	}
+	if r.EnvironmentID != 0 {
+		return r.Environments.Read(ctx, r.EnvironmentID)
+	}
+	environment, err := r.Environments.GetActive(ctx)
+	if errors.Is(err, crud.ErrNotFound) {
+		return environments.EnvironmentEntity{}, errNoEnvironment
+	}
+	return environment, err
+}
+
+// variableSets loads the collection and environment variables. Environment
+// variables take precedence when the sets are merged.
+func (r *Runner) variableSets(ctx context.Context, collectionID int64) (collectionVars, environmentVars map[string]string) {
+	if r.Collections != nil {
+		if collection, err := r.Collections.Read(ctx, collectionID); err == nil {
+			collectionVars = collection.GetVariables()
+		}
+	}
+	if environment, err := r.environment(ctx); err == nil {
+		environmentVars = environment.GetVariables()
+	} else if !errors.Is(err, errNoEnvironment) {
+		log.Warn("failed to load environment variables", "error", err)
+	}
+	return collectionVars, environmentVars
+}
+
+// resolve returns a copy of the endpoint with variables and template
+// functions substituted in everything that is sent, and the values the
+// functions generated
+func resolve(endpoint endpoints.EndpointEntity, vars map[string]string) (endpoints.EndpointEntity, map[string]string, error) {
+	template := variables.NewTemplate(vars)
+
+	headers, err := template.ExecuteMap(endpoint.GetHeaders())
+	if err != nil {
+		return endpoint, nil, fmt.Errorf("headers: %w", err)
+	}
+	params, err := template.ExecuteMap(endpoint.GetQueryParams())
+	if err != nil {
+		return endpoint, nil, fmt.Errorf("query params: %w", err)
+	}
+	url, err := template.Execute(endpoint.Url)
+	if err != nil {
+		return endpoint, nil, fmt.Errorf("url: %w", err)
+	}
+	method, err := template.Execute(endpoint.Method)
+	if err != nil {
+		return endpoint, nil, fmt.Errorf("method: %w", err)
+	}
+	body, err := template.Execute(endpoint.RequestBody)
+	if err != nil {
+		return endpoint, nil, fmt.Errorf("body: %w", err)
+	}
+
+	encodedHeaders, err := json.Marshal(headers)
+	if err != nil {
+		return endpoint, nil, err
+	}
+	encodedParams, err := json.Marshal(params)
+	if err != nil {
+		return endpoint, nil, err
+	}
+
+	endpoint.Url = url
+	endpoint.Method = method
+	endpoint.Headers = string(encodedHeaders)
+	endpoint.QueryParams = string(encodedParams)
+	endpoint.RequestBody = body
+	return endpoint, template.Generated(), nil
+}
+
+// extract applies the endpoint's extraction rules to a successful result and
+// stores the values so later requests can use them. Failures are collected
+// on the result rather than failing the run.
+func (r *Runner) extract(ctx context.Context, endpoint endpoints.EndpointEntity, result *Result) {
+	rules := endpoint.GetExtractions()
+	if len(rules) == 0 || !result.Succeeded() {
+		return
+	}
+
+	result.Extracted = map[string]string{}
+	for _, rule := range rules {
+		value, err := Extract(rule, result)
+		if err == nil {
+			err = r.setVariable(ctx, endpoint.CollectionID, rule.Scope, rule.Variable, value, false)
+		}
+		if err != nil {
+			log.Warn("extraction failed", "endpoint_id", endpoint.ID, "variable", rule.Variable, "error", err)
+			result.ExtractionErrors = append(result.ExtractionErrors, fmt.Errorf("%s: %w", rule.Variable, err))
+			continue
+		}
+		result.Extracted[rule.Variable] = value
+	}
+}
+
+// setVariable stores or removes a variable in the collection or the active
+// environment
+func (r *Runner) setVariable(ctx context.Context, collectionID int64, scope, name, value string, unset bool) error {
+	if scope == endpoints.ScopeEnvironment {
+		environment, err := r.environment(ctx)
+		if err != nil {
+			return err
+		}
+		if unset {
+			_, err = r.Environments.UnsetVariable(ctx, environment.ID, name)
+		} else {
+			_, err = r.Environments.SetVariable(ctx, environment.ID, name, value)
+		}
+		return err
+	}
+
+	if r.Collections == nil {
+		return errors.New("collection variables are not available")
+	}
+	var err error
+	if unset {
+		_, err = r.Collections.UnsetVariable(ctx, collectionID, name)
+	} else {
+		_, err = r.Collections.SetVariable(ctx, collectionID, name, value)
+	}
+	return err
+}
+
+// Extract evaluates a single extraction rule against a result
+func Extract(rule endpoints.Extraction, result *Result) (string, error) {
+	switch rule.Source {
+	case endpoints.SourceJSON:
+		results, err := query.Apply(rule.Expression, result.Body)
+		if err != nil {
+			return "", err
+		}
+		if len(results) == 0 {
+			return "", fmt.Errorf("%s matched nothing", rule.Expression)
+		}
+		return query.String(results[0]), nil
+	case endpoints.SourceHeader:
+		for name, values := range mergeMetadata(result.Headers, result.Trailers) {
+			if strings.EqualFold(name, rule.Expression) && len(values) > 0 {
+				return values[0], nil
+			}
+		}
+		return "", fmt.Errorf("header %s not found", rule.Expression)
+	case endpoints.SourceRegex:
+		pattern, err := regexp.Compile(rule.Expression)
+		if err != nil {
+			return "", err
+		}
+		match := pattern.FindStringSubmatch(result.Body)
+		if match == nil {
+			return "", fmt.Errorf("%s matched nothing", rule.Expression)
+		}
+		if len(match) > 1 {
+			return match[1], nil
+		}
+		return match[0], nil
+	}
+	return "", fmt.Errorf("unsupported extraction source: %s", rule.Source)
+}
@@ -0,0 +1,331 @@
+package scripting
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+	"time"
+
+	"github.com/dop251/goja"
+	"github.com/maniac-en/req/internal/backend/variables"
+	"github.com/maniac-en/req/internal/log"
+)
+
+func NewScriptManager() *ScriptManager {
+	return &ScriptManager{
+		Timeout: 5 * time.Second,
+	}
+}
+
+// Run executes scripts in order against the same context. Variable changes
+// made by one script are visible to the scripts after it. The output
+// collected so far is returned along with the first error.
+func (s *ScriptManager) Run(scripts []Script, ctx *Context) (*Output, error) {
+	for _, vars := range []*map[string]string{&ctx.Variables, &ctx.Collection, &ctx.Environment} {
+		if *vars == nil {
+			*vars = map[string]string{}
+		}
+	}
+
+	output := &Output{}
+	for _, script := range scripts {
+		if strings.TrimSpace(script.Source) == "" {
+			continue
+		}
+		if err := s.run(script, ctx, output); err != nil {
+			log.Warn("script failed", "script", script.Name, "phase", script.Phase, "error", err)
+			return output, fmt.Errorf("%s script %s: %w", script.Phase, script.Name, err)
+		}
+	}
+	return output, nil
+}
+
+func (s *ScriptManager) run(script Script, ctx *Context, output *Output) error {
+	vm := goja.New()
+	vm.SetFieldNameMapper(goja.TagFieldNameMapper("json", true))
+
+	b := &bindings{vm: vm, script: script, ctx: ctx, output: output}
+	if err := b.install(); err != nil {
+		return err
+	}
+
+	timer := time.AfterFunc(s.Timeout, func() {
+		vm.Interrupt(fmt.Sprintf("timed out after %s", s.Timeout))
+	})
+	defer timer.Stop()
+
+	log.Debug("running script", "script", script.Name, "phase", script.Phase)
+	if _, err := vm.RunScript(script.Name, script.Source); err != nil {
+		var exception *goja.Exception
+		if errors.As(err, &exception) {
+			return errors.New(exception.Value().String())
+		}
+		var interrupted *goja.InterruptedError
+		if errors.As(err, &interrupted) {
+			return fmt.Errorf("%v", interrupted.Value())
+		}
+		return err
+	}
+
+	if script.Phase == PhasePreRequest {
+		return b.readRequest()
+	}
+	return nil
+}
+
+// bindings exposes the script context to a single runtime
+type bindings struct {
+	vm      *goja.Runtime
+	script  Script
+	ctx     *Context
+	output  *Output
+	request *goja.Object
+}
+
+func (b *bindings) install() error {
+	console := b.vm.NewObject()
+	for _, level := range []string{"log", "info", "warn", "error", "debug"} {
+		console.Set(level, b.logger(level))
+	}
+
+	req := b.vm.NewObject()
+	if b.ctx.Request != nil {
+		b.request = b.requestObject()
+		req.Set("request", b.request)
+	}
+	if b.ctx.Response != nil && b.script.Phase == PhasePostResponse {
+		req.Set("response", b.responseObject())
+	}
+	req.Set("variables", b.variablesObject("", b.ctx.Variables))
+	req.Set("collection", b.variablesObject(ScopeCollection, b.ctx.Collection))
+	req.Set("environment", b.variablesObject(ScopeEnvironment, b.ctx.Environment))
+	req.Set("test", b.test)
+	req.Set("expect", b.expect)
+	req.Set("uuid", variables.NewUUID)
+	req.Set("timestamp", func() int64 { return time.Now().Unix() })
+	req.Set("isoTimestamp", func() string { return time.Now().UTC().Format(time.RFC3339) })
+	req.Set("crypto", b.cryptoObject())
+	req.Set("base64", map[string]any{
+		"encode": func(text string) string { return base64.StdEncoding.EncodeToString([]byte(text)) },
+		"decode": func(text string) (string, error) {
+			data, err := base64.StdEncoding.DecodeString(text)
+			return string(data), err
+		},
+	})
+
+	if err := b.vm.Set("console", console); err != nil {
+		return err
+	}
+	return b.vm.Set("req", req)
+}
+
+func (b *bindings) logger(level string) func(goja.FunctionCall) goja.Value {
+	return func(call goja.FunctionCall) goja.Value {
+		parts := make([]string, len(call.Arguments))
+		for i, arg := range call.Arguments {
+			parts[i] = b.format(arg)
+		}
+		b.output.Logs = append(b.output.Logs, LogEntry{
+			Script:  b.script.Name,
+			Level:   level,
+			Message: strings.Join(parts, " "),
+		})
+		return goja.Undefined()
+	}
+}
+
+// format renders objects as JSON, like a browser console would
+func (b *bindings) format(value goja.Value) string {
+	if object, ok := value.(*goja.Object); ok && object.ClassName() != "Function" && object.ClassName() != "Error" {
+		if data, err := json.Marshal(object.Export()); err == nil {
+			return string(data)
+		}
+	}
+	return value.String()
+}
+
+func (b *bindings) requestObject() *goja.Object {
+	request := b.ctx.Request
+	object := b.vm.NewObject()
+	object.Set("method", request.Method)
+	object.Set("url", request.URL)
+	object.Set("headers", stringMap(b.vm, request.Headers))
+	object.Set("query", stringMap(b.vm, request.QueryParams))
+	object.Set("body", request.Body)
+	return object
+}
+
+// readRequest copies the changes a pre-request script made back to the request
+func (b *bindings) readRequest() error {
+	if b.request == nil {
+		return nil
+	}
+	request := b.ctx.Request
+	request.Method = b.request.Get("method").String()
+	request.URL = b.request.Get("url").String()
+	request.Body = b.request.Get("body").String()
+
+	var err error
+	if request.Headers, err = exportStringMap(b.request.Get("headers")); err != nil {
+		return fmt.Errorf("req.request.headers: %w", err)
+	}
+	if request.QueryParams, err = exportStringMap(b.request.Get("query")); err != nil {
+		return fmt.Errorf("req.request.query: %w", err)
+	}
+	return nil
+}
+
+func (b *bindings) responseObject() *goja.Object {
+	response := b.ctx.Response
+	headers := map[string]string{}
+	for name, values := range response.Headers {
+		headers[strings.ToLower(name)] = strings.Join(values, ", ")
+	}
+
+	object := b.vm.NewObject()
+	object.Set("status", response.StatusCode)
+	object.Set("statusText", response.Status)
+	object.Set("headers", headers)
+	object.Set("body", response.Body)
+	object.Set("time", response.Duration.Milliseconds())
+	object.Set("header", func(name string) goja.Value {
+		if value, ok := headers[strings.ToLower(name)]; ok {
+			return b.vm.ToValue(value)
+		}
+		return goja.Undefined()
+	})
+	object.Set("json", func() (goja.Value, error) {
+		var decoded any
+		if err := json.Unmarshal([]byte(response.Body), &decoded); err != nil {
+			return nil, fmt.Errorf("response body is not JSON: %w", err)
+		}
+		return b.vm.ToValue(decoded), nil
+	})
+	return object
+}
+
+// variablesObject exposes a variable set. Changes are recorded so the caller
+// can persist them, and are mirrored into the merged view. An empty scope is
+// the read-only merged view.
+func (b *bindings) variablesObject(scope string, vars map[string]string) *goja.Object {
+	object := b.vm.NewObject()
+	object.Set("get", func(name string) goja.Value {
+		if value, ok := vars[name]; ok {
+			return b.vm.ToValue(value)
+		}
+		return goja.Undefined()
+	})
+	object.Set("has", func(name string) bool {
+		_, ok := vars[name]
+		return ok
+	})
+	object.Set("toObject", func() map[string]string {
+		return vars
+	})
+	if scope == "" {
+		return object
+	}
+
+	object.Set("set", func(name string, value goja.Value) {
+		text := b.format(value)
+		vars[name] = text
+		b.ctx.Variables[name] = text
+		b.output.Changes = append(b.output.Changes, VariableChange{Scope: scope, Name: name, Value: text})
+	})
+	object.Set("unset", func(name string) {
+		delete(vars, name)
+		delete(b.ctx.Variables, name)
+		b.output.Changes = append(b.output.Changes, VariableChange{Scope: scope, Name: name, Unset: true})
+	})
+	return object
+}
+
+// test runs fn and records whether it threw
+func (b *bindings) test(name string, fn goja.Callable) {
+	result := TestResult{Script: b.script.Name, Name: name, Passed: true}
+	if _, err := fn(goja.Undefined()); err != nil {
+		var exception *goja.Exception
+		if errors.As(err, &exception) {
+			result.Error = exception.Value().String()
+		} else {
+			result.Error = err.Error()
+		}
+		result.Passed = false
+	}
+	b.output.Tests = append(b.output.Tests, result)
+}
+
+// expect throws when the condition is false, for use inside req.test
+func (b *bindings) expect(condition bool, message goja.Value) {
+	if condition {
+		return
+	}
+	text := "expectation failed"
+	if message != nil && !goja.IsUndefined(message) {
+		text = message.String()
+	}
+	exception, err := b.vm.New(b.vm.Get("Error"), b.vm.ToValue(text))
+	if err != nil {
+		panic(err)
+	}
+	exception.Set("name", "AssertionError")
+	panic(exception)
+}
+
+func (b *bindings) cryptoObject() map[string]any {
+	digest := func(newHash func() hash.Hash) func(string) string {
+		return func(text string) string {
+			h := newHash()
+			h.Write([]byte(text))
+			return hex.EncodeToString(h.Sum(nil))
+		}
+	}
+	mac := func(newHash func() hash.Hash) func(string, string) string {
+		return func(key, text string) string {
+			h := hmac.New(newHash, []byte(key))
+			h.Write([]byte(text))
+			return hex.EncodeToString(h.Sum(nil))
+		}
+	}
+	return map[string]any{
+		"md5":        digest(md5.New),
+		"sha1":       digest(sha1.New),
+		"sha256":     digest(sha256.New),
+		"sha512":     digest(sha512.New),
+		"hmacSHA1":   mac(sha1.New),
+		"hmacSHA256": mac(sha256.New),
+		"hmacSHA512": mac(sha512.New),
+	}
+}
+
+func stringMap(vm *goja.Runtime, values map[string]string) *goja.Object {
+	object := vm.NewObject()
+	for key, value := range values {
+		object.Set(key, value)
+	}
+	return object
+}
+
+func exportStringMap(value goja.Value) (map[string]string, error) {
+	out := map[string]string{}
+	if value == nil || goja.IsUndefined(value) || goja.IsNull(value) {
+		return out, nil
+	}
+	exported, ok := value.Export().(map[string]any)
+	if !ok {
+		return nil, errors.New("expected an object")
+	}
+	for key, item := range exported {
+		out[key] = fmt.Sprint(item)
+	}
+	return out, nil
+}
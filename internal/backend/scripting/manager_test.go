@@ -0,0 +1,168 @@
+package scripting
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPreRequestScript(t *testing.T) {
+	manager := NewScriptManager()
+	ctx := &Context{
+		Request: &Request{
+			Method:  "POST",
+			URL:     "https://api.example.com/orders",
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Body:    `{"amount": 10}`,
+		},
+		Variables:   map[string]string{"secret": "s3cret"},
+		Environment: map[string]string{"secret": "s3cret"},
+	}
+
+	output, err := manager.Run([]Script{{
+		Name:  "Create Order",
+		Phase: PhasePreRequest,
+		Source: `
+			const ts = req.timestamp();
+			const signature = req.crypto.hmacSHA256(req.variables.get("secret"), req.request.body);
+			req.request.headers["X-Signature"] = signature;
+			req.request.headers["X-Timestamp"] = String(ts);
+			req.request.query.trace = req.uuid();
+			req.request.url = req.request.url + "?v=2";
+			req.environment.set("lastSignature", signature);
+			console.log("signed", {length: signature.length});
+		`,
+	}}, ctx)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write([]byte(`{"amount": 10}`))
+	signature := ctx.Request.Headers["X-Signature"]
+	if signature != hex.EncodeToString(mac.Sum(nil)) {
+		t.Errorf("unexpected HMAC signature %q", signature)
+	}
+	if ctx.Request.Headers["Content-Type"] != "application/json" {
+		t.Error("expected existing headers to be kept")
+	}
+	if ctx.Request.Headers["X-Timestamp"] == "" {
+		t.Error("expected timestamp header to be set")
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(ctx.Request.QueryParams["trace"]) {
+		t.Errorf("expected a v4 UUID, got %q", ctx.Request.QueryParams["trace"])
+	}
+	if ctx.Request.URL != "https://api.example.com/orders?v=2" {
+		t.Errorf("unexpected URL %q", ctx.Request.URL)
+	}
+
+	if len(output.Changes) != 1 || output.Changes[0].Scope != ScopeEnvironment || output.Changes[0].Value != signature {
+		t.Errorf("expected environment change to be recorded, got %+v", output.Changes)
+	}
+	if ctx.Variables["lastSignature"] != signature {
+		t.Error("expected change to be visible in the merged variables")
+	}
+	if len(output.Logs) != 1 || output.Logs[0].Message != `signed {"length":64}` {
+		t.Errorf("unexpected logs %+v", output.Logs)
+	}
+}
+
+func TestPostResponseScript(t *testing.T) {
+	manager := NewScriptManager()
+	ctx := &Context{
+		Response: &Response{
+			StatusCode: 201,
+			Status:     "201 Created",
+			Headers:    map[string][]string{"Content-Type": {"application/json"}},
+			Body:       `{"id": 7, "items": [1, 2, 3]}`,
+			Duration:   120 * time.Millisecond,
+		},
+	}
+
+	output, err := manager.Run([]Script{
+		{
+			Name:  "Collection",
+			Phase: PhasePostResponse,
+			Source: `
+				req.test("status is 201", () => req.expect(req.response.status === 201));
+				req.test("is fast", () => req.expect(req.response.time < 100, "took " + req.response.time + "ms"));
+			`,
+		},
+		{
+			Name:  "Create",
+			Phase: PhasePostResponse,
+			Source: `
+				const body = req.response.json();
+				req.collection.set("id", body.id);
+				req.test("has items", () => req.expect(body.items.length === 3));
+				req.test("json content", () => req.expect(req.response.header("content-type") === "application/json"));
+				console.warn("id is", req.variables.get("id"));
+			`,
+		},
+	}, ctx)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if len(output.Tests) != 4 {
+		t.Fatalf("expected 4 tests, got %+v", output.Tests)
+	}
+	for i, passed := range []bool{true, false, true, true} {
+		if output.Tests[i].Passed != passed {
+			t.Errorf("test %q: expected passed=%v, got %+v", output.Tests[i].Name, passed, output.Tests[i])
+		}
+	}
+	if output.Tests[1].Error != "AssertionError: took 120ms" {
+		t.Errorf("unexpected failure message %q", output.Tests[1].Error)
+	}
+	if ctx.Collection["id"] != "7" {
+		t.Errorf("expected id in collection variables, got %v", ctx.Collection)
+	}
+	if len(output.Logs) != 1 || output.Logs[0].Level != "warn" || output.Logs[0].Message != "id is 7" {
+		t.Errorf("unexpected logs %+v", output.Logs)
+	}
+}
+
+func TestScriptErrors(t *testing.T) {
+	manager := NewScriptManager()
+	manager.Timeout = 50 * time.Millisecond
+
+	tests := []struct {
+		name   string
+		source string
+		errMsg string
+	}{
+		{"exception", `throw new Error("boom")`, "boom"},
+		{"syntax", `const = 1`, "SyntaxError"},
+		{"timeout", `while (true) {}`, "timed out"},
+		{"no require", `require("fs")`, "require is not defined"},
+		{"no fetch", `fetch("https://example.com")`, "fetch is not defined"},
+		{"bad headers", `req.request.headers = "nope"`, "expected an object"},
+	}
+
+	for _, test := range tests {
+		ctx := &Context{Request: &Request{Method: "GET", URL: "https://example.com"}}
+		output, err := manager.Run([]Script{
+			{Name: "before", Phase: PhasePreRequest, Source: `console.log("ran")`},
+			{Name: test.name, Phase: PhasePreRequest, Source: test.source},
+			{Name: "after", Phase: PhasePreRequest, Source: `console.log("should not run")`},
+		}, ctx)
+		if err == nil || !strings.Contains(err.Error(), test.errMsg) {
+			t.Errorf("%s: expected error containing %q, got %v", test.name, test.errMsg, err)
+		}
+		if len(output.Logs) != 1 {
+			t.Errorf("%s: expected output up to the failure to be kept, got %+v", test.name, output.Logs)
+		}
+	}
+}
+
+func TestEmptyScriptsAreSkipped(t *testing.T) {
+	output, err := NewScriptManager().Run([]Script{{Name: "empty", Phase: PhasePreRequest, Source: "  \n"}}, &Context{})
+	if err != nil || len(output.Logs) != 0 {
+		t.Errorf("expected empty scripts to be skipped, got %+v, %v", output, err)
+	}
+}
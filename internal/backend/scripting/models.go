@@ -0,0 +1,83 @@
+// Package scripting runs the JavaScript pre-request and post-response hooks
+// of endpoints and collections. Scripts run in a sandbox: the runtime has no
+// filesystem, network or process access, only the req and console objects.
+package scripting
+
+import "time"
+
+const (
+	PhasePreRequest   = "pre-request"
+	PhasePostResponse = "post-response"
+)
+
+const (
+	ScopeCollection  = "collection"
+	ScopeEnvironment = "environment"
+)
+
+type ScriptManager struct {
+	Timeout time.Duration
+}
+
+// Request is the request a script sees. Pre-request scripts may change it;
+// placeholders are resolved after the scripts have run.
+type Request struct {
+	Method      string
+	URL         string
+	Headers     map[string]string
+	QueryParams map[string]string
+	Body        string
+}
+
+type Response struct {
+	StatusCode int
+	Status     string
+	Headers    map[string][]string
+	Body       string
+	Duration   time.Duration
+}
+
+// Script is one script to run. Name identifies it in logs and errors, for
+// example the endpoint or collection it belongs to.
+type Script struct {
+	Name   string
+	Phase  string
+	Source string
+}
+
+// Context is what scripts can read. Variables holds the collection and
+// environment variables merged, environment first.
+type Context struct {
+	Request     *Request
+	Response    *Response
+	Variables   map[string]string
+	Collection  map[string]string
+	Environment map[string]string
+}
+
+type LogEntry struct {
+	Script  string
+	Level   string
+	Message string
+}
+
+type TestResult struct {
+	Script string
+	Name   string
+	Passed bool
+	Error  string
+}
+
+// VariableChange is a variable set or removed by a script
+type VariableChange struct {
+	Scope string
+	Name  string
+	Value string
+	Unset bool
+}
+
+type Output struct {
+	Logs    []LogEntry
+	Tests   []TestResult
+	Changes []VariableChange
+}
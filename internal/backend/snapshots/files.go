@@ -0,0 +1,155 @@
+package snapshots
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/maniac-en/req/internal/diff"
+	"github.com/maniac-en/req/internal/log"
+)
+
+// File is the form snapshots are exported in so they can be reviewed next
+// to the code they describe. JSON bodies are stored as canonical JSON, with
+// sorted keys, so a change to the snapshot reads as a small diff.
+type File struct {
+	Endpoint       string              `json:"endpoint"`
+	Status         int                 `json:"status"`
+	Headers        map[string][]string `json:"headers,omitempty"`
+	JSON           json.RawMessage     `json:"json,omitempty"`
+	Body           string              `json:"body,omitempty"`
+	IgnoredPaths   []string            `json:"ignore,omitempty"`
+	IgnoredHeaders []string            `json:"ignoreHeaders,omitempty"`
+}
+
+// NewFile converts a snapshot to its exported form. Headers that are never
+// compared are left out so they don't churn the file.
+func NewFile(snapshot NamedSnapshot) File {
+	response := snapshot.Response()
+	for _, name := range diff.DefaultIgnoredHeaders {
+		for header := range response.Headers {
+			if strings.EqualFold(header, name) {
+				delete(response.Headers, header)
+			}
+		}
+	}
+	file := File{
+		Endpoint:       snapshot.EndpointName,
+		Status:         response.Status,
+		Headers:        response.Headers,
+		IgnoredPaths:   snapshot.GetIgnoredPaths(),
+		IgnoredHeaders: snapshot.GetIgnoredHeaders(),
+	}
+	if canonical, ok := diff.Canonical(response.Body); ok {
+		file.JSON = json.RawMessage(canonical)
+	} else {
+		file.Body = response.Body
+	}
+	return file
+}
+
+// Response returns the response the file describes
+func (f File) Response() diff.Response {
+	body := f.Body
+	if len(f.JSON) > 0 {
+		body = string(f.JSON)
+	}
+	return diff.Response{Status: f.Status, Headers: f.Headers, Body: body}
+}
+
+// Export writes one file per snapshot of the collection into dir and
+// returns their paths
+func (s *SnapshotsManager) Export(ctx context.Context, collectionID int64, dir string) ([]string, error) {
+	snapshots, err := s.ListByCollection(ctx, collectionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	used := map[string]bool{}
+	var paths []string
+	for _, snapshot := range snapshots {
+		data, err := json.MarshalIndent(NewFile(snapshot), "", "  ")
+		if err != nil {
+			return paths, fmt.Errorf("%s: %w", snapshot.EndpointName, err)
+		}
+
+		name := fileName(snapshot.EndpointName)
+		for i := 2; used[name]; i++ {
+			name = fmt.Sprintf("%s-%d", fileName(snapshot.EndpointName), i)
+		}
+		used[name] = true
+
+		path := filepath.Join(dir, name+".json")
+		if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+
+	log.Info("exported snapshots", "collection_id", collectionID, "dir", dir, "count", len(paths))
+	return paths, nil
+}
+
+// Import pins every snapshot file in dir to the endpoint of the collection
+// it names, replacing the endpoint's current snapshot
+func (s *SnapshotsManager) Import(ctx context.Context, collectionID int64, dir string) (int, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return 0, err
+	}
+	endpoints, err := s.DB.ListEndpointsByCollection(ctx, collectionID)
+	if err != nil {
+		return 0, err
+	}
+	ids := make(map[string]int64, len(endpoints))
+	for _, endpoint := range endpoints {
+		ids[endpoint.Name] = endpoint.ID
+	}
+
+	imported := 0
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return imported, err
+		}
+		var file File
+		if err := json.Unmarshal(data, &file); err != nil {
+			return imported, fmt.Errorf("%s: %w", path, err)
+		}
+		endpointID, ok := ids[file.Endpoint]
+		if !ok {
+			return imported, fmt.Errorf("%s: no endpoint named %q in the collection", path, file.Endpoint)
+		}
+
+		if err := (diff.Options{IgnorePaths: file.IgnoredPaths}).Validate(); err != nil {
+			return imported, fmt.Errorf("%s: %w", path, err)
+		}
+		if _, err := s.Pin(ctx, endpointID, file.Response()); err != nil {
+			return imported, fmt.Errorf("%s: %w", path, err)
+		}
+		if _, err := s.SetIgnored(ctx, endpointID, file.IgnoredPaths, file.IgnoredHeaders); err != nil {
+			return imported, fmt.Errorf("%s: %w", path, err)
+		}
+		imported++
+	}
+
+	log.Info("imported snapshots", "collection_id", collectionID, "dir", dir, "count", imported)
+	return imported, nil
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+func fileName(endpointName string) string {
+	name := strings.Trim(unsafeFileChars.ReplaceAllString(strings.ToLower(endpointName), "-"), "-")
+	if name == "" {
+		return "snapshot"
+	}
+	return name
+}
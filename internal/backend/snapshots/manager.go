@@ -0,0 +1,157 @@
+package snapshots
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/maniac-en/req/internal/backend/crud"
+	"github.com/maniac-en/req/internal/backend/database"
+	"github.com/maniac-en/req/internal/diff"
+	"github.com/maniac-en/req/internal/log"
+)
+
+func NewSnapshotsManager(db *database.Queries) *SnapshotsManager {
+	return &SnapshotsManager{DB: db}
+}
+
+// Pin stores a response as the endpoint's snapshot, replacing any earlier
+// one. Ignored fields are kept.
+func (s *SnapshotsManager) Pin(ctx context.Context, endpointID int64, response diff.Response) (SnapshotEntity, error) {
+	if err := crud.ValidateID(endpointID); err != nil {
+		log.Warn("snapshot pin failed validation", "endpoint_id", endpointID)
+		return SnapshotEntity{}, crud.ErrInvalidInput
+	}
+
+	headers := response.Headers
+	if headers == nil {
+		headers = map[string][]string{}
+	}
+	encodedHeaders, err := json.Marshal(headers)
+	if err != nil {
+		return SnapshotEntity{}, err
+	}
+
+	log.Debug("pinning snapshot", "endpoint_id", endpointID, "status", response.Status)
+	snapshot, err := s.DB.PinSnapshot(ctx, database.PinSnapshotParams{
+		EndpointID:      endpointID,
+		StatusCode:      int64(response.Status),
+		ResponseHeaders: string(encodedHeaders),
+		ResponseBody:    response.Body,
+	})
+	if err != nil {
+		log.Error("failed to pin snapshot", "endpoint_id", endpointID, "error", err)
+		return SnapshotEntity{}, err
+	}
+
+	log.Info("pinned snapshot", "id", snapshot.ID, "endpoint_id", endpointID)
+	return SnapshotEntity{Snapshot: snapshot}, nil
+}
+
+// ReadByEndpoint returns the endpoint's snapshot or crud.ErrNotFound when
+// none is pinned
+func (s *SnapshotsManager) ReadByEndpoint(ctx context.Context, endpointID int64) (SnapshotEntity, error) {
+	if err := crud.ValidateID(endpointID); err != nil {
+		log.Warn("snapshot read failed validation", "endpoint_id", endpointID)
+		return SnapshotEntity{}, crud.ErrInvalidInput
+	}
+
+	snapshot, err := s.DB.GetSnapshotByEndpoint(ctx, endpointID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return SnapshotEntity{}, crud.ErrNotFound
+		}
+		log.Error("failed to read snapshot", "endpoint_id", endpointID, "error", err)
+		return SnapshotEntity{}, err
+	}
+	return SnapshotEntity{Snapshot: snapshot}, nil
+}
+
+// SetIgnored replaces the JSONPaths and header names left out of
+// comparisons with the endpoint's snapshot. Paths that don't parse are
+// rejected.
+func (s *SnapshotsManager) SetIgnored(ctx context.Context, endpointID int64, paths, headers []string) (SnapshotEntity, error) {
+	if err := crud.ValidateID(endpointID); err != nil {
+		log.Warn("snapshot update failed validation", "endpoint_id", endpointID)
+		return SnapshotEntity{}, crud.ErrInvalidInput
+	}
+	if err := (diff.Options{IgnorePaths: paths}).Validate(); err != nil {
+		log.Warn("snapshot update failed validation", "endpoint_id", endpointID, "error", err)
+		return SnapshotEntity{}, fmt.Errorf("%w: %v", crud.ErrInvalidInput, err)
+	}
+	if paths == nil {
+		paths = []string{}
+	}
+	if headers == nil {
+		headers = []string{}
+	}
+	encodedPaths, err := json.Marshal(paths)
+	if err != nil {
+		return SnapshotEntity{}, err
+	}
+	encodedHeaders, err := json.Marshal(headers)
+	if err != nil {
+		return SnapshotEntity{}, err
+	}
+
+	log.Debug("updating snapshot ignored fields", "endpoint_id", endpointID, "paths", len(paths), "headers", len(headers))
+	snapshot, err := s.DB.UpdateSnapshotIgnored(ctx, database.UpdateSnapshotIgnoredParams{
+		IgnoredPaths:   string(encodedPaths),
+		IgnoredHeaders: string(encodedHeaders),
+		EndpointID:     endpointID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return SnapshotEntity{}, crud.ErrNotFound
+		}
+		log.Error("failed to update snapshot ignored fields", "endpoint_id", endpointID, "error", err)
+		return SnapshotEntity{}, err
+	}
+	return SnapshotEntity{Snapshot: snapshot}, nil
+}
+
+// Delete unpins the endpoint's snapshot
+func (s *SnapshotsManager) Delete(ctx context.Context, endpointID int64) error {
+	if err := crud.ValidateID(endpointID); err != nil {
+		log.Warn("snapshot delete failed validation", "endpoint_id", endpointID)
+		return crud.ErrInvalidInput
+	}
+
+	deleted, err := s.DB.DeleteSnapshotByEndpoint(ctx, endpointID)
+	if err != nil {
+		log.Error("failed to delete snapshot", "endpoint_id", endpointID, "error", err)
+		return err
+	}
+	if deleted == 0 {
+		return crud.ErrNotFound
+	}
+	log.Info("deleted snapshot", "endpoint_id", endpointID)
+	return nil
+}
+
+// NamedSnapshot is a snapshot with the name of its endpoint
+type NamedSnapshot struct {
+	SnapshotEntity
+	EndpointName string
+}
+
+// ListByCollection returns the snapshots of a collection's endpoints, by
+// endpoint name
+func (s *SnapshotsManager) ListByCollection(ctx context.Context, collectionID int64) ([]NamedSnapshot, error) {
+	if err := crud.ValidateID(collectionID); err != nil {
+		log.Warn("snapshot list failed validation", "collection_id", collectionID)
+		return nil, crud.ErrInvalidInput
+	}
+
+	rows, err := s.DB.ListSnapshotsByCollection(ctx, collectionID)
+	if err != nil {
+		log.Error("failed to list snapshots", "collection_id", collectionID, "error", err)
+		return nil, err
+	}
+	snapshots := make([]NamedSnapshot, len(rows))
+	for i, row := range rows {
+		snapshots[i] = NamedSnapshot{SnapshotEntity: SnapshotEntity{Snapshot: row.Snapshot}, EndpointName: row.EndpointName}
+	}
+	return snapshots, nil
+}
@@ -0,0 +1,189 @@
+package snapshots
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/maniac-en/req/internal/backend/crud"
+	"github.com/maniac-en/req/internal/backend/database"
+	"github.com/maniac-en/req/internal/backend/testutils"
+	"github.com/maniac-en/req/internal/diff"
+)
+
+func createEndpoint(t *testing.T, db *database.Queries, collectionID int64, name string) int64 {
+	t.Helper()
+	endpoint, err := db.CreateEndpoint(context.Background(), database.CreateEndpointParams{
+		CollectionID: collectionID,
+		Name:         name,
+		Method:       "GET",
+		Url:          "http://example.com",
+		Headers:      "{}",
+		QueryParams:  "{}",
+		Protocol:     "http",
+		ProtoFiles:   "[]",
+		Extractions:  "[]",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test endpoint: %v", err)
+	}
+	return endpoint.ID
+}
+
+func TestSnapshotsManager(t *testing.T) {
+	db := testutils.SetupTestDB(t, "collections", "endpoints", "snapshots")
+	manager := NewSnapshotsManager(db)
+	ctx := context.Background()
+	collectionID := testutils.CreateTestCollection(t, db, "Snapshots")
+	endpointID := createEndpoint(t, db, collectionID, "Get User")
+
+	t.Run("Pin", func(t *testing.T) {
+		if _, err := manager.ReadByEndpoint(ctx, endpointID); err != crud.ErrNotFound {
+			t.Errorf("Expected ErrNotFound before pinning, got %v", err)
+		}
+
+		_, err := manager.Pin(ctx, endpointID, diff.Response{Status: 200, Body: `{"id": 1}`})
+		if err != nil {
+			t.Fatalf("Pin failed: %v", err)
+		}
+		repinned, err := manager.Pin(ctx, endpointID, diff.Response{
+			Status:  201,
+			Headers: map[string][]string{"Content-Type": {"application/json"}},
+			Body:    `{"id": 2}`,
+		})
+		if err != nil {
+			t.Fatalf("Pin failed: %v", err)
+		}
+
+		snapshot, err := manager.ReadByEndpoint(ctx, endpointID)
+		if err != nil {
+			t.Fatalf("ReadByEndpoint failed: %v", err)
+		}
+		if snapshot.ID != repinned.ID || snapshot.StatusCode != 201 || snapshot.ResponseBody != `{"id": 2}` {
+			t.Errorf("Expected pinning again to replace the snapshot, got %+v", snapshot.Snapshot)
+		}
+		if snapshot.Response().Headers["Content-Type"][0] != "application/json" {
+			t.Errorf("Expected headers to round trip, got %v", snapshot.Response().Headers)
+		}
+	})
+
+	t.Run("Compare", func(t *testing.T) {
+		if _, err := manager.SetIgnored(ctx, endpointID, []string{"$.at"}, []string{"X-Request-Id"}); err != nil {
+			t.Fatalf("SetIgnored failed: %v", err)
+		}
+		if _, err := manager.Pin(ctx, endpointID, diff.Response{Status: 200, Body: `{"id": 2, "at": 1}`}); err != nil {
+			t.Fatalf("Pin failed: %v", err)
+		}
+		snapshot, _ := manager.ReadByEndpoint(ctx, endpointID)
+		if len(snapshot.GetIgnoredPaths()) != 1 {
+			t.Errorf("Expected ignored paths to survive pinning, got %v", snapshot.GetIgnoredPaths())
+		}
+
+		same := snapshot.Compare(diff.Response{
+			Status:  200,
+			Headers: map[string][]string{"X-Request-Id": {"abc"}, "Date": {"today"}},
+			Body:    `{"at": 2, "id": 2}`,
+		})
+		if !same.Equal() {
+			t.Errorf("Expected ignored fields not to count, got %+v", same)
+		}
+		drifted := snapshot.Compare(diff.Response{Status: 200, Body: `{"id": 3, "at": 1}`})
+		if drifted.Equal() {
+			t.Error("Expected a changed ID to count as drift")
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		other := createEndpoint(t, db, collectionID, "Delete Me")
+		if _, err := manager.Pin(ctx, other, diff.Response{Status: 204}); err != nil {
+			t.Fatalf("Pin failed: %v", err)
+		}
+		if err := manager.Delete(ctx, other); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if err := manager.Delete(ctx, other); err != crud.ErrNotFound {
+			t.Errorf("Expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		if _, err := manager.Pin(ctx, 0, diff.Response{}); err != crud.ErrInvalidInput {
+			t.Errorf("Expected ErrInvalidInput, got %v", err)
+		}
+		if _, err := manager.SetIgnored(ctx, 99999, nil, nil); err != crud.ErrNotFound {
+			t.Errorf("Expected ErrNotFound, got %v", err)
+		}
+		if _, err := manager.SetIgnored(ctx, endpointID, []string{"$.items["}, nil); !errors.Is(err, crud.ErrInvalidInput) {
+			t.Errorf("Expected an unparsable path to fail with ErrInvalidInput, got %v", err)
+		}
+	})
+}
+
+func TestExportImport(t *testing.T) {
+	db := testutils.SetupTestDB(t, "collections", "endpoints", "snapshots")
+	manager := NewSnapshotsManager(db)
+	ctx := context.Background()
+	collectionID := testutils.CreateTestCollection(t, db, "Export")
+	jsonID := createEndpoint(t, db, collectionID, "Get User")
+	textID := createEndpoint(t, db, collectionID, "Health/Check")
+
+	manager.Pin(ctx, jsonID, diff.Response{
+		Status:  200,
+		Headers: map[string][]string{"Date": {"Mon"}, "Content-Type": {"application/json"}},
+		Body:    `{"name":"ann","id":1}`,
+	})
+	manager.SetIgnored(ctx, jsonID, []string{"$.id"}, nil)
+	manager.Pin(ctx, textID, diff.Response{Status: 200, Body: "ok\n"})
+
+	dir := t.TempDir()
+	paths, err := manager.Export(ctx, collectionID, dir)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if len(paths) != 2 || filepath.Base(paths[0]) != "get-user.json" || filepath.Base(paths[1]) != "health-check.json" {
+		t.Fatalf("Unexpected exported files %v", paths)
+	}
+	data, _ := os.ReadFile(paths[0])
+	if !strings.Contains(string(data), "\"json\": {\n    \"id\": 1,\n    \"name\": \"ann\"\n  }") {
+		t.Errorf("Expected the JSON body to be stored as canonical JSON, got %s", data)
+	}
+	if strings.Contains(string(data), "Date") || !strings.Contains(string(data), "Content-Type") {
+		t.Errorf("Expected only compared headers to be exported, got %s", data)
+	}
+
+	// pin something else, then restore the exported snapshots
+	manager.Pin(ctx, jsonID, diff.Response{Status: 500})
+	manager.SetIgnored(ctx, jsonID, nil, nil)
+	imported, err := manager.Import(ctx, collectionID, dir)
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if imported != 2 {
+		t.Errorf("Expected 2 imported snapshots, got %d", imported)
+	}
+
+	snapshot, _ := manager.ReadByEndpoint(ctx, jsonID)
+	if snapshot.StatusCode != 200 || len(snapshot.GetIgnoredPaths()) != 1 {
+		t.Errorf("Expected the exported snapshot to be restored, got %+v", snapshot.Snapshot)
+	}
+	restored := snapshot.Compare(diff.Response{
+		Status:  200,
+		Headers: map[string][]string{"Content-Type": {"application/json"}},
+		Body:    `{"id": 9, "name": "ann"}`,
+	})
+	if !restored.Equal() {
+		t.Error("Expected the restored snapshot to match")
+	}
+	text, _ := manager.ReadByEndpoint(ctx, textID)
+	if text.ResponseBody != "ok\n" {
+		t.Errorf("Expected the text body to round trip, got %q", text.ResponseBody)
+	}
+
+	os.WriteFile(filepath.Join(dir, "unknown.json"), []byte(`{"endpoint": "Missing", "status": 200}`), 0o644)
+	if _, err := manager.Import(ctx, collectionID, dir); err == nil || !strings.Contains(err.Error(), "Missing") {
+		t.Errorf("Expected an error naming the unknown endpoint, got %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+// Package snapshots stores golden responses that later runs of an endpoint
+// are compared against.
+package snapshots
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/maniac-en/req/internal/backend/crud"
+	"github.com/maniac-en/req/internal/backend/database"
+	"github.com/maniac-en/req/internal/diff"
+)
+
+type SnapshotsManager struct {
+	DB *database.Queries
+}
+
+type SnapshotEntity struct {
+	database.Snapshot
+}
+
+func (s SnapshotEntity) GetID() int64 {
+	return s.ID
+}
+
+func (s SnapshotEntity) GetCreatedAt() time.Time {
+	return crud.ParseTimestamp(s.CreatedAt)
+}
+
+func (s SnapshotEntity) GetUpdatedAt() time.Time {
+	return crud.ParseTimestamp(s.UpdatedAt)
+}
+
+// GetIgnoredPaths decodes the JSONPaths left out of comparisons
+func (s SnapshotEntity) GetIgnoredPaths() []string {
+	var paths []string
+	if err := json.Unmarshal([]byte(s.IgnoredPaths), &paths); err != nil {
+		return nil
+	}
+	return paths
+}
+
+// GetIgnoredHeaders decodes the header names left out of comparisons, on
+// top of diff.DefaultIgnoredHeaders
+func (s SnapshotEntity) GetIgnoredHeaders() []string {
+	var headers []string
+	if err := json.Unmarshal([]byte(s.IgnoredHeaders), &headers); err != nil {
+		return nil
+	}
+	return headers
+}
+
+// Response returns the pinned response
+func (s SnapshotEntity) Response() diff.Response {
+	headers := map[string][]string{}
+	if err := json.Unmarshal([]byte(s.ResponseHeaders), &headers); err != nil {
+		headers = nil
+	}
+	return diff.Response{
+		Status:  int(s.StatusCode),
+		Headers: headers,
+		Body:    s.ResponseBody,
+	}
+}
+
+// Compare diffs a response against the pinned one, leaving out the
+// ignored fields. An equal result means the response has not drifted.
+func (s SnapshotEntity) Compare(response diff.Response) diff.Result {
+	return diff.Compare(s.Response(), response, diff.Options{
+		IgnoreHeaders: append(slices.Clone(diff.DefaultIgnoredHeaders), s.GetIgnoredHeaders()...),
+		IgnorePaths:   s.GetIgnoredPaths(),
+	})
+}
@@ -0,0 +1,345 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/maniac-en/req/internal/backend/collections"
+	"github.com/maniac-en/req/internal/backend/endpoints"
+	"github.com/maniac-en/req/internal/backend/folders"
+	"github.com/maniac-en/req/internal/log"
+)
+
+// Database stores collections through the managers. Saving matches
+// collections, folders and endpoints by name and updates them in place, so
+// their history, snapshots and revisions are kept. What is no longer there
+// goes to the trash.
+type Database struct {
+	Collections *collections.CollectionsManager
+	Folders     *folders.FoldersManager
+	Endpoints   *endpoints.EndpointsManager
+}
+
+func NewDatabase(collectionsManager *collections.CollectionsManager, foldersManager *folders.FoldersManager, endpointsManager *endpoints.EndpointsManager) *Database {
+	return &Database{
+		Collections: collectionsManager,
+		Folders:     foldersManager,
+		Endpoints:   endpointsManager,
+	}
+}
+
+func (d *Database) Load(ctx context.Context) ([]Collection, error) {
+	all, err := d.Collections.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	loaded := make([]Collection, 0, len(all))
+	for _, entity := range all {
+		collection, err := d.loadCollection(ctx, entity)
+		if err != nil {
+			return nil, err
+		}
+		loaded = append(loaded, collection)
+	}
+	log.Debug("loaded collections from the database", "count", len(loaded))
+	return loaded, nil
+}
+
+// LoadCollection reads a single collection
+func (d *Database) LoadCollection(ctx context.Context, id int64) (Collection, error) {
+	entity, err := d.Collections.Read(ctx, id)
+	if err != nil {
+		return Collection{}, err
+	}
+	return d.loadCollection(ctx, entity)
+}
+
+func (d *Database) loadCollection(ctx context.Context, entity collections.CollectionEntity) (Collection, error) {
+	tree, err := d.Folders.Tree(ctx, entity.ID)
+	if err != nil {
+		return Collection{}, err
+	}
+	list, err := d.Endpoints.ListByCollection(ctx, entity.ID)
+	if err != nil {
+		return Collection{}, err
+	}
+
+	byFolder := map[int64][]Endpoint{}
+	for _, endpoint := range list {
+		folderID := endpoint.GetFolderID()
+		// endpoints whose folder is gone are shown at the top
+		if _, ok := tree.Get(folderID); !ok {
+			folderID = 0
+		}
+		byFolder[folderID] = append(byFolder[folderID], FromEntity(endpoint))
+	}
+
+	return Collection{
+		Name:               entity.Name,
+		Variables:          entity.GetVariables(),
+		PreRequestScript:   entity.PreRequestScript,
+		PostResponseScript: entity.PostResponseScript,
+		Items:              loadItems(tree, byFolder, 0),
+	}, nil
+}
+
+func loadItems(tree *folders.Tree, byFolder map[int64][]Endpoint, parentID int64) Items {
+	items := Items{Endpoints: byFolder[parentID]}
+	for _, folder := range tree.Children(parentID) {
+		items.Folders = append(items.Folders, Folder{
+			Name:      folder.Name,
+			Headers:   folder.GetHeaders(),
+			Variables: folder.GetVariables(),
+			Items:     loadItems(tree, byFolder, folder.ID),
+		})
+	}
+	return items
+}
+
+func (d *Database) Save(ctx context.Context, saved []Collection) error {
+	all, err := d.Collections.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	kept := map[int64]bool{}
+	for _, collection := range saved {
+		entity, err := d.SaveCollection(ctx, collection)
+		if err != nil {
+			return err
+		}
+		kept[entity.ID] = true
+	}
+
+	for _, entity := range all {
+		if kept[entity.ID] {
+			continue
+		}
+		if err := d.Collections.Delete(ctx, entity.ID); err != nil {
+			return err
+		}
+	}
+	log.Info("saved collections to the database", "count", len(saved))
+	return nil
+}
+
+// SaveCollection creates the collection, or makes the one with its name
+// match it, leaving other collections alone
+func (d *Database) SaveCollection(ctx context.Context, collection Collection) (collections.CollectionEntity, error) {
+	all, err := d.Collections.List(ctx)
+	if err != nil {
+		return collections.CollectionEntity{}, err
+	}
+	index := slices.IndexFunc(all, func(entity collections.CollectionEntity) bool {
+		return entity.Name == collection.Name
+	})
+
+	var entity collections.CollectionEntity
+	if index >= 0 {
+		entity = all[index]
+	} else if entity, err = d.Collections.Create(ctx, collection.Name); err != nil {
+		return collections.CollectionEntity{}, err
+	}
+
+	if !maps.Equal(entity.GetVariables(), collection.Variables) {
+		if entity, err = d.Collections.SetVariables(ctx, entity.ID, collection.Variables); err != nil {
+			return collections.CollectionEntity{}, err
+		}
+	}
+	if entity.PreRequestScript != collection.PreRequestScript || entity.PostResponseScript != collection.PostResponseScript {
+		if entity, err = d.Collections.SetScripts(ctx, entity.ID, collection.PreRequestScript, collection.PostResponseScript); err != nil {
+			return collections.CollectionEntity{}, err
+		}
+	}
+	if err := d.saveItems(ctx, entity.ID, collection.Items); err != nil {
+		return collections.CollectionEntity{}, err
+	}
+	return entity, nil
+}
+
+// placed is an endpoint to save with the folder it goes in
+type placed struct {
+	folderID int64
+	endpoint Endpoint
+}
+
+// saveItems makes the folders and endpoints of a collection match items
+func (d *Database) saveItems(ctx context.Context, collectionID int64, items Items) error {
+	tree, err := d.Folders.Tree(ctx, collectionID)
+	if err != nil {
+		return err
+	}
+
+	// folders first, so every endpoint knows the folder it goes in
+	keptFolders := map[int64]bool{}
+	var wanted []placed
+	var walk func(parentID int64, items Items) error
+	walk = func(parentID int64, items Items) error {
+		for _, endpoint := range items.Endpoints {
+			wanted = append(wanted, placed{folderID: parentID, endpoint: endpoint})
+		}
+		for _, folder := range items.Folders {
+			entity, ok := findFolder(tree.Children(parentID), folder.Name)
+			if !ok {
+				if entity, err = d.Folders.CreateFolder(ctx, collectionID, parentID, folder.Name); err != nil {
+					return err
+				}
+			}
+			keptFolders[entity.ID] = true
+
+			if entity.Name != folder.Name {
+				if entity, err = d.Folders.Update(ctx, entity.ID, folder.Name); err != nil {
+					return err
+				}
+			}
+
+			if !maps.Equal(entity.GetHeaders(), folder.Headers) {
+				if _, err := d.Folders.SetHeaders(ctx, entity.ID, folder.Headers); err != nil {
+					return err
+				}
+			}
+			if !maps.Equal(entity.GetVariables(), folder.Variables) {
+				if _, err := d.Folders.SetVariables(ctx, entity.ID, folder.Variables); err != nil {
+					return err
+				}
+			}
+			if err := walk(entity.ID, folder.Items); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(0, items); err != nil {
+		return err
+	}
+
+	existing, err := d.Endpoints.ListByCollection(ctx, collectionID)
+	if err != nil {
+		return err
+	}
+	matches := matchEndpoints(existing, wanted)
+
+	order := make([]endpoints.EndpointEntity, len(wanted))
+	used := map[int64]bool{}
+	for i, want := range wanted {
+		entity, ok := matches[i]
+		if !ok {
+			created, err := d.Endpoints.CreateEndpoint(ctx, endpointData(collectionID, want))
+			if err != nil {
+				return err
+			}
+			order[i] = created
+			continue
+		}
+		used[entity.ID] = true
+
+		if entity.GetFolderID() != want.folderID {
+			if entity, err = d.Endpoints.MoveToFolder(ctx, entity.ID, want.folderID); err != nil {
+				return err
+			}
+		}
+		if !FromEntity(entity).equal(want.endpoint) {
+			if entity, err = d.Endpoints.UpdateEndpoint(ctx, entity.ID, endpointData(collectionID, want)); err != nil {
+				return err
+			}
+		}
+		order[i] = entity
+	}
+
+	if err := d.Endpoints.Reorder(ctx, order); err != nil {
+		return err
+	}
+
+	// endpoints left in folders that are gone go to the trash with them
+	for _, entity := range existing {
+		_, inFolder := tree.Get(entity.GetFolderID())
+		if used[entity.ID] || (inFolder && !keptFolders[entity.GetFolderID()]) {
+			continue
+		}
+		if err := d.Endpoints.Delete(ctx, entity.ID); err != nil {
+			return err
+		}
+	}
+	return d.deleteFolders(ctx, tree, keptFolders, 0)
+}
+
+// deleteFolders trashes the folders below parentID that were not kept
+func (d *Database) deleteFolders(ctx context.Context, tree *folders.Tree, kept map[int64]bool, parentID int64) error {
+	for _, folder := range tree.Children(parentID) {
+		if !kept[folder.ID] {
+			if err := d.Folders.Delete(ctx, folder.ID); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := d.deleteFolders(ctx, tree, kept, folder.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matchEndpoints pairs wanted endpoints with existing ones: first by name in
+// the same folder, then by name anywhere in the collection, so an endpoint
+// moved to another folder keeps its history
+func matchEndpoints(existing []endpoints.EndpointEntity, wanted []placed) map[int]endpoints.EndpointEntity {
+	matches := map[int]endpoints.EndpointEntity{}
+	taken := make([]bool, len(existing))
+	for _, sameFolder := range []bool{true, false} {
+		for i, want := range wanted {
+			if _, ok := matches[i]; ok {
+				continue
+			}
+			for j, entity := range existing {
+				if taken[j] || entity.Name != want.endpoint.Name {
+					continue
+				}
+				if sameFolder && entity.GetFolderID() != want.folderID {
+					continue
+				}
+				matches[i] = entity
+				taken[j] = true
+				break
+			}
+		}
+	}
+	return matches
+}
+
+func findFolder(children []folders.FolderEntity, name string) (folders.FolderEntity, bool) {
+	for _, folder := range children {
+		// sibling folder names differ in more than case
+		if strings.EqualFold(folder.Name, name) {
+			return folder, true
+		}
+	}
+	return folders.FolderEntity{}, false
+}
+
+func endpointData(collectionID int64, want placed) endpoints.EndpointData {
+	endpoint := want.endpoint
+	headers := "{}"
+	if len(endpoint.Headers) > 0 {
+		encoded, _ := json.Marshal(endpoint.Headers)
+		headers = string(encoded)
+	}
+	return endpoints.EndpointData{
+		CollectionID:       collectionID,
+		FolderID:           want.folderID,
+		Name:               endpoint.Name,
+		Method:             endpoint.Method,
+		URL:                endpoint.URL,
+		Headers:            headers,
+		QueryParams:        endpoint.QueryParams,
+		RequestBody:        endpoint.Body,
+		Protocol:           endpoint.Protocol,
+		ProtoFiles:         endpoint.ProtoFiles,
+		Extractions:        endpoint.Extractions,
+		PreRequestScript:   endpoint.PreRequestScript,
+		PostResponseScript: endpoint.PostResponseScript,
+	}
+}
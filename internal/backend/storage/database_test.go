@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/maniac-en/req/internal/backend/collections"
+	"github.com/maniac-en/req/internal/backend/endpoints"
+	"github.com/maniac-en/req/internal/backend/folders"
+	"github.com/maniac-en/req/internal/backend/testutils"
+)
+
+func TestDatabase(t *testing.T) {
+	db := testutils.SetupTestDB(t, "collections", "endpoints", "folders", "endpoint_revisions")
+	store := NewDatabase(collections.NewCollectionsManager(db), folders.NewFoldersManager(db), endpoints.NewEndpointsManager(db))
+	ctx := context.Background()
+
+	if err := store.Save(ctx, sampleCollections()); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	t.Run("Round trip", func(t *testing.T) {
+		loaded, err := store.Load(ctx)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if len(loaded) != 1 {
+			t.Fatalf("Expected 1 collection, got %d", len(loaded))
+		}
+		want := sampleCollections()[0]
+		got := loaded[0]
+		if got.Name != want.Name || !reflect.DeepEqual(got.Variables, want.Variables) {
+			t.Errorf("Expected collection %s with its variables, got %s %v", want.Name, got.Name, got.Variables)
+		}
+		if len(got.Endpoints) != 2 || !got.Endpoints[0].equal(want.Endpoints[0]) || !got.Endpoints[1].equal(want.Endpoints[1]) {
+			t.Errorf("Expected the endpoints in order, got %+v", got.Endpoints)
+		}
+		if len(got.Folders) != 1 || got.Folders[0].Headers["Authorization"] != "Bearer {{token}}" || len(got.Folders[0].Endpoints) != 1 {
+			t.Errorf("Expected the admin folder with its header and endpoint, got %+v", got.Folders)
+		}
+	})
+
+	t.Run("Save updates in place", func(t *testing.T) {
+		before, _ := store.Endpoints.ListByCollection(ctx, 1)
+
+		collections := sampleCollections()
+		collections[0].Endpoints[0].URL = "{{base}}/people"
+		// swap the order and move the admin endpoint to the top
+		collections[0].Endpoints = append([]Endpoint{collections[0].Folders[0].Endpoints[0]}, collections[0].Endpoints[1], collections[0].Endpoints[0])
+		collections[0].Folders = nil
+		if err := store.Save(ctx, collections); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+
+		after, _ := store.Endpoints.ListByCollection(ctx, 1)
+		if len(after) != 3 {
+			t.Fatalf("Expected 3 endpoints, got %d", len(after))
+		}
+		ids := map[string]int64{}
+		for _, endpoint := range before {
+			ids[endpoint.Name] = endpoint.ID
+		}
+		for i, name := range []string{"Ban user: by id", "Create user", "List users"} {
+			if after[i].Name != name || after[i].ID != ids[name] {
+				t.Errorf("Expected %s (id %d) at %d, got %s (id %d)", name, ids[name], i, after[i].Name, after[i].ID)
+			}
+		}
+		if after[0].GetFolderID() != 0 {
+			t.Error("Expected the endpoint to move out of the removed folder")
+		}
+
+		revisions, _ := store.Endpoints.ListRevisions(ctx, ids["List users"])
+		if len(revisions) != 1 {
+			t.Errorf("Expected the changed endpoint to keep a revision, got %d", len(revisions))
+		}
+		revisions, _ = store.Endpoints.ListRevisions(ctx, ids["Create user"])
+		if len(revisions) != 0 {
+			t.Errorf("Expected an unchanged endpoint to be left alone, got %d revisions", len(revisions))
+		}
+
+		tree, _ := store.Folders.Tree(ctx, 1)
+		if len(tree.Children(0)) != 0 {
+			t.Error("Expected the removed folder to go to the trash")
+		}
+	})
+
+	t.Run("Removed collections go to the trash", func(t *testing.T) {
+		if err := store.Save(ctx, nil); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+		loaded, _ := store.Load(ctx)
+		if len(loaded) != 0 {
+			t.Errorf("Expected no collections, got %d", len(loaded))
+		}
+		if _, err := store.Collections.Restore(ctx, 1); err != nil {
+			t.Errorf("Expected the collection to be restorable, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,570 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/maniac-en/req/internal/backend/endpoints"
+	"github.com/maniac-en/req/internal/log"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	collectionFile = "collection.yaml"
+	folderFile     = "folder.yaml"
+)
+
+// Directory keeps each collection as a directory of YAML files below Root,
+// one file per endpoint and a subdirectory per folder:
+//
+//	api/
+//	  Users/
+//	    collection.yaml   name, variables, scripts and endpoint order
+//	    List users.yaml
+//	    admin/
+//	      folder.yaml     headers, variables and endpoint order
+//	      Ban user.yaml
+//
+// Only directories with a collection.yaml are collections, and only YAML
+// files with an url and no keys req doesn't write are endpoints, so the
+// collections can live next to other YAML such as CI configs. Saving writes
+// only the files whose content changed and removes the files of whatever is
+// gone. Files req can't read as its own are never replaced or removed, so
+// the directory diffs cleanly in version control.
+//
+// A Directory remembers the req files it last loaded or saved. Save refuses
+// to run when any of them was edited, added or removed since, for example
+// by a git pull, instead of overwriting or deleting the change.
+type Directory struct {
+	Root string
+
+	// files hashes the content of the req files below Root as of the last
+	// Load or Save, nil before either
+	files map[string][sha256.Size]byte
+}
+
+// ErrChanged is returned by Save when req files changed outside req since
+// they were last loaded or saved
+var ErrChanged = errors.New("changed outside req since it was loaded, not saving over it")
+
+func NewDirectory(root string) *Directory {
+	return &Directory{Root: root}
+}
+
+// collectionMeta is the content of collection.yaml
+type collectionMeta struct {
+	Name               string            `yaml:"name"`
+	Variables          map[string]string `yaml:"variables,omitempty"`
+	PreRequestScript   string            `yaml:"pre_request_script,omitempty"`
+	PostResponseScript string            `yaml:"post_response_script,omitempty"`
+	// Order lists endpoint names when they are not in file name order
+	Order []string `yaml:"order,omitempty"`
+}
+
+// folderMeta is the content of folder.yaml, which is left out when a folder
+// has endpoints and nothing to set
+type folderMeta struct {
+	// Name is set when the folder name can't be used as the directory name
+	Name      string            `yaml:"name,omitempty"`
+	Headers   map[string]string `yaml:"headers,omitempty"`
+	Variables map[string]string `yaml:"variables,omitempty"`
+	Order     []string          `yaml:"order,omitempty"`
+}
+
+// Load reads the collections below Root. A missing Root holds no
+// collections.
+func (d *Directory) Load(ctx context.Context) ([]Collection, error) {
+	entries, err := os.ReadDir(d.Root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var loaded []Collection
+	for _, entry := range entries {
+		if !entry.IsDir() || hidden(entry.Name()) {
+			continue
+		}
+		dir := filepath.Join(d.Root, entry.Name())
+		meta := collectionMeta{Name: entry.Name()}
+		if err := readStrict(filepath.Join(dir, collectionFile), &meta); err != nil {
+			if !os.IsNotExist(err) {
+				log.Warn("skipping directory with an unknown collection.yaml", "dir", dir, "error", err)
+			}
+			continue
+		}
+		items, err := readItems(dir, meta.Order)
+		if err != nil {
+			return nil, err
+		}
+		loaded = append(loaded, Collection{
+			Name:               meta.Name,
+			Variables:          meta.Variables,
+			PreRequestScript:   meta.PreRequestScript,
+			PostResponseScript: meta.PostResponseScript,
+			Items:              items,
+		})
+	}
+	if d.files, err = d.fingerprint(); err != nil {
+		return nil, err
+	}
+	log.Debug("loaded collections from directory", "root", d.Root, "count", len(loaded))
+	return loaded, nil
+}
+
+func readItems(dir string, order []string) (Items, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return Items{}, err
+	}
+
+	var items Items
+	for _, entry := range entries {
+		name := entry.Name()
+		path := filepath.Join(dir, name)
+		switch {
+		case hidden(name):
+		case entry.IsDir():
+			meta := folderMeta{Name: name}
+			err := readStrict(filepath.Join(path, folderFile), &meta)
+			if err != nil && !os.IsNotExist(err) {
+				log.Warn("ignoring unknown folder.yaml", "path", filepath.Join(path, folderFile), "error", err)
+			}
+			nested, nestedErr := readItems(path, meta.Order)
+			if nestedErr != nil {
+				return Items{}, nestedErr
+			}
+			// a directory without a folder.yaml or requests is not a folder
+			if err != nil && len(nested.Endpoints) == 0 && len(nested.Folders) == 0 {
+				continue
+			}
+			items.Folders = append(items.Folders, Folder{
+				Name:      meta.Name,
+				Headers:   meta.Headers,
+				Variables: meta.Variables,
+				Items:     nested,
+			})
+		case isYAML(name) && name != collectionFile && name != folderFile:
+			endpoint, ok, err := readEndpoint(path)
+			if err != nil {
+				return Items{}, err
+			}
+			if !ok {
+				log.Debug("skipping YAML file that is not a request", "path", path)
+				continue
+			}
+			items.Endpoints = append(items.Endpoints, endpoint)
+		}
+	}
+	slices.SortStableFunc(items.Folders, func(a, b Folder) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	items.Endpoints = applyOrder(items.Endpoints, order)
+	return items, nil
+}
+
+// applyOrder puts the endpoints named in order first, in that order, and
+// the others after them as they were
+func applyOrder(list []Endpoint, order []string) []Endpoint {
+	if len(order) == 0 {
+		return list
+	}
+	sorted := make([]Endpoint, 0, len(list))
+	taken := make([]bool, len(list))
+	for _, name := range order {
+		for i, endpoint := range list {
+			if !taken[i] && endpoint.Name == name {
+				sorted = append(sorted, endpoint)
+				taken[i] = true
+				break
+			}
+		}
+	}
+	for i, endpoint := range list {
+		if !taken[i] {
+			sorted = append(sorted, endpoint)
+		}
+	}
+	return sorted
+}
+
+// Save writes the collections below Root, creating it if needed. It fails
+// with ErrChanged, writing nothing, when req files changed since the last
+// Load or Save.
+func (d *Directory) Save(ctx context.Context, collections []Collection) error {
+	if d.files != nil {
+		current, err := d.fingerprint()
+		if err != nil {
+			return err
+		}
+		if path, ok := changedFile(d.files, current); ok {
+			log.Warn("collection file changed outside req", "path", path)
+			return fmt.Errorf("%s %w", path, ErrChanged)
+		}
+	}
+	if err := d.save(collections); err != nil {
+		return err
+	}
+	var err error
+	d.files, err = d.fingerprint()
+	return err
+}
+
+func (d *Directory) save(collections []Collection) error {
+	w := &dirWriter{written: map[string]bool{}}
+	if err := os.MkdirAll(d.Root, 0o755); err != nil {
+		return err
+	}
+	w.written[d.Root] = true
+
+	entries, err := os.ReadDir(d.Root)
+	if err != nil {
+		return err
+	}
+	// directories that are not collections keep their names
+	taken := map[string]bool{}
+	for _, entry := range entries {
+		if entry.IsDir() && !ours(filepath.Join(d.Root, entry.Name(), collectionFile)) {
+			taken[strings.ToLower(entry.Name())] = true
+		}
+	}
+	for _, collection := range collections {
+		dir := filepath.Join(d.Root, uniqueName(fileName(collection.Name), taken))
+		files := w.itemFiles(dir, collection.Items)
+		meta := collectionMeta{
+			Name:               collection.Name,
+			Variables:          collection.Variables,
+			PreRequestScript:   collection.PreRequestScript,
+			PostResponseScript: collection.PostResponseScript,
+			Order:              orderOf(collection.Endpoints, files),
+		}
+		if err := w.writeYAML(filepath.Join(dir, collectionFile), meta); err != nil {
+			return err
+		}
+		if err := w.writeItems(dir, collection.Items, files); err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range entries {
+		dir := filepath.Join(d.Root, entry.Name())
+		if entry.IsDir() && !hidden(entry.Name()) && ours(filepath.Join(dir, collectionFile)) {
+			if err := w.prune(dir); err != nil {
+				return err
+			}
+		}
+	}
+	log.Info("saved collections to directory", "root", d.Root, "count", len(collections), "files_changed", w.changed)
+	return nil
+}
+
+// fingerprint hashes the req files in the collections below Root
+func (d *Directory) fingerprint() (map[string][sha256.Size]byte, error) {
+	files := map[string][sha256.Size]byte{}
+	var walk func(dir string) error
+	walk = func(dir string) error {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			path := filepath.Join(dir, entry.Name())
+			switch {
+			case hidden(entry.Name()):
+			case entry.IsDir():
+				if err := walk(path); err != nil {
+					return err
+				}
+			case isYAML(entry.Name()) && ours(path):
+				data, err := os.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				files[path] = sha256.Sum256(data)
+			}
+		}
+		return nil
+	}
+
+	entries, err := os.ReadDir(d.Root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return files, nil
+		}
+		return nil, err
+	}
+	for _, entry := range entries {
+		dir := filepath.Join(d.Root, entry.Name())
+		if entry.IsDir() && !hidden(entry.Name()) && ours(filepath.Join(dir, collectionFile)) {
+			if err := walk(dir); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return files, nil
+}
+
+// changedFile returns the first path, in name order, that was added,
+// removed or edited between two fingerprints
+func changedFile(before, after map[string][sha256.Size]byte) (string, bool) {
+	paths := slices.Sorted(maps.Keys(before))
+	for path := range after {
+		if _, ok := before[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	slices.Sort(paths)
+	for _, path := range paths {
+		previous, inBefore := before[path]
+		current, inAfter := after[path]
+		if !inBefore || !inAfter || previous != current {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// dirWriter remembers the files and directories a save wrote, so everything
+// else it manages can be removed afterwards
+type dirWriter struct {
+	written map[string]bool
+	changed int
+}
+
+// itemFiles picks the file names of the endpoints in items going in dir.
+// Folder directories, the reserved files and YAML files that are not req's
+// take their names first.
+func (w *dirWriter) itemFiles(dir string, items Items) []string {
+	taken := map[string]bool{collectionFile: true, folderFile: true}
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		if !entry.IsDir() && isYAML(entry.Name()) && !ours(filepath.Join(dir, entry.Name())) {
+			taken[strings.ToLower(entry.Name())] = true
+		}
+	}
+	for _, folder := range items.Folders {
+		taken[strings.ToLower(fileName(folder.Name))] = true
+	}
+	files := make([]string, len(items.Endpoints))
+	for i, endpoint := range items.Endpoints {
+		files[i] = uniqueName(fileName(endpoint.Name)+".yaml", taken)
+	}
+	return files
+}
+
+func (w *dirWriter) writeItems(dir string, items Items, files []string) error {
+	w.written[dir] = true
+	for i, endpoint := range items.Endpoints {
+		if endpoint.Protocol == endpoints.ProtocolHTTP {
+			endpoint.Protocol = ""
+		}
+		if err := w.writeYAML(filepath.Join(dir, files[i]), endpoint); err != nil {
+			return err
+		}
+	}
+
+	taken := map[string]bool{}
+	for _, folder := range items.Folders {
+		name := uniqueName(fileName(folder.Name), taken)
+		path := filepath.Join(dir, name)
+		nestedFiles := w.itemFiles(path, folder.Items)
+		meta := folderMeta{
+			Headers:   folder.Headers,
+			Variables: folder.Variables,
+			Order:     orderOf(folder.Endpoints, nestedFiles),
+		}
+		if name != folder.Name {
+			meta.Name = folder.Name
+		}
+		// an empty folder keeps its file so version control keeps the folder
+		empty := len(folder.Endpoints) == 0 && len(folder.Folders) == 0
+		if empty || meta.Name != "" || len(meta.Headers) > 0 || len(meta.Variables) > 0 || len(meta.Order) > 0 {
+			if err := w.writeYAML(filepath.Join(path, folderFile), meta); err != nil {
+				return err
+			}
+		}
+		if err := w.writeItems(path, folder.Items, nestedFiles); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeYAML writes v to path unless the file already holds the same
+// content. A file req can't read as its own is left alone.
+func (w *dirWriter) writeYAML(path string, v any) error {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	w.written[path] = true
+	if current, err := os.ReadFile(path); err == nil {
+		if bytes.Equal(current, buf.Bytes()) {
+			return nil
+		}
+		if !ours(path) {
+			return fmt.Errorf("%s is not a req file, not replacing it", path)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	w.changed++
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// prune removes the req files below dir that the save did not write, and
+// the directories it leaves empty
+func (w *dirWriter) prune(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		switch {
+		case hidden(entry.Name()):
+		case entry.IsDir():
+			if err := w.prune(path); err != nil {
+				return err
+			}
+		case isYAML(entry.Name()) && !w.written[path] && ours(path):
+			log.Debug("removing request file", "path", path)
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+			w.changed++
+		}
+	}
+
+	if w.written[dir] {
+		return nil
+	}
+	if remaining, err := os.ReadDir(dir); err == nil && len(remaining) == 0 {
+		return os.Remove(dir)
+	}
+	return nil
+}
+
+// orderOf returns the endpoint names, or nothing when loading the files in
+// name order gives the same order
+func orderOf(list []Endpoint, files []string) []string {
+	if slices.IsSorted(files) {
+		return nil
+	}
+	names := make([]string, len(list))
+	for i, endpoint := range list {
+		names[i] = endpoint.Name
+	}
+	return names
+}
+
+// readStrict reads a YAML file, failing on keys that v doesn't have
+func readStrict(path string, v any) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(v); err != nil && err != io.EOF {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// readEndpoint reads an endpoint file. It is not one, and ok is false, when
+// it has keys req doesn't write or no url. Malformed YAML is an error.
+func readEndpoint(path string) (endpoint Endpoint, ok bool, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Endpoint{}, false, err
+	}
+	var document any
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return Endpoint{}, false, fmt.Errorf("%s: %w", path, err)
+	}
+	keys, _ := document.(map[string]any)
+	if _, ok := keys["url"]; !ok {
+		return Endpoint{}, false, nil
+	}
+	endpoint = Endpoint{Name: strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))}
+	if err := readStrict(path, &endpoint); err != nil {
+		return Endpoint{}, false, nil
+	}
+	if endpoint.Method == "" {
+		endpoint.Method = "GET"
+	}
+	return endpoint, true, nil
+}
+
+// ours reports whether the file at path is one req reads, and so may
+// replace or remove
+func ours(path string) bool {
+	switch filepath.Base(path) {
+	case collectionFile:
+		return readStrict(path, &collectionMeta{}) == nil
+	case folderFile:
+		return readStrict(path, &folderMeta{}) == nil
+	}
+	_, ok, err := readEndpoint(path)
+	return ok && err == nil
+}
+
+// fileName turns a name into one that is safe as a file name on every
+// platform
+func fileName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '-'
+		}
+		return r
+	}, name)
+	// a leading dot would hide the file
+	name = strings.Trim(name, " .")
+	if name == "" {
+		return "untitled"
+	}
+	return name
+}
+
+// uniqueName numbers names that are already taken, ignoring case since
+// some file systems do
+func uniqueName(name string, taken map[string]bool) string {
+	ext := filepath.Ext(name)
+	if ext != ".yaml" {
+		ext = ""
+	}
+	base := strings.TrimSuffix(name, ext)
+	for i := 2; taken[strings.ToLower(name)]; i++ {
+		name = fmt.Sprintf("%s %d%s", base, i, ext)
+	}
+	taken[strings.ToLower(name)] = true
+	return name
+}
+
+func hidden(name string) bool {
+	return strings.HasPrefix(name, ".")
+}
+
+func isYAML(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".yaml" || ext == ".yml"
+}
@@ -0,0 +1,297 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/maniac-en/req/internal/backend/endpoints"
+)
+
+func sampleCollections() []Collection {
+	return []Collection{{
+		Name:      "Users API",
+		Variables: map[string]string{"base": "https://api.example.com"},
+		Items: Items{
+			Endpoints: []Endpoint{
+				{Name: "List users", Method: "GET", URL: "{{base}}/users", QueryParams: map[string]string{"page": "1"}},
+				{
+					Name:        "Create user",
+					Method:      "POST",
+					URL:         "{{base}}/users",
+					Headers:     map[string]string{"Content-Type": "application/json"},
+					Body:        "{\n  \"name\": \"Ada\"\n}\n",
+					Extractions: []endpoints.Extraction{{Variable: "user_id", Source: endpoints.SourceJSON, Expression: "$.id", Scope: endpoints.ScopeCollection}},
+				},
+			},
+			Folders: []Folder{{
+				Name:    "admin",
+				Headers: map[string]string{"Authorization": "Bearer {{token}}"},
+				Items: Items{Endpoints: []Endpoint{
+					{Name: "Ban user: by id", Method: "DELETE", URL: "{{base}}/users/1"},
+				}},
+			}},
+		},
+	}}
+}
+
+func TestDirectory(t *testing.T) {
+	ctx := context.Background()
+	root := filepath.Join(t.TempDir(), "api")
+	dir := NewDirectory(root)
+
+	t.Run("Missing directory is empty", func(t *testing.T) {
+		loaded, err := dir.Load(ctx)
+		if err != nil || len(loaded) != 0 {
+			t.Errorf("Expected no collections, got %v, %v", loaded, err)
+		}
+	})
+
+	t.Run("Round trip", func(t *testing.T) {
+		if err := dir.Save(ctx, sampleCollections()); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+		for _, file := range []string{
+			"Users API/collection.yaml",
+			"Users API/List users.yaml",
+			"Users API/Create user.yaml",
+			"Users API/admin/folder.yaml",
+			"Users API/admin/Ban user- by id.yaml",
+		} {
+			if _, err := os.Stat(filepath.Join(root, file)); err != nil {
+				t.Errorf("Expected %s to be written, got %v", file, err)
+			}
+		}
+
+		loaded, err := dir.Load(ctx)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if !reflect.DeepEqual(loaded, sampleCollections()) {
+			t.Errorf("Expected the saved collections back, got %+v", loaded)
+		}
+	})
+
+	t.Run("Body is a readable block", func(t *testing.T) {
+		data, err := os.ReadFile(filepath.Join(root, "Users API", "Create user.yaml"))
+		if err != nil {
+			t.Fatalf("ReadFile failed: %v", err)
+		}
+		if !strings.Contains(string(data), "body: |\n  {\n    \"name\": \"Ada\"\n  }\n") {
+			t.Errorf("Expected the body as a literal block, got:\n%s", data)
+		}
+	})
+
+	t.Run("Unchanged save writes nothing", func(t *testing.T) {
+		path := filepath.Join(root, "Users API", "List users.yaml")
+		if err := os.WriteFile(filepath.Join(root, "Users API", "README.md"), []byte("notes"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		before, _ := os.Stat(path)
+		w := &dirWriter{written: map[string]bool{}}
+		if err := w.writeYAML(path, sampleCollections()[0].Endpoints[0]); err != nil {
+			t.Fatalf("writeYAML failed: %v", err)
+		}
+		after, _ := os.Stat(path)
+		if w.changed != 0 || !after.ModTime().Equal(before.ModTime()) {
+			t.Error("Expected an unchanged file to be left alone")
+		}
+	})
+
+	t.Run("Removed items lose their files", func(t *testing.T) {
+		collections := sampleCollections()
+		collections[0].Folders = nil
+		collections[0].Endpoints = collections[0].Endpoints[:1]
+		if err := dir.Save(ctx, collections); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(root, "Users API", "admin")); !os.IsNotExist(err) {
+			t.Errorf("Expected the folder directory to be removed, got %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(root, "Users API", "Create user.yaml")); !os.IsNotExist(err) {
+			t.Errorf("Expected the endpoint file to be removed, got %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(root, "Users API", "README.md")); err != nil {
+			t.Errorf("Expected other files to be kept, got %v", err)
+		}
+	})
+
+	t.Run("Order is kept", func(t *testing.T) {
+		collections := sampleCollections()
+		if err := dir.Save(ctx, collections); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+		data, _ := os.ReadFile(filepath.Join(root, "Users API", "collection.yaml"))
+		if !strings.Contains(string(data), "order:") {
+			t.Errorf("Expected an order for endpoints out of file name order, got:\n%s", data)
+		}
+		loaded, _ := dir.Load(ctx)
+		if loaded[0].Endpoints[0].Name != "List users" {
+			t.Errorf("Expected List users first, got %s", loaded[0].Endpoints[0].Name)
+		}
+	})
+
+	t.Run("Hand written file", func(t *testing.T) {
+		if err := os.WriteFile(filepath.Join(root, "Users API", "health.yml"), []byte("url: https://api.example.com/health\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := dir.Load(ctx)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		last := loaded[0].Endpoints[len(loaded[0].Endpoints)-1]
+		if last.Name != "health" || last.Method != "GET" {
+			t.Errorf("Expected a GET named after the file, got %+v", last)
+		}
+	})
+
+	t.Run("Other YAML files are left alone", func(t *testing.T) {
+		other := map[string]string{
+			filepath.Join(root, "Users API", "ci.yaml"):       "jobs:\n  build:\n    url: https://ci.example.com\n",
+			filepath.Join(root, "Users API", "list.yaml"):     "- one\n- two\n",
+			filepath.Join(root, "deploy", "service.yaml"):     "apiVersion: v1\nkind: Service\n",
+			filepath.Join(root, "deploy", "Nginx.yaml"):       "url: https://example.com\nreplicas: 2\n",
+			filepath.Join(root, "Users API", "docs", "a.yml"): "title: notes\n",
+		}
+		for path, content := range other {
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		loaded, err := dir.Load(ctx)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if len(loaded) != 1 {
+			t.Fatalf("Expected only the directory with a collection.yaml to be loaded, got %d collections", len(loaded))
+		}
+		for _, endpoint := range loaded[0].Endpoints {
+			if endpoint.Name == "ci" || endpoint.Name == "list" {
+				t.Errorf("Expected %s.yaml not to be read as an endpoint", endpoint.Name)
+			}
+		}
+		for _, folder := range loaded[0].Folders {
+			if folder.Name == "docs" {
+				t.Error("Expected a directory without requests not to be read as a folder")
+			}
+		}
+
+		// a collection named like an existing directory goes next to it
+		collections := append(sampleCollections(), Collection{Name: "deploy", Items: Items{Endpoints: []Endpoint{{Name: "Nginx", Method: "GET", URL: "https://example.com"}}}})
+		collections[0].Endpoints = nil
+		if err := dir.Save(ctx, collections); err != nil {
+			t.Fatalf("Save failed: %v", err)
+		}
+		for path, content := range other {
+			if data, err := os.ReadFile(path); err != nil || string(data) != content {
+				t.Errorf("Expected %s to be kept as it was, got %q, %v", path, data, err)
+			}
+		}
+		if _, err := os.Stat(filepath.Join(root, "deploy 2", "Nginx.yaml")); err != nil {
+			t.Errorf("Expected the collection in its own directory, got %v", err)
+		}
+
+		w := &dirWriter{written: map[string]bool{}}
+		if err := w.writeYAML(filepath.Join(root, "deploy", "service.yaml"), Endpoint{Name: "service"}); err == nil {
+			t.Error("Expected a file that is not req's to be refused")
+		}
+	})
+
+	t.Run("Malformed file", func(t *testing.T) {
+		if err := os.WriteFile(filepath.Join(root, "Users API", "broken.yaml"), []byte("url: [\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := dir.Load(ctx); err == nil || !strings.Contains(err.Error(), "broken.yaml") {
+			t.Errorf("Expected an error naming the file, got %v", err)
+		}
+	})
+}
+
+func TestDirectoryExternalChanges(t *testing.T) {
+	ctx := context.Background()
+	root := filepath.Join(t.TempDir(), "api")
+	dir := NewDirectory(root)
+	if err := dir.Save(ctx, sampleCollections()); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	list := filepath.Join(root, "Users API", "List users.yaml")
+	pulled := filepath.Join(root, "Users API", "Pulled.yaml")
+
+	changes := []struct {
+		name   string
+		change func() error
+	}{
+		{"Edited", func() error {
+			return os.WriteFile(list, []byte("name: List users\nmethod: GET\nurl: https://example.com/users\n"), 0o644)
+		}},
+		{"Added", func() error {
+			return os.WriteFile(pulled, []byte("url: https://example.com/pulled\n"), 0o644)
+		}},
+		{"Removed", func() error {
+			return os.Remove(filepath.Join(root, "Users API", "admin", "Ban user- by id.yaml"))
+		}},
+	}
+	for _, test := range changes {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := dir.Load(ctx); err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+			if err := test.change(); err != nil {
+				t.Fatal(err)
+			}
+			before, _ := os.ReadFile(list)
+			if err := dir.Save(ctx, sampleCollections()); !errors.Is(err, ErrChanged) {
+				t.Fatalf("Expected ErrChanged, got %v", err)
+			}
+			if after, _ := os.ReadFile(list); string(after) != string(before) {
+				t.Error("Expected nothing to be written")
+			}
+			if _, err := os.Stat(pulled); test.name == "Added" && err != nil {
+				t.Errorf("Expected the added file to be kept, got %v", err)
+			}
+
+			// once loaded, the change is req's to overwrite
+			if _, err := dir.Load(ctx); err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+			if err := dir.Save(ctx, sampleCollections()); err != nil {
+				t.Errorf("Expected a save after loading to succeed, got %v", err)
+			}
+		})
+	}
+
+	// other files may change freely
+	if err := os.WriteFile(filepath.Join(root, "Users API", "README.md"), []byte("notes\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := dir.Save(ctx, sampleCollections()); err != nil {
+		t.Errorf("Expected changes to other files not to count, got %v", err)
+	}
+}
+
+func TestFileName(t *testing.T) {
+	tests := map[string]string{
+		"GET /users/{id}": "GET -users-{id}",
+		".hidden":         "hidden",
+		"  ":              "untitled",
+		"a:b?":            "a-b-",
+	}
+	for name, want := range tests {
+		if got := fileName(name); got != want {
+			t.Errorf("Expected %q for %q, got %q", want, name, got)
+		}
+	}
+
+	taken := map[string]bool{}
+	if first, second := uniqueName("List.yaml", taken), uniqueName("list.yaml", taken); first != "List.yaml" || second != "list 2.yaml" {
+		t.Errorf("Expected names differing in case to be numbered, got %q and %q", first, second)
+	}
+}
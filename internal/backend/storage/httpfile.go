@@ -0,0 +1,325 @@
+package storage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/maniac-en/req/internal/backend/endpoints"
+	"github.com/maniac-en/req/internal/backend/folders"
+)
+
+// HTTPFileExtensions are the extensions of files in the .http format
+var HTTPFileExtensions = []string{".http", ".rest"}
+
+var httpMethods = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS", "TRACE", "CONNECT"}
+
+// IsHTTPFile reports whether path has the extension of a .http file
+func IsHTTPFile(path string) bool {
+	return slices.Contains(HTTPFileExtensions, strings.ToLower(filepath.Ext(path)))
+}
+
+// HTTPFileName is the collection name of a .http file: its file name
+// without the extension
+func HTTPFileName(path string) string {
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
+
+// ReadHTTPFile parses a file in the .http format of the JetBrains HTTP
+// client and the VS Code REST Client into a collection. Requests are
+// separated by lines starting with ###, whose text names the request unless
+// a "# @name" comment does. "@name = value" lines become collection
+// variables, and other lines starting with # or // are comments. Response
+// handlers and references are ignored.
+//
+// A "# @folder path" comment, written by WriteHTTPFile, puts the request in
+// a folder.
+func ReadHTTPFile(r io.Reader, name string) (Collection, error) {
+	collection := Collection{Name: name}
+	folderItems := map[string]*Items{}
+	var folderOrder []string
+	taken := map[string]bool{}
+
+	var block httpBlock
+	flush := func() {
+		if block.request == nil {
+			block = httpBlock{}
+			return
+		}
+		endpoint := block.endpoint()
+		base := endpoint.Name
+		for i := 2; taken[endpoint.Name]; i++ {
+			endpoint.Name = fmt.Sprintf("%s %d", base, i)
+		}
+		taken[endpoint.Name] = true
+
+		if block.folder == "" {
+			collection.Endpoints = append(collection.Endpoints, endpoint)
+		} else {
+			if folderItems[block.folder] == nil {
+				folderItems[block.folder] = &Items{}
+				folderOrder = append(folderOrder, block.folder)
+			}
+			items := folderItems[block.folder]
+			items.Endpoints = append(items.Endpoints, endpoint)
+		}
+		block = httpBlock{}
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		trimmed := strings.TrimSpace(line)
+
+		if rest, ok := strings.CutPrefix(trimmed, "###"); ok {
+			flush()
+			block.name = strings.TrimSpace(rest)
+			continue
+		}
+		if block.inBody {
+			block.addBodyLine(line)
+			continue
+		}
+		if block.request == nil {
+			if name, value, ok := parseHTTPVariable(trimmed); ok {
+				if collection.Variables == nil {
+					collection.Variables = map[string]string{}
+				}
+				collection.Variables[name] = value
+				continue
+			}
+		}
+		if comment, ok := httpComment(trimmed); ok {
+			block.addComment(comment)
+			continue
+		}
+
+		switch {
+		case block.request == nil && trimmed == "":
+		case block.request == nil:
+			block.request = parseRequestLine(trimmed)
+		case trimmed == "":
+			block.inBody = true
+		case isResponsePart(trimmed):
+			// a handler or response reference can follow the headers directly
+			block.inBody = true
+			block.addBodyLine(line)
+		case !block.hasHeaders && (strings.HasPrefix(trimmed, "?") || strings.HasPrefix(trimmed, "&")):
+			// long query strings may continue on the following lines
+			block.request.URL += strings.Join(withoutVersion(strings.Fields(trimmed)), "")
+		default:
+			key, value, ok := strings.Cut(trimmed, ":")
+			if !ok || strings.TrimSpace(key) == "" {
+				return Collection{}, fmt.Errorf("line %d: expected a header, got %q", number, trimmed)
+			}
+			if block.request.Headers == nil {
+				block.request.Headers = map[string]string{}
+			}
+			block.request.Headers[strings.TrimSpace(key)] = strings.TrimSpace(value)
+			block.hasHeaders = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Collection{}, err
+	}
+	flush()
+
+	for _, path := range folderOrder {
+		addToFolder(&collection.Items, folders.SplitPath(path), *folderItems[path])
+	}
+	return collection, nil
+}
+
+// httpBlock is the request between two ### separators
+type httpBlock struct {
+	name       string
+	folder     string
+	request    *Endpoint
+	hasHeaders bool
+	inBody     bool
+	body       []string
+	// inHandler skips a response handler script that follows the body
+	inHandler bool
+	// done ignores the rest of the block after a response reference
+	done bool
+}
+
+func (b *httpBlock) addComment(comment string) {
+	key, value, _ := strings.Cut(comment, " ")
+	switch key {
+	case "@name":
+		b.name = strings.TrimSpace(value)
+	case "@folder":
+		b.folder = strings.TrimSpace(value)
+	}
+}
+
+func (b *httpBlock) addBodyLine(line string) {
+	trimmed := strings.TrimSpace(line)
+	switch {
+	case b.done:
+	case b.inHandler:
+		b.inHandler = !strings.HasSuffix(trimmed, "%}")
+	case strings.HasPrefix(trimmed, "> {%"):
+		b.inHandler = !strings.HasSuffix(trimmed, "%}")
+	case isResponsePart(trimmed):
+		// handler files, response references and redirections end the request
+		b.done = true
+	default:
+		b.body = append(b.body, line)
+	}
+}
+
+func (b *httpBlock) endpoint() Endpoint {
+	endpoint := *b.request
+	endpoint.Name = b.name
+	if endpoint.Name == "" {
+		endpoint.Name = endpoint.Method + " " + endpoint.URL
+	}
+	for len(b.body) > 0 && strings.TrimSpace(b.body[len(b.body)-1]) == "" {
+		b.body = b.body[:len(b.body)-1]
+	}
+	if len(b.body) > 0 {
+		endpoint.Body = strings.Join(b.body, "\n")
+	}
+	return endpoint
+}
+
+// isResponsePart reports whether a line starts a response handler, a
+// response reference or a redirection of the response to a file
+func isResponsePart(line string) bool {
+	for _, prefix := range []string{"> ", "<> ", ">> ", ">>! "} {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRequestLine reads "METHOD URL HTTP/VERSION", where the method and
+// version may be left out
+func parseRequestLine(line string) *Endpoint {
+	fields := strings.Fields(line)
+	endpoint := &Endpoint{Method: "GET"}
+	if len(fields) > 1 && slices.Contains(httpMethods, strings.ToUpper(fields[0])) {
+		endpoint.Method = strings.ToUpper(fields[0])
+		fields = fields[1:]
+	}
+	endpoint.URL = strings.Join(withoutVersion(fields), " ")
+	return endpoint
+}
+
+// withoutVersion drops the HTTP version ending a request line
+func withoutVersion(fields []string) []string {
+	if len(fields) > 1 && strings.HasPrefix(strings.ToUpper(fields[len(fields)-1]), "HTTP/") {
+		return fields[:len(fields)-1]
+	}
+	return fields
+}
+
+// parseHTTPVariable reads a "@name = value" line
+func parseHTTPVariable(line string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(line, "@")
+	if !ok {
+		return "", "", false
+	}
+	name, value, ok := strings.Cut(rest, "=")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" || strings.ContainsAny(name, " \t") {
+		return "", "", false
+	}
+	return name, strings.TrimSpace(value), true
+}
+
+// httpComment returns the text of a # or // comment line
+func httpComment(line string) (string, bool) {
+	if rest, ok := strings.CutPrefix(line, "#"); ok {
+		return strings.TrimSpace(rest), true
+	}
+	if rest, ok := strings.CutPrefix(line, "//"); ok {
+		return strings.TrimSpace(rest), true
+	}
+	return "", false
+}
+
+// addToFolder adds items to the folder at path below parent, creating the
+// folders on the way
+func addToFolder(parent *Items, path []string, items Items) {
+	if len(path) == 0 {
+		parent.Endpoints = append(parent.Endpoints, items.Endpoints...)
+		return
+	}
+	index := slices.IndexFunc(parent.Folders, func(folder Folder) bool {
+		return folder.Name == path[0]
+	})
+	if index < 0 {
+		parent.Folders = append(parent.Folders, Folder{Name: path[0]})
+		index = len(parent.Folders) - 1
+	}
+	addToFolder(&parent.Folders[index].Items, path[1:], items)
+}
+
+// WriteHTTPFile writes a collection in the .http format. Folders are kept as
+// "# @folder" comments. Folder headers and variables, extractions and
+// scripts have no place in the format and are left out, and so are gRPC
+// endpoints, whose names are returned.
+func WriteHTTPFile(w io.Writer, collection Collection) ([]string, error) {
+	out := bufio.NewWriter(w)
+	for _, name := range slices.Sorted(maps.Keys(collection.Variables)) {
+		fmt.Fprintf(out, "@%s = %s\n", name, collection.Variables[name])
+	}
+
+	var skipped []string
+	first := len(collection.Variables) == 0
+	var write func(path string, items Items)
+	write = func(path string, items Items) {
+		for _, endpoint := range items.Endpoints {
+			if endpoint.Protocol == endpoints.ProtocolGRPC {
+				skipped = append(skipped, endpoint.Name)
+				continue
+			}
+			if !first {
+				out.WriteString("\n")
+			}
+			first = false
+			writeHTTPRequest(out, path, endpoint)
+		}
+		for _, folder := range items.Folders {
+			nested := folder.Name
+			if path != "" {
+				nested = path + folders.Separator + folder.Name
+			}
+			write(nested, folder.Items)
+		}
+	}
+	write("", collection.Items)
+	return skipped, out.Flush()
+}
+
+func writeHTTPRequest(out *bufio.Writer, folder string, endpoint Endpoint) {
+	fmt.Fprintf(out, "### %s\n", endpoint.Name)
+	if folder != "" {
+		fmt.Fprintf(out, "# @folder %s\n", folder)
+	}
+
+	url := endpoint.URL
+	for i, key := range slices.Sorted(maps.Keys(endpoint.QueryParams)) {
+		separator := "&"
+		if i == 0 && !strings.Contains(url, "?") {
+			separator = "?"
+		}
+		url += separator + key + "=" + endpoint.QueryParams[key]
+	}
+	fmt.Fprintf(out, "%s %s\n", endpoint.Method, url)
+	for _, key := range slices.Sorted(maps.Keys(endpoint.Headers)) {
+		fmt.Fprintf(out, "%s: %s\n", key, endpoint.Headers[key])
+	}
+	if endpoint.Body != "" {
+		fmt.Fprintf(out, "\n%s\n", strings.TrimRight(endpoint.Body, "\n"))
+	}
+}
@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/maniac-en/req/internal/backend/endpoints"
+)
+
+const sampleHTTPFile = `@host = https://api.example.com
+@token = secret
+
+### List users
+// the first page only
+GET {{host}}/users
+    ?page=1
+    &size=20 HTTP/1.1
+Accept: application/json
+
+###
+# @name createUser
+POST {{host}}/users
+Content-Type: application/json
+Authorization: Bearer {{token}}
+
+{
+  "name": "Ada"
+}
+
+> {%
+  client.global.set("id", response.body.id);
+%}
+
+### Health
+{{host}}/health
+
+### List users
+# @folder admin/audit
+DELETE {{host}}/users/1
+<> 2024-01-01T000000.200.json
+`
+
+func TestReadHTTPFile(t *testing.T) {
+	collection, err := ReadHTTPFile(strings.NewReader(sampleHTTPFile), "users")
+	if err != nil {
+		t.Fatalf("ReadHTTPFile failed: %v", err)
+	}
+
+	if collection.Name != "users" {
+		t.Errorf("Expected the collection to be named users, got %s", collection.Name)
+	}
+	wantVars := map[string]string{"host": "https://api.example.com", "token": "secret"}
+	if !reflect.DeepEqual(collection.Variables, wantVars) {
+		t.Errorf("Expected variables %v, got %v", wantVars, collection.Variables)
+	}
+
+	want := []Endpoint{
+		{
+			Name:    "List users",
+			Method:  "GET",
+			URL:     "{{host}}/users?page=1&size=20",
+			Headers: map[string]string{"Accept": "application/json"},
+		},
+		{
+			Name:    "createUser",
+			Method:  "POST",
+			URL:     "{{host}}/users",
+			Headers: map[string]string{"Content-Type": "application/json", "Authorization": "Bearer {{token}}"},
+			Body:    "{\n  \"name\": \"Ada\"\n}",
+		},
+		{Name: "Health", Method: "GET", URL: "{{host}}/health"},
+	}
+	if !reflect.DeepEqual(collection.Endpoints, want) {
+		t.Errorf("Expected endpoints %+v, got %+v", want, collection.Endpoints)
+	}
+
+	if len(collection.Folders) != 1 || len(collection.Folders[0].Folders) != 1 {
+		t.Fatalf("Expected the admin/audit folders, got %+v", collection.Folders)
+	}
+	nested := collection.Folders[0].Folders[0].Endpoints
+	if len(nested) != 1 || nested[0].Name != "List users 2" || nested[0].Body != "" {
+		t.Errorf("Expected a renamed request without the response reference, got %+v", nested)
+	}
+}
+
+func TestReadHTTPFileErrors(t *testing.T) {
+	_, err := ReadHTTPFile(strings.NewReader("GET /users\nnot a header\n"), "broken")
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Expected an error pointing at line 2, got %v", err)
+	}
+
+	collection, err := ReadHTTPFile(strings.NewReader("# only comments\n###\n\n"), "empty")
+	if err != nil || len(collection.Endpoints) != 0 {
+		t.Errorf("Expected no requests, got %+v, %v", collection.Endpoints, err)
+	}
+}
+
+func TestWriteHTTPFile(t *testing.T) {
+	collection := sampleCollections()[0]
+	collection.Endpoints = append(collection.Endpoints, Endpoint{Name: "Stream", Protocol: endpoints.ProtocolGRPC, Method: "users.Users/Stream", URL: "localhost:50051"})
+
+	var out bytes.Buffer
+	skipped, err := WriteHTTPFile(&out, collection)
+	if err != nil {
+		t.Fatalf("WriteHTTPFile failed: %v", err)
+	}
+	if !reflect.DeepEqual(skipped, []string{"Stream"}) {
+		t.Errorf("Expected the gRPC endpoint to be skipped, got %v", skipped)
+	}
+
+	want := `@base = https://api.example.com
+
+### List users
+GET {{base}}/users?page=1
+
+### Create user
+POST {{base}}/users
+Content-Type: application/json
+
+{
+  "name": "Ada"
+}
+
+### Ban user: by id
+# @folder admin
+DELETE {{base}}/users/1
+`
+	if out.String() != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, out.String())
+	}
+
+	t.Run("Round trip", func(t *testing.T) {
+		read, err := ReadHTTPFile(&out, collection.Name)
+		if err != nil {
+			t.Fatalf("ReadHTTPFile failed: %v", err)
+		}
+		if len(read.Endpoints) != 2 || read.Endpoints[1].Body != "{\n  \"name\": \"Ada\"\n}" {
+			t.Errorf("Expected the endpoints back, got %+v", read.Endpoints)
+		}
+		if len(read.Folders) != 1 || read.Folders[0].Name != "admin" || read.Folders[0].Endpoints[0].Name != "Ban user: by id" {
+			t.Errorf("Expected the admin folder back, got %+v", read.Folders)
+		}
+	})
+}
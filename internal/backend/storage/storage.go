@@ -0,0 +1,115 @@
+// Package storage moves whole collections between the places req can keep
+// them. The database is where the managers work; a directory of request
+// files is where a project repo keeps its API collection under version
+// control. Both implement Store, so collections can be loaded from one and
+// saved into the other.
+//
+// The managers keep working on the database rather than on a Store. Trash,
+// revisions, history, search and ordering are queries a directory of files
+// can't answer, so a directory is copied into the database when req starts
+// and Sync writes the database back to it after every change.
+package storage
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/maniac-en/req/internal/backend/endpoints"
+)
+
+// Store holds a set of collections. Save replaces what the store holds with
+// the given collections.
+type Store interface {
+	Load(ctx context.Context) ([]Collection, error)
+	Save(ctx context.Context, collections []Collection) error
+}
+
+type Collection struct {
+	Name               string
+	Variables          map[string]string
+	PreRequestScript   string
+	PostResponseScript string
+	Items
+}
+
+type Folder struct {
+	Name      string
+	Headers   map[string]string
+	Variables map[string]string
+	Items
+}
+
+// Items are the contents of a collection or folder, endpoints in their order
+// and folders sorted by name
+type Items struct {
+	Endpoints []Endpoint
+	Folders   []Folder
+}
+
+// Endpoint is the request of an endpoint. An empty protocol means HTTP.
+type Endpoint struct {
+	Name               string                 `yaml:"name"`
+	Protocol           string                 `yaml:"protocol,omitempty"`
+	Method             string                 `yaml:"method"`
+	URL                string                 `yaml:"url"`
+	Headers            map[string]string      `yaml:"headers,omitempty"`
+	QueryParams        map[string]string      `yaml:"query,omitempty"`
+	Body               string                 `yaml:"body,omitempty"`
+	ProtoFiles         []string               `yaml:"proto_files,omitempty"`
+	Extractions        []endpoints.Extraction `yaml:"extractions,omitempty"`
+	PreRequestScript   string                 `yaml:"pre_request_script,omitempty"`
+	PostResponseScript string                 `yaml:"post_response_script,omitempty"`
+}
+
+// FromEntity copies the request of a stored endpoint
+func FromEntity(entity endpoints.EndpointEntity) Endpoint {
+	endpoint := Endpoint{
+		Name:               entity.Name,
+		Method:             entity.Method,
+		URL:                entity.Url,
+		Headers:            entity.GetHeaders(),
+		QueryParams:        entity.GetQueryParams(),
+		Body:               entity.RequestBody,
+		ProtoFiles:         entity.GetProtoFiles(),
+		Extractions:        entity.GetExtractions(),
+		PreRequestScript:   entity.PreRequestScript,
+		PostResponseScript: entity.PostResponseScript,
+	}
+	if entity.IsGRPC() {
+		endpoint.Protocol = endpoints.ProtocolGRPC
+	}
+	return endpoint
+}
+
+// equal reports whether two endpoints send the same request, ignoring the
+// defaults filled in when an endpoint is stored
+func (e Endpoint) equal(other Endpoint) bool {
+	a, b := e.normalize(), other.normalize()
+	return a.Name == b.Name &&
+		a.Protocol == b.Protocol &&
+		a.Method == b.Method &&
+		a.URL == b.URL &&
+		maps.Equal(a.Headers, b.Headers) &&
+		maps.Equal(a.QueryParams, b.QueryParams) &&
+		a.Body == b.Body &&
+		slices.Equal(a.ProtoFiles, b.ProtoFiles) &&
+		slices.Equal(a.Extractions, b.Extractions) &&
+		a.PreRequestScript == b.PreRequestScript &&
+		a.PostResponseScript == b.PostResponseScript
+}
+
+func (e Endpoint) normalize() Endpoint {
+	if e.Protocol == endpoints.ProtocolHTTP {
+		e.Protocol = ""
+	}
+	extractions := make([]endpoints.Extraction, len(e.Extractions))
+	for i, extraction := range e.Extractions {
+		if extraction.Scope == "" {
+			extraction.Scope = endpoints.ScopeCollection
+		}
+		extractions[i] = extraction
+	}
+	e.Extractions = extractions
+	return e
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/maniac-en/req/internal/log"
+)
+
+// Sync copies the collections of one store into another soon after they
+// change, so a collection directory stays current while req runs and a
+// crash loses at most the last Delay of edits.
+type Sync struct {
+	From, To Store
+	// Delay lets the writes of one edit land before saving
+	Delay time.Duration
+
+	dirty   atomic.Bool
+	changed chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+
+	mu  sync.Mutex
+	err error
+}
+
+func NewSync(from, to Store, delay time.Duration) *Sync {
+	return &Sync{
+		From:    from,
+		To:      to,
+		Delay:   delay,
+		changed: make(chan struct{}, 1),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+// Start saves in the background until Close
+func (s *Sync) Start() {
+	go s.run()
+}
+
+// Changed tells the sync that From changed. It never blocks, so it can be
+// called from a database hook.
+func (s *Sync) Changed() {
+	s.dirty.Store(true)
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Sync) run() {
+	defer close(s.done)
+	for {
+		select {
+		case <-s.changed:
+		case <-s.stop:
+			return
+		}
+		timer := time.NewTimer(s.Delay)
+		select {
+		case <-timer.C:
+		case <-s.stop:
+			timer.Stop()
+			return
+		}
+		s.save()
+	}
+}
+
+func (s *Sync) save() {
+	if !s.dirty.Swap(false) {
+		return
+	}
+	ctx := context.Background()
+	loaded, err := s.From.Load(ctx)
+	if err == nil {
+		err = s.To.Save(ctx, loaded)
+	}
+	if err != nil {
+		log.Error("failed to sync collections", "error", err)
+	}
+	s.mu.Lock()
+	s.err = err
+	s.mu.Unlock()
+}
+
+// Close stops the sync and saves what changed since the last save. It
+// returns the error of the last save, if it failed.
+func (s *Sync) Close() error {
+	close(s.stop)
+	<-s.done
+	s.save()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
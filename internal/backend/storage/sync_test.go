@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryStore keeps collections in memory and counts saves
+type memoryStore struct {
+	mu          sync.Mutex
+	collections []Collection
+	saves       int
+}
+
+func (m *memoryStore) Load(ctx context.Context) ([]Collection, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.collections, nil
+}
+
+func (m *memoryStore) Save(ctx context.Context, collections []Collection) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.collections = collections
+	m.saves++
+	return nil
+}
+
+func (m *memoryStore) snapshot() ([]Collection, int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.collections, m.saves
+}
+
+func TestSync(t *testing.T) {
+	from, to := &memoryStore{}, &memoryStore{}
+	s := NewSync(from, to, time.Millisecond)
+	s.Start()
+
+	from.Save(context.Background(), sampleCollections())
+	s.Changed()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if collections, _ := to.snapshot(); len(collections) == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the change to be saved while running")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	_, saves := to.snapshot()
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, after := to.snapshot(); after != saves {
+		t.Errorf("Expected no save on close without changes, got %d saves after %d", after, saves)
+	}
+
+	// changes made just before closing are saved by Close
+	from, to = &memoryStore{}, &memoryStore{}
+	s = NewSync(from, to, time.Hour)
+	s.Start()
+	from.Save(context.Background(), sampleCollections())
+	s.Changed()
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if collections, _ := to.snapshot(); len(collections) != 1 {
+		t.Error("Expected Close to save the last change")
+	}
+}
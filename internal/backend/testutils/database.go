@@ -12,6 +12,12 @@ import (
 
 // SetupTestDB creates an in-memory SQLite database with specified tables
 func SetupTestDB(t *testing.T, tables ...string) *database.Queries {
+	return database.New(OpenTestDB(t, tables...))
+}
+
+// OpenTestDB is SetupTestDB for tests that also need the connection, for
+// example to add a trigger that makes writes fail
+func OpenTestDB(t *testing.T, tables ...string) *sql.DB {
 	db, err := sql.Open("sqlite3", ":memory:")
 	if err != nil {
 		t.Fatalf("Failed to open test database: %v", err)
@@ -29,7 +35,7 @@ func SetupTestDB(t *testing.T, tables ...string) *database.Queries {
 		}
 	}
 
-	return database.New(db)
+	return db
 }
 
 // getTableSchema returns the SQL schema for creating the specified table
@@ -40,6 +46,20 @@ func getTableSchema(table string) string {
 				id INTEGER PRIMARY KEY AUTOINCREMENT,
 				name TEXT NOT NULL,
 				created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				updated_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				variables TEXT DEFAULT '{}' NOT NULL,
+				pre_request_script TEXT DEFAULT '' NOT NULL,
+				post_response_script TEXT DEFAULT '' NOT NULL,
+				deleted_at TEXT,
+				sort_order INTEGER DEFAULT 0 NOT NULL
+			);`,
+		"environments": `
+			CREATE TABLE environments (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				name TEXT NOT NULL UNIQUE,
+				variables TEXT DEFAULT '{}' NOT NULL,
+				active INTEGER DEFAULT 0 NOT NULL,
+				created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
 				updated_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
 			);`,
 		"endpoints": `
@@ -54,8 +74,47 @@ func getTableSchema(table string) string {
 				request_body TEXT DEFAULT '' NOT NULL,
 				created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
 				updated_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				protocol TEXT DEFAULT 'http' NOT NULL,
+				proto_files TEXT DEFAULT '[]' NOT NULL,
+				extractions TEXT DEFAULT '[]' NOT NULL,
+				pre_request_script TEXT DEFAULT '' NOT NULL,
+				post_response_script TEXT DEFAULT '' NOT NULL,
+				folder_id INTEGER,
+				sort_order INTEGER DEFAULT 0 NOT NULL,
+				deleted_at TEXT,
+				FOREIGN KEY (collection_id) REFERENCES collections(id) ON DELETE CASCADE
+			);`,
+		"folders": `
+			CREATE TABLE folders (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				collection_id INTEGER NOT NULL,
+				parent_id INTEGER,
+				name TEXT NOT NULL,
+				headers TEXT DEFAULT '{}' NOT NULL,
+				variables TEXT DEFAULT '{}' NOT NULL,
+				created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				updated_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				deleted_at TEXT,
 				FOREIGN KEY (collection_id) REFERENCES collections(id) ON DELETE CASCADE
 			);`,
+		"endpoint_revisions": `
+			CREATE TABLE endpoint_revisions (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				endpoint_id INTEGER NOT NULL,
+				name TEXT NOT NULL,
+				method TEXT NOT NULL,
+				url TEXT NOT NULL,
+				headers TEXT DEFAULT '{}' NOT NULL,
+				query_params TEXT DEFAULT '{}' NOT NULL,
+				request_body TEXT DEFAULT '' NOT NULL,
+				protocol TEXT DEFAULT 'http' NOT NULL,
+				proto_files TEXT DEFAULT '[]' NOT NULL,
+				extractions TEXT DEFAULT '[]' NOT NULL,
+				pre_request_script TEXT DEFAULT '' NOT NULL,
+				post_response_script TEXT DEFAULT '' NOT NULL,
+				created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				FOREIGN KEY (endpoint_id) REFERENCES endpoints(id) ON DELETE CASCADE
+			);`,
 		"history": `
 			CREATE TABLE history (
 				id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -74,6 +133,31 @@ func getTableSchema(table string) string {
 				response_headers TEXT DEFAULT '{}',
 				executed_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
 			);`,
+		// history_fts must be created after history
+		"history_fts": `
+			CREATE VIRTUAL TABLE history_fts USING fts4(
+				content="history", url, query_params, request_headers, request_body, response_headers, response_body
+			);
+			CREATE TRIGGER history_fts_before_delete BEFORE DELETE ON history BEGIN
+				DELETE FROM history_fts WHERE docid = old.id;
+			END;
+			CREATE TRIGGER history_fts_after_insert AFTER INSERT ON history BEGIN
+				INSERT INTO history_fts (docid, url, query_params, request_headers, request_body, response_headers, response_body)
+				VALUES (new.id, new.url, new.query_params, new.request_headers, new.request_body, new.response_headers, new.response_body);
+			END;`,
+		"snapshots": `
+			CREATE TABLE snapshots (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				endpoint_id INTEGER NOT NULL UNIQUE,
+				status_code INTEGER NOT NULL,
+				response_headers TEXT DEFAULT '{}' NOT NULL,
+				response_body TEXT DEFAULT '' NOT NULL,
+				ignored_paths TEXT DEFAULT '[]' NOT NULL,
+				ignored_headers TEXT DEFAULT '[]' NOT NULL,
+				created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				updated_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				FOREIGN KEY (endpoint_id) REFERENCES endpoints(id) ON DELETE CASCADE
+			);`,
 	}
 
 	return schemas[table]
@@ -0,0 +1,180 @@
+package trash
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/maniac-en/req/internal/backend/collections"
+	"github.com/maniac-en/req/internal/backend/crud"
+	"github.com/maniac-en/req/internal/backend/database"
+	"github.com/maniac-en/req/internal/backend/endpoints"
+	"github.com/maniac-en/req/internal/backend/folders"
+	"github.com/maniac-en/req/internal/log"
+)
+
+func NewTrashManager(db *database.Queries) *TrashManager {
+	return &TrashManager{DB: db}
+}
+
+// List returns the deleted items, most recent first. Folders and endpoints
+// that went to the trash along with a folder or collection are not listed
+// on their own, since they are restored with it.
+func (t *TrashManager) List(ctx context.Context) ([]Item, error) {
+	trashedCollections, err := t.DB.ListTrashedCollections(ctx)
+	if err != nil {
+		log.Error("failed to list trashed collections", "error", err)
+		return nil, err
+	}
+	trashedFolders, err := t.DB.ListTrashedFolders(ctx)
+	if err != nil {
+		log.Error("failed to list trashed folders", "error", err)
+		return nil, err
+	}
+	trashedEndpoints, err := t.DB.ListTrashedEndpoints(ctx)
+	if err != nil {
+		log.Error("failed to list trashed endpoints", "error", err)
+		return nil, err
+	}
+
+	var items []Item
+	for _, collection := range trashedCollections {
+		items = append(items, Item{
+			Kind:      KindCollection,
+			ID:        collection.ID,
+			Name:      collection.Name,
+			DeletedAt: crud.ParseTimestamp(collection.DeletedAt.String),
+		})
+	}
+
+	deletedWith := make(map[int64]string, len(trashedFolders))
+	for _, folder := range trashedFolders {
+		deletedWith[folder.ID] = folder.DeletedAt.String
+	}
+	locations := &locator{db: t.DB, trees: map[int64]*folders.Tree{}, names: map[int64]string{}}
+	for _, folder := range trashedFolders {
+		if folder.ParentID.Valid && deletedWith[folder.ParentID.Int64] == folder.DeletedAt.String {
+			continue
+		}
+		location, ok := locations.find(ctx, folder.CollectionID, folder.ParentID)
+		if !ok {
+			continue
+		}
+		items = append(items, Item{
+			Kind:      KindFolder,
+			ID:        folder.ID,
+			Name:      folder.Name,
+			Location:  location,
+			DeletedAt: crud.ParseTimestamp(folder.DeletedAt.String),
+		})
+	}
+	for _, endpoint := range trashedEndpoints {
+		if endpoint.FolderID.Valid && deletedWith[endpoint.FolderID.Int64] == endpoint.DeletedAt.String {
+			continue
+		}
+		location, ok := locations.find(ctx, endpoint.CollectionID, endpoint.FolderID)
+		if !ok {
+			continue
+		}
+		items = append(items, Item{
+			Kind:      KindEndpoint,
+			ID:        endpoint.ID,
+			Name:      endpoint.Name,
+			Location:  location,
+			DeletedAt: crud.ParseTimestamp(endpoint.DeletedAt.String),
+		})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].DeletedAt.After(items[j].DeletedAt)
+	})
+	return items, nil
+}
+
+// Restore takes an item out of the trash
+func (t *TrashManager) Restore(ctx context.Context, kind string, id int64) error {
+	var err error
+	switch kind {
+	case KindCollection:
+		_, err = collections.NewCollectionsManager(t.DB).Restore(ctx, id)
+	case KindFolder:
+		_, err = folders.NewFoldersManager(t.DB).Restore(ctx, id)
+	case KindEndpoint:
+		_, err = endpoints.NewEndpointsManager(t.DB).Restore(ctx, id)
+	default:
+		log.Warn("trash restore failed kind validation", "kind", kind)
+		return fmt.Errorf("%w: unknown kind %q", crud.ErrInvalidInput, kind)
+	}
+	return err
+}
+
+// Empty permanently deletes the items that have been in the trash for longer
+// than olderThan, or every item when it is 0, and returns how many rows
+// were deleted
+func (t *TrashManager) Empty(ctx context.Context, olderThan time.Duration) (int64, error) {
+	before := sql.NullString{String: crud.FormatTimestamp(time.Now().Add(-olderThan)), Valid: true}
+
+	log.Debug("emptying trash", "older_than", olderThan)
+	// folders and endpoints first, they are matched on their collection
+	endpointsDeleted, err := t.DB.PurgeEndpoints(ctx, before)
+	if err != nil {
+		log.Error("failed to purge endpoints", "error", err)
+		return 0, err
+	}
+	foldersDeleted, err := t.DB.PurgeFolders(ctx, before)
+	if err != nil {
+		log.Error("failed to purge folders", "error", err)
+		return 0, err
+	}
+	collectionsDeleted, err := t.DB.PurgeCollections(ctx, before)
+	if err != nil {
+		log.Error("failed to purge collections", "error", err)
+		return 0, err
+	}
+
+	// revisions are history of the endpoints, not items of their own
+	revisionsDeleted, err := t.DB.PurgeOrphanedEndpointRevisions(ctx)
+	if err != nil {
+		log.Error("failed to purge revisions", "error", err)
+		return 0, err
+	}
+
+	total := endpointsDeleted + foldersDeleted + collectionsDeleted
+	log.Info("emptied trash", "endpoints", endpointsDeleted, "folders", foldersDeleted, "collections", collectionsDeleted, "revisions", revisionsDeleted)
+	return total, nil
+}
+
+// locator describes where items were deleted from. It reports false for
+// items of collections that are in the trash themselves.
+type locator struct {
+	db    *database.Queries
+	trees map[int64]*folders.Tree
+	names map[int64]string
+}
+
+func (l *locator) find(ctx context.Context, collectionID int64, folderID sql.NullInt64) (string, bool) {
+	name, ok := l.names[collectionID]
+	if !ok {
+		collection, err := l.db.GetCollection(ctx, collectionID)
+		if err != nil {
+			l.names[collectionID] = ""
+			return "", false
+		}
+		name = collection.Name
+		l.names[collectionID] = name
+		tree, err := folders.NewFoldersManager(l.db).Tree(ctx, collectionID)
+		if err != nil {
+			tree = folders.NewTree(nil)
+		}
+		l.trees[collectionID] = tree
+	}
+	if name == "" {
+		return "", false
+	}
+	if path := l.trees[collectionID].Path(folderID.Int64); folderID.Valid && path != "" {
+		return name + folders.Separator + path, true
+	}
+	return name, true
+}
@@ -0,0 +1,166 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/maniac-en/req/internal/backend/collections"
+	"github.com/maniac-en/req/internal/backend/crud"
+	"github.com/maniac-en/req/internal/backend/endpoints"
+	"github.com/maniac-en/req/internal/backend/folders"
+	"github.com/maniac-en/req/internal/backend/testutils"
+)
+
+type fixture struct {
+	trash       *TrashManager
+	collections *collections.CollectionsManager
+	endpoints   *endpoints.EndpointsManager
+	folders     *folders.FoldersManager
+}
+
+func setup(t *testing.T) fixture {
+	t.Helper()
+	db := testutils.SetupTestDB(t, "collections", "endpoints", "folders", "endpoint_revisions")
+	return fixture{
+		trash:       NewTrashManager(db),
+		collections: collections.NewCollectionsManager(db),
+		endpoints:   endpoints.NewEndpointsManager(db),
+		folders:     folders.NewFoldersManager(db),
+	}
+}
+
+func (f fixture) endpoint(t *testing.T, collectionID, folderID int64, name string) endpoints.EndpointEntity {
+	t.Helper()
+	endpoint, err := f.endpoints.CreateEndpoint(context.Background(), endpoints.EndpointData{
+		CollectionID: collectionID,
+		FolderID:     folderID,
+		Name:         name,
+		Method:       "GET",
+	})
+	if err != nil {
+		t.Fatalf("CreateEndpoint failed: %v", err)
+	}
+	return endpoint
+}
+
+func TestTrashEndpoint(t *testing.T) {
+	f := setup(t)
+	ctx := context.Background()
+	collection, _ := f.collections.Create(ctx, "API")
+	users, _ := f.folders.CreateFolder(ctx, collection.ID, 0, "users")
+	endpoint := f.endpoint(t, collection.ID, users.ID, "list")
+
+	if err := f.endpoints.Delete(ctx, endpoint.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := f.endpoints.Read(ctx, endpoint.ID); err != crud.ErrNotFound {
+		t.Errorf("Expected a trashed endpoint to be hidden, got %v", err)
+	}
+	items, err := f.trash.List(ctx)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(items) != 1 || items[0].Kind != KindEndpoint || items[0].Location != "API/users" {
+		t.Fatalf("Expected the endpoint in the trash, got %+v", items)
+	}
+
+	// the folder is gone by the time the endpoint comes back
+	f.folders.Delete(ctx, users.ID)
+	if err := f.trash.Restore(ctx, KindEndpoint, endpoint.ID); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	restored, err := f.endpoints.Read(ctx, endpoint.ID)
+	if err != nil {
+		t.Fatalf("Read failed: %v", err)
+	}
+	if restored.GetFolderID() != 0 {
+		t.Errorf("Expected the endpoint at the top, got folder %d", restored.GetFolderID())
+	}
+	if err := f.trash.Restore(ctx, KindEndpoint, endpoint.ID); !errors.Is(err, crud.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound restoring twice, got %v", err)
+	}
+}
+
+func TestTrashFolder(t *testing.T) {
+	f := setup(t)
+	ctx := context.Background()
+	collection, _ := f.collections.Create(ctx, "API")
+	users, _ := f.folders.CreateFolder(ctx, collection.ID, 0, "users")
+	admin, _ := f.folders.CreateFolder(ctx, collection.ID, users.ID, "admin")
+	f.endpoint(t, collection.ID, users.ID, "list")
+	f.endpoint(t, collection.ID, admin.ID, "list admins")
+	kept := f.endpoint(t, collection.ID, admin.ID, "deleted before")
+	f.endpoints.Delete(ctx, kept.ID)
+	time.Sleep(time.Millisecond)
+
+	if err := f.folders.Delete(ctx, users.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if left, _ := f.endpoints.ListByCollection(ctx, collection.ID); len(left) != 0 {
+		t.Errorf("Expected the folder's endpoints to be hidden, got %d", len(left))
+	}
+	items, _ := f.trash.List(ctx)
+	if len(items) != 2 || items[0].Kind != KindFolder || items[0].Name != "users" || items[1].Name != "deleted before" {
+		t.Fatalf("Expected the folder and the earlier endpoint in the trash, got %+v", items)
+	}
+
+	// a new folder took the name in the meantime
+	f.folders.CreateFolder(ctx, collection.ID, 0, "users")
+	if err := f.trash.Restore(ctx, KindFolder, users.ID); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	tree, _ := f.folders.Tree(ctx, collection.ID)
+	if _, ok := tree.Find("users (copy)/admin"); !ok {
+		t.Errorf("Expected the folder restored as 'users (copy)' with its subfolder")
+	}
+	if all, _ := f.endpoints.ListByCollection(ctx, collection.ID); len(all) != 2 {
+		t.Errorf("Expected the 2 endpoints deleted with the folder back, got %d", len(all))
+	}
+}
+
+func TestTrashCollectionAndEmpty(t *testing.T) {
+	f := setup(t)
+	ctx := context.Background()
+	collection, _ := f.collections.Create(ctx, "API")
+	users, _ := f.folders.CreateFolder(ctx, collection.ID, 0, "users")
+	f.endpoint(t, collection.ID, users.ID, "list")
+	other, _ := f.collections.Create(ctx, "Other")
+	stale := f.endpoint(t, other.ID, 0, "stale")
+
+	f.collections.Delete(ctx, collection.ID)
+	f.endpoints.Delete(ctx, stale.ID)
+	items, _ := f.trash.List(ctx)
+	if len(items) != 2 {
+		t.Fatalf("Expected the collection and the endpoint in the trash, got %+v", items)
+	}
+	if all, _ := f.collections.List(ctx); len(all) != 1 {
+		t.Errorf("Expected 1 collection left, got %d", len(all))
+	}
+
+	if deleted, _ := f.trash.Empty(ctx, time.Hour); deleted != 0 {
+		t.Errorf("Expected nothing older than an hour, deleted %d", deleted)
+	}
+	if err := f.trash.Restore(ctx, KindCollection, collection.ID); err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if all, _ := f.endpoints.ListByCollection(ctx, collection.ID); len(all) != 1 {
+		t.Errorf("Expected the collection's endpoint back, got %d", len(all))
+	}
+
+	f.collections.Delete(ctx, collection.ID)
+	deleted, err := f.trash.Empty(ctx, 0)
+	if err != nil {
+		t.Fatalf("Empty failed: %v", err)
+	}
+	if deleted != 4 {
+		t.Errorf("Expected 4 rows deleted, got %d", deleted)
+	}
+	if items, _ := f.trash.List(ctx); len(items) != 0 {
+		t.Errorf("Expected an empty trash, got %+v", items)
+	}
+	if err := f.trash.Restore(ctx, "history", 1); !errors.Is(err, crud.ErrInvalidInput) {
+		t.Errorf("Expected ErrInvalidInput for an unknown kind, got %v", err)
+	}
+}
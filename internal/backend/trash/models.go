@@ -0,0 +1,30 @@
+// Package trash lists and restores the collections, folders and endpoints
+// that were deleted, and empties the trash for good.
+package trash
+
+import (
+	"time"
+
+	"github.com/maniac-en/req/internal/backend/database"
+)
+
+// Kinds of items in the trash
+const (
+	KindCollection = "collection"
+	KindFolder     = "folder"
+	KindEndpoint   = "endpoint"
+)
+
+// Item is something that was deleted. Location is the collection, and the
+// folder path, it was deleted from. It is empty for collections.
+type Item struct {
+	Kind      string
+	ID        int64
+	Name      string
+	Location  string
+	DeletedAt time.Time
+}
+
+type TrashManager struct {
+	DB *database.Queries
+}
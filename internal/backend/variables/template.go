@@ -0,0 +1,277 @@
+package variables
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	mathrand "math/rand/v2"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxDepth bounds how deeply placeholders can be nested
+const maxDepth = 8
+
+// Template resolves {{name}} variables and {{$function args}} template
+// functions for a single request. Function results are cached by expression,
+// so every {{$timestamp}} in a request sends the same value.
+type Template struct {
+	vars      map[string]string
+	generated map[string]string
+	// Now and LookupEnv can be replaced in tests
+	Now       func() time.Time
+	LookupEnv func(string) (string, bool)
+}
+
+// function evaluates a template function. args is the text after the
+// function name, with surrounding whitespace removed.
+type function func(t *Template, args string) (string, error)
+
+var functions = map[string]function{
+	"uuid":        noArgs(func(*Template) string { return NewUUID() }),
+	"timestamp":   noArgs(func(t *Template) string { return strconv.FormatInt(t.Now().Unix(), 10) }),
+	"timestampMs": noArgs(func(t *Template) string { return strconv.FormatInt(t.Now().UnixMilli(), 10) }),
+	"isoDate":     noArgs(func(t *Template) string { return t.Now().UTC().Format(time.RFC3339) }),
+	"randomInt":   randomInt,
+	"env":         env,
+	"base64": func(_ *Template, args string) (string, error) {
+		return base64.StdEncoding.EncodeToString([]byte(args)), nil
+	},
+	"md5":        digest(md5.New),
+	"sha1":       digest(sha1.New),
+	"sha256":     digest(sha256.New),
+	"sha512":     digest(sha512.New),
+	"hmacSHA1":   mac(sha1.New),
+	"hmacSHA256": mac(sha256.New),
+	"hmacSHA512": mac(sha512.New),
+}
+
+// NewTemplate creates a template that resolves the given variables
+func NewTemplate(vars map[string]string) *Template {
+	return &Template{
+		vars:      vars,
+		generated: map[string]string{},
+		Now:       time.Now,
+		LookupEnv: os.LookupEnv,
+	}
+}
+
+// Execute resolves the placeholders in text. Unknown variables are left
+// untouched like in Substitute; unknown or failing functions are errors.
+// Nested placeholders such as {{$sha256 {{secret}}}} resolve from the inside
+// out. Only the original text is scanned: substituted values are inserted
+// as-is, so a variable holding "{{$env SECRET}}" is sent literally.
+func (t *Template) Execute(text string) (string, error) {
+	return t.expand(text, 0)
+}
+
+func (t *Template) expand(text string, depth int) (string, error) {
+	var out strings.Builder
+	for {
+		start := strings.Index(text, "{{")
+		if start < 0 {
+			break
+		}
+		// in a run such as {{{name}}} the last two braces open the placeholder
+		for start+2 < len(text) && text[start+2] == '{' {
+			start++
+		}
+		end := closing(text, start+2)
+		if end < 0 {
+			out.WriteString(text[:start+2])
+			text = text[start+2:]
+			continue
+		}
+		out.WriteString(text[:start])
+		raw := text[start+2 : end]
+		text = text[end+2:]
+
+		if depth >= maxDepth {
+			out.WriteString("{{" + raw + "}}")
+			continue
+		}
+		inner, err := t.expand(raw, depth+1)
+		if err != nil {
+			return "", err
+		}
+		// the variable or function name must come from the template itself
+		if name, _, _ := strings.Cut(strings.TrimSpace(raw), " "); strings.Contains(name, "{{") {
+			out.WriteString("{{" + inner + "}}")
+			continue
+		}
+		value, ok, err := t.evaluate(strings.TrimSpace(inner))
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			value = "{{" + inner + "}}"
+		}
+		out.WriteString(value)
+	}
+	out.WriteString(text)
+	return out.String(), nil
+}
+
+// closing returns the index of the }} that ends the placeholder whose
+// contents start at from, or -1 when it is never closed
+func closing(text string, from int) int {
+	open := 1
+	for i := from; i+1 < len(text); {
+		switch text[i : i+2] {
+		case "{{":
+			open++
+			i += 2
+		case "}}":
+			open--
+			if open == 0 {
+				return i
+			}
+			i += 2
+		default:
+			i++
+		}
+	}
+	return -1
+}
+
+// ExecuteMap applies Execute to the keys and values of a map
+func (t *Template) ExecuteMap(values map[string]string) (map[string]string, error) {
+	out := make(map[string]string, len(values))
+	for key, value := range values {
+		resolvedKey, err := t.Execute(key)
+		if err != nil {
+			return nil, err
+		}
+		resolvedValue, err := t.Execute(value)
+		if err != nil {
+			return nil, err
+		}
+		out[resolvedKey] = resolvedValue
+	}
+	return out, nil
+}
+
+// Generated returns the values produced by template functions, keyed by
+// their expression
+func (t *Template) Generated() map[string]string {
+	generated := make(map[string]string, len(t.generated))
+	for expression, value := range t.generated {
+		generated[expression] = value
+	}
+	return generated
+}
+
+func (t *Template) evaluate(expression string) (string, bool, error) {
+	if value, ok := t.vars[expression]; ok {
+		return value, true, nil
+	}
+	if !strings.HasPrefix(expression, "$") {
+		return "", false, nil
+	}
+	if value, ok := t.generated[expression]; ok {
+		return value, true, nil
+	}
+
+	name, args, _ := strings.Cut(expression[1:], " ")
+	call, ok := functions[name]
+	if !ok {
+		return "", false, fmt.Errorf("unknown template function $%s", name)
+	}
+	value, err := call(t, strings.TrimSpace(args))
+	if err != nil {
+		return "", false, fmt.Errorf("$%s: %w", name, err)
+	}
+	t.generated[expression] = value
+	return value, true, nil
+}
+
+// NewUUID returns a random version 4 UUID
+func NewUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func noArgs(call func(*Template) string) function {
+	return func(t *Template, args string) (string, error) {
+		if args != "" {
+			return "", errors.New("takes no arguments")
+		}
+		return call(t), nil
+	}
+}
+
+// randomInt returns a number between min and max inclusive, 0 to 1000 by default
+func randomInt(_ *Template, args string) (string, error) {
+	low, high := 0, 1000
+	if args != "" {
+		fields := strings.Fields(args)
+		if len(fields) != 2 {
+			return "", errors.New("expected a minimum and a maximum")
+		}
+		var err error
+		if low, err = strconv.Atoi(fields[0]); err != nil {
+			return "", fmt.Errorf("invalid minimum %q", fields[0])
+		}
+		if high, err = strconv.Atoi(fields[1]); err != nil {
+			return "", fmt.Errorf("invalid maximum %q", fields[1])
+		}
+		if high < low {
+			return "", errors.New("maximum is less than minimum")
+		}
+	}
+	// the span is computed in uint64 so the full int range cannot overflow
+	span := uint64(high) - uint64(low) + 1
+	offset := mathrand.Uint64()
+	if span != 0 {
+		offset = mathrand.Uint64N(span)
+	}
+	return strconv.Itoa(int(uint64(low) + offset)), nil
+}
+
+func env(t *Template, args string) (string, error) {
+	if args == "" {
+		return "", errors.New("expected a variable name")
+	}
+	value, ok := t.LookupEnv(args)
+	if !ok {
+		return "", fmt.Errorf("%s is not set", args)
+	}
+	return value, nil
+}
+
+// digest hashes the whole argument text
+func digest(newHash func() hash.Hash) function {
+	return func(_ *Template, args string) (string, error) {
+		h := newHash()
+		h.Write([]byte(args))
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+}
+
+// mac signs everything after the first word with the first word as the key
+func mac(newHash func() hash.Hash) function {
+	return func(_ *Template, args string) (string, error) {
+		key, message, ok := strings.Cut(args, " ")
+		if !ok || key == "" {
+			return "", errors.New("expected a key and a message")
+		}
+		h := hmac.New(newHash, []byte(key))
+		h.Write([]byte(strings.TrimSpace(message)))
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+}
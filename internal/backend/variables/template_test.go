@@ -0,0 +1,156 @@
+package variables
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestTemplate(vars map[string]string) *Template {
+	template := NewTemplate(vars)
+	template.Now = func() time.Time { return time.Date(2025, 8, 1, 12, 0, 0, 0, time.UTC) }
+	template.LookupEnv = func(name string) (string, bool) {
+		if name == "API_KEY" {
+			return "from-shell", true
+		}
+		return "", false
+	}
+	return template
+}
+
+func TestTemplateExecute(t *testing.T) {
+	signature := hmac.New(sha256.New, []byte("s3cret"))
+	signature.Write([]byte("1754049600"))
+
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"{{host}}/{{ id }}", "api.example.com/42"},
+		{"{{missing}}", "{{missing}}"},
+		{"{{$timestamp}}", "1754049600"},
+		{"{{$timestampMs}}", "1754049600000"},
+		{"{{$isoDate}}", "2025-08-01T12:00:00Z"},
+		{"{{$env API_KEY}}", "from-shell"},
+		{"Basic {{$base64 user:pass}}", "Basic dXNlcjpwYXNz"},
+		{"{{$md5 hello}}", "5d41402abc4b2a76b9719d911017c592"},
+		{"{{$sha256 {{id}}}}", "73475cb40a568e8da8a045ced110137e159f890ac4da883b6b17dc651b3a8049"},
+		{"{{$hmacSHA256 {{secret}} {{$timestamp}}}}", hex.EncodeToString(signature.Sum(nil))},
+		{"{{$randomInt 7 7}}", "7"},
+		{`{"id": {{{id}}}}`, `{"id": {42}}`},
+		{"{{ {{id}}", "{{ 42"},
+	}
+
+	for _, test := range tests {
+		template := newTestTemplate(map[string]string{"host": "api.example.com", "id": "42", "secret": "s3cret"})
+		got, err := template.Execute(test.text)
+		if err != nil {
+			t.Errorf("Execute(%q) failed: %v", test.text, err)
+			continue
+		}
+		if got != test.expected {
+			t.Errorf("Execute(%q) = %q, expected %q", test.text, got, test.expected)
+		}
+	}
+}
+
+func TestTemplateGenerated(t *testing.T) {
+	template := NewTemplate(nil)
+
+	first, err := template.Execute("{{$uuid}}")
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(first) {
+		t.Errorf("expected a version 4 UUID, got %q", first)
+	}
+	second, err := template.Execute(`{"key": "{{$uuid}}"}`)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if second != `{"key": "`+first+`"}` {
+		t.Errorf("expected the same UUID within a request, got %q and %q", first, second)
+	}
+
+	number, err := template.Execute("{{$randomInt 1 100}}")
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	if n, err := strconv.Atoi(number); err != nil || n < 1 || n > 100 {
+		t.Errorf("expected a number between 1 and 100, got %q", number)
+	}
+
+	for _, text := range []string{"{{$randomInt -9223372036854775808 9223372036854775807}}", "{{$randomInt -9223372036854775808 0}}"} {
+		if _, err := template.Execute(text); err != nil {
+			t.Errorf("Execute(%q) failed: %v", text, err)
+		}
+	}
+
+	generated := template.Generated()
+	if generated["$uuid"] != first || generated["$randomInt 1 100"] != number {
+		t.Errorf("unexpected generated values %v", generated)
+	}
+	if other := NewTemplate(nil); len(other.Generated()) != 0 {
+		t.Error("expected a new template to start without generated values")
+	}
+}
+
+func TestTemplateErrors(t *testing.T) {
+	tests := []struct {
+		text    string
+		message string
+	}{
+		{"{{$nope}}", "unknown template function $nope"},
+		{"{{$uuid extra}}", "$uuid: takes no arguments"},
+		{"{{$randomInt 10}}", "$randomInt: expected a minimum and a maximum"},
+		{"{{$randomInt 10 1}}", "$randomInt: maximum is less than minimum"},
+		{"{{$env MISSING}}", "$env: MISSING is not set"},
+		{"{{$hmacSHA256 key}}", "$hmacSHA256: expected a key and a message"},
+	}
+
+	for _, test := range tests {
+		_, err := newTestTemplate(nil).Execute(test.text)
+		if err == nil || !strings.Contains(err.Error(), test.message) {
+			t.Errorf("Execute(%q) error = %v, expected %q", test.text, err, test.message)
+		}
+	}
+}
+
+func TestTemplateDoesNotRescanValues(t *testing.T) {
+	vars := map[string]string{"token": "{{$env API_KEY}}", "name": "$env API_KEY", "other": "{{id}}", "id": "42"}
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"Bearer {{token}}", "Bearer {{$env API_KEY}}"},
+		{"{{other}}", "{{id}}"},
+		{"{{ {{name}} }}", "{{ $env API_KEY }}"},
+		{"{{$base64 {{token}}}}", "e3skZW52IEFQSV9LRVl9fQ=="},
+	}
+
+	for _, test := range tests {
+		got, err := newTestTemplate(vars).Execute(test.text)
+		if err != nil {
+			t.Errorf("Execute(%q) failed: %v", test.text, err)
+			continue
+		}
+		if got != test.expected {
+			t.Errorf("Execute(%q) = %q, expected %q", test.text, got, test.expected)
+		}
+	}
+}
+
+func TestTemplateExecuteMap(t *testing.T) {
+	got, err := newTestTemplate(map[string]string{"name": "Trace"}).ExecuteMap(map[string]string{"X-{{name}}": "{{$timestamp}}"})
+	if err != nil {
+		t.Fatalf("ExecuteMap failed: %v", err)
+	}
+	if got["X-Trace"] != "1754049600" {
+		t.Errorf("unexpected map %v", got)
+	}
+}
@@ -0,0 +1,89 @@
+// Package variables resolves {{name}} placeholders in requests from
+// collection and environment variables, and {{$function}} placeholders from
+// built-in generators.
+package variables
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+var placeholder = regexp.MustCompile(`{{\s*([^{}\s]+)\s*}}`)
+
+// Merge combines variable sets, with later sets taking precedence
+func Merge(sets ...map[string]string) map[string]string {
+	merged := map[string]string{}
+	for _, set := range sets {
+		for name, value := range set {
+			merged[name] = value
+		}
+	}
+	return merged
+}
+
+// Substitute replaces every {{name}} with its value. Unknown placeholders are
+// left untouched so they stay visible in the sent request.
+func Substitute(text string, vars map[string]string) string {
+	if !strings.Contains(text, "{{") {
+		return text
+	}
+	return placeholder.ReplaceAllStringFunc(text, func(match string) string {
+		name := placeholder.FindStringSubmatch(match)[1]
+		if value, ok := vars[name]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// SubstituteMap applies Substitute to the keys and values of a map
+func SubstituteMap(values map[string]string, vars map[string]string) map[string]string {
+	out := make(map[string]string, len(values))
+	for key, value := range values {
+		out[Substitute(key, vars)] = Substitute(value, vars)
+	}
+	return out
+}
+
+// Unresolved lists the placeholder names in text that have no value.
+// Template functions such as {{$uuid}} are resolved at send time and are not
+// listed.
+func Unresolved(text string, vars map[string]string) []string {
+	var names []string
+	for _, match := range placeholder.FindAllStringSubmatch(text, -1) {
+		if strings.HasPrefix(match[1], "$") {
+			continue
+		}
+		if _, ok := vars[match[1]]; !ok {
+			names = append(names, match[1])
+		}
+	}
+	return names
+}
+
+// Decode parses a stored variables JSON object, returning an empty map when it is malformed
+func Decode(data string) map[string]string {
+	vars := map[string]string{}
+	if err := json.Unmarshal([]byte(data), &vars); err != nil {
+		return map[string]string{}
+	}
+	return vars
+}
+
+// Encode serializes variables for storage
+func Encode(vars map[string]string) (string, error) {
+	if len(vars) == 0 {
+		return "{}", nil
+	}
+	data, err := json.Marshal(vars)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// ValidateName reports whether name can be used as a variable
+func ValidateName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, "{} \t\n")
+}
@@ -0,0 +1,67 @@
+package variables
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSubstitute(t *testing.T) {
+	vars := map[string]string{"host": "api.example.com", "token": "abc", "id": "42"}
+
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{"https://{{host}}/users/{{id}}", "https://api.example.com/users/42"},
+		{"Bearer {{ token }}", "Bearer abc"},
+		{"{{missing}}", "{{missing}}"},
+		{"no placeholders", "no placeholders"},
+		{`{"id": {{id}}}`, `{"id": 42}`},
+	}
+
+	for _, test := range tests {
+		if got := Substitute(test.text, vars); got != test.expected {
+			t.Errorf("Substitute(%q) = %q, expected %q", test.text, got, test.expected)
+		}
+	}
+}
+
+func TestSubstituteMap(t *testing.T) {
+	got := SubstituteMap(map[string]string{"X-{{name}}": "{{value}}"}, map[string]string{"name": "Trace", "value": "1"})
+	if !reflect.DeepEqual(got, map[string]string{"X-Trace": "1"}) {
+		t.Errorf("unexpected map %v", got)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	merged := Merge(
+		map[string]string{"host": "collection", "id": "1"},
+		map[string]string{"host": "environment"},
+	)
+	if merged["host"] != "environment" || merged["id"] != "1" {
+		t.Errorf("expected later sets to take precedence, got %v", merged)
+	}
+}
+
+func TestUnresolved(t *testing.T) {
+	got := Unresolved("{{a}}/{{b}}/{{c}}/{{$uuid}}", map[string]string{"b": ""})
+	if !reflect.DeepEqual(got, []string{"a", "c"}) {
+		t.Errorf("expected [a c], got %v", got)
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	data, err := Encode(map[string]string{"token": "abc"})
+	if err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if vars := Decode(data); vars["token"] != "abc" {
+		t.Errorf("expected token to round trip, got %v", vars)
+	}
+	if vars := Decode("not json"); len(vars) != 0 {
+		t.Errorf("expected malformed data to decode as empty, got %v", vars)
+	}
+	if data, _ := Encode(nil); data != "{}" {
+		t.Errorf("expected empty variables to encode as {}, got %s", data)
+	}
+}
@@ -0,0 +1,277 @@
+// Package cli implements the non-interactive req subcommands. Running req
+// without a subcommand starts the TUI instead.
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/maniac-en/req/internal/backend/collections"
+	"github.com/maniac-en/req/internal/backend/endpoints"
+	"github.com/maniac-en/req/internal/backend/environments"
+	"github.com/maniac-en/req/internal/backend/folders"
+	"github.com/maniac-en/req/internal/backend/history"
+	"github.com/maniac-en/req/internal/backend/runner"
+	"github.com/maniac-en/req/internal/backend/snapshots"
+	"github.com/maniac-en/req/internal/backend/trash"
+)
+
+const usage = `Usage:
+  req [--db FILE | --workspace NAME] [COMMAND]
+                                      use another database, also set with REQ_DB
+                                      or REQ_WORKSPACE
+  req --dir DIR [COMMAND]             work on the collection files in DIR, saving
+                                      changes back to them on exit
+  req --config FILE [COMMAND]         read settings from FILE instead of
+                                      config.toml in the config directory, also
+                                      set with REQ_CONFIG
+  req                                 start the interactive UI
+  req run [--env NAME] [--bail] COLLECTION|FILE.http
+                                      run every endpoint of a collection in order
+  req import [--name COLLECTION] FILE.http...
+                                      load the requests of .http or .rest files into
+                                      the collections named after them
+  req export COLLECTION [FILE.http]   write a collection in the .http format
+  req env list                        list environments
+  req env create NAME                 create an environment
+  req env delete NAME                 delete an environment
+  req env use NAME                    make NAME the active environment
+  req env deactivate                  leave no environment active
+  req env show [NAME]                 print the variables of an environment
+  req env set NAME KEY=VALUE...       set environment variables
+  req env unset NAME KEY...           remove environment variables
+  req diff [--ignore-header NAME]... [--ignore PATH]... ENTRY [ENTRY]
+                                      compare two history entries, or an entry with
+                                      a fresh run of its endpoint; exits 1 on differences
+  req snapshot pin [--ignore PATH]... [--ignore-header NAME]... ENTRY
+                                      pin a history entry as its endpoint's snapshot
+  req snapshot list COLLECTION        list the pinned snapshots of a collection
+  req snapshot delete COLLECTION ENDPOINT
+                                      unpin an endpoint's snapshot
+  req snapshot export COLLECTION DIR  write a collection's snapshots to DIR
+  req snapshot import COLLECTION DIR  pin the snapshots stored in DIR
+  req mock [--port N] [--latency DURATION] [--status CODE] [--routes FILE] COLLECTION
+                                      serve a collection's recorded responses locally
+  req record [--port N] [--mitm] COLLECTION
+                                      record traffic sent through a local proxy into
+                                      COLLECTION, creating it if needed
+  req load [--requests N] [--duration DURATION] [--concurrency N] [--rate R] [--json FILE] [--env NAME] COLLECTION ENDPOINT
+                                      send an endpoint repeatedly and report throughput,
+                                      errors and latency percentiles
+  req code [--lang LANGUAGE] [--copy] [--env NAME] COLLECTION ENDPOINT
+                                      print client code sending an endpoint's request in
+                                      go, python, javascript, httpie or wget
+  req folder list COLLECTION          print the folders and endpoints of a collection
+  req folder create COLLECTION PATH
+                                      create a folder and any missing parents
+  req folder delete COLLECTION PATH
+                                      delete a folder with everything inside it
+  req folder move COLLECTION ENDPOINT PATH
+                                      move an endpoint into a folder, / for the top
+  req folder show COLLECTION PATH     print the headers and variables of a folder
+  req folder set COLLECTION PATH KEY=VALUE...
+                                      set folder variables
+  req folder unset COLLECTION PATH KEY...
+                                      remove folder variables
+  req folder header COLLECTION PATH "NAME: VALUE"...
+                                      set folder headers, an empty value removes one
+  req trash list                      list deleted collections, folders and endpoints
+  req trash restore KIND ID           restore a deleted collection, folder or endpoint
+  req trash empty [--older-than AGE]  permanently delete what is in the trash
+  req revision list COLLECTION ENDPOINT
+                                      list the earlier versions of an endpoint
+  req revision diff COLLECTION ENDPOINT ID
+                                      show what changed since a revision
+  req revision restore COLLECTION ENDPOINT ID
+                                      put an endpoint back the way it was in a revision
+  req workspace list                  list workspaces, marking the open one
+  req history prune [--max-age AGE] [--max-entries N] [--max-size SIZE] [--failed-bodies-only]
+                                      delete history beyond the retention limits
+`
+
+type CLI struct {
+	Collections  *collections.CollectionsManager
+	Environments *environments.EnvironmentsManager
+	History      *history.HistoryManager
+	Snapshots    *snapshots.SnapshotsManager
+	Trash        *trash.TrashManager
+	Runner       *runner.Runner
+	// DataDir holds files req creates besides the database, such as the
+	// proxy CA
+	DataDir string
+	// Workspace names the open database
+	Workspace string
+	Stdout    io.Writer
+	Stderr    io.Writer
+}
+
+func New(
+	collectionsManager *collections.CollectionsManager,
+	environmentsManager *environments.EnvironmentsManager,
+	historyManager *history.HistoryManager,
+	snapshotsManager *snapshots.SnapshotsManager,
+	trashManager *trash.TrashManager,
+	runner *runner.Runner,
+) *CLI {
+	return &CLI{
+		Collections:  collectionsManager,
+		Environments: environmentsManager,
+		History:      historyManager,
+		Snapshots:    snapshotsManager,
+		Trash:        trashManager,
+		Runner:       runner,
+		Stdout:       os.Stdout,
+		Stderr:       os.Stderr,
+	}
+}
+
+// Run executes the subcommand in args and returns the process exit code
+func (c *CLI) Run(ctx context.Context, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(c.Stderr, usage)
+		return 2
+	}
+
+	var err error
+	switch args[0] {
+	case "run":
+		var passed bool
+		passed, err = c.run(ctx, args[1:])
+		if err == nil && !passed {
+			return 1
+		}
+	case "import":
+		err = c.importFile(ctx, args[1:])
+	case "export":
+		err = c.export(ctx, args[1:])
+	case "diff":
+		var equal bool
+		equal, err = c.diff(ctx, args[1:])
+		if err == nil && !equal {
+			return 1
+		}
+	case "env":
+		err = c.env(ctx, args[1:])
+	case "history":
+		err = c.history(ctx, args[1:])
+	case "snapshot":
+		err = c.snapshot(ctx, args[1:])
+	case "mock":
+		err = c.mock(ctx, args[1:])
+	case "record":
+		err = c.record(ctx, args[1:])
+	case "load":
+		err = c.loadTest(ctx, args[1:])
+	case "code":
+		err = c.code(ctx, args[1:])
+	case "folder":
+		err = c.folder(ctx, args[1:])
+	case "trash":
+		err = c.trash(ctx, args[1:])
+	case "revision":
+		err = c.revision(ctx, args[1:])
+	case "workspace":
+		err = c.workspace(args[1:])
+	case "help", "-h", "--help":
+		fmt.Fprint(c.Stdout, usage)
+		return 0
+	default:
+		err = usageError(fmt.Sprintf("unknown command %q", args[0]))
+	}
+
+	if err != nil {
+		fmt.Fprintf(c.Stderr, "req %s: %v\n", args[0], err)
+		if _, ok := err.(usageError); ok {
+			fmt.Fprint(c.Stderr, usage)
+		}
+		return 2
+	}
+	return 0
+}
+
+type usageError string
+
+func (e usageError) Error() string {
+	return string(e)
+}
+
+// parseFlags parses flags that may appear before or after positional
+// arguments and returns the positional ones
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	fs.SetOutput(io.Discard)
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, usageError(err.Error())
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// findEndpoint looks an endpoint of a collection up by name, ignoring case.
+// Names that match no endpoint may be prefixed with a folder path, as in
+// users/admin/List.
+func (c *CLI) findEndpoint(ctx context.Context, collectionID int64, name string) (endpoints.EndpointEntity, error) {
+	all, err := c.Runner.Endpoints.ListByCollection(ctx, collectionID)
+	if err != nil {
+		return endpoints.EndpointEntity{}, err
+	}
+	for _, endpoint := range all {
+		if strings.EqualFold(endpoint.Name, name) {
+			return endpoint, nil
+		}
+	}
+
+	// endpoint names may contain the separator themselves, so try every split
+	if strings.Contains(name, folders.Separator) {
+		tree, err := c.Runner.Folders.Tree(ctx, collectionID)
+		if err != nil {
+			return endpoints.EndpointEntity{}, err
+		}
+		for i := strings.Index(name, folders.Separator); i >= 0; {
+			if folder, ok := tree.Find(name[:i]); ok {
+				for _, endpoint := range all {
+					if endpoint.GetFolderID() == folder.ID && strings.EqualFold(endpoint.Name, name[i+1:]) {
+						return endpoint, nil
+					}
+				}
+			}
+			next := strings.Index(name[i+1:], folders.Separator)
+			if next < 0 {
+				break
+			}
+			i += next + 1
+		}
+	}
+	return endpoints.EndpointEntity{}, fmt.Errorf("endpoint %q not found", name)
+}
+
+// findCollection looks a collection up by ID or by name, ignoring case
+func (c *CLI) findCollection(ctx context.Context, nameOrID string) (collections.CollectionEntity, error) {
+	if id, err := strconv.ParseInt(nameOrID, 10, 64); err == nil {
+		if collection, err := c.Collections.Read(ctx, id); err == nil {
+			return collection, nil
+		}
+	}
+
+	all, err := c.Collections.List(ctx)
+	if err != nil {
+		return collections.CollectionEntity{}, err
+	}
+	for _, collection := range all {
+		if strings.EqualFold(collection.GetName(), nameOrID) {
+			return collection, nil
+		}
+	}
+	return collections.CollectionEntity{}, fmt.Errorf("collection %q not found", nameOrID)
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/maniac-en/req/internal/codegen"
+)
+
+// code prints client code that sends an endpoint's request, or copies it to
+// the clipboard
+func (c *CLI) code(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("code", flag.ContinueOnError)
+	lang := fs.String("lang", "go", "language to generate: "+strings.Join(codegen.Names(), ", "))
+	toClipboard := fs.Bool("copy", false, "copy the code to the clipboard instead of printing it")
+	envName := fs.String("env", "", "environment to use instead of the active one")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 2 {
+		return usageError("expected a collection and an endpoint name")
+	}
+	language, err := codegen.Find(*lang)
+	if err != nil {
+		return usageError(err.Error())
+	}
+
+	collection, err := c.findCollection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	endpoint, err := c.findEndpoint(ctx, collection.ID, positional[1])
+	if err != nil {
+		return err
+	}
+	if *envName != "" {
+		environment, err := c.Environments.ReadByName(ctx, *envName)
+		if err != nil {
+			return fmt.Errorf("environment %q: %w", *envName, err)
+		}
+		c.Runner.EnvironmentID = environment.ID
+	}
+
+	request, err := c.Runner.ClientRequest(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	snippet := language.Generate(request)
+	if !*toClipboard {
+		fmt.Fprint(c.Stdout, snippet)
+		return nil
+	}
+	if err := clipboard.WriteAll(snippet); err != nil {
+		return fmt.Errorf("copying to the clipboard: %w", err)
+	}
+	fmt.Fprintf(c.Stdout, "copied %s code for %s to the clipboard\n", language.Label, endpoint.Name)
+	return nil
+}
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/maniac-en/req/internal/backend/history"
+	"github.com/maniac-en/req/internal/diff"
+)
+
+// stringList collects a flag that may be repeated
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// diff compares two history entries, or an entry with a fresh run of its
+// endpoint, and reports whether the responses match
+func (c *CLI) diff(ctx context.Context, args []string) (bool, error) {
+	var ignoreHeaders, ignorePaths stringList
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	fs.Var(&ignoreHeaders, "ignore-header", "header to leave out of the comparison")
+	fs.Var(&ignorePaths, "ignore", "JSONPath to leave out of the comparison")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return false, err
+	}
+	if len(positional) != 1 && len(positional) != 2 {
+		return false, usageError("expected one or two history entry IDs")
+	}
+	opts := diff.Options{
+		IgnoreHeaders: append(slices.Clone(diff.DefaultIgnoredHeaders), ignoreHeaders...),
+		IgnorePaths:   ignorePaths,
+	}
+	if err := opts.Validate(); err != nil {
+		return false, usageError(err.Error())
+	}
+
+	left, err := c.historyEntry(ctx, positional[0])
+	if err != nil {
+		return false, err
+	}
+
+	var right diff.Response
+	rightLabel := ""
+	if len(positional) == 2 {
+		entry, err := c.historyEntry(ctx, positional[1])
+		if err != nil {
+			return false, err
+		}
+		right = entry.Response()
+		rightLabel = fmt.Sprintf("#%d", entry.ID)
+	} else {
+		result, err := c.Runner.Rerun(ctx, left)
+		if err != nil {
+			return false, err
+		}
+		right = result.Response()
+		rightLabel = "fresh run"
+		if result.HistoryID != 0 {
+			rightLabel = fmt.Sprintf("fresh run #%d", result.HistoryID)
+		}
+	}
+
+	result := diff.Compare(left.Response(), right, opts)
+	fmt.Fprintf(c.Stdout, "#%d %s → %s\n", left.ID, left.GetName(), rightLabel)
+	if result.Equal() {
+		fmt.Fprintln(c.Stdout, "responses match")
+		return true, nil
+	}
+	c.printDiff(result, "")
+	if result.Count() == 1 {
+		fmt.Fprintln(c.Stdout, "\n1 difference")
+	} else {
+		fmt.Fprintf(c.Stdout, "\n%d differences\n", result.Count())
+	}
+	return false, nil
+}
+
+// printDiff lists the differences of a comparison, each line prefixed with
+// indent
+func (c *CLI) printDiff(result diff.Result, indent string) {
+	if result.Status != nil {
+		fmt.Fprintf(c.Stdout, "%sstatus: %s → %s\n", indent, result.Status.Left, result.Status.Right)
+	}
+	if len(result.Headers) > 0 {
+		fmt.Fprintf(c.Stdout, "%sheaders:\n", indent)
+		for _, change := range result.Headers {
+			fmt.Fprintf(c.Stdout, "%s  %s\n", indent, change)
+		}
+	}
+	if len(result.Body) > 0 {
+		fmt.Fprintf(c.Stdout, "%sbody:\n", indent)
+		for _, change := range result.Body {
+			fmt.Fprintf(c.Stdout, "%s  %s\n", indent, change)
+		}
+	}
+}
+
+func (c *CLI) historyEntry(ctx context.Context, value string) (history.HistoryEntity, error) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(value, "#"), 10, 64)
+	if err != nil {
+		return history.HistoryEntity{}, usageError(fmt.Sprintf("invalid history entry ID %q", value))
+	}
+	entry, err := c.History.Read(ctx, id)
+	if err != nil {
+		return history.HistoryEntity{}, fmt.Errorf("history entry %d: %w", id, err)
+	}
+	return entry, nil
+}
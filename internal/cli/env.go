@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/maniac-en/req/internal/backend/environments"
+)
+
+func (c *CLI) env(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return usageError("expected an env subcommand")
+	}
+	command, args := args[0], args[1:]
+
+	switch command {
+	case "list":
+		return c.envList(ctx)
+	case "create":
+		if len(args) != 1 {
+			return usageError("expected an environment name")
+		}
+		_, err := c.Environments.Create(ctx, args[0])
+		return err
+	case "deactivate":
+		return c.Environments.Deactivate(ctx)
+	case "show":
+		if len(args) == 0 {
+			environment, err := c.Environments.GetActive(ctx)
+			if err != nil {
+				return fmt.Errorf("no active environment")
+			}
+			c.printVariables(environment)
+			return nil
+		}
+	}
+
+	if len(args) == 0 {
+		return usageError("expected an environment name")
+	}
+	environment, err := c.Environments.ReadByName(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("environment %q: %w", args[0], err)
+	}
+	args = args[1:]
+
+	switch command {
+	case "delete":
+		return c.Environments.Delete(ctx, environment.ID)
+	case "use":
+		return c.Environments.Activate(ctx, environment.ID)
+	case "show":
+		c.printVariables(environment)
+		return nil
+	case "set":
+		vars := environment.GetVariables()
+		for _, arg := range args {
+			name, value, ok := strings.Cut(arg, "=")
+			if !ok {
+				return usageError(fmt.Sprintf("expected KEY=VALUE, got %q", arg))
+			}
+			vars[name] = value
+		}
+		_, err := c.Environments.SetVariables(ctx, environment.ID, vars)
+		return err
+	case "unset":
+		vars := environment.GetVariables()
+		for _, name := range args {
+			delete(vars, name)
+		}
+		_, err := c.Environments.SetVariables(ctx, environment.ID, vars)
+		return err
+	}
+	return usageError(fmt.Sprintf("unknown env subcommand %q", command))
+}
+
+func (c *CLI) envList(ctx context.Context) error {
+	all, err := c.Environments.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, environment := range all {
+		marker := " "
+		if environment.IsActive() {
+			marker = "*"
+		}
+		fmt.Fprintf(c.Stdout, "%s %s (%d variables)\n", marker, environment.GetName(), len(environment.GetVariables()))
+	}
+	return nil
+}
+
+func (c *CLI) printVariables(environment environments.EnvironmentEntity) {
+	vars := environment.GetVariables()
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(c.Stdout, "%s=%s\n", name, vars[name])
+	}
+}
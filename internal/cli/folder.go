@@ -0,0 +1,171 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/maniac-en/req/internal/backend/collections"
+	"github.com/maniac-en/req/internal/backend/folders"
+)
+
+func (c *CLI) folder(ctx context.Context, args []string) error {
+	if len(args) < 2 {
+		return usageError("expected a folder subcommand and a collection")
+	}
+	command, args := args[0], args[1:]
+
+	collection, err := c.findCollection(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	args = args[1:]
+	tree, err := c.Runner.Folders.Tree(ctx, collection.ID)
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case "list":
+		return c.folderList(ctx, collection, tree)
+	case "create":
+		if len(args) != 1 {
+			return usageError("expected a folder path")
+		}
+		_, err := c.Runner.Folders.CreatePath(ctx, collection.ID, args[0])
+		return err
+	case "move":
+		if len(args) != 2 {
+			return usageError("expected an endpoint and a folder path")
+		}
+		endpoint, err := c.findEndpoint(ctx, collection.ID, args[0])
+		if err != nil {
+			return err
+		}
+		folderID := int64(0)
+		if len(folders.SplitPath(args[1])) > 0 {
+			folder, err := findFolder(tree, args[1])
+			if err != nil {
+				return err
+			}
+			folderID = folder.ID
+		}
+		_, err = c.Runner.Endpoints.MoveToFolder(ctx, endpoint.ID, folderID)
+		return err
+	}
+
+	if len(args) == 0 {
+		return usageError("expected a folder path")
+	}
+	folder, err := findFolder(tree, args[0])
+	if err != nil {
+		return err
+	}
+	args = args[1:]
+
+	switch command {
+	case "delete":
+		return c.Runner.Folders.Delete(ctx, folder.ID)
+	case "show":
+		c.printFolder(folder)
+		return nil
+	case "set":
+		vars := folder.GetVariables()
+		for _, arg := range args {
+			name, value, ok := strings.Cut(arg, "=")
+			if !ok {
+				return usageError(fmt.Sprintf("expected KEY=VALUE, got %q", arg))
+			}
+			vars[name] = value
+		}
+		_, err := c.Runner.Folders.SetVariables(ctx, folder.ID, vars)
+		return err
+	case "unset":
+		vars := folder.GetVariables()
+		for _, name := range args {
+			delete(vars, name)
+		}
+		_, err := c.Runner.Folders.SetVariables(ctx, folder.ID, vars)
+		return err
+	case "header":
+		headers := folder.GetHeaders()
+		for _, arg := range args {
+			name, value, ok := strings.Cut(arg, ":")
+			if !ok {
+				return usageError(fmt.Sprintf("expected \"Name: value\", got %q", arg))
+			}
+			name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+			for existing := range headers {
+				if strings.EqualFold(existing, name) {
+					delete(headers, existing)
+				}
+			}
+			if value != "" {
+				headers[name] = value
+			}
+		}
+		_, err := c.Runner.Folders.SetHeaders(ctx, folder.ID, headers)
+		return err
+	}
+	return usageError(fmt.Sprintf("unknown folder subcommand %q", command))
+}
+
+// folderList prints the folders of a collection as a tree, each followed by
+// its endpoints
+func (c *CLI) folderList(ctx context.Context, collection collections.CollectionEntity, tree *folders.Tree) error {
+	all, err := c.Runner.Endpoints.ListByCollection(ctx, collection.ID)
+	if err != nil {
+		return err
+	}
+	names := make(map[int64][]string)
+	for _, endpoint := range all {
+		names[endpoint.GetFolderID()] = append(names[endpoint.GetFolderID()], endpoint.Name)
+	}
+
+	var print func(folderID int64, depth int)
+	print = func(folderID int64, depth int) {
+		indent := strings.Repeat("  ", depth)
+		for _, folder := range tree.Children(folderID) {
+			fmt.Fprintf(c.Stdout, "%s%s/\n", indent, folder.Name)
+			print(folder.ID, depth+1)
+		}
+		sort.Strings(names[folderID])
+		for _, name := range names[folderID] {
+			fmt.Fprintf(c.Stdout, "%s%s\n", indent, name)
+		}
+	}
+	fmt.Fprintf(c.Stdout, "%s/\n", collection.GetName())
+	print(0, 1)
+	return nil
+}
+
+func (c *CLI) printFolder(folder folders.FolderEntity) {
+	headers := folder.GetHeaders()
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(c.Stdout, "%s: %s\n", name, headers[name])
+	}
+
+	vars := folder.GetVariables()
+	names = names[:0]
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(c.Stdout, "%s=%s\n", name, vars[name])
+	}
+}
+
+func findFolder(tree *folders.Tree, path string) (folders.FolderEntity, error) {
+	folder, ok := tree.Find(path)
+	if !ok {
+		return folders.FolderEntity{}, fmt.Errorf("folder %q not found", path)
+	}
+	return folder, nil
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/maniac-en/req/internal/backend/history"
+)
+
+func (c *CLI) history(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return usageError("expected a history subcommand")
+	}
+
+	switch args[0] {
+	case "prune":
+		return c.historyPrune(ctx, args[1:])
+	}
+	return usageError(fmt.Sprintf("unknown history subcommand %q", args[0]))
+}
+
+// historyPrune applies the configured retention, with flags overriding
+// individual limits
+func (c *CLI) historyPrune(ctx context.Context, args []string) error {
+	retention := c.History.Retention
+
+	fs := flag.NewFlagSet("history prune", flag.ContinueOnError)
+	fs.Func("max-age", "delete entries older than AGE, like 30d", func(value string) error {
+		age, err := history.ParseAge(value)
+		retention.MaxAge = age
+		return err
+	})
+	fs.IntVar(&retention.MaxEntriesPerCollection, "max-entries", retention.MaxEntriesPerCollection, "keep at most N entries per collection")
+	fs.Func("max-size", "keep the newest entries that fit in SIZE, like 100MB", func(value string) error {
+		size, err := history.ParseSize(value)
+		retention.MaxTotalBytes = size
+		return err
+	})
+	fs.BoolVar(&retention.FailedBodiesOnly, "failed-bodies-only", retention.FailedBodiesOnly, "drop bodies of successful requests")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return usageError("history prune takes no arguments")
+	}
+
+	result, err := c.History.Prune(ctx, retention)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Stdout, "deleted %d entries", result.Deleted)
+	if retention.FailedBodiesOnly {
+		fmt.Fprintf(c.Stdout, ", dropped bodies of %d", result.Stripped)
+	}
+	fmt.Fprintf(c.Stdout, "\n%d entries left (%s)\n", result.Entries, history.FormatSize(result.Size))
+	return nil
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/maniac-en/req/internal/backend/collections"
+	"github.com/maniac-en/req/internal/backend/storage"
+)
+
+// importFile loads the requests of .http files as endpoints, each file into
+// the collection named after it
+func (c *CLI) importFile(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	name := fs.String("name", "", "collection to import into instead of the file name")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		return usageError("expected a .http or .rest file")
+	}
+	if *name != "" && len(positional) > 1 {
+		return usageError("--name takes a single file")
+	}
+
+	for _, path := range positional {
+		collection, count, err := c.loadHTTPFile(ctx, path, *name)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(c.Stdout, "imported %s from %s into %s\n", plural(count, "request"), path, collection.GetName())
+	}
+	return nil
+}
+
+// loadHTTPFile reads a .http file into a collection. Importing a file again
+// updates the collection to match it.
+func (c *CLI) loadHTTPFile(ctx context.Context, path, name string) (collections.CollectionEntity, int, error) {
+	if !storage.IsHTTPFile(path) {
+		return collections.CollectionEntity{}, 0, fmt.Errorf("%s: expected a file ending in %s", path, strings.Join(storage.HTTPFileExtensions, " or "))
+	}
+	if name == "" {
+		name = storage.HTTPFileName(path)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return collections.CollectionEntity{}, 0, err
+	}
+	defer file.Close()
+
+	parsed, err := storage.ReadHTTPFile(file, name)
+	if err != nil {
+		return collections.CollectionEntity{}, 0, fmt.Errorf("%s: %w", path, err)
+	}
+	collection, err := c.store().SaveCollection(ctx, parsed)
+	if err != nil {
+		return collections.CollectionEntity{}, 0, err
+	}
+	return collection, countEndpoints(parsed.Items), nil
+}
+
+// export writes a collection as a .http file, or to stdout without a file
+func (c *CLI) export(ctx context.Context, args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return usageError("expected a collection and an optional .http file")
+	}
+	entity, err := c.findCollection(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	collection, err := c.store().LoadCollection(ctx, entity.ID)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = c.Stdout
+	if len(args) == 2 {
+		if !storage.IsHTTPFile(args[1]) {
+			return fmt.Errorf("%s: expected a file ending in %s", args[1], strings.Join(storage.HTTPFileExtensions, " or "))
+		}
+		file, err := os.Create(args[1])
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	skipped, err := storage.WriteHTTPFile(out, collection)
+	if err != nil {
+		return err
+	}
+	for _, name := range skipped {
+		fmt.Fprintf(c.Stderr, "skipped gRPC endpoint %s, the .http format has no place for it\n", name)
+	}
+	if len(args) == 2 {
+		fmt.Fprintf(c.Stdout, "exported %s to %s\n", plural(countEndpoints(collection.Items)-len(skipped), "request"), args[1])
+	}
+	return nil
+}
+
+func (c *CLI) store() *storage.Database {
+	return storage.NewDatabase(c.Collections, c.Runner.Folders, c.Runner.Endpoints)
+}
+
+func countEndpoints(items storage.Items) int {
+	count := len(items.Endpoints)
+	for _, folder := range items.Folders {
+		count += countEndpoints(folder.Items)
+	}
+	return count
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/maniac-en/req/internal/backend/load"
+)
+
+// loadTest sends one endpoint repeatedly and prints a summary, optionally
+// exporting it as JSON. Interrupting the test still prints what was measured.
+func (c *CLI) loadTest(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("load", flag.ContinueOnError)
+	requests := fs.Int("requests", 0, "number of requests to send")
+	duration := fs.Duration("duration", 0, "how long to send requests for")
+	concurrency := fs.Int("concurrency", 10, "requests in flight at once")
+	rate := fs.Float64("rate", 0, "requests started per second")
+	jsonPath := fs.String("json", "", "file to write the report to")
+	envName := fs.String("env", "", "environment to use instead of the active one")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 2 {
+		return usageError("expected a collection and an endpoint name")
+	}
+	options := load.Options{Requests: *requests, Duration: *duration, Concurrency: *concurrency, Rate: *rate}
+	if options.Requests == 0 && options.Duration == 0 {
+		options.Requests = 100
+	}
+	if err := options.Validate(); err != nil {
+		return usageError(err.Error())
+	}
+
+	collection, err := c.findCollection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	endpoint, err := c.findEndpoint(ctx, collection.ID, positional[1])
+	if err != nil {
+		return err
+	}
+	if *envName != "" {
+		environment, err := c.Environments.ReadByName(ctx, *envName)
+		if err != nil {
+			return fmt.Errorf("environment %q: %w", *envName, err)
+		}
+		c.Runner.EnvironmentID = environment.ID
+	}
+
+	fmt.Fprintf(c.Stdout, "load testing %s with %s\n\n", endpoint.Name, describeOptions(options))
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var progress func(load.Report)
+	if isTerminal(c.Stderr) {
+		progress = func(report load.Report) {
+			fmt.Fprintf(c.Stderr, "\r\033[K%s", progressLine(report))
+		}
+	}
+	report, err := c.Runner.Load(ctx, endpoint, options, progress)
+	if progress != nil {
+		fmt.Fprint(c.Stderr, "\r\033[K")
+	}
+	if err != nil {
+		return err
+	}
+
+	c.printLoadReport(report)
+	if *jsonPath != "" {
+		if err := report.WriteFile(*jsonPath); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.Stdout, "\nreport written to %s\n", *jsonPath)
+	}
+	return nil
+}
+
+func describeOptions(options load.Options) string {
+	var limit string
+	switch {
+	case options.Requests > 0 && options.Duration > 0:
+		limit = fmt.Sprintf("%d requests or %s", options.Requests, options.Duration)
+	case options.Requests > 0:
+		limit = fmt.Sprintf("%d requests", options.Requests)
+	default:
+		limit = options.Duration.String()
+	}
+	description := fmt.Sprintf("%s, %d concurrent", limit, options.Concurrency)
+	if options.Rate > 0 {
+		description += fmt.Sprintf(", at most %g/s", options.Rate)
+	}
+	return description
+}
+
+func progressLine(report load.Report) string {
+	return fmt.Sprintf("%d requests  %.1f req/s  %.1f%% errors  p50 %s  p90 %s  p99 %s",
+		report.Requests,
+		report.Throughput(),
+		report.ErrorRate()*100,
+		roundLatency(report.Latency.P50),
+		roundLatency(report.Latency.P90),
+		roundLatency(report.Latency.P99),
+	)
+}
+
+func (c *CLI) printLoadReport(report load.Report) {
+	if !report.Completed {
+		fmt.Fprintln(c.Stdout, "stopped early")
+	}
+	fmt.Fprintf(c.Stdout, "requests     %d in %s\n", report.Requests, report.Elapsed.Round(time.Millisecond))
+	fmt.Fprintf(c.Stdout, "throughput   %.1f req/s\n", report.Throughput())
+	fmt.Fprintf(c.Stdout, "errors       %d (%.1f%%)\n", report.Failed, report.ErrorRate()*100)
+	latency := report.Latency
+	fmt.Fprintf(c.Stdout, "latency      min %s  mean %s  max %s\n", roundLatency(latency.Min), roundLatency(latency.Mean), roundLatency(latency.Max))
+	fmt.Fprintf(c.Stdout, "             p50 %s  p90 %s  p99 %s\n", roundLatency(latency.P50), roundLatency(latency.P90), roundLatency(latency.P99))
+
+	statuses := make([]int, 0, len(report.Statuses))
+	for status := range report.Statuses {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+	for i, status := range statuses {
+		label := ""
+		if i == 0 {
+			label = "statuses"
+		}
+		fmt.Fprintf(c.Stdout, "%-12s %d × %d\n", label, status, report.Statuses[status])
+	}
+
+	messages := make([]string, 0, len(report.Errors))
+	for message := range report.Errors {
+		messages = append(messages, message)
+	}
+	sort.Strings(messages)
+	for _, message := range messages {
+		fmt.Fprintf(c.Stdout, "  %d × %s\n", report.Errors[message], message)
+	}
+}
+
+// roundLatency keeps latencies readable without hiding sub-millisecond ones
+func roundLatency(d time.Duration) time.Duration {
+	if d < 10*time.Millisecond {
+		return d.Round(10 * time.Microsecond)
+	}
+	return d.Round(time.Millisecond)
+}
+
+func isTerminal(w any) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/maniac-en/req/internal/backend/mock"
+)
+
+// mock serves a collection from a local HTTP server until interrupted
+func (c *CLI) mock(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("mock", flag.ContinueOnError)
+	port := fs.Int("port", 8080, "port to listen on")
+	latency := fs.String("latency", "", "delay added to every response")
+	status := fs.Int("status", 0, "status every response answers with")
+	routesFile := fs.String("routes", "", "JSON file of static routes and overrides")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usageError("expected a collection name")
+	}
+	if *status != 0 && (*status < 100 || *status > 999) {
+		return usageError(fmt.Sprintf("invalid status %d", *status))
+	}
+
+	var config mock.Config
+	if *routesFile != "" {
+		if config, err = mock.ReadConfig(*routesFile); err != nil {
+			return err
+		}
+	}
+	// flags win over the routes file
+	if *latency != "" {
+		config.Latency = *latency
+	}
+	if *status != 0 {
+		config.Status = *status
+	}
+	serverLatency, err := mock.ParseLatency(config.Latency)
+	if err != nil {
+		return usageError(err.Error())
+	}
+
+	collection, err := c.findCollection(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	loader := mock.NewLoader(c.Runner.Endpoints, c.Snapshots, c.History)
+	routes, err := loader.Load(ctx, collection.ID, config.Routes)
+	if err != nil {
+		return err
+	}
+	if len(routes) == 0 {
+		return fmt.Errorf("collection %q has no HTTP endpoints to serve", collection.GetName())
+	}
+
+	server := mock.NewServer(routes)
+	server.Latency = serverLatency
+	server.Status = config.Status
+	var mu sync.Mutex
+	server.OnRequest = func(hit mock.Hit) {
+		mu.Lock()
+		defer mu.Unlock()
+		target := "no route"
+		if hit.Route != nil {
+			target = hit.Route.Name
+			if target == "" {
+				target = hit.Route.Pattern
+			}
+			target += " (" + hit.Route.Source + ")"
+		}
+		fmt.Fprintf(c.Stdout, "%s %-7s %s → %d %s\n", time.Now().Format(time.TimeOnly), hit.Method, hit.Path, hit.Status, target)
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort("localhost", strconv.Itoa(*port)))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Stdout, "mocking %s on http://%s\n\n", collection.GetName(), listener.Addr())
+	for _, route := range server.Routes() {
+		name := route.Name
+		if name == "" {
+			name = "-"
+		}
+		fmt.Fprintf(c.Stdout, "  %-7s %-32s %-24s %s\n", route.Method, route.Pattern, name, route.Source)
+	}
+	fmt.Fprintln(c.Stdout)
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	httpServer := &http.Server{Handler: server}
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		httpServer.Shutdown(shutdown)
+	}()
+	if err := httpServer.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
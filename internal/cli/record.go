@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/maniac-en/req/internal/backend/proxy"
+)
+
+// record runs a forward proxy that saves the traffic sent through it into a
+// collection until interrupted
+func (c *CLI) record(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("record", flag.ContinueOnError)
+	port := fs.Int("port", 8888, "port to listen on")
+	mitm := fs.Bool("mitm", false, "intercept HTTPS using a local CA")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usageError("expected a collection name")
+	}
+
+	collection, err := c.findCollection(ctx, positional[0])
+	if err != nil {
+		if collection, err = c.Collections.Create(ctx, positional[0]); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.Stdout, "created collection %s\n", collection.GetName())
+	}
+
+	recorder, err := proxy.NewRecorder(ctx, c.Runner.Endpoints, c.History, collection.ID, collection.GetName())
+	if err != nil {
+		return err
+	}
+	var ca *proxy.CA
+	if *mitm {
+		if ca, err = proxy.LoadOrCreateCA(filepath.Join(c.DataDir, "ca")); err != nil {
+			return err
+		}
+	}
+
+	p := proxy.NewProxy(recorder, ca)
+	var mu sync.Mutex
+	p.OnExchange = func(exchange proxy.Exchange, recorded proxy.Recorded, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		note := recorded.EndpointName
+		switch {
+		case err != nil:
+			note = "not recorded: " + err.Error()
+		case recorded.Created:
+			note = "new endpoint " + note
+		}
+		fmt.Fprintf(c.Stdout, "%s %-7s %s → %d %s\n", time.Now().Format(time.TimeOnly), exchange.Method, exchange.URL, exchange.Status, note)
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort("localhost", strconv.Itoa(*port)))
+	if err != nil {
+		return err
+	}
+	address := "http://" + listener.Addr().String()
+	fmt.Fprintf(c.Stdout, "recording into %s through the proxy at %s\n", collection.GetName(), address)
+	c.printProxySetup(address, ca)
+
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	server := &http.Server{Handler: p}
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		server.Shutdown(shutdown)
+	}()
+	if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (c *CLI) printProxySetup(address string, ca *proxy.CA) {
+	lines := []string{
+		"",
+		"  export HTTP_PROXY=" + address + " HTTPS_PROXY=" + address,
+	}
+	if ca != nil {
+		lines = append(lines,
+			"",
+			"HTTPS is intercepted. Clients must trust the CA certificate:",
+			"  "+ca.CertPath,
+			"  curl --proxy "+address+" --cacert "+ca.CertPath+" https://...",
+		)
+	} else {
+		lines = append(lines, "", "HTTPS is passed through without recording; use --mitm to record it.")
+	}
+	fmt.Fprintln(c.Stdout, strings.Join(lines, "\n"))
+	fmt.Fprintln(c.Stdout)
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/maniac-en/req/internal/backend/endpoints"
+)
+
+func (c *CLI) revision(ctx context.Context, args []string) error {
+	if len(args) < 3 {
+		return usageError("expected a revision subcommand, a collection and an endpoint")
+	}
+	command, args := args[0], args[1:]
+
+	collection, err := c.findCollection(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	endpoint, err := c.findEndpoint(ctx, collection.ID, args[1])
+	if err != nil {
+		return err
+	}
+	args = args[2:]
+
+	if command == "list" {
+		if len(args) > 0 {
+			return usageError("revision list takes a collection and an endpoint")
+		}
+		return c.revisionList(ctx, endpoint)
+	}
+
+	if len(args) != 1 {
+		return usageError("expected a revision ID")
+	}
+	revision, err := c.findRevision(ctx, endpoint, args[0])
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case "diff":
+		for _, change := range endpoints.CompareVersions(revision.Version(), endpoint.Version()) {
+			fmt.Fprintln(c.Stdout, change)
+		}
+		return nil
+	case "restore":
+		_, err := c.Runner.Endpoints.RestoreRevision(ctx, revision.ID)
+		return err
+	}
+	return usageError(fmt.Sprintf("unknown revision subcommand %q", command))
+}
+
+func (c *CLI) revisionList(ctx context.Context, endpoint endpoints.EndpointEntity) error {
+	revisions, err := c.Runner.Endpoints.ListRevisions(ctx, endpoint.ID)
+	if err != nil {
+		return err
+	}
+	for _, revision := range revisions {
+		fmt.Fprintf(c.Stdout, "%5d  %s  %-7s %s  %s\n", revision.ID, revision.GetCreatedAt().Local().Format(time.DateTime), revision.Method, revision.Url, revision.Name)
+	}
+	return nil
+}
+
+// findRevision parses a revision ID, making sure it belongs to the endpoint
+func (c *CLI) findRevision(ctx context.Context, endpoint endpoints.EndpointEntity, arg string) (endpoints.Revision, error) {
+	id, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return endpoints.Revision{}, usageError(fmt.Sprintf("invalid revision ID %q", arg))
+	}
+	revision, err := c.Runner.Endpoints.GetRevision(ctx, id)
+	if err != nil || revision.EndpointID != endpoint.ID {
+		return endpoints.Revision{}, fmt.Errorf("endpoint %q has no revision %d", endpoint.Name, id)
+	}
+	return revision, nil
+}
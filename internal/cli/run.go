@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/maniac-en/req/internal/backend/collections"
+	"github.com/maniac-en/req/internal/backend/runner"
+	"github.com/maniac-en/req/internal/backend/storage"
+)
+
+// run executes a collection, or the requests of a .http file, and reports
+// whether every step passed
+func (c *CLI) run(ctx context.Context, args []string) (bool, error) {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	envName := fs.String("env", "", "environment to use instead of the active one")
+	bail := fs.Bool("bail", false, "stop at the first failed request")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return false, err
+	}
+	if len(positional) != 1 {
+		return false, usageError("expected a collection name or a .http file")
+	}
+
+	// a .http file is loaded into the collection named after it and run from
+	// there, so its runs are kept in the history
+	var collection collections.CollectionEntity
+	if storage.IsHTTPFile(positional[0]) {
+		collection, _, err = c.loadHTTPFile(ctx, positional[0], "")
+	} else {
+		collection, err = c.findCollection(ctx, positional[0])
+	}
+	if err != nil {
+		return false, err
+	}
+	if *envName != "" {
+		environment, err := c.Environments.ReadByName(ctx, *envName)
+		if err != nil {
+			return false, fmt.Errorf("environment %q: %w", *envName, err)
+		}
+		c.Runner.EnvironmentID = environment.ID
+	}
+
+	run, err := c.Runner.RunCollection(ctx, collection.ID, runner.RunOptions{
+		StopOnFailure: *bail,
+		OnStep:        c.printStep,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	passed := len(run.Steps) - run.Failed()
+	fmt.Fprintf(c.Stdout, "\n%d passed, %d failed in %s\n", passed, run.Failed(), run.Duration.Round(time.Millisecond))
+	return run.Passed(), nil
+}
+
+func (c *CLI) printStep(step runner.Step) {
+	mark := "✓"
+	if !step.Passed() {
+		mark = "✗"
+	}
+	if step.Err != nil {
+		fmt.Fprintf(c.Stdout, "%s %s\n    error: %v\n", mark, step.Endpoint.Name, step.Err)
+		return
+	}
+
+	result := step.Result
+	fmt.Fprintf(c.Stdout, "%s %s  %s %s  %s  %s\n",
+		mark,
+		step.Endpoint.Name,
+		strings.ToUpper(result.Endpoint.Method),
+		result.Endpoint.Url,
+		result.Status,
+		result.Duration.Round(time.Millisecond),
+	)
+
+	names := make([]string, 0, len(result.Extracted))
+	for name := range result.Extracted {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(c.Stdout, "    %s = %s\n", name, result.Extracted[name])
+	}
+	for _, err := range result.ExtractionErrors {
+		fmt.Fprintf(c.Stdout, "    extraction failed: %v\n", err)
+	}
+	for _, test := range result.Tests {
+		if test.Passed {
+			fmt.Fprintf(c.Stdout, "    ✓ %s\n", test.Name)
+		} else {
+			fmt.Fprintf(c.Stdout, "    ✗ %s: %s\n", test.Name, test.Error)
+		}
+	}
+	for _, err := range result.ScriptErrors {
+		fmt.Fprintf(c.Stdout, "    script failed: %v\n", err)
+	}
+	for _, entry := range result.Logs {
+		fmt.Fprintf(c.Stdout, "    [%s] %s\n", entry.Level, entry.Message)
+	}
+	if result.Snapshot != nil && !result.Snapshot.Equal() {
+		fmt.Fprintf(c.Stdout, "    snapshot drifted:\n")
+		c.printDiff(*result.Snapshot, "      ")
+	}
+}
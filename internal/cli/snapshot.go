@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/maniac-en/req/internal/diff"
+)
+
+func (c *CLI) snapshot(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return usageError("expected a snapshot subcommand")
+	}
+
+	switch args[0] {
+	case "pin":
+		return c.snapshotPin(ctx, args[1:])
+	case "list":
+		return c.snapshotList(ctx, args[1:])
+	case "delete":
+		return c.snapshotDelete(ctx, args[1:])
+	case "export":
+		return c.snapshotExport(ctx, args[1:])
+	case "import":
+		return c.snapshotImport(ctx, args[1:])
+	}
+	return usageError(fmt.Sprintf("unknown snapshot subcommand %q", args[0]))
+}
+
+// snapshotPin pins a history entry's response to the endpoint that produced
+// it. Ignored fields are only replaced when flags are given.
+func (c *CLI) snapshotPin(ctx context.Context, args []string) error {
+	var ignorePaths, ignoreHeaders stringList
+	fs := flag.NewFlagSet("snapshot pin", flag.ContinueOnError)
+	fs.Var(&ignorePaths, "ignore", "JSONPath to leave out of comparisons")
+	fs.Var(&ignoreHeaders, "ignore-header", "header to leave out of comparisons")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return usageError("expected a history entry ID")
+	}
+	if err := (diff.Options{IgnorePaths: ignorePaths}).Validate(); err != nil {
+		return usageError(err.Error())
+	}
+
+	entry, err := c.historyEntry(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	endpoint, err := c.Runner.EndpointFor(ctx, entry)
+	if err != nil {
+		return err
+	}
+	if _, err := c.Snapshots.Pin(ctx, endpoint.ID, entry.Response()); err != nil {
+		return err
+	}
+	if len(ignorePaths) > 0 || len(ignoreHeaders) > 0 {
+		if _, err := c.Snapshots.SetIgnored(ctx, endpoint.ID, ignorePaths, ignoreHeaders); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(c.Stdout, "pinned #%d as the snapshot of %s\n", entry.ID, endpoint.Name)
+	return nil
+}
+
+func (c *CLI) snapshotList(ctx context.Context, args []string) error {
+	if len(args) != 1 {
+		return usageError("expected a collection name")
+	}
+	collection, err := c.findCollection(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	snapshots, err := c.Snapshots.ListByCollection(ctx, collection.ID)
+	if err != nil {
+		return err
+	}
+	if len(snapshots) == 0 {
+		fmt.Fprintln(c.Stdout, "no snapshots")
+		return nil
+	}
+	for _, snapshot := range snapshots {
+		fmt.Fprintf(c.Stdout, "%s  %d  pinned %s\n", snapshot.EndpointName, snapshot.StatusCode, snapshot.GetUpdatedAt().Local().Format(time.DateTime))
+		for _, path := range snapshot.GetIgnoredPaths() {
+			fmt.Fprintf(c.Stdout, "    ignore %s\n", path)
+		}
+		for _, header := range snapshot.GetIgnoredHeaders() {
+			fmt.Fprintf(c.Stdout, "    ignore header %s\n", header)
+		}
+	}
+	return nil
+}
+
+func (c *CLI) snapshotDelete(ctx context.Context, args []string) error {
+	if len(args) != 2 {
+		return usageError("expected a collection and an endpoint name")
+	}
+	collection, err := c.findCollection(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	endpoint, err := c.findEndpoint(ctx, collection.ID, args[1])
+	if err != nil {
+		return err
+	}
+	if err := c.Snapshots.Delete(ctx, endpoint.ID); err != nil {
+		return fmt.Errorf("%s: %w", endpoint.Name, err)
+	}
+	fmt.Fprintf(c.Stdout, "unpinned the snapshot of %s\n", endpoint.Name)
+	return nil
+}
+
+func (c *CLI) snapshotExport(ctx context.Context, args []string) error {
+	if len(args) != 2 {
+		return usageError("expected a collection and a directory")
+	}
+	collection, err := c.findCollection(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	paths, err := c.Snapshots.Export(ctx, collection.ID, args[1])
+	for _, path := range paths {
+		fmt.Fprintln(c.Stdout, path)
+	}
+	return err
+}
+
+func (c *CLI) snapshotImport(ctx context.Context, args []string) error {
+	if len(args) != 2 {
+		return usageError("expected a collection and a directory")
+	}
+	collection, err := c.findCollection(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	imported, err := c.Snapshots.Import(ctx, collection.ID, args[1])
+	if err != nil {
+		return err
+	}
+	if imported == 1 {
+		fmt.Fprintln(c.Stdout, "imported 1 snapshot")
+	} else {
+		fmt.Fprintf(c.Stdout, "imported %d snapshots\n", imported)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/maniac-en/req/internal/backend/history"
+)
+
+func (c *CLI) trash(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return usageError("expected a trash subcommand")
+	}
+	command, args := args[0], args[1:]
+
+	switch command {
+	case "list":
+		return c.trashList(ctx)
+	case "restore":
+		if len(args) != 2 {
+			return usageError("expected a kind and an ID")
+		}
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return usageError(fmt.Sprintf("invalid ID %q", args[1]))
+		}
+		return c.Trash.Restore(ctx, args[0], id)
+	case "empty":
+		return c.trashEmpty(ctx, args)
+	}
+	return usageError(fmt.Sprintf("unknown trash subcommand %q", command))
+}
+
+func (c *CLI) trashList(ctx context.Context) error {
+	items, err := c.Trash.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		name := item.Name
+		if item.Location != "" {
+			name = item.Location + "/" + item.Name
+		}
+		fmt.Fprintf(c.Stdout, "%-10s %5d  %s  %s\n", item.Kind, item.ID, item.DeletedAt.Local().Format(time.DateTime), name)
+	}
+	return nil
+}
+
+func (c *CLI) trashEmpty(ctx context.Context, args []string) error {
+	var olderThan time.Duration
+	fs := flag.NewFlagSet("trash empty", flag.ContinueOnError)
+	fs.Func("older-than", "only delete items trashed more than AGE ago, like 30d", func(value string) error {
+		age, err := history.ParseAge(value)
+		olderThan = age
+		return err
+	})
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return usageError("trash empty takes no arguments")
+	}
+
+	deleted, err := c.Trash.Empty(ctx, olderThan)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(c.Stdout, "permanently deleted %d items\n", deleted)
+	return nil
+}
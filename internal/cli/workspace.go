@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/maniac-en/req/internal/workspace"
+)
+
+func (c *CLI) workspace(args []string) error {
+	if len(args) != 1 || args[0] != "list" {
+		return usageError("expected workspace list")
+	}
+
+	names, err := workspace.List(c.DataDir)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		marker := " "
+		if name == c.Workspace {
+			marker = "*"
+		}
+		fmt.Fprintf(c.Stdout, "%s %s\n", marker, name)
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+// Package codegen turns a request into client code that sends the same
+// request, so a call can be handed to someone who doesn't use req.
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Request is an HTTP request as it goes over the wire: the URL already
+// carries the query string and the body is only set when it is sent.
+type Request struct {
+	Method  string
+	URL     string
+	Headers map[string]string
+	Body    string
+}
+
+type Language struct {
+	// Name identifies the language on the command line
+	Name string
+	// Label describes the language and the client it uses
+	Label    string
+	generate func(Request) string
+}
+
+// Generate returns a snippet sending the request
+func (l Language) Generate(request Request) string {
+	request.Method = strings.ToUpper(request.Method)
+	return l.generate(request)
+}
+
+// Languages lists the supported languages in the order they are offered
+var Languages = []Language{
+	{Name: "go", Label: "Go (net/http)", generate: goSnippet},
+	{Name: "python", Label: "Python (requests)", generate: pythonSnippet},
+	{Name: "javascript", Label: "JavaScript (fetch)", generate: javascriptSnippet},
+	{Name: "httpie", Label: "HTTPie", generate: httpieSnippet},
+	{Name: "wget", Label: "wget", generate: wgetSnippet},
+}
+
+// Find returns the language with the given name
+func Find(name string) (Language, error) {
+	for _, language := range Languages {
+		if strings.EqualFold(language.Name, name) {
+			return language, nil
+		}
+	}
+	return Language{}, fmt.Errorf("unknown language %q, expected one of %s", name, strings.Join(Names(), ", "))
+}
+
+// Names returns the names of the supported languages
+func Names() []string {
+	names := make([]string, len(Languages))
+	for i, language := range Languages {
+		names[i] = language.Name
+	}
+	return names
+}
+
+type header struct {
+	name  string
+	value string
+}
+
+// headers returns the request headers sorted by name so snippets are stable
+func (r Request) headers() []header {
+	names := make([]string, 0, len(r.Headers))
+	for name := range r.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	headers := make([]header, len(names))
+	for i, name := range names {
+		headers[i] = header{name: name, value: r.Headers[name]}
+	}
+	return headers
+}
+
+// quote returns a double-quoted string literal valid in Go, Python and
+// JavaScript
+func quote(s string) string {
+	var b bytes.Buffer
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(s); err != nil {
+		return `""`
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// shellQuote quotes a word for POSIX shells
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func goSnippet(r Request) string {
+	var b strings.Builder
+	b.WriteString("package main\n\nimport (\n\t\"fmt\"\n\t\"io\"\n\t\"net/http\"\n")
+	if r.Body != "" {
+		b.WriteString("\t\"strings\"\n")
+	}
+	b.WriteString(")\n\nfunc main() {\n")
+
+	body := "nil"
+	if r.Body != "" {
+		literal := "`" + r.Body + "`"
+		// raw strings can't hold backticks and drop carriage returns
+		if strings.ContainsAny(r.Body, "`\r") {
+			literal = quote(r.Body)
+		}
+		fmt.Fprintf(&b, "\tbody := strings.NewReader(%s)\n", literal)
+		body = "body"
+	}
+	fmt.Fprintf(&b, "\treq, err := http.NewRequest(%s, %s, %s)\n", quote(r.Method), quote(r.URL), body)
+	b.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	for _, h := range r.headers() {
+		fmt.Fprintf(&b, "\treq.Header.Set(%s, %s)\n", quote(h.name), quote(h.value))
+	}
+	b.WriteString("\n\tresp, err := http.DefaultClient.Do(req)\n")
+	b.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	b.WriteString("\tdefer resp.Body.Close()\n\n")
+	b.WriteString("\tdata, err := io.ReadAll(resp.Body)\n")
+	b.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	b.WriteString("\tfmt.Println(resp.Status)\n")
+	b.WriteString("\tfmt.Println(string(data))\n")
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func pythonSnippet(r Request) string {
+	var b strings.Builder
+	b.WriteString("import requests\n\n")
+
+	args := []string{quote(r.Method), quote(r.URL)}
+	if headers := r.headers(); len(headers) > 0 {
+		b.WriteString("headers = {\n")
+		for _, h := range headers {
+			fmt.Fprintf(&b, "    %s: %s,\n", quote(h.name), quote(h.value))
+		}
+		b.WriteString("}\n")
+		args = append(args, "headers=headers")
+	}
+	if r.Body != "" {
+		fmt.Fprintf(&b, "data = %s\n", quote(r.Body))
+		args = append(args, "data=data")
+	}
+	if len(args) > 2 {
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "response = requests.request(%s)\n", strings.Join(args, ", "))
+	b.WriteString("print(response.status_code)\n")
+	b.WriteString("print(response.text)\n")
+	return b.String()
+}
+
+func javascriptSnippet(r Request) string {
+	var b strings.Builder
+	var options []string
+	if r.Method != "GET" {
+		options = append(options, "  method: "+quote(r.Method)+",\n")
+	}
+	if headers := r.headers(); len(headers) > 0 {
+		var h strings.Builder
+		h.WriteString("  headers: {\n")
+		for _, header := range headers {
+			fmt.Fprintf(&h, "    %s: %s,\n", quote(header.name), quote(header.value))
+		}
+		h.WriteString("  },\n")
+		options = append(options, h.String())
+	}
+	if r.Body != "" {
+		options = append(options, "  body: "+quote(r.Body)+",\n")
+	}
+
+	if len(options) == 0 {
+		fmt.Fprintf(&b, "const response = await fetch(%s);\n", quote(r.URL))
+	} else {
+		fmt.Fprintf(&b, "const response = await fetch(%s, {\n%s});\n", quote(r.URL), strings.Join(options, ""))
+	}
+	b.WriteString("console.log(response.status);\n")
+	b.WriteString("console.log(await response.text());\n")
+	return b.String()
+}
+
+func httpieSnippet(r Request) string {
+	words := []string{"http"}
+	// HTTPie switches to POST when a body is piped in, so the method is
+	// spelled out whenever there is one
+	if r.Method != "GET" || r.Body != "" {
+		words = append(words, r.Method)
+	}
+	words = append(words, shellQuote(r.URL))
+	for _, h := range r.headers() {
+		words = append(words, shellQuote(h.name+":"+h.value))
+	}
+	command := shellLines(words)
+	if r.Body != "" {
+		command = "printf '%s' " + shellQuote(r.Body) + " | " + command
+	}
+	return command + "\n"
+}
+
+func wgetSnippet(r Request) string {
+	words := []string{"wget", "--quiet", "--output-document=-"}
+	if r.Method != "GET" {
+		words = append(words, "--method="+r.Method)
+	}
+	for _, h := range r.headers() {
+		words = append(words, "--header="+shellQuote(h.name+": "+h.value))
+	}
+	if r.Body != "" {
+		words = append(words, "--body-data="+shellQuote(r.Body))
+	}
+	words = append(words, shellQuote(r.URL))
+	return shellLines(words) + "\n"
+}
+
+// shellLines joins a command's words, continuing it over several lines once
+// it has more than a few arguments
+func shellLines(words []string) string {
+	if len(words) <= 4 {
+		return strings.Join(words, " ")
+	}
+	return strings.Join(words, " \\\n  ")
+}
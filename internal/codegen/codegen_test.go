@@ -0,0 +1,136 @@
+package codegen
+
+import (
+	"go/format"
+	"strings"
+	"testing"
+)
+
+var post = Request{
+	Method: "post",
+	URL:    "https://api.example.com/users?team=a&x=1",
+	Headers: map[string]string{
+		"Content-Type":  "application/json",
+		"Authorization": "Bearer it's",
+	},
+	Body: `{"name": "ann", "bio": "line\nbreak"}`,
+}
+
+var get = Request{Method: "GET", URL: "https://api.example.com/users"}
+
+func generate(t *testing.T, name string, request Request) string {
+	t.Helper()
+	language, err := Find(name)
+	if err != nil {
+		t.Fatalf("Expected %s to be supported: %v", name, err)
+	}
+	return language.Generate(request)
+}
+
+func TestGo(t *testing.T) {
+	snippet := generate(t, "go", post)
+	if _, err := format.Source([]byte(snippet)); err != nil {
+		t.Fatalf("Expected valid Go, got %v:\n%s", err, snippet)
+	}
+	for _, expected := range []string{
+		"body := strings.NewReader(`{\"name\": \"ann\", \"bio\": \"line\\nbreak\"}`)",
+		`req, err := http.NewRequest("POST", "https://api.example.com/users?team=a&x=1", body)`,
+		`req.Header.Set("Authorization", "Bearer it's")`,
+	} {
+		if !strings.Contains(snippet, expected) {
+			t.Errorf("Expected snippet to contain %q:\n%s", expected, snippet)
+		}
+	}
+
+	snippet = generate(t, "go", get)
+	if _, err := format.Source([]byte(snippet)); err != nil {
+		t.Fatalf("Expected valid Go, got %v:\n%s", err, snippet)
+	}
+	if strings.Contains(snippet, "strings") {
+		t.Errorf("Expected no strings import without a body:\n%s", snippet)
+	}
+
+	snippet = generate(t, "go", Request{Method: "PUT", URL: "http://x", Body: "a `tick`"})
+	if !strings.Contains(snippet, `strings.NewReader("a `+"`tick`"+`")`) {
+		t.Errorf("Expected bodies with backticks to be quoted:\n%s", snippet)
+	}
+}
+
+func TestPython(t *testing.T) {
+	expected := `import requests
+
+headers = {
+    "Authorization": "Bearer it's",
+    "Content-Type": "application/json",
+}
+data = "{\"name\": \"ann\", \"bio\": \"line\\nbreak\"}"
+
+response = requests.request("POST", "https://api.example.com/users?team=a&x=1", headers=headers, data=data)
+print(response.status_code)
+print(response.text)
+`
+	if snippet := generate(t, "python", post); snippet != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, snippet)
+	}
+}
+
+func TestJavaScript(t *testing.T) {
+	expected := `const response = await fetch("https://api.example.com/users?team=a&x=1", {
+  method: "POST",
+  headers: {
+    "Authorization": "Bearer it's",
+    "Content-Type": "application/json",
+  },
+  body: "{\"name\": \"ann\", \"bio\": \"line\\nbreak\"}",
+});
+console.log(response.status);
+console.log(await response.text());
+`
+	if snippet := generate(t, "javascript", post); snippet != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, snippet)
+	}
+
+	snippet := generate(t, "javascript", get)
+	if !strings.HasPrefix(snippet, `const response = await fetch("https://api.example.com/users");`) {
+		t.Errorf("Expected a bare fetch for a plain GET:\n%s", snippet)
+	}
+}
+
+func TestHTTPie(t *testing.T) {
+	expected := `printf '%s' '{"name": "ann", "bio": "line\nbreak"}' | http \
+  POST \
+  'https://api.example.com/users?team=a&x=1' \
+  'Authorization:Bearer it'\''s' \
+  'Content-Type:application/json'
+`
+	if snippet := generate(t, "httpie", post); snippet != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, snippet)
+	}
+	if snippet := generate(t, "httpie", get); snippet != "http 'https://api.example.com/users'\n" {
+		t.Errorf("Expected a one-line GET, got %q", snippet)
+	}
+}
+
+func TestWget(t *testing.T) {
+	expected := `wget \
+  --quiet \
+  --output-document=- \
+  --method=POST \
+  --header='Authorization: Bearer it'\''s' \
+  --header='Content-Type: application/json' \
+  --body-data='{"name": "ann", "bio": "line\nbreak"}' \
+  'https://api.example.com/users?team=a&x=1'
+`
+	if snippet := generate(t, "wget", post); snippet != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, snippet)
+	}
+}
+
+func TestFind(t *testing.T) {
+	if language, err := Find("Python"); err != nil || language.Name != "python" {
+		t.Errorf("Expected names to match case-insensitively, got %v %v", language, err)
+	}
+	if _, err := Find("cobol"); err == nil || !strings.Contains(err.Error(), "go, python, javascript, httpie, wget") {
+		t.Errorf("Expected an error listing the languages, got %v", err)
+	}
+}
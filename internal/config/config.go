@@ -0,0 +1,306 @@
+// Package config reads the settings file loaded when req starts. The file is
+// TOML, or YAML when its name ends in .yaml or .yml, and every setting is
+// optional:
+//
+//	keymap = "vim"    # or emacs, on top of the [keys] below
+//	theme = "auto"    # dark, light, high-contrast or one of [themes]
+//
+//	[http]
+//	timeout = "30s"
+//
+//	[log]
+//	level = "info"
+//	max_size = 10     # megabytes before the log is rotated
+//	max_backups = 2
+//	max_age = 7       # days
+//	compress = true
+//
+//	[history]
+//	max_age = "30d"
+//	max_entries = 1000
+//	max_size = "100MB"
+//	failed_bodies_only = false
+//
+//	[paths]
+//	data = "~/api/req"
+//	log = "/tmp/req.log"
+//
+//	[themes.ocean]
+//	base = "dark"
+//	accent = "#5FAFFF"
+//
+//	[colors]          # on top of the theme
+//	danger = "#FF5F00"
+//
+//	[keys]
+//	send = ["ctrl+r", "r"]
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/maniac-en/req/internal/backend/history"
+	"github.com/maniac-en/req/internal/log"
+	"gopkg.in/yaml.v3"
+)
+
+// fileNames are looked for in the config directory, in order
+var fileNames = []string{"config.toml", "config.yaml", "config.yml"}
+
+type Config struct {
+	HTTP    HTTP    `toml:"http" yaml:"http"`
+	Log     Log     `toml:"log" yaml:"log"`
+	History History `toml:"history" yaml:"history"`
+	Paths   Paths   `toml:"paths" yaml:"paths"`
+	// Theme names a built-in theme or one of Themes
+	Theme string `toml:"theme" yaml:"theme"`
+	// Themes are palettes of colors by name, on top of the built-in theme
+	// named by their "base"
+	Themes map[string]map[string]string `toml:"themes" yaml:"themes"`
+	// Colors replace colors of the theme by name
+	Colors map[string]string `toml:"colors" yaml:"colors"`
+	// Keymap names a preset of key bindings, which Keys then override
+	Keymap string `toml:"keymap" yaml:"keymap"`
+	// Keys binds actions by name to the keys that trigger them
+	Keys map[string][]string `toml:"keys" yaml:"keys"`
+
+	// Path is the file the settings were read from, empty when there was none
+	Path string `toml:"-" yaml:"-"`
+}
+
+type HTTP struct {
+	// Timeout bounds each HTTP and gRPC request
+	Timeout Duration `toml:"timeout" yaml:"timeout"`
+}
+
+type Log struct {
+	Level string `toml:"level" yaml:"level"`
+	// MaxSize is the size in megabytes at which the log is rotated
+	MaxSize    int  `toml:"max_size" yaml:"max_size"`
+	MaxBackups int  `toml:"max_backups" yaml:"max_backups"`
+	MaxAge     int  `toml:"max_age" yaml:"max_age"`
+	Compress   bool `toml:"compress" yaml:"compress"`
+}
+
+// History holds the retention policy in the same form as the
+// REQ_HISTORY_* variables
+type History struct {
+	MaxAge           string `toml:"max_age" yaml:"max_age"`
+	MaxEntries       int    `toml:"max_entries" yaml:"max_entries"`
+	MaxSize          string `toml:"max_size" yaml:"max_size"`
+	FailedBodiesOnly bool   `toml:"failed_bodies_only" yaml:"failed_bodies_only"`
+}
+
+type Paths struct {
+	// Data replaces the data directory holding the databases
+	Data string `toml:"data" yaml:"data"`
+	// Log replaces the log file in the cache directory
+	Log string `toml:"log" yaml:"log"`
+}
+
+// Duration reads durations written as "30s" or "1m30s"
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	duration, err := time.ParseDuration(string(text))
+	if err != nil {
+		return fmt.Errorf("expected a duration like 30s or 2m, got %q", text)
+	}
+	d.Duration = duration
+	return nil
+}
+
+// Default returns the settings used when there is no config file
+func Default() Config {
+	rotation := log.DefaultRotation()
+	return Config{
+		HTTP: HTTP{Timeout: Duration{30 * time.Second}},
+		Log: Log{
+			Level:      "info",
+			MaxSize:    rotation.MaxSize,
+			MaxBackups: rotation.MaxBackups,
+			MaxAge:     rotation.MaxAge,
+			Compress:   rotation.Compress,
+		},
+		Theme: "auto",
+	}
+}
+
+// DefaultPath returns the config file to read when none is given: the first
+// of config.toml, config.yaml and config.yml in the user's config directory,
+// or config.toml when none exists
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	for _, name := range fileNames {
+		path := filepath.Join(dir, "req", name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return filepath.Join(dir, "req", fileNames[0]), nil
+}
+
+// Load reads the config file at path on top of the defaults. A missing file
+// is only an error when required is set, as it is for a file the user named.
+func Load(path string, required bool) (Config, error) {
+	cfg := Default()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && !required {
+			return cfg, nil
+		}
+		return Config{}, fmt.Errorf("config %s: %w", path, err)
+	}
+
+	if err := decode(path, data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("config %s: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, fmt.Errorf("config %s: %w", path, err)
+	}
+	cfg.Path = path
+	return cfg, nil
+}
+
+// decode rejects settings it does not know, so typos don't go unnoticed
+func decode(path string, data []byte, cfg *Config) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		// an empty file decodes to nothing
+		if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		return nil
+	}
+
+	meta, err := toml.Decode(string(data), cfg)
+	if err != nil {
+		return err
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, len(undecoded))
+		for i, key := range undecoded {
+			keys[i] = key.String()
+		}
+		return fmt.Errorf("unknown setting %s", strings.Join(keys, ", "))
+	}
+	return nil
+}
+
+// Validate checks the values that decoding alone accepts
+func (c Config) Validate() error {
+	var errs []error
+	if c.HTTP.Timeout.Duration <= 0 {
+		errs = append(errs, fmt.Errorf("http.timeout: expected a positive duration, got %s", c.HTTP.Timeout.Duration))
+	}
+	if _, err := c.LogLevel(); err != nil {
+		errs = append(errs, err)
+	}
+	if c.Log.MaxSize <= 0 {
+		errs = append(errs, fmt.Errorf("log.max_size: expected a size in megabytes above zero, got %d", c.Log.MaxSize))
+	}
+	for name, value := range map[string]int{"log.max_backups": c.Log.MaxBackups, "log.max_age": c.Log.MaxAge, "history.max_entries": c.History.MaxEntries} {
+		if value < 0 {
+			errs = append(errs, fmt.Errorf("%s: expected zero or more, got %d", name, value))
+		}
+	}
+	if _, err := c.Retention(); err != nil {
+		errs = append(errs, err)
+	}
+	for name, value := range map[string]string{"paths.data": c.Paths.Data, "paths.log": c.Paths.Log} {
+		if value != "" && !filepath.IsAbs(value) && !strings.HasPrefix(value, "~/") {
+			errs = append(errs, fmt.Errorf("%s: expected an absolute path or one starting with ~/, got %q", name, value))
+		}
+	}
+	// map iteration order would shuffle the messages
+	slices.SortFunc(errs, func(a, b error) int {
+		return strings.Compare(a.Error(), b.Error())
+	})
+	return errors.Join(errs...)
+}
+
+var logLevels = map[string]slog.Level{
+	"debug": slog.LevelDebug,
+	"info":  slog.LevelInfo,
+	"warn":  slog.LevelWarn,
+	"error": slog.LevelError,
+}
+
+// Rotation returns the log rotation of the [log] section
+func (c Config) Rotation() log.Rotation {
+	return log.Rotation{
+		MaxSize:    c.Log.MaxSize,
+		MaxBackups: c.Log.MaxBackups,
+		MaxAge:     c.Log.MaxAge,
+		Compress:   c.Log.Compress,
+	}
+}
+
+func (c Config) LogLevel() (slog.Level, error) {
+	level, err := ParseLogLevel(c.Log.Level)
+	if err != nil {
+		return 0, fmt.Errorf("log.level: %w", err)
+	}
+	return level, nil
+}
+
+// ParseLogLevel reads a level written as debug, info, warn or error
+func ParseLogLevel(value string) (slog.Level, error) {
+	level, ok := logLevels[strings.ToLower(value)]
+	if !ok {
+		return 0, fmt.Errorf("expected debug, info, warn or error, got %q", value)
+	}
+	return level, nil
+}
+
+// Retention returns the history retention policy of the [history] section
+func (c Config) Retention() (history.Retention, error) {
+	retention := history.DefaultRetention()
+	if c.History.MaxAge != "" {
+		age, err := history.ParseAge(c.History.MaxAge)
+		if err != nil {
+			return retention, fmt.Errorf("history.max_age: %w", err)
+		}
+		retention.MaxAge = age
+	}
+	if c.History.MaxSize != "" {
+		size, err := history.ParseSize(c.History.MaxSize)
+		if err != nil {
+			return retention, fmt.Errorf("history.max_size: %w", err)
+		}
+		retention.MaxTotalBytes = size
+	}
+	retention.MaxEntriesPerCollection = c.History.MaxEntries
+	retention.FailedBodiesOnly = c.History.FailedBodiesOnly
+	return retention, nil
+}
+
+// ExpandPath resolves a leading ~/ in a configured path
+func ExpandPath(path string) (string, error) {
+	rest, ok := strings.CutPrefix(path, "~/")
+	if !ok {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, rest), nil
+}
@@ -0,0 +1,193 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/maniac-en/req/internal/backend/history"
+)
+
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("Missing file keeps the defaults", func(t *testing.T) {
+		cfg, err := Load(filepath.Join(t.TempDir(), "config.toml"), false)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if !reflect.DeepEqual(cfg, Default()) {
+			t.Errorf("Expected the defaults, got %+v", cfg)
+		}
+		// upgrading must not delete history nobody asked to limit
+		if retention, err := cfg.Retention(); err != nil || retention != (history.Retention{}) {
+			t.Errorf("Expected no history limits, got %+v, %v", retention, err)
+		}
+	})
+
+	t.Run("Missing file named by the user", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.toml")
+		_, err := Load(path, true)
+		if err == nil || !strings.Contains(err.Error(), path) {
+			t.Errorf("Expected an error naming %s, got %v", path, err)
+		}
+	})
+
+	t.Run("TOML", func(t *testing.T) {
+		path := writeConfig(t, "config.toml", `
+keymap = "vim"
+theme = "ocean"
+
+[http]
+timeout = "5s"
+
+[log]
+level = "debug"
+max_backups = 0
+
+[history]
+max_entries = 100
+max_size = "1MB"
+
+[paths]
+data = "~/req"
+
+[themes.ocean]
+base = "light"
+accent = "#0087AF"
+
+[colors]
+accent = "#FFFFFF"
+
+[keys]
+send = ["ctrl+r", "r"]
+`)
+		cfg, err := Load(path, true)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if cfg.Path != path {
+			t.Errorf("Expected path %s, got %s", path, cfg.Path)
+		}
+		if cfg.HTTP.Timeout.Duration != 5*time.Second {
+			t.Errorf("Expected a 5s timeout, got %s", cfg.HTTP.Timeout)
+		}
+		if level, _ := cfg.LogLevel(); level != slog.LevelDebug {
+			t.Errorf("Expected debug level, got %s", level)
+		}
+		if cfg.Log.MaxBackups != 0 || cfg.Log.MaxSize != Default().Log.MaxSize {
+			t.Errorf("Expected max_backups 0 and the default max_size, got %+v", cfg.Log)
+		}
+		retention, err := cfg.Retention()
+		if err != nil {
+			t.Fatalf("Retention failed: %v", err)
+		}
+		if retention.MaxEntriesPerCollection != 100 || retention.MaxTotalBytes != 1<<20 || retention.MaxAge != 0 {
+			t.Errorf("Expected 100 entries, 1MB and no age limit, got %+v", retention)
+		}
+		if cfg.Theme != "ocean" || cfg.Themes["ocean"]["base"] != "light" || cfg.Colors["accent"] != "#FFFFFF" {
+			t.Errorf("Expected the ocean theme and the accent color, got %q, %v and %v", cfg.Theme, cfg.Themes, cfg.Colors)
+		}
+		if !reflect.DeepEqual(cfg.Keys["send"], []string{"ctrl+r", "r"}) || cfg.Keymap != "vim" {
+			t.Errorf("Expected the vim keymap and the send keys, got %q and %v", cfg.Keymap, cfg.Keys)
+		}
+	})
+
+	t.Run("YAML", func(t *testing.T) {
+		path := writeConfig(t, "config.yaml", `
+http:
+  timeout: 1m
+log:
+  compress: false
+keys:
+  send: [ctrl+s]
+`)
+		cfg, err := Load(path, true)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if cfg.HTTP.Timeout.Duration != time.Minute {
+			t.Errorf("Expected a 1m timeout, got %s", cfg.HTTP.Timeout)
+		}
+		if cfg.Log.Compress || cfg.Log.Level != "info" {
+			t.Errorf("Expected compression off and the default level, got %+v", cfg.Log)
+		}
+		if !reflect.DeepEqual(cfg.Keys["send"], []string{"ctrl+s"}) {
+			t.Errorf("Expected the send keys, got %v", cfg.Keys)
+		}
+	})
+
+	t.Run("Empty YAML file", func(t *testing.T) {
+		cfg, err := Load(writeConfig(t, "config.yml", ""), true)
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if cfg.HTTP != Default().HTTP {
+			t.Errorf("Expected the defaults, got %+v", cfg)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    string
+	}{
+		{"Unknown TOML setting", "config.toml", "[http]\ntimeuot = \"5s\"\n", "unknown setting http.timeuot"},
+		{"Unknown YAML setting", "config.yaml", "log:\n  levle: debug\n", "field levle not found"},
+		{"TOML syntax", "config.toml", "[http\n", "line 2"},
+		{"Bad duration", "config.toml", "[http]\ntimeout = \"soon\"\n", "expected a duration"},
+		{"Zero timeout", "config.toml", "[http]\ntimeout = \"0s\"\n", "http.timeout"},
+		{"Bad level", "config.toml", "[log]\nlevel = \"loud\"\n", "log.level"},
+		{"Negative backups", "config.toml", "[log]\nmax_backups = -1\n", "log.max_backups"},
+		{"Zero size", "config.toml", "[log]\nmax_size = 0\n", "log.max_size"},
+		{"Bad age", "config.toml", "[history]\nmax_age = \"forever\"\n", "history.max_age"},
+		{"Relative path", "config.toml", "[paths]\ndata = \"req\"\n", "paths.data"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfig(t, tt.file, tt.content)
+			_, err := Load(path, true)
+			if err == nil {
+				t.Fatal("Expected an error")
+			}
+			if !strings.Contains(err.Error(), tt.want) || !strings.HasPrefix(err.Error(), "config "+path) {
+				t.Errorf("Expected an error for %s mentioning %q, got %v", path, tt.want, err)
+			}
+		})
+	}
+
+	t.Run("Every mistake is reported", func(t *testing.T) {
+		_, err := Load(writeConfig(t, "config.toml", "[http]\ntimeout = \"-1s\"\n[log]\nlevel = \"loud\"\n"), true)
+		if err == nil || !strings.Contains(err.Error(), "http.timeout") || !strings.Contains(err.Error(), "log.level") {
+			t.Errorf("Expected both mistakes, got %v", err)
+		}
+	})
+}
+
+func TestExpandPath(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	for path, want := range map[string]string{
+		"~/req":    filepath.Join(home, "req"),
+		"/var/req": "/var/req",
+	} {
+		got, err := ExpandPath(path)
+		if err != nil || got != want {
+			t.Errorf("Expected %s for %s, got %s (%v)", want, path, got, err)
+		}
+	}
+}
@@ -0,0 +1,332 @@
+// Package diff compares two responses. JSON bodies are compared structurally
+// so key order and formatting never count as a difference; other bodies are
+// compared line by line. Headers are compared by name, ignoring case.
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/maniac-en/req/internal/query"
+)
+
+type Kind string
+
+const (
+	Added   Kind = "added"
+	Removed Kind = "removed"
+	Changed Kind = "changed"
+)
+
+// Change is a single difference. Path is a JSONPath for JSON bodies, a
+// lowercase header name for headers and "line N" for text bodies. Left and
+// Right hold the rendered values, empty on the side the value is missing.
+type Change struct {
+	Path  string
+	Kind  Kind
+	Left  string
+	Right string
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case Added:
+		return fmt.Sprintf("+ %s: %s", c.Path, c.Right)
+	case Removed:
+		return fmt.Sprintf("- %s: %s", c.Path, c.Left)
+	}
+	return fmt.Sprintf("~ %s: %s → %s", c.Path, c.Left, c.Right)
+}
+
+type Response struct {
+	Status  int
+	Headers map[string][]string
+	Body    string
+}
+
+type Options struct {
+	// IgnoreHeaders are header names left out of the comparison
+	IgnoreHeaders []string
+	// IgnorePaths are JSONPaths whose values, including everything nested
+	// below them, are left out of the comparison. They are evaluated against
+	// both bodies, so `$.items[*].id` and `$..id` ignore every match.
+	IgnorePaths []string
+}
+
+// Validate reports the first ignored path that does not parse. Compare
+// skips such paths.
+func (o Options) Validate() error {
+	for _, path := range o.IgnorePaths {
+		if _, err := query.Compile(path); err != nil {
+			return fmt.Errorf("invalid ignored path %q: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// DefaultIgnoredHeaders change on every response, so comparing them would
+// make any two responses differ
+var DefaultIgnoredHeaders = []string{"Date", "Age", "Expires"}
+
+type Result struct {
+	// Status is nil when both responses have the same status
+	Status  *Change
+	Headers []Change
+	Body    []Change
+	// JSON reports whether the bodies were compared structurally
+	JSON bool
+}
+
+func (r Result) Equal() bool {
+	return r.Count() == 0
+}
+
+func (r Result) Count() int {
+	count := len(r.Headers) + len(r.Body)
+	if r.Status != nil {
+		count++
+	}
+	return count
+}
+
+// Compare diffs two responses
+func Compare(left, right Response, opts Options) Result {
+	var result Result
+	if left.Status != right.Status {
+		result.Status = &Change{
+			Path:  "status",
+			Kind:  Changed,
+			Left:  strconv.Itoa(left.Status),
+			Right: strconv.Itoa(right.Status),
+		}
+	}
+	result.Headers = Headers(left.Headers, right.Headers, opts.IgnoreHeaders)
+
+	leftValue, leftErr := decode(left.Body)
+	rightValue, rightErr := decode(right.Body)
+	if leftErr == nil && rightErr == nil {
+		result.JSON = true
+		ignored := locate(opts.IgnorePaths, leftValue, rightValue)
+		result.Body = ignorePaths(JSON(leftValue, rightValue), ignored)
+	} else {
+		result.Body = Text(left.Body, right.Body)
+	}
+	return result
+}
+
+// Headers compares header sets by name, ignoring case. Repeated values are
+// compared in order.
+func Headers(left, right map[string][]string, ignore []string) []Change {
+	skip := map[string]bool{}
+	for _, name := range ignore {
+		skip[strings.ToLower(name)] = true
+	}
+	normalize := func(headers map[string][]string) map[string]string {
+		normalized := map[string]string{}
+		for name, values := range headers {
+			name = strings.ToLower(name)
+			if skip[name] {
+				continue
+			}
+			if existing, ok := normalized[name]; ok {
+				values = append([]string{existing}, values...)
+			}
+			normalized[name] = strings.Join(values, ", ")
+		}
+		return normalized
+	}
+	leftHeaders, rightHeaders := normalize(left), normalize(right)
+
+	var changes []Change
+	for _, name := range unionKeys(leftHeaders, rightHeaders) {
+		leftValue, inLeft := leftHeaders[name]
+		rightValue, inRight := rightHeaders[name]
+		switch {
+		case !inRight:
+			changes = append(changes, Change{Path: name, Kind: Removed, Left: leftValue})
+		case !inLeft:
+			changes = append(changes, Change{Path: name, Kind: Added, Right: rightValue})
+		case leftValue != rightValue:
+			changes = append(changes, Change{Path: name, Kind: Changed, Left: leftValue, Right: rightValue})
+		}
+	}
+	return changes
+}
+
+// JSON structurally compares two decoded documents. Objects are compared by
+// key and arrays by index; numbers are equal when their values are.
+func JSON(left, right any) []Change {
+	var changes []Change
+	compare("$", left, right, &changes)
+	return changes
+}
+
+func compare(path string, left, right any, changes *[]Change) {
+	switch l := left.(type) {
+	case map[string]any:
+		if r, ok := right.(map[string]any); ok {
+			for _, key := range unionKeys(l, r) {
+				leftValue, inLeft := l[key]
+				rightValue, inRight := r[key]
+				child := query.ChildPath(path, key)
+				switch {
+				case !inRight:
+					*changes = append(*changes, Change{Path: child, Kind: Removed, Left: render(leftValue)})
+				case !inLeft:
+					*changes = append(*changes, Change{Path: child, Kind: Added, Right: render(rightValue)})
+				default:
+					compare(child, leftValue, rightValue, changes)
+				}
+			}
+			return
+		}
+	case []any:
+		if r, ok := right.([]any); ok {
+			for i := 0; i < max(len(l), len(r)); i++ {
+				child := fmt.Sprintf("%s[%d]", path, i)
+				switch {
+				case i >= len(r):
+					*changes = append(*changes, Change{Path: child, Kind: Removed, Left: render(l[i])})
+				case i >= len(l):
+					*changes = append(*changes, Change{Path: child, Kind: Added, Right: render(r[i])})
+				default:
+					compare(child, l[i], r[i], changes)
+				}
+			}
+			return
+		}
+	}
+
+	if !equalScalars(left, right) {
+		*changes = append(*changes, Change{Path: path, Kind: Changed, Left: render(left), Right: render(right)})
+	}
+}
+
+func equalScalars(left, right any) bool {
+	leftNumber, leftOK := left.(json.Number)
+	rightNumber, rightOK := right.(json.Number)
+	if leftOK && rightOK {
+		l, lok := new(big.Float).SetString(leftNumber.String())
+		r, rok := new(big.Float).SetString(rightNumber.String())
+		if lok && rok {
+			return l.Cmp(r) == 0
+		}
+		return leftNumber == rightNumber
+	}
+	switch left.(type) {
+	case map[string]any, []any:
+		return false
+	}
+	switch right.(type) {
+	case map[string]any, []any:
+		return false
+	}
+	return left == right
+}
+
+// locate evaluates the ignored paths against both documents and returns
+// the concrete paths they match
+func locate(paths []string, docs ...any) []string {
+	var located []string
+	for _, path := range paths {
+		q, err := query.Compile(path)
+		if err != nil {
+			continue
+		}
+		for _, doc := range docs {
+			matches, err := q.Locate(doc)
+			if err != nil {
+				continue
+			}
+			located = append(located, matches...)
+		}
+	}
+	return located
+}
+
+func ignorePaths(changes []Change, paths []string) []Change {
+	if len(paths) == 0 {
+		return changes
+	}
+	var kept []Change
+	for _, change := range changes {
+		if !underAny(change.Path, paths) {
+			kept = append(kept, change)
+		}
+	}
+	return kept
+}
+
+func underAny(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if path == prefix || strings.HasPrefix(path, prefix+".") || strings.HasPrefix(path, prefix+"[") {
+			return true
+		}
+	}
+	return false
+}
+
+// Canonical re-encodes a JSON body with sorted keys and indentation so two
+// equal documents render identically. Other bodies are returned unchanged.
+func Canonical(body string) (string, bool) {
+	value, err := decode(body)
+	if err != nil {
+		return body, false
+	}
+	encoded, err := encode(value, "  ")
+	if err != nil {
+		return body, false
+	}
+	return encoded, true
+}
+
+func decode(body string) (any, error) {
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("unexpected data after JSON value")
+	}
+	return value, nil
+}
+
+func encode(value any, indent string) (string, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", indent)
+	if err := encoder.Encode(value); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+func render(value any) string {
+	encoded, err := encode(value, "")
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return encoded
+}
+
+func unionKeys[V any](left, right map[string]V) []string {
+	keys := make([]string, 0, len(left)+len(right))
+	for key := range left {
+		keys = append(keys, key)
+	}
+	for key := range right {
+		if _, ok := left[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,186 @@
+package diff
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompareIgnoresKeyOrder(t *testing.T) {
+	left := Response{Status: 200, Body: `{"id": 1, "tags": ["a", "b"], "meta": {"x": 1.0}}`}
+	right := Response{Status: 200, Body: `{"meta":{"x":1},"tags":["a","b"],"id":1}`}
+
+	result := Compare(left, right, Options{})
+	if !result.JSON {
+		t.Error("Expected bodies to be compared as JSON")
+	}
+	if !result.Equal() {
+		t.Errorf("Expected no differences, got %v", result.Body)
+	}
+}
+
+func TestCompareJSONChanges(t *testing.T) {
+	left := Response{Status: 200, Body: `{"user": {"name": "ann", "email": "a@x"}, "items": [1, 2], "weird key": true}`}
+	right := Response{Status: 500, Body: `{"user": {"name": "bob", "age": 3}, "items": [1], "weird key": false}`}
+
+	result := Compare(left, right, Options{})
+	if result.Status == nil || result.Status.Left != "200" || result.Status.Right != "500" {
+		t.Errorf("Expected status change 200 → 500, got %v", result.Status)
+	}
+
+	expected := []Change{
+		{Path: "$.items[1]", Kind: Removed, Left: "2"},
+		{Path: "$.user.age", Kind: Added, Right: "3"},
+		{Path: "$.user.email", Kind: Removed, Left: `"a@x"`},
+		{Path: "$.user.name", Kind: Changed, Left: `"ann"`, Right: `"bob"`},
+		{Path: "$['weird key']", Kind: Changed, Left: "true", Right: "false"},
+	}
+	if !reflect.DeepEqual(result.Body, expected) {
+		t.Errorf("Expected %v, got %v", expected, result.Body)
+	}
+	if result.Count() != 6 {
+		t.Errorf("Expected 6 differences, got %d", result.Count())
+	}
+}
+
+func TestCompareTypeChange(t *testing.T) {
+	changes := Compare(Response{Body: `{"a": [1]}`}, Response{Body: `{"a": {"0": 1}}`}, Options{}).Body
+	if len(changes) != 1 || changes[0].Kind != Changed || changes[0].Left != "[1]" || changes[0].Right != `{"0":1}` {
+		t.Errorf("Expected one change replacing the array, got %v", changes)
+	}
+}
+
+func TestCompareIgnorePaths(t *testing.T) {
+	left := Response{Body: `{"id": 1, "meta": {"requestId": "a", "at": 1}, "items": [{"ts": 1}]}`}
+	right := Response{Body: `{"id": 2, "meta": {"requestId": "b"}, "items": [{"ts": 2}]}`}
+
+	result := Compare(left, right, Options{IgnorePaths: []string{"$.meta", ".items[0].ts"}})
+	if len(result.Body) != 1 || result.Body[0].Path != "$.id" {
+		t.Errorf("Expected only $.id to differ, got %v", result.Body)
+	}
+
+	left = Response{Body: `{"id": 1, "items": [{"id": 1, "name": "a"}, {"id": 2, "name": "b"}], "meta": {"id": 1, "first name": "x"}}`}
+	right = Response{Body: `{"id": 2, "items": [{"id": 3, "name": "a"}, {"id": 4, "name": "c"}, {"id": 5}], "meta": {"id": 2, "first name": "y"}}`}
+	tests := []struct {
+		name     string
+		paths    []string
+		expected []string
+	}{
+		{"Wildcard", []string{"$.items[*].id"}, []string{"$.id", "$.items[1].name", "$.items[2]", "$.meta['first name']", "$.meta.id"}},
+		{"Recursive descent", []string{"$..id"}, []string{"$.items[1].name", "$.items[2]", "$.meta['first name']"}},
+		{"Bracket", []string{"$['meta']['first name']", "$['items']"}, []string{"$.id", "$.meta.id"}},
+		{"Added values", []string{"$.items[2]"}, []string{"$.id", "$.items[0].id", "$.items[1].id", "$.items[1].name", "$.meta['first name']", "$.meta.id"}},
+		{"Unparsable paths are skipped", []string{"$.items["}, []string{"$.id", "$.items[0].id", "$.items[1].id", "$.items[1].name", "$.items[2]", "$.meta['first name']", "$.meta.id"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result := Compare(left, right, Options{IgnorePaths: test.paths})
+			var paths []string
+			for _, change := range result.Body {
+				paths = append(paths, change.Path)
+			}
+			if strings.Join(paths, " ") != strings.Join(test.expected, " ") {
+				t.Errorf("Expected %v to differ, got %v", test.expected, paths)
+			}
+		})
+	}
+}
+
+func TestOptionsValidate(t *testing.T) {
+	if err := (Options{IgnorePaths: []string{"$.a[*].b", "$..id", "$['a b']", ".c"}}).Validate(); err != nil {
+		t.Errorf("Expected valid paths, got %v", err)
+	}
+	if err := (Options{IgnorePaths: []string{"$.a", "$['a"}}).Validate(); err == nil {
+		t.Error("Expected an unterminated bracket to fail")
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	left := map[string][]string{
+		"Content-Type": {"application/json"},
+		"Date":         {"Mon"},
+		"X-Old":        {"1"},
+		"Vary":         {"Accept", "Origin"},
+	}
+	right := map[string][]string{
+		"content-type": {"text/plain"},
+		"Date":         {"Tue"},
+		"X-New":        {"2"},
+		"vary":         {"Accept", "Origin"},
+	}
+
+	changes := Headers(left, right, DefaultIgnoredHeaders)
+	expected := []Change{
+		{Path: "content-type", Kind: Changed, Left: "application/json", Right: "text/plain"},
+		{Path: "x-new", Kind: Added, Right: "2"},
+		{Path: "x-old", Kind: Removed, Left: "1"},
+	}
+	if !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Expected %v, got %v", expected, changes)
+	}
+}
+
+func TestCompareText(t *testing.T) {
+	result := Compare(Response{Body: "a\nb\nc\n"}, Response{Body: "a\nB\nc\nd"}, Options{})
+	if result.JSON {
+		t.Error("Expected a text comparison")
+	}
+	expected := []Change{
+		{Path: "line 2", Kind: Changed, Left: "b", Right: "B"},
+		{Path: "line 4", Kind: Added, Right: "d"},
+	}
+	if !reflect.DeepEqual(result.Body, expected) {
+		t.Errorf("Expected %v, got %v", expected, result.Body)
+	}
+}
+
+func TestAlign(t *testing.T) {
+	rows := Align("a\nb\nc\nd", "a\nx\nc\nd\ne")
+	var kinds []string
+	for _, row := range rows {
+		kinds = append(kinds, string(row.Kind))
+	}
+	expected := []string{"", "changed", "", "", "added"}
+	if !reflect.DeepEqual(kinds, expected) {
+		t.Errorf("Expected kinds %v, got %v", expected, kinds)
+	}
+	if rows[4].LeftLine != 0 || rows[4].RightLine != 5 || rows[4].Right != "e" {
+		t.Errorf("Unexpected added row %+v", rows[4])
+	}
+
+	rows = Align("a\nb\nc", "c")
+	if len(rows) != 3 || rows[0].Kind != Removed || rows[1].Kind != Removed || rows[2].Kind != "" {
+		t.Errorf("Expected two removed lines then an equal one, got %+v", rows)
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	left, ok := Canonical(`{"b": 1, "a": {"d": "<x>", "c": [true]}}`)
+	if !ok {
+		t.Fatal("Expected JSON to be canonicalized")
+	}
+	right, _ := Canonical(`{"a":{"c":[true],"d":"<x>"},"b":1}`)
+	if left != right {
+		t.Errorf("Expected equal canonical forms, got %q and %q", left, right)
+	}
+	if !strings.HasPrefix(left, "{\n  \"a\"") || !strings.Contains(left, `"<x>"`) {
+		t.Errorf("Unexpected canonical form %q", left)
+	}
+
+	if text, ok := Canonical("not json"); ok || text != "not json" {
+		t.Errorf("Expected text to be returned unchanged, got %q", text)
+	}
+}
+
+func TestChangeString(t *testing.T) {
+	tests := map[string]Change{
+		"+ $.a: 1":     {Path: "$.a", Kind: Added, Right: "1"},
+		"- $.a: 1":     {Path: "$.a", Kind: Removed, Left: "1"},
+		"~ $.a: 1 → 2": {Path: "$.a", Kind: Changed, Left: "1", Right: "2"},
+	}
+	for expected, change := range tests {
+		if got := change.String(); got != expected {
+			t.Errorf("Expected %q, got %q", expected, got)
+		}
+	}
+}
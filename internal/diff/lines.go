@@ -0,0 +1,181 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxCells bounds the line matching table. Larger inputs are paired line by
+// line instead, which still shows every difference but aligns less well.
+const maxCells = 4_000_000
+
+// Row is one line of a side by side view. Line numbers start at 1 and are 0
+// on the side the line is missing from.
+type Row struct {
+	Kind      Kind // empty when both sides are equal
+	Left      string
+	Right     string
+	LeftLine  int
+	RightLine int
+}
+
+// Align pairs the lines of two texts for side by side display. Runs of
+// removed lines followed by added lines are paired up as changes.
+func Align(left, right string) []Row {
+	leftLines, rightLines := splitLines(left), splitLines(right)
+	ops := match(leftLines, rightLines)
+
+	var rows []Row
+	l, r := 0, 0
+	for i := 0; i < len(ops); {
+		if ops[i] == opEqual {
+			l++
+			r++
+			rows = append(rows, Row{Left: leftLines[l-1], Right: rightLines[r-1], LeftLine: l, RightLine: r})
+			i++
+			continue
+		}
+
+		var removed, added []int
+		for ; i < len(ops) && ops[i] != opEqual; i++ {
+			if ops[i] == opRemove {
+				l++
+				removed = append(removed, l)
+			} else {
+				r++
+				added = append(added, r)
+			}
+		}
+		for j := 0; j < max(len(removed), len(added)); j++ {
+			row := Row{Kind: Changed}
+			if j < len(removed) {
+				row.LeftLine = removed[j]
+				row.Left = leftLines[removed[j]-1]
+			} else {
+				row.Kind = Added
+			}
+			if j < len(added) {
+				row.RightLine = added[j]
+				row.Right = rightLines[added[j]-1]
+			} else {
+				row.Kind = Removed
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// Text compares two bodies line by line
+func Text(left, right string) []Change {
+	var changes []Change
+	for _, row := range Align(left, right) {
+		switch row.Kind {
+		case Added:
+			changes = append(changes, Change{Path: fmt.Sprintf("line %d", row.RightLine), Kind: Added, Right: row.Right})
+		case Removed:
+			changes = append(changes, Change{Path: fmt.Sprintf("line %d", row.LeftLine), Kind: Removed, Left: row.Left})
+		case Changed:
+			changes = append(changes, Change{Path: fmt.Sprintf("line %d", row.LeftLine), Kind: Changed, Left: row.Left, Right: row.Right})
+		}
+	}
+	return changes
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+type op int
+
+const (
+	opEqual op = iota
+	opRemove
+	opAdd
+)
+
+// match returns the edit script turning left into right, using the longest
+// common subsequence of lines once the shared prefix and suffix are trimmed
+func match(left, right []string) []op {
+	prefix := 0
+	for prefix < len(left) && prefix < len(right) && left[prefix] == right[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(left)-prefix && suffix < len(right)-prefix &&
+		left[len(left)-1-suffix] == right[len(right)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]op, 0, len(left)+len(right))
+	for range prefix {
+		ops = append(ops, opEqual)
+	}
+	ops = append(ops, lcs(left[prefix:len(left)-suffix], right[prefix:len(right)-suffix])...)
+	for range suffix {
+		ops = append(ops, opEqual)
+	}
+	return ops
+}
+
+func lcs(left, right []string) []op {
+	n, m := len(left), len(right)
+	if (n+1)*(m+1) > maxCells {
+		var ops []op
+		for i := 0; i < max(n, m); i++ {
+			switch {
+			case i < n && i < m && left[i] == right[i]:
+				ops = append(ops, opEqual)
+			case i < n && i < m:
+				ops = append(ops, opRemove, opAdd)
+			case i < n:
+				ops = append(ops, opRemove)
+			default:
+				ops = append(ops, opAdd)
+			}
+		}
+		return ops
+	}
+
+	// lengths[i][j] is the LCS length of left[i:] and right[j:]
+	lengths := make([][]int, n+1)
+	for i := range lengths {
+		lengths[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if left[i] == right[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]op, 0, n+m)
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case left[i] == right[j]:
+			ops = append(ops, opEqual)
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			ops = append(ops, opRemove)
+			i++
+		default:
+			ops = append(ops, opAdd)
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, opRemove)
+	}
+	for ; j < m; j++ {
+		ops = append(ops, opAdd)
+	}
+	return ops
+}
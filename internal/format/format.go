@@ -0,0 +1,192 @@
+// Package format detects response body types and pretty-prints them for display.
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"mime"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+type Kind string
+
+const (
+	Plain Kind = "text"
+	JSON  Kind = "json"
+	XML   Kind = "xml"
+	HTML  Kind = "html"
+	YAML  Kind = "yaml"
+)
+
+// Detect determines the body kind from the Content-Type header, falling back
+// to sniffing the body when the header is missing or generic.
+func Detect(contentType, body string) Kind {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = strings.ToLower(strings.TrimSpace(contentType))
+	}
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return JSON
+	case mediaType == "text/html" || mediaType == "application/xhtml+xml":
+		return HTML
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		return XML
+	case strings.Contains(mediaType, "yaml"):
+		return YAML
+	}
+	return sniff(body)
+}
+
+// ContentType returns the Content-Type value from a header map regardless of
+// how the header name is cased.
+func ContentType(headers map[string][]string) string {
+	for name, values := range headers {
+		if strings.EqualFold(name, "Content-Type") && len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// Pretty re-indents the body according to its kind. The original body is
+// returned along with the error when it cannot be parsed.
+func Pretty(kind Kind, body string) (string, error) {
+	switch kind {
+	case JSON:
+		return prettyJSON(body)
+	case XML:
+		return prettyXML(body)
+	case HTML:
+		return prettyHTML(body)
+	}
+	return body, nil
+}
+
+func sniff(body string) Kind {
+	trimmed := strings.TrimSpace(body)
+	if len(trimmed) > 512 {
+		trimmed = trimmed[:512]
+	}
+	lower := strings.ToLower(trimmed)
+
+	switch {
+	case strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "["):
+		return JSON
+	case strings.HasPrefix(lower, "<!doctype html") || strings.HasPrefix(lower, "<html"):
+		return HTML
+	case strings.HasPrefix(trimmed, "<?xml") || strings.HasPrefix(trimmed, "<"):
+		return XML
+	case strings.HasPrefix(trimmed, "---"):
+		return YAML
+	}
+	return Plain
+}
+
+func prettyJSON(body string) (string, error) {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, []byte(strings.TrimSpace(body)), "", "  "); err != nil {
+		return body, err
+	}
+	return buf.String(), nil
+}
+
+func prettyXML(body string) (string, error) {
+	decoder := xml.NewDecoder(strings.NewReader(body))
+	decoder.Strict = false
+
+	var buf bytes.Buffer
+	encoder := xml.NewEncoder(&buf)
+	encoder.Indent("", "  ")
+
+	for {
+		token, err := decoder.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return body, err
+		}
+		if data, ok := token.(xml.CharData); ok {
+			data = bytes.TrimSpace(data)
+			if len(data) == 0 {
+				continue
+			}
+			token = data
+		}
+		if err := encoder.EncodeToken(xml.CopyToken(token)); err != nil {
+			return body, err
+		}
+	}
+	if err := encoder.Flush(); err != nil {
+		return body, err
+	}
+	return buf.String(), nil
+}
+
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true,
+	"hr": true, "img": true, "input": true, "link": true, "meta": true,
+	"param": true, "source": true, "track": true, "wbr": true,
+}
+
+// prettyHTML puts every tag on its own line indented by nesting depth. The
+// content of script and style elements is kept as-is.
+func prettyHTML(body string) (string, error) {
+	tokenizer := html.NewTokenizer(strings.NewReader(body))
+
+	var b strings.Builder
+	depth := 0
+	writeLine := func(text string) {
+		b.WriteString(strings.Repeat("  ", depth))
+		b.WriteString(text)
+		b.WriteString("\n")
+	}
+
+	rawElement := ""
+	for {
+		tokenType := tokenizer.Next()
+		switch tokenType {
+		case html.ErrorToken:
+			if errors.Is(tokenizer.Err(), io.EOF) {
+				return strings.TrimRight(b.String(), "\n"), nil
+			}
+			return body, tokenizer.Err()
+		case html.TextToken:
+			text := string(tokenizer.Raw())
+			if rawElement == "" {
+				text = strings.Join(strings.Fields(text), " ")
+			}
+			for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+				if strings.TrimSpace(line) != "" {
+					writeLine(strings.TrimSpace(line))
+				}
+			}
+		case html.StartTagToken:
+			raw := string(tokenizer.Raw())
+			name, _ := tokenizer.TagName()
+			writeLine(raw)
+			if !voidElements[string(name)] {
+				depth++
+			}
+			if string(name) == "script" || string(name) == "style" {
+				rawElement = string(name)
+			}
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			if string(name) == rawElement {
+				rawElement = ""
+			}
+			depth = max(depth-1, 0)
+			writeLine(string(tokenizer.Raw()))
+		default:
+			writeLine(string(tokenizer.Raw()))
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package format
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		contentType string
+		body        string
+		expected    Kind
+	}{
+		{"application/json", `{"a": 1}`, JSON},
+		{"application/json; charset=utf-8", `{}`, JSON},
+		{"application/problem+json", `{}`, JSON},
+		{"text/html; charset=utf-8", `<html></html>`, HTML},
+		{"application/xml", `<a/>`, XML},
+		{"application/atom+xml", `<feed/>`, XML},
+		{"application/x-yaml", "a: 1", YAML},
+		{"text/plain", "hello", Plain},
+		{"", `[1, 2]`, JSON},
+		{"", `<!DOCTYPE html><html></html>`, HTML},
+		{"", `<?xml version="1.0"?><a/>`, XML},
+		{"", "---\na: 1", YAML},
+		{"", "plain text", Plain},
+	}
+
+	for _, test := range tests {
+		if got := Detect(test.contentType, test.body); got != test.expected {
+			t.Errorf("Detect(%q, %q) = %s, expected %s", test.contentType, test.body, got, test.expected)
+		}
+	}
+}
+
+func TestContentType(t *testing.T) {
+	headers := map[string][]string{"content-type": {"application/json"}}
+	if got := ContentType(headers); got != "application/json" {
+		t.Errorf("expected application/json, got %q", got)
+	}
+	if got := ContentType(map[string][]string{}); got != "" {
+		t.Errorf("expected empty content type, got %q", got)
+	}
+}
+
+func TestPrettyJSON(t *testing.T) {
+	pretty, err := Pretty(JSON, `{"user":{"id":1,"tags":["a","b"]}}`)
+	if err != nil {
+		t.Fatalf("Pretty failed: %v", err)
+	}
+	expected := `{
+  "user": {
+    "id": 1,
+    "tags": [
+      "a",
+      "b"
+    ]
+  }
+}`
+	if pretty != expected {
+		t.Errorf("unexpected JSON output:\n%s", pretty)
+	}
+
+	invalid := `{"broken":`
+	pretty, err = Pretty(JSON, invalid)
+	if err == nil {
+		t.Error("expected invalid JSON to return an error")
+	}
+	if pretty != invalid {
+		t.Error("expected invalid JSON to be returned unchanged")
+	}
+}
+
+func TestPrettyXML(t *testing.T) {
+	pretty, err := Pretty(XML, `<root><item id="1">one</item><item id="2">two</item></root>`)
+	if err != nil {
+		t.Fatalf("Pretty failed: %v", err)
+	}
+	lines := strings.Split(pretty, "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines, got %d:\n%s", len(lines), pretty)
+	}
+	if lines[1] != `  <item id="1">one</item>` {
+		t.Errorf("unexpected indentation %q", lines[1])
+	}
+}
+
+func TestPrettyHTML(t *testing.T) {
+	pretty, err := Pretty(HTML, `<html><head><meta charset="utf-8"><script>if (a < b) { run(); }</script></head><body><p>Hello   world</p></body></html>`)
+	if err != nil {
+		t.Fatalf("Pretty failed: %v", err)
+	}
+	expected := `<html>
+  <head>
+    <meta charset="utf-8">
+    <script>
+      if (a < b) { run(); }
+    </script>
+  </head>
+  <body>
+    <p>
+      Hello world
+    </p>
+  </body>
+</html>`
+	if pretty != expected {
+		t.Errorf("unexpected HTML output:\n%s", pretty)
+	}
+}
+
+func TestPrettyPassthrough(t *testing.T) {
+	body := "a: 1\nb:\n  - c\n"
+	pretty, err := Pretty(YAML, body)
+	if err != nil || pretty != body {
+		t.Errorf("expected YAML to be returned unchanged, got %q, %v", pretty, err)
+	}
+}
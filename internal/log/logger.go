@@ -18,6 +18,26 @@ type Logger struct {
 type Config struct {
 	Level       slog.Level
 	LogFilePath string
+	// Rotation limits the log file, the defaults are used when it is nil
+	Rotation *Rotation
+}
+
+// Rotation sets when the log file is rotated and how many old ones are kept.
+// Sizes are in megabytes and ages in days.
+type Rotation struct {
+	MaxSize    int
+	MaxBackups int
+	MaxAge     int
+	Compress   bool
+}
+
+func DefaultRotation() Rotation {
+	return Rotation{
+		MaxSize:    10,
+		MaxBackups: 2,
+		MaxAge:     7,
+		Compress:   true,
+	}
 }
 
 var (
@@ -36,12 +56,16 @@ func createLogger(config Config) *Logger {
 	var handler slog.Handler
 
 	if config.LogFilePath != "" {
+		rotation := DefaultRotation()
+		if config.Rotation != nil {
+			rotation = *config.Rotation
+		}
 		fileLogger = &lumberjack.Logger{
 			Filename:   config.LogFilePath,
-			MaxSize:    10, // MB
-			MaxBackups: 2,
-			MaxAge:     7, // days
-			Compress:   true,
+			MaxSize:    rotation.MaxSize,
+			MaxBackups: rotation.MaxBackups,
+			MaxAge:     rotation.MaxAge,
+			Compress:   rotation.Compress,
 		}
 		handler = slog.NewJSONHandler(fileLogger, &slog.HandlerOptions{
 			Level: config.Level,
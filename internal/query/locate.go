@@ -0,0 +1,162 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// location is a node together with the JSONPath it was found at
+type location struct {
+	path  string
+	value any
+}
+
+// Locate runs the query against a document decoded with Decode and returns
+// the concrete JSONPath of every matching value, like `$.items[0].id`.
+// Paths are written the way ChildPath writes them. Queries that compute new
+// values, such as keys and length, have no location and fail.
+func (q *Query) Locate(doc any) ([]string, error) {
+	nodes := []location{{path: "$", value: doc}}
+	for _, st := range q.stages {
+		var next []location
+		for _, node := range nodes {
+			switch st := st.(type) {
+			case path:
+				next = append(next, st.locate(node)...)
+			case selectStage:
+				if st.cond.eval(node.value) {
+					next = append(next, node)
+				}
+			default:
+				return nil, fmt.Errorf("%s selects values that are not in the document", q.expr)
+			}
+		}
+		nodes = next
+	}
+
+	paths := make([]string, len(nodes))
+	for i, node := range nodes {
+		paths[i] = node.path
+	}
+	return paths, nil
+}
+
+var identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// ChildPath returns the path of an object member: `$.name` for plain names
+// and `$['first name']` for anything else
+func ChildPath(parent, key string) string {
+	if identifier.MatchString(key) {
+		return parent + "." + key
+	}
+	return parent + "['" + strings.ReplaceAll(key, "'", `\'`) + "']"
+}
+
+func indexPath(parent string, i int) string {
+	return fmt.Sprintf("%s[%d]", parent, i)
+}
+
+func (p path) locate(node location) []location {
+	nodes := []location{node}
+	for _, s := range p {
+		var next []location
+		for _, n := range nodes {
+			next = append(next, locateStep(s, n)...)
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// locateStep mirrors selectFrom, keeping track of where each value is
+func locateStep(s step, node location) []location {
+	switch s := s.(type) {
+	case field:
+		if object, ok := node.value.(map[string]any); ok {
+			if value, ok := object[string(s)]; ok {
+				return []location{{path: ChildPath(node.path, string(s)), value: value}}
+			}
+		}
+	case index:
+		if array, ok := node.value.([]any); ok {
+			idx := int(s)
+			if idx < 0 {
+				idx += len(array)
+			}
+			if idx >= 0 && idx < len(array) {
+				return []location{{path: indexPath(node.path, idx), value: array[idx]}}
+			}
+		}
+	case slice:
+		array, ok := node.value.([]any)
+		if !ok {
+			return nil
+		}
+		selected := s.selectFrom(array)
+		if len(selected) == 0 {
+			return nil
+		}
+		start := 0
+		if s.start != nil {
+			start = *s.start
+			if start < 0 {
+				start = max(start+len(array), 0)
+			}
+		}
+		out := make([]location, len(selected))
+		for i, value := range selected {
+			out[i] = location{path: indexPath(node.path, start+i), value: value}
+		}
+		return out
+	case wildcard:
+		return locateChildren(node)
+	case current:
+		return []location{node}
+	case union:
+		var out []location
+		for _, member := range s {
+			out = append(out, locateStep(member, node)...)
+		}
+		return out
+	case descend:
+		var out []location
+		var walk func(location)
+		walk = func(n location) {
+			out = append(out, locateStep(s.step, n)...)
+			for _, child := range locateChildren(n) {
+				walk(child)
+			}
+		}
+		walk(node)
+		return out
+	case filter:
+		var out []location
+		for _, child := range locateChildren(node) {
+			if s.cond.eval(child.value) {
+				out = append(out, child)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// locateChildren is children with paths
+func locateChildren(node location) []location {
+	switch v := node.value.(type) {
+	case []any:
+		out := make([]location, len(v))
+		for i, value := range v {
+			out[i] = location{path: indexPath(node.path, i), value: value}
+		}
+		return out
+	case map[string]any:
+		out := make([]location, 0, len(v))
+		for _, key := range sortedKeys(v) {
+			out = append(out, location{path: ChildPath(node.path, key), value: v[key]})
+		}
+		return out
+	}
+	return nil
+}
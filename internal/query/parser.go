@@ -0,0 +1,449 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type parser struct {
+	src string
+	pos int
+}
+
+func (p *parser) parseStage() (stage, error) {
+	p.skipSpace()
+	if isIdentStart(p.peek()) {
+		start := p.pos
+		name := p.ident()
+		switch name {
+		case "keys":
+			return keysStage{}, nil
+		case "length":
+			return lengthStage{}, nil
+		case "select":
+			p.skipSpace()
+			if !p.consume("(") {
+				return nil, p.errorf("expected '(' after select")
+			}
+			cond, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			p.skipSpace()
+			if !p.consume(")") {
+				return nil, p.errorf("expected ')' to close select")
+			}
+			return selectStage{cond: cond}, nil
+		}
+		p.pos = start
+		return nil, p.errorf("unknown function %q", name)
+	}
+
+	switch p.peek() {
+	case '$', '.':
+		return p.parsePath()
+	}
+	return nil, p.errorf("expected '$' or '.'")
+}
+
+// parsePath parses a path starting at '$', '@' or '.'
+func (p *parser) parsePath() (path, error) {
+	steps := path{}
+	if p.peek() == '$' || p.peek() == '@' {
+		p.pos++
+	}
+
+	for !p.eof() {
+		switch {
+		case p.consume(".."):
+			s, err := p.parseDescendant()
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, descend{step: s})
+		case p.consume("."):
+			switch c := p.peek(); {
+			case c == '[':
+				// jq style .[0], handled as a bracket on the next iteration
+			case c == '*':
+				p.pos++
+				steps = append(steps, wildcard{})
+			case c == '"':
+				name, err := p.quoted()
+				if err != nil {
+					return nil, err
+				}
+				steps = append(steps, field(name))
+			case isIdentStart(c):
+				steps = append(steps, field(p.ident()))
+			default:
+				// a bare '.' is the identity
+			}
+		case p.peek() == '[':
+			s, err := p.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, s)
+		default:
+			return steps, nil
+		}
+	}
+	return steps, nil
+}
+
+func (p *parser) parseDescendant() (step, error) {
+	switch c := p.peek(); {
+	case c == '*':
+		p.pos++
+		return wildcard{}, nil
+	case c == '[':
+		return p.parseBracket()
+	case isIdentStart(c):
+		return field(p.ident()), nil
+	}
+	// a bare '..' selects every node, like jq's recurse
+	return current{}, nil
+}
+
+func (p *parser) parseBracket() (step, error) {
+	p.pos++ // '['
+	p.skipSpace()
+
+	var s step
+	switch c := p.peek(); {
+	case c == ']':
+		s = wildcard{}
+	case c == '*':
+		p.pos++
+		s = wildcard{}
+	case c == '?':
+		p.pos++
+		p.skipSpace()
+		parens := p.consume("(")
+		cond, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if parens && !p.consume(")") {
+			return nil, p.errorf("expected ')' to close filter")
+		}
+		s = filter{cond: cond}
+	case c == '\'' || c == '"':
+		var names union
+		for {
+			name, err := p.quoted()
+			if err != nil {
+				return nil, err
+			}
+			names = append(names, field(name))
+			p.skipSpace()
+			if !p.consume(",") {
+				break
+			}
+			p.skipSpace()
+		}
+		s = names
+		if len(names) == 1 {
+			s = names[0]
+		}
+	case c == '-' || c == ':' || isDigit(c):
+		var err error
+		if s, err = p.parseIndexes(); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, p.errorf("unexpected %q in brackets", c)
+	}
+
+	p.skipSpace()
+	if !p.consume("]") {
+		return nil, p.errorf("expected ']'")
+	}
+	return s, nil
+}
+
+// parseIndexes parses `1`, `-1`, `1,2` or a slice like `1:3`
+func (p *parser) parseIndexes() (step, error) {
+	first, err := p.optionalInt()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.consume(":") {
+		p.skipSpace()
+		end, err := p.optionalInt()
+		if err != nil {
+			return nil, err
+		}
+		return slice{start: first, end: end}, nil
+	}
+	if first == nil {
+		return nil, p.errorf("expected an index")
+	}
+
+	indexes := union{index(*first)}
+	for {
+		p.skipSpace()
+		if !p.consume(",") {
+			break
+		}
+		p.skipSpace()
+		next, err := p.optionalInt()
+		if err != nil {
+			return nil, err
+		}
+		if next == nil {
+			return nil, p.errorf("expected an index")
+		}
+		indexes = append(indexes, index(*next))
+	}
+	if len(indexes) == 1 {
+		return indexes[0], nil
+	}
+	return indexes, nil
+}
+
+func (p *parser) optionalInt() (*int, error) {
+	start := p.pos
+	if p.peek() == '-' {
+		p.pos++
+	}
+	for isDigit(p.peek()) {
+		p.pos++
+	}
+	if start == p.pos {
+		return nil, nil
+	}
+	text := p.src[start:p.pos]
+	value, err := strconv.Atoi(text)
+	if err != nil {
+		p.pos = start
+		return nil, p.errorf("invalid index %q", text)
+	}
+	return &value, nil
+}
+
+func (p *parser) parseOr() (condition, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if !p.consume("||") && !p.consumeWord("or") {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logical{left: left, right: right}
+	}
+}
+
+func (p *parser) parseAnd() (condition, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if !p.consume("&&") && !p.consumeWord("and") {
+			return left, nil
+		}
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = logical{and: true, left: left, right: right}
+	}
+}
+
+func (p *parser) parseComparison() (condition, error) {
+	p.skipSpace()
+	if p.peek() == '!' && !strings.HasPrefix(p.src[p.pos:], "!=") {
+		p.pos++
+		cond, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		return negation{cond: cond}, nil
+	}
+	if p.consume("(") {
+		cond, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if !p.consume(")") {
+			return nil, p.errorf("expected ')'")
+		}
+		return cond, nil
+	}
+
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	op := ""
+	for _, candidate := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.consume(candidate) {
+			op = candidate
+			break
+		}
+	}
+	if op == "" {
+		if !left.isPath {
+			return nil, p.errorf("expected a comparison operator")
+		}
+		return truthy{operand: left}, nil
+	}
+
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return comparison{op: op, left: left, right: right}, nil
+}
+
+func (p *parser) parseOperand() (operand, error) {
+	p.skipSpace()
+	switch c := p.peek(); {
+	case c == '@' || c == '.':
+		steps, err := p.parsePath()
+		if err != nil {
+			return operand{}, err
+		}
+		return operand{path: steps, isPath: true}, nil
+	case c == '$':
+		return operand{}, p.errorf("filters can only refer to the current node with '@'")
+	case c == '\'' || c == '"':
+		value, err := p.quoted()
+		if err != nil {
+			return operand{}, err
+		}
+		return operand{literal: value}, nil
+	case c == '-' || isDigit(c):
+		start := p.pos
+		p.pos++
+		for !p.eof() && strings.IndexByte("0123456789.eE+-", p.peek()) >= 0 {
+			p.pos++
+		}
+		value, err := strconv.ParseFloat(p.src[start:p.pos], 64)
+		if err != nil {
+			p.pos = start
+			return operand{}, p.errorf("invalid number")
+		}
+		return operand{literal: value}, nil
+	case isIdentStart(c):
+		start := p.pos
+		switch word := p.ident(); word {
+		case "true":
+			return operand{literal: true}, nil
+		case "false":
+			return operand{literal: false}, nil
+		case "null":
+			return operand{literal: nil}, nil
+		default:
+			p.pos = start
+			return operand{}, p.errorf("unknown value %q", word)
+		}
+	}
+	return operand{}, p.errorf("expected a path or a value")
+}
+
+// quoted reads a single or double quoted string at the current position
+func (p *parser) quoted() (string, error) {
+	quote := p.peek()
+	start := p.pos
+	p.pos++
+
+	var b strings.Builder
+	for !p.eof() {
+		c := p.src[p.pos]
+		switch {
+		case c == quote:
+			p.pos++
+			if quote == '"' {
+				value, err := strconv.Unquote(p.src[start:p.pos])
+				if err != nil {
+					p.pos = start
+					return "", p.errorf("invalid string")
+				}
+				return value, nil
+			}
+			return b.String(), nil
+		case c == '\\' && p.pos+1 < len(p.src):
+			p.pos++
+			b.WriteByte(p.src[p.pos])
+		default:
+			b.WriteByte(c)
+		}
+		p.pos++
+	}
+	p.pos = start
+	return "", p.errorf("unterminated string")
+}
+
+func (p *parser) ident() string {
+	start := p.pos
+	for !p.eof() && (isIdentStart(p.peek()) || isDigit(p.peek()) || p.peek() == '-') {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *parser) consume(token string) bool {
+	if strings.HasPrefix(p.src[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
+
+// consumeWord is like consume but requires the word to end at a boundary
+func (p *parser) consumeWord(word string) bool {
+	rest := p.src[p.pos:]
+	if !strings.HasPrefix(rest, word) {
+		return false
+	}
+	if len(rest) > len(word) && (isIdentStart(rest[len(word)]) || isDigit(rest[len(word)])) {
+		return false
+	}
+	p.pos += len(word)
+	return true
+}
+
+func (p *parser) skipSpace() {
+	for !p.eof() && unicode.IsSpace(rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *parser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("position %d: %s", p.pos+1, fmt.Sprintf(format, args...))
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
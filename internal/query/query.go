@@ -0,0 +1,140 @@
+// Package query evaluates JSONPath and jq-style expressions against decoded
+// JSON documents. Both syntaxes compile to the same steps, so `$.items[0].id`
+// and `.items[0].id` are equivalent.
+//
+// Supported JSONPath: `$`, `.name`, `['name']`, `[0]`, `[-1]`, `[1:3]`,
+// `[*]`, `..name`, unions like `[0,2]` and filters like `[?(@.price < 10)]`.
+// Supported jq: `.`, `.name`, `."name"`, `.[0]`, `.[]`, pipes and the
+// `select`, `keys` and `length` functions.
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type Query struct {
+	expr   string
+	stages []stage
+}
+
+// Compile parses an expression so it can be evaluated against many documents
+func Compile(expr string) (*Query, error) {
+	p := &parser{src: strings.TrimSpace(expr)}
+	if p.src == "" {
+		return nil, errors.New("expression cannot be empty")
+	}
+
+	q := &Query{expr: expr}
+	for {
+		st, err := p.parseStage()
+		if err != nil {
+			return nil, err
+		}
+		q.stages = append(q.stages, st)
+
+		p.skipSpace()
+		if p.eof() {
+			return q, nil
+		}
+		if !p.consume("|") {
+			return nil, p.errorf("unexpected %q", p.peek())
+		}
+	}
+}
+
+func (q *Query) String() string {
+	return q.expr
+}
+
+// Evaluate runs the query against a document decoded with Decode and
+// returns every matching value.
+func (q *Query) Evaluate(doc any) ([]any, error) {
+	nodes := []any{doc}
+	for _, st := range q.stages {
+		var next []any
+		for _, node := range nodes {
+			out, err := st.apply(node)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, out...)
+		}
+		nodes = next
+	}
+	return nodes, nil
+}
+
+// Decode parses a JSON body, keeping numbers as json.Number so large
+// integers survive a round trip.
+func Decode(body string) (any, error) {
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber()
+
+	var doc any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	if decoder.More() {
+		return nil, errors.New("invalid JSON: unexpected data after top-level value")
+	}
+	return doc, nil
+}
+
+// Apply compiles expr and evaluates it against a JSON body
+func Apply(expr, body string) ([]any, error) {
+	q, err := Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := Decode(body)
+	if err != nil {
+		return nil, err
+	}
+	return q.Evaluate(doc)
+}
+
+// Render formats results as indented JSON. A single result is rendered as
+// is; anything else is rendered as an array.
+func Render(results []any) (string, error) {
+	var value any = results
+	if len(results) == 1 {
+		value = results[0]
+	}
+	if results == nil {
+		value = []any{}
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// String renders a single value for use outside JSON: strings are returned
+// unquoted and everything else as compact JSON.
+func String(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case nil:
+		return "null"
+	case json.Number:
+		return v.String()
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Sprint(value)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
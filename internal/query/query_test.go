@@ -0,0 +1,207 @@
+package query
+
+import (
+	"strings"
+	"testing"
+)
+
+const store = `{
+  "store": {
+    "book": [
+      {"category": "reference", "author": "Nigel Rees", "title": "Sayings of the Century", "price": 8.95},
+      {"category": "fiction", "author": "Evelyn Waugh", "title": "Sword of Honour", "price": 12.99},
+      {"category": "fiction", "author": "Herman Melville", "title": "Moby Dick", "isbn": "0-553-21311-3", "price": 8.99},
+      {"category": "fiction", "author": "J. R. R. Tolkien", "title": "The Lord of the Rings", "isbn": "0-395-19395-8", "price": 22.99}
+    ],
+    "bicycle": {"color": "red", "price": 19.95},
+    "first name": "Ada"
+  },
+  "count": 12345678901234567890
+}`
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected string
+	}{
+		{"$.store.bicycle.color", `"red"`},
+		{".store.bicycle.color", `"red"`},
+		{"$['store']['first name']", `"Ada"`},
+		{`."store"."first name"`, `"Ada"`},
+		{"$.store.book[0].author", `"Nigel Rees"`},
+		{".store.book[-1].title", `"The Lord of the Rings"`},
+		{"$.store.book[*].price", `[8.95,12.99,8.99,22.99]`},
+		{".store.book[].price", `[8.95,12.99,8.99,22.99]`},
+		{"$.store.book[1:3].price", `[12.99,8.99]`},
+		{"$.store.book[:1].price", `8.95`},
+		{"$.store.book[-2:].price", `[8.99,22.99]`},
+		{"$.store.book[0,2].price", `[8.95,8.99]`},
+		{"$.store.bicycle['color','price']", `["red",19.95]`},
+		{"$..isbn", `["0-553-21311-3","0-395-19395-8"]`},
+		{"$.store..price", `[19.95,8.95,12.99,8.99,22.99]`},
+		{"$.store.book[?(@.price < 10)].title", `["Sayings of the Century","Moby Dick"]`},
+		{"$.store.book[?(@.isbn)].author", `["Herman Melville","J. R. R. Tolkien"]`},
+		{"$.store.book[?(!@.isbn)].price", `[8.95,12.99]`},
+		{"$.store.book[?(@.category == 'fiction' && @.price > 20)].title", `"The Lord of the Rings"`},
+		{"$.store.book[?(@.price >= 22.99 || @.author == \"Nigel Rees\")].price", `[8.95,22.99]`},
+		{".store.book[] | select(.price > 10 and .category != \"reference\") | .author", `["Evelyn Waugh","J. R. R. Tolkien"]`},
+		{".store.book | length", `4`},
+		{".store.bicycle | keys", `["color","price"]`},
+		{".store.book[0].title | length", `22`},
+		{"$.count", `12345678901234567890`},
+		{".", ``},
+		{"$.missing", `[]`},
+		{"$.store.book[10]", `[]`},
+	}
+
+	doc, err := Decode(store)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+
+	for _, test := range tests {
+		q, err := Compile(test.expr)
+		if err != nil {
+			t.Errorf("Compile(%q) failed: %v", test.expr, err)
+			continue
+		}
+		results, err := q.Evaluate(doc)
+		if err != nil {
+			t.Errorf("Evaluate(%q) failed: %v", test.expr, err)
+			continue
+		}
+		if test.expected == "" {
+			if len(results) != 1 {
+				t.Errorf("expected %q to return the document, got %d results", test.expr, len(results))
+			}
+			continue
+		}
+		got := String(append([]any{}, results...))
+		if len(results) == 1 {
+			got = String(results[0])
+			if s, ok := results[0].(string); ok {
+				got = `"` + s + `"`
+			}
+		}
+		if got != test.expected {
+			t.Errorf("%q: expected %s, got %s", test.expr, test.expected, got)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"store.book",
+		"$.store[",
+		"$.store['book",
+		"$.book[?(@.price <)]",
+		"$.book[?($.price < 10)]",
+		".a | unknown",
+		"select(.a",
+		"$.a b",
+	}
+
+	for _, expr := range tests {
+		if _, err := Compile(expr); err == nil {
+			t.Errorf("expected %q to fail to compile", expr)
+		}
+	}
+}
+
+func TestEvaluateErrors(t *testing.T) {
+	if _, err := Apply(".a | keys", `{"a": 1}`); err == nil {
+		t.Error("expected keys of a number to fail")
+	}
+	if _, err := Apply(".a | length", `{"a": true}`); err == nil {
+		t.Error("expected length of a boolean to fail")
+	}
+	if _, err := Apply(".a", `{"a": `); err == nil {
+		t.Error("expected invalid JSON to fail")
+	}
+}
+
+func TestRender(t *testing.T) {
+	results, err := Apply("$.items[*].name", `{"items": [{"name": "a<b"}, {"name": "c"}]}`)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	rendered, err := Render(results)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	expected := "[\n  \"a<b\",\n  \"c\"\n]"
+	if rendered != expected {
+		t.Errorf("expected %q, got %q", expected, rendered)
+	}
+
+	rendered, err = Render(results[:1])
+	if err != nil || rendered != `"a<b"` {
+		t.Errorf("expected a single result to render unwrapped, got %q, %v", rendered, err)
+	}
+
+	rendered, err = Render(nil)
+	if err != nil || rendered != "[]" {
+		t.Errorf("expected no results to render as [], got %q, %v", rendered, err)
+	}
+}
+
+func TestString(t *testing.T) {
+	results, err := Apply("$.user", `{"user": {"id": 7, "name": "ada", "admin": false}}`)
+	if err != nil {
+		t.Fatalf("Apply failed: %v", err)
+	}
+	if got := String(results[0]); got != `{"admin":false,"id":7,"name":"ada"}` {
+		t.Errorf("unexpected object string %s", got)
+	}
+
+	results, _ = Apply(".user.name", `{"user": {"name": "ada"}}`)
+	if got := String(results[0]); got != "ada" {
+		t.Errorf("expected strings to be unquoted, got %s", got)
+	}
+}
+
+func TestLocate(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected string
+	}{
+		{"$.store.bicycle.color", "$.store.bicycle.color"},
+		{"$['store']['first name']", "$.store['first name']"},
+		{".store.book[-1].title", "$.store.book[3].title"},
+		{"$.store.book[*].isbn", "$.store.book[2].isbn $.store.book[3].isbn"},
+		{"$.store.book[1:3]", "$.store.book[1] $.store.book[2]"},
+		{"$.store.book[-1:]", "$.store.book[3]"},
+		{"$.store.book[0,2].price", "$.store.book[0].price $.store.book[2].price"},
+		{"$..isbn", "$.store.book[2].isbn $.store.book[3].isbn"},
+		{"$.store.book[?(@.price > 20)]", "$.store.book[3]"},
+		{".store.book[] | select(.price > 20) | .title", "$.store.book[3].title"},
+		{"$", "$"},
+		{"$.missing", ""},
+	}
+
+	doc, err := Decode(store)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	for _, test := range tests {
+		q, err := Compile(test.expr)
+		if err != nil {
+			t.Errorf("%q: compile failed: %v", test.expr, err)
+			continue
+		}
+		paths, err := q.Locate(doc)
+		if err != nil {
+			t.Errorf("%q: locate failed: %v", test.expr, err)
+			continue
+		}
+		if got := strings.Join(paths, " "); got != test.expected {
+			t.Errorf("%q: expected %s, got %s", test.expr, test.expected, got)
+		}
+	}
+
+	q, _ := Compile(".store.book | length")
+	if _, err := q.Locate(doc); err == nil {
+		t.Error("expected length to have no location")
+	}
+}
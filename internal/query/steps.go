@@ -0,0 +1,372 @@
+package query
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"unicode/utf8"
+)
+
+// stage is one element of a pipeline: a path or a function
+type stage interface {
+	apply(node any) ([]any, error)
+}
+
+// step selects values from a single node. Steps never fail; a step that
+// does not apply to a node simply selects nothing.
+type step interface {
+	selectFrom(node any) []any
+}
+
+type path []step
+
+func (p path) apply(node any) ([]any, error) {
+	return p.evaluate(node), nil
+}
+
+func (p path) evaluate(node any) []any {
+	nodes := []any{node}
+	for _, s := range p {
+		var next []any
+		for _, n := range nodes {
+			next = append(next, s.selectFrom(n)...)
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+type field string
+
+func (f field) selectFrom(node any) []any {
+	if object, ok := node.(map[string]any); ok {
+		if value, ok := object[string(f)]; ok {
+			return []any{value}
+		}
+	}
+	return nil
+}
+
+type index int
+
+func (i index) selectFrom(node any) []any {
+	array, ok := node.([]any)
+	if !ok {
+		return nil
+	}
+	idx := int(i)
+	if idx < 0 {
+		idx += len(array)
+	}
+	if idx < 0 || idx >= len(array) {
+		return nil
+	}
+	return []any{array[idx]}
+}
+
+type slice struct {
+	start, end *int
+}
+
+func (s slice) selectFrom(node any) []any {
+	array, ok := node.([]any)
+	if !ok {
+		return nil
+	}
+	bound := func(value *int, fallback int) int {
+		if value == nil {
+			return fallback
+		}
+		if *value < 0 {
+			return max(*value+len(array), 0)
+		}
+		return min(*value, len(array))
+	}
+	start, end := bound(s.start, 0), bound(s.end, len(array))
+	if start >= end {
+		return nil
+	}
+	return append([]any(nil), array[start:end]...)
+}
+
+type wildcard struct{}
+
+func (wildcard) selectFrom(node any) []any {
+	return children(node)
+}
+
+type current struct{}
+
+func (current) selectFrom(node any) []any {
+	return []any{node}
+}
+
+type union []step
+
+func (u union) selectFrom(node any) []any {
+	var out []any
+	for _, s := range u {
+		out = append(out, s.selectFrom(node)...)
+	}
+	return out
+}
+
+// descend applies its step to the node and every node below it
+type descend struct {
+	step step
+}
+
+func (d descend) selectFrom(node any) []any {
+	var out []any
+	var walk func(any)
+	walk = func(n any) {
+		out = append(out, d.step.selectFrom(n)...)
+		for _, child := range children(n) {
+			walk(child)
+		}
+	}
+	walk(node)
+	return out
+}
+
+type filter struct {
+	cond condition
+}
+
+func (f filter) selectFrom(node any) []any {
+	var out []any
+	for _, child := range children(node) {
+		if f.cond.eval(child) {
+			out = append(out, child)
+		}
+	}
+	return out
+}
+
+// children returns array elements in order and object values sorted by key
+func children(node any) []any {
+	switch v := node.(type) {
+	case []any:
+		return v
+	case map[string]any:
+		out := make([]any, 0, len(v))
+		for _, key := range sortedKeys(v) {
+			out = append(out, v[key])
+		}
+		return out
+	}
+	return nil
+}
+
+func sortedKeys(object map[string]any) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+type selectStage struct {
+	cond condition
+}
+
+func (s selectStage) apply(node any) ([]any, error) {
+	if s.cond.eval(node) {
+		return []any{node}, nil
+	}
+	return nil, nil
+}
+
+type keysStage struct{}
+
+func (keysStage) apply(node any) ([]any, error) {
+	switch v := node.(type) {
+	case map[string]any:
+		keys := []any{}
+		for _, key := range sortedKeys(v) {
+			keys = append(keys, key)
+		}
+		return []any{keys}, nil
+	case []any:
+		keys := make([]any, len(v))
+		for i := range v {
+			keys[i] = json.Number(fmt.Sprint(i))
+		}
+		return []any{keys}, nil
+	}
+	return nil, fmt.Errorf("keys: %s has no keys", typeName(node))
+}
+
+type lengthStage struct{}
+
+func (lengthStage) apply(node any) ([]any, error) {
+	var length int
+	switch v := node.(type) {
+	case map[string]any:
+		length = len(v)
+	case []any:
+		length = len(v)
+	case string:
+		length = utf8.RuneCountInString(v)
+	case nil:
+		length = 0
+	default:
+		return nil, fmt.Errorf("length: %s has no length", typeName(node))
+	}
+	return []any{json.Number(fmt.Sprint(length))}, nil
+}
+
+type condition interface {
+	eval(node any) bool
+}
+
+type logical struct {
+	and         bool
+	left, right condition
+}
+
+func (l logical) eval(node any) bool {
+	if l.and {
+		return l.left.eval(node) && l.right.eval(node)
+	}
+	return l.left.eval(node) || l.right.eval(node)
+}
+
+type negation struct {
+	cond condition
+}
+
+func (n negation) eval(node any) bool {
+	return !n.cond.eval(node)
+}
+
+// truthy matches when the operand exists and is neither null nor false
+type truthy struct {
+	operand operand
+}
+
+func (t truthy) eval(node any) bool {
+	value, ok := t.operand.resolve(node)
+	return ok && value != nil && value != false
+}
+
+type comparison struct {
+	op          string
+	left, right operand
+}
+
+func (c comparison) eval(node any) bool {
+	left, ok := c.left.resolve(node)
+	if !ok {
+		return false
+	}
+	right, ok := c.right.resolve(node)
+	if !ok {
+		return false
+	}
+	left, right = normalize(left), normalize(right)
+
+	switch c.op {
+	case "==":
+		return reflect.DeepEqual(left, right)
+	case "!=":
+		return !reflect.DeepEqual(left, right)
+	}
+
+	var order int
+	switch l := left.(type) {
+	case float64:
+		r, ok := right.(float64)
+		if !ok {
+			return false
+		}
+		order = cmp.Compare(l, r)
+	case string:
+		r, ok := right.(string)
+		if !ok {
+			return false
+		}
+		order = cmp.Compare(l, r)
+	default:
+		return false
+	}
+
+	switch c.op {
+	case "<":
+		return order < 0
+	case "<=":
+		return order <= 0
+	case ">":
+		return order > 0
+	case ">=":
+		return order >= 0
+	}
+	return false
+}
+
+// operand is either a relative path or a literal value
+type operand struct {
+	path    path
+	literal any
+	isPath  bool
+}
+
+func (o operand) resolve(node any) (any, bool) {
+	if !o.isPath {
+		return o.literal, true
+	}
+	values := o.path.evaluate(node)
+	if len(values) == 0 {
+		return nil, false
+	}
+	return values[0], true
+}
+
+// normalize converts numbers to float64, recursively, so values decoded
+// from documents compare equal to literals
+func normalize(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return v.String()
+		}
+		return f
+	case int:
+		return float64(v)
+	case []any:
+		out := make([]any, len(v))
+		for i := range v {
+			out[i] = normalize(v[i])
+		}
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key := range v {
+			out[key] = normalize(v[key])
+		}
+		return out
+	}
+	return value
+}
+
+func typeName(node any) string {
+	switch node.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case json.Number, float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", node)
+}
@@ -3,31 +3,61 @@ package app
 import (
 	"github.com/maniac-en/req/internal/backend/collections"
 	"github.com/maniac-en/req/internal/backend/endpoints"
+	"github.com/maniac-en/req/internal/backend/environments"
+	"github.com/maniac-en/req/internal/backend/folders"
+	"github.com/maniac-en/req/internal/backend/grpc"
 	"github.com/maniac-en/req/internal/backend/history"
 	"github.com/maniac-en/req/internal/backend/http"
+	"github.com/maniac-en/req/internal/backend/runner"
+	"github.com/maniac-en/req/internal/backend/scripting"
+	"github.com/maniac-en/req/internal/backend/snapshots"
+	"github.com/maniac-en/req/internal/backend/trash"
 )
 
 type Context struct {
 	Collections      *collections.CollectionsManager
 	Endpoints        *endpoints.EndpointsManager
+	Folders          *folders.FoldersManager
+	Environments     *environments.EnvironmentsManager
 	HTTP             *http.HTTPManager
+	GRPC             *grpc.GRPCManager
+	Scripts          *scripting.ScriptManager
+	Snapshots        *snapshots.SnapshotsManager
 	History          *history.HistoryManager
+	Trash            *trash.TrashManager
+	Runner           *runner.Runner
 	DummyDataCreated bool
 	Version          string
+	// Workspace names the open database, and DataDir holds the others
+	Workspace string
+	DataDir   string
 }
 
 func NewContext(
 	collections *collections.CollectionsManager,
 	endpoints *endpoints.EndpointsManager,
+	environments *environments.EnvironmentsManager,
 	httpManager *http.HTTPManager,
+	grpcManager *grpc.GRPCManager,
+	scriptManager *scripting.ScriptManager,
+	snapshotsManager *snapshots.SnapshotsManager,
 	history *history.HistoryManager,
+	foldersManager *folders.FoldersManager,
+	trashManager *trash.TrashManager,
 	version string,
 ) *Context {
 	return &Context{
 		Collections:      collections,
 		Endpoints:        endpoints,
+		Folders:          foldersManager,
+		Environments:     environments,
 		HTTP:             httpManager,
+		GRPC:             grpcManager,
+		Scripts:          scriptManager,
+		Snapshots:        snapshotsManager,
 		History:          history,
+		Trash:            trashManager,
+		Runner:           runner.NewRunner(collections, endpoints, environments, httpManager, grpcManager, scriptManager, snapshotsManager, history, foldersManager),
 		DummyDataCreated: false,
 		Version:          version,
 	}
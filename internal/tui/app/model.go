@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"sort"
 	"strings"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/maniac-en/req/internal/tui/keybinds"
 	"github.com/maniac-en/req/internal/tui/messages"
 	"github.com/maniac-en/req/internal/tui/styles"
+	"github.com/maniac-en/req/internal/tui/undo"
 	"github.com/maniac-en/req/internal/tui/views"
 )
 
@@ -21,6 +23,12 @@ type ViewName string
 const (
 	Collections ViewName = "collections"
 	Endpoints   ViewName = "endpoints"
+	Response    ViewName = "response"
+	History     ViewName = "history"
+	Load        ViewName = "load"
+	Trash       ViewName = "trash"
+	Revisions   ViewName = "revisions"
+	Workspaces  ViewName = "workspaces"
 )
 
 type Heading struct {
@@ -37,6 +45,11 @@ type AppModel struct {
 	keys        []key.Binding
 	help        help.Model
 	errorMsg    string
+	// notice reports what the last undo reverted, until the next key press
+	notice string
+	undo   *undo.Stack
+	// switchTo is the workspace to reopen on after the UI quits
+	switchTo string
 }
 
 func (a AppModel) Init() tea.Cmd {
@@ -65,7 +78,35 @@ func (a AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					Data:     msg.Item,
 				}
 			}
+		case "endpoints":
+			if msg.Item.Folder {
+				a.Views[Endpoints], cmd = a.Views[Endpoints].Update(msg)
+				return a, cmd
+			}
+			return a, func() tea.Msg {
+				return messages.NavigateToView{
+					ViewName: string(Response),
+					Data:     msg.Item,
+				}
+			}
 		}
+	case messages.RequestCompleted, messages.ResponseFiltered, messages.CodeCopied:
+		a.Views[Response], cmd = a.Views[Response].Update(msg)
+		return a, cmd
+	case messages.BodyFormatted:
+		// each body viewer ignores bodies formatted for another
+		a.Views[Response], cmd = a.Views[Response].Update(msg)
+		cmds = append(cmds, cmd)
+		a.Views[History], cmd = a.Views[History].Update(msg)
+		cmds = append(cmds, cmd)
+		return a, tea.Batch(cmds...)
+	case messages.HistorySearched, messages.HistoryRerun:
+		a.Views[History], cmd = a.Views[History].Update(msg)
+		return a, cmd
+	case messages.LoadProgress, messages.LoadFinished:
+		// tests keep running while another view is open
+		a.Views[Load], cmd = a.Views[Load].Update(msg)
+		return a, cmd
 	case messages.NavigateToView:
 		a.Views[a.focusedView].OnBlur()
 
@@ -79,24 +120,112 @@ func (a AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		a.focusedView = ViewName(msg.ViewName)
 		a.Views[a.focusedView].OnFocus()
-		return a, nil
+		return a, a.Views[a.focusedView].Init()
+	case messages.SwitchWorkspace:
+		log.Info("switching workspace", "from", a.ctx.Workspace, "to", msg.Name)
+		a.switchTo = msg.Name
+		return a, tea.Quit
 	case messages.ShowError:
 		log.Error("user operation failed", "error", msg.Message)
 		a.errorMsg = msg.Message
 		return a, nil
 	case tea.KeyMsg:
 		a.errorMsg = ""
+		a.notice = ""
 		switch {
 		case key.Matches(msg, keybinds.Keys.Quit):
 			return a, tea.Quit
+		case key.Matches(msg, keybinds.Keys.Undo):
+			if (a.focusedView != Collections && a.focusedView != Endpoints) || a.isCapturingInput() {
+				break
+			}
+			return a, a.undoLast()
+		case key.Matches(msg, keybinds.Keys.Trash):
+			if a.focusedView != Collections || a.isCapturingInput() {
+				break
+			}
+			return a, func() tea.Msg {
+				return messages.NavigateToView{
+					ViewName: string(Trash),
+					Data:     nil,
+				}
+			}
+		case key.Matches(msg, keybinds.Keys.Workspaces):
+			if a.focusedView != Collections || a.isCapturingInput() {
+				break
+			}
+			return a, func() tea.Msg {
+				return messages.NavigateToView{
+					ViewName: string(Workspaces),
+					Data:     nil,
+				}
+			}
+		case key.Matches(msg, keybinds.Keys.History):
+			if a.focusedView != Collections || a.isCapturingInput() {
+				break
+			}
+			return a, func() tea.Msg {
+				return messages.NavigateToView{
+					ViewName: string(History),
+					Data:     nil,
+				}
+			}
 		case key.Matches(msg, keybinds.Keys.Back):
-			if a.focusedView == Endpoints {
+			if a.isCapturingInput() {
+				break
+			}
+			if view, ok := a.Views[a.focusedView].(views.BackHandler); ok && view.Back() {
+				return a, nil
+			}
+			switch a.focusedView {
+			case History, Workspaces:
 				return a, func() tea.Msg {
 					return messages.NavigateToView{
 						ViewName: string(Collections),
 						Data:     nil,
 					}
 				}
+			case Trash:
+				// restored items show up once the list is reloaded
+				return a, tea.Sequence(func() tea.Msg {
+					return messages.NavigateToView{
+						ViewName: string(Collections),
+						Data:     nil,
+					}
+				}, func() tea.Msg {
+					return messages.RefreshItemsList{}
+				})
+			case Endpoints:
+				return a, func() tea.Msg {
+					return messages.NavigateToView{
+						ViewName: string(Collections),
+						Data:     nil,
+					}
+				}
+			case Response:
+				return a, func() tea.Msg {
+					return messages.NavigateToView{
+						ViewName: string(Endpoints),
+						Data:     nil,
+					}
+				}
+			case Load:
+				return a, func() tea.Msg {
+					return messages.NavigateToView{
+						ViewName: string(Response),
+						Data:     nil,
+					}
+				}
+			case Revisions:
+				// a restore can rename the endpoint
+				return a, tea.Sequence(func() tea.Msg {
+					return messages.NavigateToView{
+						ViewName: string(Endpoints),
+						Data:     nil,
+					}
+				}, func() tea.Msg {
+					return messages.RefreshItemsList{}
+				})
 			}
 		}
 	}
@@ -107,6 +236,42 @@ func (a AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return a, tea.Batch(cmds...)
 }
 
+// undoLast reverts the most recent change made in the collections or
+// endpoints view, and refreshes both so they show the result
+func (a *AppModel) undoLast() tea.Cmd {
+	op, ok := a.undo.Pop()
+	if !ok {
+		a.notice = "nothing to undo"
+		return nil
+	}
+	if err := op.Revert(context.Background()); err != nil {
+		log.Error("undo failed", "operation", op.Description, "error", err)
+		a.errorMsg = "undo " + op.Description + ": " + err.Error()
+		return nil
+	}
+	log.Info("undid operation", "operation", op.Description)
+	a.notice = "undid " + op.Description
+
+	var cmd tea.Cmd
+	var cmds []tea.Cmd
+	for _, name := range []ViewName{Collections, Endpoints} {
+		a.Views[name], cmd = a.Views[name].Update(messages.RefreshItemsList{})
+		cmds = append(cmds, cmd)
+	}
+	return tea.Batch(cmds...)
+}
+
+// SwitchTo returns the workspace chosen in the UI before it quit, or "" when
+// it quit for good
+func (a AppModel) SwitchTo() string {
+	return a.switchTo
+}
+
+func (a AppModel) isCapturingInput() bool {
+	view, ok := a.Views[a.focusedView].(views.InputCapturer)
+	return ok && view.IsCapturingInput()
+}
+
 func (a AppModel) View() string {
 	footer := a.Footer()
 	header := a.Header()
@@ -117,6 +282,10 @@ func (a AppModel) View() string {
 		errorBar := styles.ErrorBarStyle.Width(a.width).Render("Error: " + a.errorMsg)
 		return lipgloss.JoinVertical(lipgloss.Top, header, view, errorBar, help, footer)
 	}
+	if a.notice != "" {
+		noticeBar := styles.NoticeBarStyle.Width(a.width).Render(a.notice)
+		return lipgloss.JoinVertical(lipgloss.Top, header, view, noticeBar, help, footer)
+	}
 
 	return lipgloss.JoinVertical(lipgloss.Top, header, view, help, footer)
 }
@@ -127,8 +296,23 @@ func (a AppModel) Help() string {
 	var appHelp []key.Binding
 	appHelp = append(appHelp, a.keys...)
 
-	if a.focusedView == Endpoints {
+	switch a.focusedView {
+	case Collections:
+		if !a.isCapturingInput() {
+			appHelp = append(appHelp, keybinds.Keys.Undo, keybinds.Keys.Trash, keybinds.Keys.Workspaces)
+		}
+		appHelp = append(appHelp, keybinds.Keys.History)
+	case Endpoints:
+		if !a.isCapturingInput() {
+			appHelp = append(appHelp, keybinds.Keys.Undo)
+		}
+		appHelp = append(appHelp, keybinds.Keys.Back)
+	case Response:
 		appHelp = append(appHelp, keybinds.Keys.Back)
+	case History, Load, Trash, Revisions, Workspaces:
+		if !a.isCapturingInput() {
+			appHelp = append(appHelp, keybinds.Keys.Back)
+		}
 	}
 
 	allHelp := append(viewHelp, appHelp...)
@@ -176,7 +360,7 @@ func (a AppModel) Header() string {
 func (a AppModel) Footer() string {
 	name := styles.ApplyGradientToFooter("REQ")
 	footerText := styles.FooterSegmentStyle.Render(a.Views[a.focusedView].GetFooterSegment())
-	version := styles.FooterVersionStyle.Width(a.width - lipgloss.Width(name) - lipgloss.Width(footerText)).Render(a.ctx.Version)
+	version := styles.FooterVersionStyle.Width(a.width - lipgloss.Width(name) - lipgloss.Width(footerText)).Render(a.ctx.Workspace + "  " + a.ctx.Version)
 	return lipgloss.JoinHorizontal(lipgloss.Left, name, footerText, version)
 }
 
@@ -185,15 +369,25 @@ func NewAppModel(ctx *Context) AppModel {
 		keybinds.Keys.Quit,
 	}
 
+	// the collections and endpoints views share one history to undo
+	undoStack := undo.NewStack()
+
 	model := AppModel{
 		focusedView: Collections,
 		ctx:         ctx,
 		help:        help.New(),
 		keys:        appKeybinds,
+		undo:        undoStack,
 	}
 	model.Views = map[ViewName]views.ViewInterface{
-		Collections: views.NewCollectionsView(model.ctx.Collections, model.ctx.Endpoints, 1),
-		Endpoints:   views.NewEndpointsView(model.ctx.Endpoints, 2),
+		Collections: views.NewCollectionsView(model.ctx.Collections, model.ctx.Endpoints, undoStack, 1),
+		Endpoints:   views.NewEndpointsView(model.ctx.Endpoints, model.ctx.Folders, model.ctx.Collections, undoStack, 2),
+		Response:    views.NewResponseView(model.ctx.Runner, 3),
+		History:     views.NewHistoryView(model.ctx.History, model.ctx.Runner, 4),
+		Load:        views.NewLoadView(model.ctx.Runner, 5),
+		Trash:       views.NewTrashView(model.ctx.Trash, 6),
+		Revisions:   views.NewRevisionsView(model.ctx.Endpoints, 7),
+		Workspaces:  views.NewWorkspacesView(model.ctx.DataDir, model.ctx.Workspace, 8),
 	}
 	return model
 }
@@ -0,0 +1,199 @@
+package bodyViewer
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/maniac-en/req/internal/format"
+	"github.com/maniac-en/req/internal/tui/messages"
+	"github.com/maniac-en/req/internal/tui/styles"
+	"github.com/mattn/go-runewidth"
+)
+
+// lastID hands out viewer IDs so formatted bodies reach the viewer that asked
+// for them when several are on screen
+var lastID atomic.Int64
+
+// BodyViewer displays a response body with optional pretty-printing. Only the
+// lines inside the window are truncated and highlighted on render, so very
+// large bodies scroll without re-rendering the whole document.
+type BodyViewer struct {
+	id         int64
+	width      int
+	height     int
+	offset     int
+	preamble   []string
+	raw        []string
+	pretty     []string
+	kind       format.Kind
+	showRaw    bool
+	generation int
+	keys       KeyMap
+}
+
+type KeyMap struct {
+	Up       key.Binding
+	Down     key.Binding
+	PageUp   key.Binding
+	PageDown key.Binding
+	Toggle   key.Binding
+}
+
+func (b BodyViewer) Init() tea.Cmd {
+	return nil
+}
+
+func (b BodyViewer) Update(msg tea.Msg) (BodyViewer, tea.Cmd) {
+	switch msg := msg.(type) {
+	case messages.BodyFormatted:
+		if msg.ViewerID == b.id && msg.Generation == b.generation {
+			b.pretty = msg.Lines
+			b.clampOffset()
+		}
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, b.keys.Up):
+			b.offset--
+		case key.Matches(msg, b.keys.Down):
+			b.offset++
+		case key.Matches(msg, b.keys.PageUp):
+			b.offset -= b.height
+		case key.Matches(msg, b.keys.PageDown):
+			b.offset += b.height
+		case key.Matches(msg, b.keys.Toggle):
+			b.showRaw = !b.showRaw
+		}
+		b.clampOffset()
+	}
+	return b, nil
+}
+
+func (b BodyViewer) View() string {
+	if b.height <= 0 {
+		return ""
+	}
+
+	lines := make([]string, 0, b.height)
+	highlight := !b.showRaw && b.pretty != nil
+	for i := b.offset; i < b.offset+b.height && i < b.lineCount(); i++ {
+		if i < len(b.preamble) {
+			lines = append(lines, b.preamble[i])
+			continue
+		}
+		line := truncate(b.bodyLines()[i-len(b.preamble)], b.width)
+		if highlight {
+			line = styles.Highlight(b.kind, line)
+		}
+		lines = append(lines, line)
+	}
+	for len(lines) < b.height {
+		lines = append(lines, "")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (b BodyViewer) Help() []key.Binding {
+	return []key.Binding{
+		b.keys.Up,
+		b.keys.Down,
+		b.keys.PageDown,
+		b.keys.PageUp,
+		b.keys.Toggle,
+	}
+}
+
+// SetContent replaces the displayed body. The preamble is shown as-is above
+// the body. Pretty-printing runs in the returned command.
+func (b *BodyViewer) SetContent(preamble []string, body, contentType string) tea.Cmd {
+	b.generation++
+	b.offset = 0
+	b.preamble = preamble
+	b.raw = strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n")
+	b.pretty = nil
+	b.kind = format.Detect(contentType, body)
+
+	id := b.id
+	generation := b.generation
+	kind := b.kind
+	return func() tea.Msg {
+		pretty, err := format.Pretty(kind, body)
+		if err != nil {
+			pretty = body
+		}
+		return messages.BodyFormatted{
+			ViewerID:   id,
+			Generation: generation,
+			Lines:      strings.Split(strings.ReplaceAll(pretty, "\r\n", "\n"), "\n"),
+		}
+	}
+}
+
+func (b *BodyViewer) SetSize(width, height int) {
+	b.width = width
+	b.height = height
+	b.clampOffset()
+}
+
+func (b BodyViewer) Kind() format.Kind {
+	return b.kind
+}
+
+func (b BodyViewer) IsRaw() bool {
+	return b.showRaw
+}
+
+func (b BodyViewer) ScrollPercent() float64 {
+	maxOffset := b.lineCount() - b.height
+	if maxOffset <= 0 {
+		return 1
+	}
+	return float64(b.offset) / float64(maxOffset)
+}
+
+func (b BodyViewer) bodyLines() []string {
+	if b.showRaw || b.pretty == nil {
+		return b.raw
+	}
+	return b.pretty
+}
+
+func (b BodyViewer) lineCount() int {
+	return len(b.preamble) + len(b.bodyLines())
+}
+
+func (b *BodyViewer) clampOffset() {
+	b.offset = min(b.offset, b.lineCount()-b.height)
+	b.offset = max(b.offset, 0)
+}
+
+// truncate cuts a line to the given display width without scanning past it,
+// which keeps minified multi-megabyte lines cheap to render.
+func truncate(line string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	var sb strings.Builder
+	used := 0
+	for _, r := range line {
+		if r == '\t' {
+			r = ' '
+		}
+		w := runewidth.RuneWidth(r)
+		if used+w > width {
+			break
+		}
+		sb.WriteRune(r)
+		used += w
+	}
+	return sb.String()
+}
+
+func NewBodyViewer(keys KeyMap) BodyViewer {
+	return BodyViewer{
+		id:   lastID.Add(1),
+		keys: keys,
+		kind: format.Plain,
+	}
+}
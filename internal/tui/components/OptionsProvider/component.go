@@ -2,6 +2,7 @@ package optionsProvider
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
@@ -10,6 +11,7 @@ import (
 	"github.com/maniac-en/req/internal/log"
 	"github.com/maniac-en/req/internal/tui/keybinds"
 	"github.com/maniac-en/req/internal/tui/messages"
+	"github.com/maniac-en/req/internal/tui/styles"
 )
 
 type focusedComp string
@@ -30,12 +32,16 @@ type OptionsProvider[T, U any] struct {
 	getItems       func(context.Context) ([]T, error)
 	itemMapper     func([]T) []list.Item
 	source         string
+	// confirming is the item waiting for the delete to be confirmed
+	confirming *Option
 }
 
 type Option struct {
 	Name    string
 	ID      int64
 	Subtext string
+	// Folder marks options that open a folder rather than an item
+	Folder bool
 }
 
 func (o Option) Title() string       { return o.Name }
@@ -56,6 +62,15 @@ func (o OptionsProvider[T, U]) Update(msg tea.Msg) (OptionsProvider[T, U], tea.C
 		o.width = msg.Width
 		o.list.SetSize(o.list.Width(), o.height)
 	case tea.KeyMsg:
+		if o.confirming != nil {
+			item := *o.confirming
+			o.confirming = nil
+			o.list.SetSize(o.list.Width(), o.height)
+			if key.Matches(msg, o.keys.Confirm) {
+				return o, func() tea.Msg { return messages.DeleteItem{ItemID: item.ID} }
+			}
+			return o, nil
+		}
 		switch o.focused {
 		case listComponent:
 			if !o.IsFiltering() {
@@ -66,7 +81,11 @@ func (o OptionsProvider[T, U]) Update(msg tea.Msg) (OptionsProvider[T, U], tea.C
 					o.focused = textComponent
 					return o, tea.Batch(cmds...)
 				case key.Matches(msg, o.keys.DeleteItem):
-					return o, func() tea.Msg { return messages.DeleteItem{ItemID: int64(o.GetSelected().ID)} }
+					if selected := o.GetSelected(); selected.ID > 0 {
+						o.confirming = &selected
+						o.list.SetSize(o.list.Width(), o.height-lipgloss.Height(o.confirmPrompt()))
+					}
+					return o, nil
 				case key.Matches(msg, o.keys.Choose):
 					return o, func() tea.Msg {
 						return messages.ChooseItem[Option]{
@@ -104,12 +123,25 @@ func (o OptionsProvider[T, U]) Update(msg tea.Msg) (OptionsProvider[T, U], tea.C
 }
 
 func (o OptionsProvider[T, U]) View() string {
+	if o.confirming != nil {
+		return lipgloss.JoinVertical(lipgloss.Left, o.list.View(), o.confirmPrompt())
+	}
 	if o.focused == textComponent {
 		return lipgloss.JoinVertical(lipgloss.Left, o.list.View(), o.input.View())
 	}
 	return o.list.View()
 }
 
+func (o OptionsProvider[T, U]) confirmPrompt() string {
+	target := fmt.Sprintf("%q", o.confirming.Name)
+	// collections and folders take their contents along
+	if o.confirming.Folder || o.source == "collections" {
+		target += " and everything in it"
+	}
+	prompt := fmt.Sprintf("Move %s to the trash? Press %s to confirm, any other key to cancel", target, o.keys.Confirm.Help().Key)
+	return styles.InputStyle.Render(prompt)
+}
+
 func (o *OptionsProvider[T, U]) OnFocus() {
 }
 
@@ -125,13 +157,40 @@ func (o OptionsProvider[T, U]) GetSelected() Option {
 			Subtext: "",
 		}
 	}
-	return o.list.SelectedItem().(Option)
+	// nothing is selected in an empty list, or while a filter is reapplied
+	// to refreshed items
+	selected, ok := o.list.SelectedItem().(Option)
+	if !ok {
+		return Option{ID: -1}
+	}
+	return selected
+}
+
+// Items returns the options shown, ignoring any filter
+func (o OptionsProvider[T, U]) Items() []list.Item {
+	return o.list.Items()
+}
+
+// Index is the position of the selected item
+func (o OptionsProvider[T, U]) Index() int {
+	return o.list.Index()
+}
+
+// Select moves the cursor to the item at index
+func (o *OptionsProvider[T, U]) Select(index int) {
+	o.list.Select(index)
 }
 
 func (o OptionsProvider[T, U]) IsFiltering() bool {
 	return o.list.FilterState() == list.Filtering
 }
 
+// IsCapturingInput reports whether keys are going to the filter or the
+// add/edit input
+func (o OptionsProvider[T, U]) IsCapturingInput() bool {
+	return o.focused == textComponent || o.IsFiltering() || o.confirming != nil
+}
+
 func (o *OptionsProvider[T, U]) RefreshItems() {
 	newItems, err := o.getItems(context.Background())
 	if err != nil {
@@ -143,6 +202,9 @@ func (o *OptionsProvider[T, U]) RefreshItems() {
 
 func (o *OptionsProvider[T, U]) Help() []key.Binding {
 	var binds []key.Binding
+	if o.confirming != nil {
+		return []key.Binding{o.keys.Confirm}
+	}
 	switch o.focused {
 	case listComponent:
 		if o.IsFiltering() {
@@ -17,6 +17,7 @@ type ListKeyMap struct {
 	Choose               key.Binding
 	Accept               key.Binding
 	Back                 key.Binding
+	Confirm              key.Binding
 }
 
 func (c ListKeyMap) ShortHelp() []key.Binding {
@@ -45,5 +46,6 @@ func NewListKeyMap() *ListKeyMap {
 		Choose:               Keys.Choose,
 		Accept:               Keys.Choose,
 		Back:                 Keys.Back,
+		Confirm:              Keys.Confirm,
 	}
 }
@@ -19,6 +19,31 @@ type Keymaps struct {
 	ClearFilter          key.Binding
 	CancelWhileFiltering key.Binding
 	AcceptWhileFiltering key.Binding
+	Send                 key.Binding
+	ToggleRaw            key.Binding
+	History              key.Binding
+	Diff                 key.Binding
+	DiffRun              key.Binding
+	Pin                  key.Binding
+	LoadTest             key.Binding
+	StartStop            key.Binding
+	NextField            key.Binding
+	PrevField            key.Binding
+	SaveReport           key.Binding
+	CopyCode             key.Binding
+	Move                 key.Binding
+	MoveToCollection     key.Binding
+	Duplicate            key.Binding
+	MoveUp               key.Binding
+	MoveDown             key.Binding
+	Undo                 key.Binding
+	Trash                key.Binding
+	EmptyTrash           key.Binding
+	Confirm              key.Binding
+	Revisions            key.Binding
+	Restore              key.Binding
+	CompareCurrent       key.Binding
+	Workspaces           key.Binding
 	Quit                 key.Binding
 }
 
@@ -75,6 +100,106 @@ var Keys = Keymaps{
 		key.WithKeys("x", "backspace"),
 		key.WithHelp("x", "delete"),
 	),
+	Send: key.NewBinding(
+		key.WithKeys("r"),
+		key.WithHelp("r", "resend"),
+	),
+	ToggleRaw: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "raw/pretty"),
+	),
+	History: key.NewBinding(
+		key.WithKeys("H"),
+		key.WithHelp("H", "history"),
+	),
+	Diff: key.NewBinding(
+		key.WithKeys("d"),
+		key.WithHelp("d", "mark/diff"),
+	),
+	DiffRun: key.NewBinding(
+		key.WithKeys("D"),
+		key.WithHelp("D", "diff with fresh run"),
+	),
+	Pin: key.NewBinding(
+		key.WithKeys("P"),
+		key.WithHelp("P", "pin snapshot"),
+	),
+	LoadTest: key.NewBinding(
+		key.WithKeys("L"),
+		key.WithHelp("L", "load test"),
+	),
+	StartStop: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "start/stop"),
+	),
+	NextField: key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("tab", "next field"),
+	),
+	PrevField: key.NewBinding(
+		key.WithKeys("shift+tab"),
+		key.WithHelp("shift+tab", "prev field"),
+	),
+	SaveReport: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "save report"),
+	),
+	CopyCode: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "copy as code"),
+	),
+	Move: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "move"),
+	),
+	MoveToCollection: key.NewBinding(
+		key.WithKeys("M"),
+		key.WithHelp("M", "move to collection"),
+	),
+	Duplicate: key.NewBinding(
+		key.WithKeys("d"),
+		key.WithHelp("d", "duplicate"),
+	),
+	MoveUp: key.NewBinding(
+		key.WithKeys("K", "shift+up"),
+		key.WithHelp("K/shift+↑", "move up"),
+	),
+	MoveDown: key.NewBinding(
+		key.WithKeys("J", "shift+down"),
+		key.WithHelp("J/shift+↓", "move down"),
+	),
+	Undo: key.NewBinding(
+		key.WithKeys("u"),
+		key.WithHelp("u", "undo"),
+	),
+	Trash: key.NewBinding(
+		key.WithKeys("T"),
+		key.WithHelp("T", "trash"),
+	),
+	EmptyTrash: key.NewBinding(
+		key.WithKeys("E"),
+		key.WithHelp("E", "empty trash"),
+	),
+	Confirm: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "confirm"),
+	),
+	Revisions: key.NewBinding(
+		key.WithKeys("R"),
+		key.WithHelp("R", "revisions"),
+	),
+	Restore: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "restore"),
+	),
+	CompareCurrent: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "compare with current"),
+	),
+	Workspaces: key.NewBinding(
+		key.WithKeys("W"),
+		key.WithHelp("W", "workspaces"),
+	),
 	Quit: key.NewBinding(
 		key.WithKeys("ctrl+c"),
 		key.WithHelp("ctrl+c", "quit"),
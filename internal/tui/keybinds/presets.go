@@ -0,0 +1,52 @@
+package keybinds
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// presets remap actions for people used to an editor's keys, on top of the
+// defaults which already move with h, j, k and l
+var presets = map[string]map[string][]string{
+	"vim": {
+		"prev_page":   {"ctrl+b", "h", "pgup", "ctrl+u", "left"},
+		"next_page":   {"ctrl+f", "l", "pgdown", "ctrl+d", "right"},
+		"insert_item": {"o", "a"},
+		"remove":      {"x", "d", "backspace"},
+		"duplicate":   {"y"},
+	},
+	"emacs": {
+		"up":                     {"ctrl+p", "up"},
+		"down":                   {"ctrl+n", "down"},
+		"prev_page":              {"alt+v", "pgup"},
+		"next_page":              {"ctrl+v", "pgdown"},
+		"back":                   {"ctrl+g", "esc"},
+		"clear_filter":           {"ctrl+g", "esc"},
+		"cancel_while_filtering": {"ctrl+g", "esc"},
+		"filter":                 {"ctrl+s", "/"},
+		"remove":                 {"ctrl+d", "backspace"},
+		"undo":                   {"ctrl+_", "u"},
+		"next_field":             {"ctrl+n", "tab"},
+		"prev_field":             {"ctrl+p", "shift+tab"},
+	},
+}
+
+// PresetNames lists the presets Preset accepts, besides default
+func PresetNames() []string {
+	return slices.Sorted(maps.Keys(presets))
+}
+
+// Preset returns the keys a preset gives to actions, to pass to Remap. The
+// default preset and an empty name change nothing.
+func Preset(name string) (map[string][]string, error) {
+	if name == "" || name == "default" {
+		return map[string][]string{}, nil
+	}
+	preset, ok := presets[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown preset %q, expected default, %s", name, strings.Join(PresetNames(), " or "))
+	}
+	return maps.Clone(preset), nil
+}
@@ -0,0 +1,100 @@
+package keybinds
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// actions maps the config file names of the bindings in Keys, the field
+// names in snake case such as insert_item, to the bindings. Bindings without
+// keys are left out, nothing listens to them.
+func actions() map[string]*key.Binding {
+	bindings := map[string]*key.Binding{}
+	value := reflect.ValueOf(&Keys).Elem()
+	for i := range value.NumField() {
+		binding := value.Field(i).Addr().Interface().(*key.Binding)
+		if len(binding.Keys()) > 0 {
+			bindings[snakeCase(value.Type().Field(i).Name)] = binding
+		}
+	}
+	return bindings
+}
+
+// ActionNames lists the actions Remap accepts
+func ActionNames() []string {
+	return slices.Sorted(maps.Keys(actions()))
+}
+
+// Remap binds actions to new keys, keeping their help descriptions. Nothing
+// changes when an action is unknown or has no keys, or when two actions of
+// the same view would end up sharing a key.
+func Remap(remapped map[string][]string) error {
+	bindings := actions()
+	keys := map[string][]string{}
+	for name, binding := range bindings {
+		keys[name] = binding.Keys()
+	}
+	for _, name := range slices.Sorted(maps.Keys(remapped)) {
+		if _, ok := bindings[name]; !ok {
+			return fmt.Errorf("unknown action %q, expected one of %s", name, strings.Join(ActionNames(), ", "))
+		}
+		if len(remapped[name]) == 0 || slices.Contains(remapped[name], "") {
+			return fmt.Errorf("%s: expected at least one key and no empty ones", name)
+		}
+		keys[name] = remapped[name]
+	}
+	if err := conflicts(keys); err != nil {
+		return err
+	}
+
+	for name, keys := range remapped {
+		binding := bindings[name]
+		binding.SetKeys(keys...)
+		binding.SetHelp(helpKeys(keys), binding.Help().Desc)
+	}
+	return nil
+}
+
+// keySymbols shortens key names the way the default help does
+var keySymbols = map[string]string{
+	"up":         "↑",
+	"down":       "↓",
+	"left":       "←",
+	"right":      "→",
+	"shift+up":   "shift+↑",
+	"shift+down": "shift+↓",
+	"pgdown":     "pgdn",
+}
+
+// helpKeys is how the help bar shows a remapped binding, with the first few
+// keys as it has room for
+func helpKeys(keys []string) string {
+	shown := make([]string, min(len(keys), 3))
+	for i, k := range keys[:len(shown)] {
+		shown[i] = k
+		if symbol, ok := keySymbols[k]; ok {
+			shown[i] = symbol
+		}
+	}
+	return strings.Join(shown, "/")
+}
+
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
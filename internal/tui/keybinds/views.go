@@ -0,0 +1,66 @@
+package keybinds
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// views lists the actions listening for keys at the same time, in each view
+// or while a view takes input, with quit and back from the app included. Two
+// of them sharing a key makes one of them unreachable.
+var views = map[string][]string{
+	"collections":        {"quit", "back", "undo", "trash", "workspaces", "history", "up", "down", "prev_page", "next_page", "filter", "clear_filter", "insert_item", "edit_item", "remove", "choose", "duplicate"},
+	"endpoints":          {"quit", "back", "undo", "up", "down", "prev_page", "next_page", "filter", "clear_filter", "insert_item", "edit_item", "remove", "choose", "move", "move_to_collection", "duplicate", "move_up", "move_down", "revisions"},
+	"collection picker":  {"quit", "back", "prev_page", "next_page", "choose"},
+	"response":           {"quit", "back", "up", "down", "prev_page", "next_page", "toggle_raw", "filter", "send", "load_test", "copy_code"},
+	"history":            {"quit", "back", "up", "down", "prev_page", "next_page", "toggle_raw", "filter", "choose", "diff", "diff_run", "pin"},
+	"load test":          {"quit", "back", "start_stop", "edit_item", "save_report"},
+	"load test settings": {"quit", "cancel_while_filtering", "start_stop", "next_field", "prev_field"},
+	"trash":              {"quit", "back", "up", "down", "choose", "empty_trash"},
+	"revisions":          {"quit", "back", "up", "down", "compare_current", "restore"},
+	"workspaces":         {"quit", "back", "up", "down", "choose", "insert_item"},
+	"workspace name":     {"quit", "cancel_while_filtering", "choose"},
+	"filters":            {"quit", "cancel_while_filtering", "accept_while_filtering"},
+}
+
+// shared pairs actions that take the same key on purpose: a filter is
+// cleared with the key that goes back once there is none
+var shared = [][2]string{
+	{"back", "clear_filter"},
+}
+
+// conflicts reports keys bound to two actions of the same view, given the
+// keys of every action
+func conflicts(keys map[string][]string) error {
+	// the same clash usually shows up in several views, it is reported once
+	found := map[string][]string{}
+	for _, view := range slices.Sorted(maps.Keys(views)) {
+		owners := map[string]string{}
+		for _, action := range views[view] {
+			for _, k := range keys[action] {
+				other, ok := owners[k]
+				owners[k] = action
+				if !ok || other == action || isShared(other, action) {
+					continue
+				}
+				clash := fmt.Sprintf("%q is bound to both %s and %s", k, other, action)
+				if !slices.Contains(found[clash], view) {
+					found[clash] = append(found[clash], view)
+				}
+			}
+		}
+	}
+
+	var errs []error
+	for _, clash := range slices.Sorted(maps.Keys(found)) {
+		errs = append(errs, fmt.Errorf("%s (%s)", clash, strings.Join(found[clash], ", ")))
+	}
+	return errors.Join(errs...)
+}
+
+func isShared(a, b string) bool {
+	return slices.Contains(shared, [2]string{a, b}) || slices.Contains(shared, [2]string{b, a})
+}
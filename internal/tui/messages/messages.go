@@ -1,5 +1,11 @@
 package messages
 
+import (
+	"github.com/maniac-en/req/internal/backend/history"
+	"github.com/maniac-en/req/internal/backend/load"
+	"github.com/maniac-en/req/internal/backend/runner"
+)
+
 type ItemAdded struct {
 	Item string
 }
@@ -29,3 +35,56 @@ type RefreshItemsList struct{}
 type ShowError struct {
 	Message string
 }
+
+type RequestCompleted struct {
+	EndpointID int64
+	Result     *runner.Result
+	Err        error
+}
+
+type BodyFormatted struct {
+	ViewerID   int64
+	Generation int
+	Lines      []string
+}
+
+type ResponseFiltered struct {
+	Generation int
+	Document   any
+	Body       string
+	Err        error
+}
+
+type HistorySearched struct {
+	Generation int
+	Result     history.PaginatedHistory
+	Err        error
+}
+
+type HistoryRerun struct {
+	Entry  history.HistoryEntity
+	Result *runner.Result
+	Err    error
+}
+
+type LoadProgress struct {
+	Generation int
+	Report     load.Report
+}
+
+type LoadFinished struct {
+	Generation int
+	Report     load.Report
+	Err        error
+}
+
+type CodeCopied struct {
+	EndpointID int64
+	Language   string
+	Err        error
+}
+
+// SwitchWorkspace restarts the UI on another workspace's database
+type SwitchWorkspace struct {
+	Name string
+}
@@ -5,13 +5,27 @@ import (
 )
 
 var (
-	footerNameStyle    = lipgloss.NewStyle().Bold(true).Background(footerNameBG)
-	footerNameBGStyle  = lipgloss.NewStyle().Background(footerNameBG).Padding(0, 3, 0)
-	FooterSegmentStyle = lipgloss.NewStyle().Background(footerSegmentBG).PaddingLeft(2).Foreground(footerSegmentFG)
-	FooterVersionStyle = lipgloss.NewStyle().Background(footerSegmentBG).AlignHorizontal(lipgloss.Right).PaddingRight(2).Foreground(footerSegmentFG)
-	TabHeadingInactive = lipgloss.NewStyle().Width(25).AlignHorizontal(lipgloss.Center).Border(lipgloss.NormalBorder(), false, false, false, true)
-	TabHeadingActive   = lipgloss.NewStyle().Background(accent).Foreground(headingForeground).Width(25).AlignHorizontal(lipgloss.Center).Border(lipgloss.NormalBorder(), false, false, false, true)
-	HelpStyle          = lipgloss.NewStyle().Padding(1, 0, 1, 2)
-	AppHelpStyle       = lipgloss.NewStyle().Padding(1, 0).Foreground(helpFG)
-	ErrorBarStyle      = lipgloss.NewStyle().Background(lipgloss.Color("#FF0000")).Foreground(lipgloss.Color("#FFFFFF")).Padding(0, 1)
+	footerNameStyle    lipgloss.Style
+	footerNameBGStyle  lipgloss.Style
+	FooterSegmentStyle lipgloss.Style
+	FooterVersionStyle lipgloss.Style
+	TabHeadingInactive lipgloss.Style
+	TabHeadingActive   lipgloss.Style
+	HelpStyle          lipgloss.Style
+	AppHelpStyle       lipgloss.Style
+	NoticeBarStyle     lipgloss.Style
+	ErrorBarStyle      lipgloss.Style
 )
+
+func buildAppStyles() {
+	footerNameStyle = highlight(lipgloss.NewStyle().Bold(true).Background(footerNameBG))
+	footerNameBGStyle = highlight(lipgloss.NewStyle().Background(footerNameBG).Padding(0, 3, 0))
+	FooterSegmentStyle = highlight(lipgloss.NewStyle().Background(footerSegmentBG).PaddingLeft(2).Foreground(footerSegmentFG))
+	FooterVersionStyle = highlight(lipgloss.NewStyle().Background(footerSegmentBG).AlignHorizontal(lipgloss.Right).PaddingRight(2).Foreground(footerSegmentFG))
+	TabHeadingInactive = lipgloss.NewStyle().Width(25).AlignHorizontal(lipgloss.Center).Border(lipgloss.NormalBorder(), false, false, false, true)
+	TabHeadingActive = highlight(lipgloss.NewStyle().Background(accent).Foreground(headingForeground).Width(25).AlignHorizontal(lipgloss.Center).Border(lipgloss.NormalBorder(), false, false, false, true))
+	HelpStyle = lipgloss.NewStyle().Padding(1, 0, 1, 2)
+	AppHelpStyle = lipgloss.NewStyle().Padding(1, 0).Foreground(helpFG)
+	NoticeBarStyle = highlight(lipgloss.NewStyle().Background(accent).Foreground(headingForeground).Padding(0, 1))
+	ErrorBarStyle = highlight(strong(lipgloss.NewStyle().Background(danger).Foreground(dangerForeground).Padding(0, 1)))
+}
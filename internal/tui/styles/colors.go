@@ -1,14 +1,99 @@
 package styles
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"github.com/charmbracelet/lipgloss"
+)
 
+// The palette holds a light and a dark variant of each color, lipgloss picks
+// one from the background bubbletea asks the terminal for when it starts.
+// Themes that don't adapt set both.
 var (
-	footerNameBG      = lipgloss.Color("#1a1a1a")
-	footerNameFGFrom  = lipgloss.Color("#41A0AE")
-	footerNameFGTo    = lipgloss.Color("#77F07F")
-	accent            = lipgloss.Color("#77F07F")
-	headingForeground = lipgloss.Color("#000000")
-	footerSegmentBG   = lipgloss.Color("#262626")
-	footerSegmentFG   = lipgloss.Color("#656565")
-	helpFG            = lipgloss.Color("#3C3C3C")
+	footerNameBG      lipgloss.AdaptiveColor
+	footerNameFGFrom  lipgloss.AdaptiveColor
+	footerNameFGTo    lipgloss.AdaptiveColor
+	accent            lipgloss.AdaptiveColor
+	headingForeground lipgloss.AdaptiveColor
+	footerSegmentBG   lipgloss.AdaptiveColor
+	footerSegmentFG   lipgloss.AdaptiveColor
+	helpFG            lipgloss.AdaptiveColor
+	danger            lipgloss.AdaptiveColor
+	dangerForeground  lipgloss.AdaptiveColor
+	removed           lipgloss.AdaptiveColor
+	syntaxKey         lipgloss.AdaptiveColor
+	syntaxString      lipgloss.AdaptiveColor
+	syntaxNumber      lipgloss.AdaptiveColor
+	syntaxKeyword     lipgloss.AdaptiveColor
+	syntaxPunctuation lipgloss.AdaptiveColor
+	syntaxComment     lipgloss.AdaptiveColor
 )
+
+// palette names the colors that themes and the config file set
+var palette = map[string]*lipgloss.AdaptiveColor{
+	"footer_name_background": &footerNameBG,
+	"footer_name_from":       &footerNameFGFrom,
+	"footer_name_to":         &footerNameFGTo,
+	"accent":                 &accent,
+	"heading":                &headingForeground,
+	"footer_background":      &footerSegmentBG,
+	"footer_foreground":      &footerSegmentFG,
+	"help":                   &helpFG,
+	"danger":                 &danger,
+	"danger_foreground":      &dangerForeground,
+	"removed":                &removed,
+	"syntax_key":             &syntaxKey,
+	"syntax_string":          &syntaxString,
+	"syntax_number":          &syntaxNumber,
+	"syntax_keyword":         &syntaxKeyword,
+	"syntax_punctuation":     &syntaxPunctuation,
+	"syntax_comment":         &syntaxComment,
+}
+
+// plain is set by the high contrast theme, which shows with reverse video and
+// bold text what the other themes show with colors
+var plain bool
+
+func init() {
+	setPalette(themes[Light], themes[Dark])
+	build()
+}
+
+// build creates the styles from the palette
+func build() {
+	buildAppStyles()
+	buildCollectionsStyles()
+	buildDiffStyles()
+	buildHistoryStyles()
+	buildResponseStyles()
+	buildSyntaxStyles()
+}
+
+func setPalette(light, dark map[string]string) {
+	for name, color := range palette {
+		*color = lipgloss.AdaptiveColor{Light: light[name], Dark: dark[name]}
+	}
+}
+
+// resolve picks the variant of a color lipgloss would use, for the footer
+// gradient which is computed from it
+func resolve(color lipgloss.AdaptiveColor) string {
+	if color.Light == color.Dark || lipgloss.HasDarkBackground() {
+		return color.Dark
+	}
+	return color.Light
+}
+
+// highlight sets apart what the other themes give a background color
+func highlight(style lipgloss.Style) lipgloss.Style {
+	if plain {
+		return style.Reverse(true)
+	}
+	return style
+}
+
+// strong sets apart what the other themes give a foreground color
+func strong(style lipgloss.Style) lipgloss.Style {
+	if plain {
+		return style.Bold(true)
+	}
+	return style
+}
@@ -0,0 +1,26 @@
+package styles
+
+import "github.com/charmbracelet/lipgloss"
+
+var (
+	DiffTitleStyle     lipgloss.Style
+	DiffLineNoStyle    lipgloss.Style
+	DiffRemovedStyle   lipgloss.Style
+	DiffAddedStyle     lipgloss.Style
+	DiffSeparatorStyle lipgloss.Style
+	HistoryMarkStyle   lipgloss.Style
+)
+
+func buildDiffStyles() {
+	DiffTitleStyle = lipgloss.NewStyle().Bold(true)
+	DiffLineNoStyle = lipgloss.NewStyle().Foreground(footerSegmentFG)
+	DiffRemovedStyle = lipgloss.NewStyle().Foreground(removed)
+	DiffAddedStyle = lipgloss.NewStyle().Foreground(accent)
+	DiffSeparatorStyle = lipgloss.NewStyle().Foreground(footerSegmentFG)
+	HistoryMarkStyle = strong(lipgloss.NewStyle().Foreground(syntaxNumber))
+	if plain {
+		// without colors, changes are told apart by their decoration
+		DiffRemovedStyle = DiffRemovedStyle.Strikethrough(true)
+		DiffAddedStyle = DiffAddedStyle.Underline(true)
+	}
+}
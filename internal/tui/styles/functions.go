@@ -6,7 +6,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-func gradientText(text string, startColor, endColor lipgloss.Color, base, additional lipgloss.Style) string {
+func gradientText(text string, startColor, endColor string, base, additional lipgloss.Style) string {
 	n := len(text)
 	result := ""
 
@@ -21,9 +21,9 @@ func gradientText(text string, startColor, endColor lipgloss.Color, base, additi
 	return additional.Render(result)
 }
 
-func interpolateColor(start, end lipgloss.Color, ratio float64) string {
-	r1, g1, b1 := hexToRGB(string(start))
-	r2, g2, b2 := hexToRGB(string(end))
+func interpolateColor(start, end string, ratio float64) string {
+	r1, g1, b1 := hexToRGB(start)
+	r2, g2, b2 := hexToRGB(end)
 
 	r := int(float64(r1) + (float64(r2)-float64(r1))*ratio)
 	g := int(float64(g1) + (float64(g2)-float64(g1))*ratio)
@@ -39,5 +39,5 @@ func hexToRGB(hex string) (int, int, int) {
 }
 
 func ApplyGradientToFooter(text string) string {
-	return gradientText("REQ", footerNameFGFrom, footerNameFGTo, footerNameStyle, footerNameBGStyle)
+	return gradientText("REQ", resolve(footerNameFGFrom), resolve(footerNameFGTo), footerNameStyle, footerNameBGStyle)
 }
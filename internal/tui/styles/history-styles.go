@@ -0,0 +1,19 @@
+package styles
+
+import "github.com/charmbracelet/lipgloss"
+
+var (
+	HistoryRowStyle         lipgloss.Style
+	HistorySelectedRowStyle lipgloss.Style
+	HistoryStatusOKStyle    lipgloss.Style
+	HistoryStatusErrorStyle lipgloss.Style
+	HistoryMetaStyle        lipgloss.Style
+)
+
+func buildHistoryStyles() {
+	HistoryRowStyle = lipgloss.NewStyle().PaddingLeft(2)
+	HistorySelectedRowStyle = strong(lipgloss.NewStyle().Foreground(accent).PaddingLeft(1).Border(lipgloss.NormalBorder(), false, false, false, true).BorderForeground(accent))
+	HistoryStatusOKStyle = lipgloss.NewStyle().Foreground(accent)
+	HistoryStatusErrorStyle = strong(lipgloss.NewStyle().Foreground(danger))
+	HistoryMetaStyle = lipgloss.NewStyle().Foreground(footerSegmentFG)
+}
@@ -0,0 +1,100 @@
+package styles
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Output returns the terminal the UI should draw on. The high contrast theme
+// gets one that drops colors from every style, those of the bubbles
+// components included, while keeping bold, underline and reverse video.
+// Lipgloss would drop those along with the colors once NO_COLOR is set.
+func Output(terminal *os.File) io.Writer {
+	if !plain {
+		return terminal
+	}
+	return &noColorOutput{File: terminal}
+}
+
+// noColorOutput is still an *os.File to bubbletea, which sizes the UI and
+// sets raw mode through it
+type noColorOutput struct {
+	*os.File
+	// pending holds an escape sequence cut off at the end of a write
+	pending []byte
+}
+
+func (o *noColorOutput) Write(p []byte) (int, error) {
+	data := append(o.pending, p...)
+	o.pending = nil
+
+	var out []byte
+	for i := 0; i < len(data); {
+		if data[i] != 0x1b || i+1 >= len(data) || data[i+1] != '[' {
+			if data[i] == 0x1b && i+1 == len(data) {
+				o.pending = append(o.pending, data[i:]...)
+				break
+			}
+			out = append(out, data[i])
+			i++
+			continue
+		}
+		// a CSI sequence is parameters and intermediates up to a final byte
+		end := i + 2
+		for end < len(data) && (data[end] < 0x40 || data[end] > 0x7e) {
+			end++
+		}
+		if end == len(data) {
+			o.pending = append(o.pending, data[i:]...)
+			break
+		}
+		if data[end] == 'm' {
+			out = append(out, withoutColors(data[i+2:end])...)
+		} else {
+			out = append(out, data[i:end+1]...)
+		}
+		i = end + 1
+	}
+
+	if _, err := o.File.Write(out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// withoutColors rewrites the parameters of a graphics sequence without the
+// ones setting foreground, background or underline colors, and drops the
+// sequence when nothing is left
+func withoutColors(params []byte) []byte {
+	if len(params) == 0 {
+		return []byte("\x1b[m")
+	}
+	fields := strings.Split(string(params), ";")
+	var kept []string
+	for i := 0; i < len(fields); i++ {
+		code, err := strconv.Atoi(strings.SplitN(fields[i], ":", 2)[0])
+		switch {
+		case err != nil:
+			kept = append(kept, fields[i])
+		case code == 38 || code == 48 || code == 58:
+			// extended colors take their values as the next parameters
+			if strings.Contains(fields[i], ":") {
+				continue
+			}
+			if i+1 < len(fields) && fields[i+1] == "5" {
+				i += 2
+			} else if i+1 < len(fields) && fields[i+1] == "2" {
+				i += 4
+			}
+		case code >= 30 && code <= 49, code == 59, code >= 90 && code <= 97, code >= 100 && code <= 107:
+		default:
+			kept = append(kept, fields[i])
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	return []byte("\x1b[" + strings.Join(kept, ";") + "m")
+}
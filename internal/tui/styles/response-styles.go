@@ -0,0 +1,23 @@
+package styles
+
+import "github.com/charmbracelet/lipgloss"
+
+var (
+	ResponseTitleStyle   lipgloss.Style
+	StatusSuccessStyle   lipgloss.Style
+	StatusErrorStyle     lipgloss.Style
+	ResponseMetaStyle    lipgloss.Style
+	ResponseSectionStyle lipgloss.Style
+	ResponseBodyStyle    lipgloss.Style
+	ResponseFilterStyle  lipgloss.Style
+)
+
+func buildResponseStyles() {
+	ResponseTitleStyle = lipgloss.NewStyle().Bold(true).Padding(0, 2)
+	StatusSuccessStyle = highlight(lipgloss.NewStyle().Background(accent).Foreground(headingForeground).Padding(0, 1))
+	StatusErrorStyle = highlight(strong(lipgloss.NewStyle().Background(danger).Foreground(dangerForeground).Padding(0, 1)))
+	ResponseMetaStyle = lipgloss.NewStyle().Foreground(footerSegmentFG).PaddingLeft(1)
+	ResponseSectionStyle = lipgloss.NewStyle().Bold(true).Foreground(accent)
+	ResponseBodyStyle = lipgloss.NewStyle().Padding(1, 2)
+	ResponseFilterStyle = lipgloss.NewStyle().PaddingLeft(2)
+}
@@ -0,0 +1,277 @@
+package styles
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/maniac-en/req/internal/format"
+)
+
+var (
+	SyntaxKeyStyle         lipgloss.Style
+	SyntaxStringStyle      lipgloss.Style
+	SyntaxNumberStyle      lipgloss.Style
+	SyntaxKeywordStyle     lipgloss.Style
+	SyntaxPunctuationStyle lipgloss.Style
+	SyntaxCommentStyle     lipgloss.Style
+)
+
+func buildSyntaxStyles() {
+	SyntaxKeyStyle = lipgloss.NewStyle().Foreground(syntaxKey)
+	SyntaxStringStyle = lipgloss.NewStyle().Foreground(syntaxString)
+	SyntaxNumberStyle = lipgloss.NewStyle().Foreground(syntaxNumber)
+	SyntaxKeywordStyle = lipgloss.NewStyle().Foreground(syntaxKeyword)
+	SyntaxPunctuationStyle = lipgloss.NewStyle().Foreground(syntaxPunctuation)
+	SyntaxCommentStyle = lipgloss.NewStyle().Foreground(syntaxComment).Italic(true)
+}
+
+// Highlight colorizes a single line of a body of the given kind. Lines are
+// highlighted independently so only the visible part of a body is styled.
+func Highlight(kind format.Kind, line string) string {
+	switch kind {
+	case format.JSON:
+		return highlightJSON(line)
+	case format.XML, format.HTML:
+		return highlightMarkup(line)
+	case format.YAML:
+		return highlightYAML(line)
+	}
+	return line
+}
+
+func highlightJSON(line string) string {
+	var b strings.Builder
+	runes := []rune(line)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == '"':
+			end := closingQuote(runes, i)
+			text := string(runes[i:end])
+			if isJSONKey(runes, end) {
+				b.WriteString(SyntaxKeyStyle.Render(text))
+			} else {
+				b.WriteString(SyntaxStringStyle.Render(text))
+			}
+			i = end
+		case r == '-' || unicode.IsDigit(r):
+			end := i + 1
+			for end < len(runes) && strings.ContainsRune("0123456789.eE+-", runes[end]) {
+				end++
+			}
+			b.WriteString(SyntaxNumberStyle.Render(string(runes[i:end])))
+			i = end
+		case unicode.IsLetter(r):
+			end := i + 1
+			for end < len(runes) && unicode.IsLetter(runes[end]) {
+				end++
+			}
+			b.WriteString(SyntaxKeywordStyle.Render(string(runes[i:end])))
+			i = end
+		case strings.ContainsRune("{}[]:,", r):
+			b.WriteString(SyntaxPunctuationStyle.Render(string(r)))
+			i++
+		default:
+			b.WriteRune(r)
+			i++
+		}
+	}
+	return b.String()
+}
+
+func highlightMarkup(line string) string {
+	var b strings.Builder
+	for line != "" {
+		open := strings.IndexByte(line, '<')
+		if open < 0 {
+			b.WriteString(line)
+			break
+		}
+		b.WriteString(line[:open])
+		line = line[open:]
+
+		if strings.HasPrefix(line, "<!--") {
+			end := strings.Index(line, "-->")
+			if end < 0 {
+				b.WriteString(SyntaxCommentStyle.Render(line))
+				break
+			}
+			b.WriteString(SyntaxCommentStyle.Render(line[:end+3]))
+			line = line[end+3:]
+			continue
+		}
+
+		end := strings.IndexByte(line, '>') + 1
+		if end == 0 {
+			end = len(line)
+		}
+		b.WriteString(highlightTag([]rune(line[:end])))
+		line = line[end:]
+	}
+	return b.String()
+}
+
+// highlightTag styles a single tag: the name, attribute names and quoted values
+func highlightTag(tag []rune) string {
+	var b strings.Builder
+	i := 0
+	for i < len(tag) && strings.ContainsRune("<>/?!", tag[i]) {
+		i++
+	}
+	b.WriteString(SyntaxPunctuationStyle.Render(string(tag[:i])))
+
+	start := i
+	for i < len(tag) && !unicode.IsSpace(tag[i]) && !strings.ContainsRune("/>", tag[i]) {
+		i++
+	}
+	b.WriteString(SyntaxKeyStyle.Render(string(tag[start:i])))
+
+	for i < len(tag) {
+		r := tag[i]
+		switch {
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(tag) && tag[end] != r {
+				end++
+			}
+			if end < len(tag) {
+				end++
+			}
+			b.WriteString(SyntaxStringStyle.Render(string(tag[i:end])))
+			i = end
+		case strings.ContainsRune("=/>?", r):
+			b.WriteString(SyntaxPunctuationStyle.Render(string(r)))
+			i++
+		case unicode.IsSpace(r):
+			b.WriteRune(r)
+			i++
+		default:
+			end := i
+			for end < len(tag) && !unicode.IsSpace(tag[end]) && !strings.ContainsRune("=/>", tag[end]) {
+				end++
+			}
+			b.WriteString(SyntaxKeywordStyle.Render(string(tag[i:end])))
+			i = end
+		}
+	}
+	return b.String()
+}
+
+func highlightYAML(line string) string {
+	trimmed := strings.TrimLeft(line, " ")
+	indent := line[:len(line)-len(trimmed)]
+
+	if strings.HasPrefix(trimmed, "#") {
+		return indent + SyntaxCommentStyle.Render(trimmed)
+	}
+	if trimmed == "---" || trimmed == "..." {
+		return indent + SyntaxPunctuationStyle.Render(trimmed)
+	}
+
+	var b strings.Builder
+	b.WriteString(indent)
+	for strings.HasPrefix(trimmed, "- ") || trimmed == "-" {
+		b.WriteString(SyntaxPunctuationStyle.Render("-"))
+		trimmed = strings.TrimPrefix(strings.TrimPrefix(trimmed, "-"), " ")
+		b.WriteString(" ")
+	}
+
+	value := trimmed
+	if idx := yamlKeySeparator(trimmed); idx >= 0 {
+		b.WriteString(SyntaxKeyStyle.Render(trimmed[:idx]))
+		b.WriteString(SyntaxPunctuationStyle.Render(":"))
+		value = trimmed[idx+1:]
+	}
+
+	comment := ""
+	if idx := strings.Index(value, " #"); idx >= 0 {
+		value, comment = value[:idx], value[idx:]
+	}
+	b.WriteString(highlightYAMLValue(value))
+	if comment != "" {
+		b.WriteString(SyntaxCommentStyle.Render(comment))
+	}
+	return b.String()
+}
+
+func highlightYAMLValue(value string) string {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "" {
+		return value
+	}
+	leading := value[:strings.Index(value, trimmed)]
+
+	switch {
+	case strings.HasPrefix(trimmed, `"`) || strings.HasPrefix(trimmed, "'"):
+		return leading + SyntaxStringStyle.Render(trimmed)
+	case isNumber(trimmed):
+		return leading + SyntaxNumberStyle.Render(trimmed)
+	}
+	switch strings.ToLower(trimmed) {
+	case "true", "false", "null", "~", "yes", "no":
+		return leading + SyntaxKeywordStyle.Render(trimmed)
+	}
+	return leading + SyntaxStringStyle.Render(trimmed)
+}
+
+// yamlKeySeparator returns the index of the colon ending a mapping key
+func yamlKeySeparator(line string) int {
+	if strings.HasPrefix(line, `"`) || strings.HasPrefix(line, "'") {
+		end := strings.IndexRune(line[1:], rune(line[0]))
+		if end < 0 {
+			return -1
+		}
+		if rest := line[end+2:]; strings.HasPrefix(rest, ":") {
+			return end + 2
+		}
+		return -1
+	}
+	for i, r := range line {
+		if r == ':' && (i == len(line)-1 || line[i+1] == ' ') {
+			return i
+		}
+		if r == ' ' && i+1 < len(line) && line[i+1] == '#' {
+			return -1
+		}
+	}
+	return -1
+}
+
+func closingQuote(runes []rune, start int) int {
+	for i := start + 1; i < len(runes); i++ {
+		if runes[i] == '\\' {
+			i++
+			continue
+		}
+		if runes[i] == '"' {
+			return i + 1
+		}
+	}
+	return len(runes)
+}
+
+func isJSONKey(runes []rune, end int) bool {
+	for i := end; i < len(runes); i++ {
+		if runes[i] == ':' {
+			return true
+		}
+		if !unicode.IsSpace(runes[i]) {
+			return false
+		}
+	}
+	return false
+}
+
+func isNumber(value string) bool {
+	if value == "" {
+		return false
+	}
+	for i, r := range value {
+		if unicode.IsDigit(r) || r == '.' || (i == 0 && (r == '-' || r == '+')) {
+			continue
+		}
+		return false
+	}
+	return true
+}
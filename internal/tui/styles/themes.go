@@ -0,0 +1,152 @@
+package styles
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Built-in themes. Auto follows the terminal's background, high contrast
+// drops colors altogether.
+const (
+	Auto         = "auto"
+	Dark         = "dark"
+	Light        = "light"
+	HighContrast = "high-contrast"
+)
+
+var themes = map[string]map[string]string{
+	Dark: {
+		"footer_name_background": "#1a1a1a",
+		"footer_name_from":       "#41A0AE",
+		"footer_name_to":         "#77F07F",
+		"accent":                 "#77F07F",
+		"heading":                "#000000",
+		"footer_background":      "#262626",
+		"footer_foreground":      "#656565",
+		"help":                   "#3C3C3C",
+		"danger":                 "#FF0000",
+		"danger_foreground":      "#FFFFFF",
+		"removed":                "#FF5F5F",
+		"syntax_key":             "#41A0AE",
+		"syntax_string":          "#77F07F",
+		"syntax_number":          "#E5C07B",
+		"syntax_keyword":         "#C678DD",
+		"syntax_punctuation":     "#656565",
+		"syntax_comment":         "#5C6370",
+	},
+	Light: {
+		"footer_name_background": "#E4E4E4",
+		"footer_name_from":       "#0087AF",
+		"footer_name_to":         "#00875F",
+		"accent":                 "#00875F",
+		"heading":                "#FFFFFF",
+		"footer_background":      "#D0D0D0",
+		"footer_foreground":      "#4E4E4E",
+		"help":                   "#9E9E9E",
+		"danger":                 "#D70000",
+		"danger_foreground":      "#FFFFFF",
+		"removed":                "#AF0000",
+		"syntax_key":             "#005F87",
+		"syntax_string":          "#00875F",
+		"syntax_number":          "#AF5F00",
+		"syntax_keyword":         "#8700AF",
+		"syntax_punctuation":     "#6C6C6C",
+		"syntax_comment":         "#8A8A8A",
+	},
+}
+
+// hexColor is the only form accepted, the footer gradient is computed from it
+var hexColor = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// SetTheme switches to a built-in theme or to one of the custom palettes,
+// with colors then replaced by name, and rebuilds the styles. A custom
+// palette sets colors on top of its base theme, auto unless it names dark or
+// light as "base". When NO_COLOR is set the high contrast theme is used
+// whatever the name. Nothing changes when the theme or a color is invalid.
+func SetTheme(name string, custom map[string]map[string]string, colors map[string]string) error {
+	for _, themeName := range slices.Sorted(maps.Keys(custom)) {
+		if slices.Contains(ThemeNames(), themeName) {
+			return fmt.Errorf("themes.%s: can't replace the built-in theme, name it something else", themeName)
+		}
+		theme := maps.Clone(custom[themeName])
+		if base, ok := theme["base"]; ok && base != Auto && base != Dark && base != Light {
+			return fmt.Errorf("themes.%s.base: expected auto, dark or light, got %q", themeName, base)
+		}
+		delete(theme, "base")
+		if err := checkColors("themes."+themeName, theme); err != nil {
+			return err
+		}
+	}
+	if err := checkColors("colors", colors); err != nil {
+		return err
+	}
+
+	if name == "" {
+		name = Auto
+	}
+	base, overrides := name, map[string]string{}
+	if theme, ok := custom[name]; ok {
+		base = theme["base"]
+		if base == "" {
+			base = Auto
+		}
+		overrides = maps.Clone(theme)
+		delete(overrides, "base")
+	} else if !slices.Contains(ThemeNames(), name) {
+		return fmt.Errorf("theme: unknown theme %q, expected %s or one of [themes]", name, strings.Join(ThemeNames(), ", "))
+	}
+	maps.Copy(overrides, colors)
+	if os.Getenv("NO_COLOR") != "" {
+		base = HighContrast
+	}
+
+	light, dark := themes[Light], themes[Dark]
+	switch base {
+	case Dark, Light:
+		light, dark = themes[base], themes[base]
+		// the components of bubbles adapt to the background as well
+		lipgloss.SetHasDarkBackground(base == Dark)
+	case HighContrast:
+		// the colors are dropped by Output, the ASCII profile lipgloss picks
+		// for NO_COLOR would drop reverse video and bold text as well
+		lipgloss.SetColorProfile(termenv.ANSI)
+		// there is no color left to pick from the background
+		lipgloss.SetHasDarkBackground(true)
+	}
+	light, dark = maps.Clone(light), maps.Clone(dark)
+	maps.Copy(light, overrides)
+	maps.Copy(dark, overrides)
+	setPalette(light, dark)
+	plain = base == HighContrast
+	build()
+	return nil
+}
+
+func checkColors(setting string, colors map[string]string) error {
+	for _, name := range slices.Sorted(maps.Keys(colors)) {
+		if _, ok := palette[name]; !ok {
+			return fmt.Errorf("%s: unknown color %q, expected one of %s", setting, name, strings.Join(ColorNames(), ", "))
+		}
+		if !hexColor.MatchString(colors[name]) {
+			return fmt.Errorf("%s.%s: expected a color like #77F07F, got %q", setting, name, colors[name])
+		}
+	}
+	return nil
+}
+
+// ThemeNames lists the built-in themes
+func ThemeNames() []string {
+	return []string{Auto, Dark, Light, HighContrast}
+}
+
+// ColorNames lists the colors of a palette
+func ColorNames() []string {
+	return slices.Sorted(maps.Keys(palette))
+}
@@ -0,0 +1,46 @@
+// Package undo keeps the recent changes made in the TUI so they can be
+// reverted in reverse order.
+package undo
+
+import "context"
+
+// limit is how many operations are remembered
+const limit = 50
+
+// Operation is a change that can be reverted. Description names the change,
+// as in "delete Login", and Revert undoes it through the managers.
+type Operation struct {
+	Description string
+	Revert      func(context.Context) error
+}
+
+// Stack holds operations, the most recent on top. It is shared by the views.
+type Stack struct {
+	operations []Operation
+}
+
+func NewStack() *Stack {
+	return &Stack{}
+}
+
+// Push records an operation, dropping the oldest beyond the limit
+func (s *Stack) Push(description string, revert func(context.Context) error) {
+	s.operations = append(s.operations, Operation{Description: description, Revert: revert})
+	if len(s.operations) > limit {
+		s.operations = s.operations[len(s.operations)-limit:]
+	}
+}
+
+// Pop removes the most recent operation
+func (s *Stack) Pop() (Operation, bool) {
+	if len(s.operations) == 0 {
+		return Operation{}, false
+	}
+	operation := s.operations[len(s.operations)-1]
+	s.operations = s.operations[:len(s.operations)-1]
+	return operation, true
+}
+
+func (s *Stack) Len() int {
+	return len(s.operations)
+}
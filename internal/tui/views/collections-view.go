@@ -14,6 +14,7 @@ import (
 	optionsProvider "github.com/maniac-en/req/internal/tui/components/OptionsProvider"
 	"github.com/maniac-en/req/internal/tui/keybinds"
 	"github.com/maniac-en/req/internal/tui/messages"
+	"github.com/maniac-en/req/internal/tui/undo"
 )
 
 type CollectionsView struct {
@@ -25,6 +26,7 @@ type CollectionsView struct {
 	help             help.Model
 	keys             *keybinds.ListKeyMap
 	order            int
+	undo             *undo.Stack
 }
 
 func (c CollectionsView) Init() tea.Cmd {
@@ -36,7 +38,15 @@ func (c CollectionsView) Name() string {
 }
 
 func (c CollectionsView) Help() []key.Binding {
-	return c.list.Help()
+	help := c.list.Help()
+	if !c.list.IsCapturingInput() {
+		help = append(help, keybinds.Keys.Duplicate, keybinds.Keys.MoveUp, keybinds.Keys.MoveDown)
+	}
+	return help
+}
+
+func (c CollectionsView) IsCapturingInput() bool {
+	return c.list.IsCapturingInput()
 }
 
 func (c CollectionsView) GetFooterSegment() string {
@@ -52,13 +62,40 @@ func (c CollectionsView) Update(msg tea.Msg) (ViewInterface, tea.Cmd) {
 		c.width = msg.Width
 		c.list, cmd = c.list.Update(msg)
 		cmds = append(cmds, cmd)
+	case tea.KeyMsg:
+		if c.list.IsCapturingInput() || len(c.list.Items()) == 0 {
+			break
+		}
+		switch {
+		case key.Matches(msg, keybinds.Keys.Duplicate):
+			selected := c.list.GetSelected()
+			copied, err := c.manager.Duplicate(context.Background(), selected.ID)
+			if err != nil {
+				return c, func() tea.Msg {
+					return messages.ShowError{Message: err.Error()}
+				}
+			}
+			c.undo.Push("duplicate "+selected.Name, func(ctx context.Context) error {
+				return c.manager.Delete(ctx, copied.ID)
+			})
+			return c, func() tea.Msg {
+				return messages.RefreshItemsList{}
+			}
+		case key.Matches(msg, keybinds.Keys.MoveUp):
+			return c, c.shift(-1)
+		case key.Matches(msg, keybinds.Keys.MoveDown):
+			return c, c.shift(1)
+		}
 	case messages.ItemAdded:
-		_, err := c.manager.Create(context.Background(), msg.Item)
+		created, err := c.manager.Create(context.Background(), msg.Item)
 		if err != nil {
 			return c, func() tea.Msg {
 				return messages.ShowError{Message: err.Error()}
 			}
 		}
+		c.undo.Push("create "+created.Name, func(ctx context.Context) error {
+			return c.manager.Delete(ctx, created.ID)
+		})
 		return c, func() tea.Msg {
 			return messages.RefreshItemsList{}
 		}
@@ -66,9 +103,21 @@ func (c CollectionsView) Update(msg tea.Msg) (ViewInterface, tea.Cmd) {
 		c.list.RefreshItems()
 		return c, nil
 	case messages.ItemEdited:
-		c.manager.Update(context.Background(), msg.ItemID, msg.Item)
+		previous := c.list.GetSelected().Name
+		if _, err := c.manager.Update(context.Background(), msg.ItemID, msg.Item); err == nil {
+			c.undo.Push("rename "+previous, func(ctx context.Context) error {
+				_, err := c.manager.Update(ctx, msg.ItemID, previous)
+				return err
+			})
+		}
 	case messages.DeleteItem:
-		c.manager.Delete(context.Background(), msg.ItemID)
+		name := c.list.GetSelected().Name
+		if err := c.manager.Delete(context.Background(), msg.ItemID); err == nil {
+			c.undo.Push("delete "+name, func(ctx context.Context) error {
+				_, err := c.manager.Restore(ctx, msg.ItemID)
+				return err
+			})
+		}
 		c.list.RefreshItems()
 	}
 
@@ -78,6 +127,23 @@ func (c CollectionsView) Update(msg tea.Msg) (ViewInterface, tea.Cmd) {
 	return c, tea.Batch(cmds...)
 }
 
+// shift moves the selected collection up or down, keeping it selected
+func (c *CollectionsView) shift(offset int) tea.Cmd {
+	selected := c.list.GetSelected()
+	if _, err := c.manager.Shift(context.Background(), selected.ID, offset); err != nil {
+		return func() tea.Msg {
+			return messages.ShowError{Message: err.Error()}
+		}
+	}
+	c.list.RefreshItems()
+	for i, item := range c.list.Items() {
+		if item.(optionsProvider.Option).ID == selected.ID {
+			c.list.Select(i)
+		}
+	}
+	return nil
+}
+
 func (c CollectionsView) View() string {
 	return c.list.View()
 }
@@ -130,7 +196,7 @@ func itemMapper(items []collections.CollectionEntity, endpointsManager *endpoint
 	return opts
 }
 
-func NewCollectionsView(collManager *collections.CollectionsManager, endpointsManager *endpoints.EndpointsManager, order int) *CollectionsView {
+func NewCollectionsView(collManager *collections.CollectionsManager, endpointsManager *endpoints.EndpointsManager, undoStack *undo.Stack, order int) *CollectionsView {
 	keybinds := keybinds.NewListKeyMap()
 	config := defaultListConfig[collections.CollectionEntity, string](keybinds)
 
@@ -148,5 +214,6 @@ func NewCollectionsView(collManager *collections.CollectionsManager, endpointsMa
 		help:             help.New(),
 		keys:             keybinds,
 		order:            order,
+		undo:             undoStack,
 	}
 }
@@ -4,24 +4,51 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/maniac-en/req/internal/backend/collections"
 	"github.com/maniac-en/req/internal/backend/database"
 	"github.com/maniac-en/req/internal/backend/endpoints"
+	"github.com/maniac-en/req/internal/backend/folders"
 	optionsProvider "github.com/maniac-en/req/internal/tui/components/OptionsProvider"
 	"github.com/maniac-en/req/internal/tui/keybinds"
 	"github.com/maniac-en/req/internal/tui/messages"
+	"github.com/maniac-en/req/internal/tui/styles"
+	"github.com/maniac-en/req/internal/tui/undo"
 )
 
+// endpointsEntry is a row of the endpoints list, either a subfolder of the
+// current folder or one of its endpoints
+type endpointsEntry struct {
+	folder   *folders.FolderEntity
+	endpoint *endpoints.EndpointEntity
+	count    int
+}
+
 type EndpointsView struct {
-	height     int
-	collection optionsProvider.Option
-	width      int
-	order      int
-	list       optionsProvider.OptionsProvider[endpoints.EndpointEntity, database.Endpoint]
-	manager    *endpoints.EndpointsManager
+	height         int
+	collection     optionsProvider.Option
+	width          int
+	order          int
+	list           optionsProvider.OptionsProvider[endpointsEntry, database.Endpoint]
+	manager        *endpoints.EndpointsManager
+	foldersManager *folders.FoldersManager
+	collections    *collections.CollectionsManager
+	tree           *folders.Tree
+	// folderID is the folder being browsed, 0 at the top of the collection
+	folderID int64
+	// held is the item picked up with the move key, waiting to be dropped
+	held     *optionsProvider.Option
+	heldFrom int64
+	// targets are the collections offered when moving an endpoint to another
+	// collection, target is the one shown
+	targets []collections.CollectionEntity
+	target  int
+	undo    *undo.Stack
 }
 
 func (e *EndpointsView) Init() tea.Cmd {
@@ -33,33 +60,173 @@ func (e *EndpointsView) Name() string {
 }
 
 func (e *EndpointsView) Help() []key.Binding {
-	return e.list.Help()
+	if e.targets != nil {
+		return []key.Binding{keybinds.Keys.PrevPage, keybinds.Keys.NextPage, keybinds.Keys.Choose}
+	}
+	help := e.list.Help()
+	if !e.list.IsCapturingInput() {
+		help = append(help, keybinds.Keys.Move, keybinds.Keys.MoveToCollection, keybinds.Keys.Duplicate, keybinds.Keys.MoveUp, keybinds.Keys.MoveDown, keybinds.Keys.Revisions)
+	}
+	return help
+}
+
+func (e *EndpointsView) IsCapturingInput() bool {
+	return e.list.IsCapturingInput() || e.targets != nil
 }
 
 func (e *EndpointsView) GetFooterSegment() string {
+	if path := e.tree.Path(e.folderID); path != "" {
+		return fmt.Sprintf("%s/%s/", e.collection.Name, path)
+	}
 	return fmt.Sprintf("%s/", e.collection.Name)
 }
 
+// Back leaves the current folder for its parent. It reports false at the top
+// of the collection so the app can go back to the collections.
+func (e *EndpointsView) Back() bool {
+	if e.held != nil {
+		e.held = nil
+		return true
+	}
+	if e.folderID == 0 {
+		return false
+	}
+	parentID := int64(0)
+	if folder, ok := e.tree.Get(e.folderID); ok {
+		parentID = folder.GetParentID()
+	}
+	e.open(parentID)
+	return true
+}
+
 func (e *EndpointsView) Update(msg tea.Msg) (ViewInterface, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
+	ctx := context.Background()
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		e.height = msg.Height
 		e.width = msg.Width
-		e.list, cmd = e.list.Update(msg)
-		cmds = append(cmds, cmd)
+		e.list, cmd = e.list.Update(tea.WindowSizeMsg{Width: msg.Width, Height: msg.Height - 1})
+		return e, cmd
+	case tea.KeyMsg:
+		if e.targets != nil {
+			return e, e.chooseTarget(msg)
+		}
+		if e.list.IsCapturingInput() {
+			break
+		}
+		switch {
+		case key.Matches(msg, keybinds.Keys.Move):
+			return e, e.move()
+		case key.Matches(msg, keybinds.Keys.MoveToCollection):
+			return e, e.pickTarget()
+		case key.Matches(msg, keybinds.Keys.Duplicate):
+			return e, e.duplicate()
+		case key.Matches(msg, keybinds.Keys.MoveUp):
+			return e, e.shift(-1)
+		case key.Matches(msg, keybinds.Keys.MoveDown):
+			return e, e.shift(1)
+		case key.Matches(msg, keybinds.Keys.Revisions):
+			if selected, ok := e.selectedEndpoint(); ok {
+				return e, func() tea.Msg {
+					return messages.NavigateToView{ViewName: "revisions", Data: selected}
+				}
+			}
+			return e, nil
+		}
+	case messages.ChooseItem[optionsProvider.Option]:
+		e.open(msg.Item.ID)
+		return e, nil
 	case messages.ItemAdded:
-		e.manager.CreateEndpoint(context.Background(), endpoints.EndpointData{
+		// a trailing separator creates folders, relative to the current one
+		if strings.HasSuffix(msg.Item, folders.Separator) {
+			path := msg.Item
+			if current := e.tree.Path(e.folderID); current != "" {
+				path = current + folders.Separator + path
+			}
+			// undoing removes the outermost folder that did not exist yet
+			var first string
+			parts := folders.SplitPath(path)
+			for i := range parts {
+				prefix := strings.Join(parts[:i+1], folders.Separator)
+				if _, ok := e.tree.Find(prefix); !ok {
+					first = prefix
+					break
+				}
+			}
+			if _, err := e.foldersManager.CreatePath(ctx, e.collection.ID, path); err != nil {
+				cmds = append(cmds, showError(err))
+			}
+			e.refreshTree()
+			if created, ok := e.tree.Find(first); ok {
+				e.undo.Push("create "+first+folders.Separator, func(ctx context.Context) error {
+					return e.foldersManager.Delete(ctx, created.ID)
+				})
+			}
+			break
+		}
+		created, err := e.manager.CreateEndpoint(ctx, endpoints.EndpointData{
 			CollectionID: e.collection.ID,
+			FolderID:     e.folderID,
 			Name:         msg.Item,
 			Method:       "GET",
 		})
+		if err != nil {
+			cmds = append(cmds, showError(err))
+			break
+		}
+		e.undo.Push("create "+created.Name, func(ctx context.Context) error {
+			return e.manager.Delete(ctx, created.ID)
+		})
 	case messages.ItemEdited:
-		e.manager.UpdateEndpointName(context.Background(), msg.ItemID, msg.Item)
+		selected := e.list.GetSelected()
+		if selected.Folder {
+			name := strings.TrimSuffix(msg.Item, folders.Separator)
+			previous := strings.TrimSuffix(selected.Name, folders.Separator)
+			if _, err := e.foldersManager.Update(ctx, msg.ItemID, name); err != nil {
+				cmds = append(cmds, showError(err))
+			} else {
+				e.undo.Push("rename "+selected.Name, func(ctx context.Context) error {
+					_, err := e.foldersManager.Update(ctx, msg.ItemID, previous)
+					return err
+				})
+			}
+			e.refreshTree()
+			break
+		}
+		if _, err := e.manager.UpdateEndpointName(ctx, msg.ItemID, msg.Item); err == nil {
+			e.undo.Push("rename "+selected.Name, func(ctx context.Context) error {
+				_, err := e.manager.UpdateEndpointName(ctx, msg.ItemID, selected.Name)
+				return err
+			})
+		}
 	case messages.DeleteItem:
-		e.manager.Delete(context.Background(), msg.ItemID)
+		selected := e.list.GetSelected()
+		if selected.Folder {
+			if err := e.foldersManager.Delete(ctx, msg.ItemID); err != nil {
+				cmds = append(cmds, showError(err))
+			} else {
+				e.undo.Push("delete "+selected.Name, func(ctx context.Context) error {
+					_, err := e.foldersManager.Restore(ctx, msg.ItemID)
+					return err
+				})
+			}
+			e.refreshTree()
+		} else if err := e.manager.Delete(ctx, msg.ItemID); err == nil {
+			e.undo.Push("delete "+selected.Name, func(ctx context.Context) error {
+				_, err := e.manager.Restore(ctx, msg.ItemID)
+				return err
+			})
+		}
 		e.list.RefreshItems()
+	case messages.RefreshItemsList:
+		e.refreshTree()
+		if _, ok := e.tree.Get(e.folderID); !ok {
+			e.folderID = 0
+		}
+		e.list.RefreshItems()
+		return e, nil
 	}
 
 	e.list, cmd = e.list.Update(msg)
@@ -68,8 +235,155 @@ func (e *EndpointsView) Update(msg tea.Msg) (ViewInterface, tea.Cmd) {
 	return e, tea.Batch(cmds...)
 }
 
+// move picks up the selected item, or drops the held one into the current
+// folder
+func (e *EndpointsView) move() tea.Cmd {
+	if e.held == nil {
+		selected := e.list.GetSelected()
+		if selected.ID <= 0 {
+			return nil
+		}
+		e.held = &selected
+		e.heldFrom = e.folderID
+		return nil
+	}
+
+	held, from := *e.held, e.heldFrom
+	e.held = nil
+	var err error
+	if held.Folder {
+		_, err = e.foldersManager.Move(context.Background(), held.ID, e.folderID)
+		e.refreshTree()
+		if err == nil {
+			e.undo.Push("move "+held.Name, func(ctx context.Context) error {
+				_, err := e.foldersManager.Move(ctx, held.ID, from)
+				return err
+			})
+		}
+	} else {
+		_, err = e.manager.MoveToFolder(context.Background(), held.ID, e.folderID)
+		if err == nil {
+			e.undo.Push("move "+held.Name, func(ctx context.Context) error {
+				_, err := e.manager.MoveToFolder(ctx, held.ID, from)
+				return err
+			})
+		}
+	}
+	e.list.RefreshItems()
+	if err != nil {
+		return showError(err)
+	}
+	return nil
+}
+
+// selectedEndpoint returns the selected option when it is an endpoint
+func (e *EndpointsView) selectedEndpoint() (optionsProvider.Option, bool) {
+	if len(e.list.Items()) == 0 {
+		return optionsProvider.Option{}, false
+	}
+	selected := e.list.GetSelected()
+	return selected, selected.ID > 0 && !selected.Folder
+}
+
+func (e *EndpointsView) duplicate() tea.Cmd {
+	selected, ok := e.selectedEndpoint()
+	if !ok {
+		return nil
+	}
+	copied, err := e.manager.Duplicate(context.Background(), selected.ID)
+	if err != nil {
+		return showError(err)
+	}
+	e.undo.Push("duplicate "+selected.Name, func(ctx context.Context) error {
+		return e.manager.Delete(ctx, copied.ID)
+	})
+	e.list.RefreshItems()
+	e.list.Select(e.list.Index() + 1)
+	return nil
+}
+
+// shift moves the selected endpoint up or down, keeping it selected
+func (e *EndpointsView) shift(offset int) tea.Cmd {
+	selected, ok := e.selectedEndpoint()
+	if !ok {
+		return nil
+	}
+	if _, err := e.manager.Shift(context.Background(), selected.ID, offset); err != nil {
+		return showError(err)
+	}
+	e.list.RefreshItems()
+	for i, item := range e.list.Items() {
+		if option := item.(optionsProvider.Option); option.ID == selected.ID && !option.Folder {
+			e.list.Select(i)
+		}
+	}
+	return nil
+}
+
+// pickTarget starts choosing the collection to move the selected endpoint to
+func (e *EndpointsView) pickTarget() tea.Cmd {
+	if _, ok := e.selectedEndpoint(); !ok {
+		return nil
+	}
+	all, err := e.collections.List(context.Background())
+	if err != nil {
+		return showError(err)
+	}
+	targets := []collections.CollectionEntity{}
+	for _, collection := range all {
+		if collection.ID != e.collection.ID {
+			targets = append(targets, collection)
+		}
+	}
+	if len(targets) == 0 {
+		return showError(errors.New("there is no other collection to move to"))
+	}
+	e.targets = targets
+	e.target = 0
+	return nil
+}
+
+func (e *EndpointsView) chooseTarget(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, keybinds.Keys.PrevPage):
+		e.target = (e.target + len(e.targets) - 1) % len(e.targets)
+	case key.Matches(msg, keybinds.Keys.NextPage):
+		e.target = (e.target + 1) % len(e.targets)
+	case key.Matches(msg, keybinds.Keys.Back):
+		e.targets = nil
+	case key.Matches(msg, keybinds.Keys.Choose):
+		target := e.targets[e.target]
+		e.targets = nil
+		selected, ok := e.selectedEndpoint()
+		if !ok {
+			return nil
+		}
+		if _, err := e.manager.MoveToCollection(context.Background(), selected.ID, target.ID); err != nil {
+			return showError(err)
+		}
+		// back in its collection and folder, at the end
+		collectionID, folderID := e.collection.ID, e.folderID
+		e.undo.Push("move "+selected.Name+" to "+target.Name, func(ctx context.Context) error {
+			if _, err := e.manager.MoveToCollection(ctx, selected.ID, collectionID); err != nil {
+				return err
+			}
+			_, err := e.manager.MoveToFolder(ctx, selected.ID, folderID)
+			return err
+		})
+		e.list.RefreshItems()
+	}
+	return nil
+}
+
 func (e *EndpointsView) View() string {
-	return e.list.View()
+	status := "Enter a name ending in / to add a folder"
+	if e.targets != nil {
+		selected, _ := e.selectedEndpoint()
+		status = fmt.Sprintf("Move %s to collection: ‹ %s › (%d of %d)", selected.Name, e.targets[e.target].Name, e.target+1, len(e.targets))
+	} else if e.held != nil {
+		status = fmt.Sprintf("Moving %s, press %s in the target folder or %s to cancel", e.held.Name, keybinds.Keys.Move.Help().Key, keybinds.Keys.Back.Help().Key)
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, e.list.View(), styles.HelpStyle.Render(status))
 }
 
 func (e *EndpointsView) OnFocus() {
@@ -80,10 +394,11 @@ func (e *EndpointsView) SetState(items ...any) error {
 	if len(items) == 1 {
 		if collection, ok := items[0].(optionsProvider.Option); ok {
 			e.collection = collection
-			epListFunc := func(ctx context.Context) ([]endpoints.EndpointEntity, error) {
-				return e.manager.ListByCollection(ctx, collection.ID)
-			}
-			e.list.SetGetItemsFunc(epListFunc)
+			e.held = nil
+			e.targets = nil
+			e.refreshTree()
+			e.folderID = 0
+			e.list.SetGetItemsFunc(e.listEntries)
 			return nil
 		}
 	}
@@ -98,20 +413,80 @@ func (e *EndpointsView) Order() int {
 	return e.order
 }
 
-func itemMapperEp(items []endpoints.EndpointEntity) []list.Item {
+// open browses into a folder of the collection
+func (e *EndpointsView) open(folderID int64) {
+	if _, ok := e.tree.Get(folderID); !ok {
+		folderID = 0
+	}
+	e.folderID = folderID
+	e.list.RefreshItems()
+}
+
+func (e *EndpointsView) refreshTree() {
+	tree, err := e.foldersManager.Tree(context.Background(), e.collection.ID)
+	if err != nil {
+		tree = folders.NewTree(nil)
+	}
+	e.tree = tree
+}
+
+// listEntries lists the subfolders of the current folder, then its endpoints
+func (e *EndpointsView) listEntries(ctx context.Context) ([]endpointsEntry, error) {
+	all, err := e.manager.ListByCollection(ctx, e.collection.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int64]int)
+	var entries []endpointsEntry
+	for _, endpoint := range all {
+		counts[endpoint.GetFolderID()]++
+	}
+	for _, folder := range e.tree.Children(e.folderID) {
+		count := len(e.tree.Children(folder.ID)) + counts[folder.ID]
+		entries = append(entries, endpointsEntry{folder: &folder, count: count})
+	}
+	for _, endpoint := range all {
+		if endpoint.GetFolderID() == e.folderID {
+			entries = append(entries, endpointsEntry{endpoint: &endpoint})
+		}
+	}
+	return entries, nil
+}
+
+func itemMapperEp(items []endpointsEntry) []list.Item {
 	opts := make([]list.Item, len(items))
 	for i, item := range items {
+		if item.folder != nil {
+			opts[i] = optionsProvider.Option{
+				Name:    item.folder.GetName() + folders.Separator,
+				Subtext: fmt.Sprintf("%d items", item.count),
+				ID:      item.folder.GetID(),
+				Folder:  true,
+			}
+			continue
+		}
+		subtext := item.endpoint.Method
+		if item.endpoint.IsGRPC() {
+			subtext = "gRPC " + item.endpoint.Method
+		}
 		newOpt := optionsProvider.Option{
-			Name:    item.GetName(),
-			Subtext: item.Method,
-			ID:      item.GetID(),
+			Name:    item.endpoint.GetName(),
+			Subtext: subtext,
+			ID:      item.endpoint.GetID(),
 		}
 		opts[i] = newOpt
 	}
 	return opts
 }
 
-func NewEndpointsView(epManager *endpoints.EndpointsManager, order int) *EndpointsView {
+func showError(err error) tea.Cmd {
+	return func() tea.Msg {
+		return messages.ShowError{Message: err.Error()}
+	}
+}
+
+func NewEndpointsView(epManager *endpoints.EndpointsManager, foldersManager *folders.FoldersManager, collectionsManager *collections.CollectionsManager, undoStack *undo.Stack, order int) *EndpointsView {
 	view := &EndpointsView{
 		order: order,
 		collection: optionsProvider.Option{
@@ -119,20 +494,20 @@ func NewEndpointsView(epManager *endpoints.EndpointsManager, order int) *Endpoin
 			Subtext: "",
 			ID:      0,
 		},
-		manager: epManager,
+		manager:        epManager,
+		foldersManager: foldersManager,
+		collections:    collectionsManager,
+		undo:           undoStack,
+		tree:           folders.NewTree(nil),
 	}
 
 	keybinds := keybinds.NewListKeyMap()
-	config := defaultListConfig[endpoints.EndpointEntity, database.Endpoint](keybinds)
-
-	epListFunc := func(ctx context.Context) ([]endpoints.EndpointEntity, error) {
-		return epManager.ListByCollection(ctx, view.collection.ID)
-	}
+	config := defaultListConfig[endpointsEntry, database.Endpoint](keybinds)
 
-	config.GetItemsFunc = epListFunc
+	config.GetItemsFunc = view.listEntries
 	config.ItemMapper = itemMapperEp
 	config.AdditionalKeymaps = keybinds
-	config.Source = "collections"
+	config.Source = "endpoints"
 
 	view.list = optionsProvider.NewOptionsProvider(config)
 
@@ -0,0 +1,173 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/maniac-en/req/internal/diff"
+	"github.com/maniac-en/req/internal/tui/styles"
+	"github.com/mattn/go-runewidth"
+)
+
+// responseDiff shows two responses side by side. Headers list only the
+// differences while bodies are shown in full, aligned line by line, with
+// JSON bodies canonicalized so key order doesn't show up as a change.
+type responseDiff struct {
+	leftTitle  string
+	rightTitle string
+	result     diff.Result
+	headerRows []diff.Row
+	bodyRows   []diff.Row
+	lines      []string
+	offset     int
+	width      int
+	height     int
+}
+
+func newResponseDiff(leftTitle string, left diff.Response, rightTitle string, right diff.Response) *responseDiff {
+	d := &responseDiff{
+		leftTitle:  leftTitle,
+		rightTitle: rightTitle,
+		result:     diff.Compare(left, right, diff.Options{IgnoreHeaders: diff.DefaultIgnoredHeaders}),
+	}
+
+	var rows []diff.Row
+	if d.result.Status != nil {
+		rows = append(rows, diff.Row{Kind: diff.Changed, Left: "status " + d.result.Status.Left, Right: "status " + d.result.Status.Right})
+	}
+	for _, change := range d.result.Headers {
+		row := diff.Row{Kind: change.Kind}
+		if change.Kind != diff.Added {
+			row.Left = change.Path + ": " + change.Left
+		}
+		if change.Kind != diff.Removed {
+			row.Right = change.Path + ": " + change.Right
+		}
+		rows = append(rows, row)
+	}
+	d.headerRows = rows
+
+	leftBody, _ := diff.Canonical(left.Body)
+	rightBody, _ := diff.Canonical(right.Body)
+	d.bodyRows = diff.Align(leftBody, rightBody)
+	return d
+}
+
+func (d *responseDiff) setSize(width, height int) {
+	d.width = width
+	d.height = height
+	d.render()
+}
+
+func (d *responseDiff) scroll(delta int) {
+	d.offset = max(0, min(d.offset+delta, len(d.lines)-d.bodyHeight()))
+}
+
+func (d *responseDiff) page(delta int) {
+	d.scroll(delta * d.bodyHeight())
+}
+
+func (d *responseDiff) bodyHeight() int {
+	return max(d.height-2, 1)
+}
+
+func (d *responseDiff) view() string {
+	column := d.columnWidth()
+	out := []string{
+		d.split(styles.DiffTitleStyle.Render(fit(d.leftTitle, column)), styles.DiffTitleStyle.Render(fit(d.rightTitle, column))),
+		styles.ResponseMetaStyle.Render(d.summary()),
+	}
+	end := min(d.offset+d.bodyHeight(), len(d.lines))
+	out = append(out, d.lines[d.offset:end]...)
+	for len(out) < d.height {
+		out = append(out, "")
+	}
+	return strings.Join(out, "\n")
+}
+
+func (d *responseDiff) summary() string {
+	if d.result.Equal() {
+		return "responses match"
+	}
+	var parts []string
+	if d.result.Status != nil {
+		parts = append(parts, fmt.Sprintf("status %s → %s", d.result.Status.Left, d.result.Status.Right))
+	}
+	if n := len(d.result.Headers); n > 0 {
+		parts = append(parts, plural(n, "header difference"))
+	}
+	if n := len(d.result.Body); n > 0 {
+		kind := "body difference"
+		if d.result.JSON {
+			kind = "JSON difference"
+		}
+		parts = append(parts, plural(n, kind))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// render lays the rows out for the current width
+func (d *responseDiff) render() {
+	d.lines = d.lines[:0]
+	d.lines = append(d.lines, "  "+styles.ResponseSectionStyle.Render("Headers"))
+	if len(d.headerRows) == 0 {
+		d.lines = append(d.lines, " "+styles.ResponseMetaStyle.Render("no differences"))
+	}
+	for _, row := range d.headerRows {
+		d.lines = append(d.lines, d.row(row, false))
+	}
+	d.lines = append(d.lines, "", "  "+styles.ResponseSectionStyle.Render("Body"))
+	for _, row := range d.bodyRows {
+		d.lines = append(d.lines, d.row(row, true))
+	}
+	d.scroll(0)
+}
+
+func (d *responseDiff) row(row diff.Row, numbered bool) string {
+	column := d.columnWidth()
+	cell := func(text string, line int, style lipgloss.Style) string {
+		prefix := ""
+		if numbered {
+			number := ""
+			if line > 0 {
+				number = fmt.Sprintf("%d", line)
+			}
+			prefix = styles.DiffLineNoStyle.Render(fmt.Sprintf("%4s ", number))
+		}
+		width := max(column-lipgloss.Width(prefix), 0)
+		return prefix + style.Render(fit(strings.ReplaceAll(text, "\t", "    "), width))
+	}
+
+	leftStyle, rightStyle := lipgloss.NewStyle(), lipgloss.NewStyle()
+	if row.Kind == diff.Removed || row.Kind == diff.Changed {
+		leftStyle = styles.DiffRemovedStyle
+	}
+	if row.Kind == diff.Added || row.Kind == diff.Changed {
+		rightStyle = styles.DiffAddedStyle
+	}
+	return d.split(
+		cell(row.Left, row.LeftLine, leftStyle),
+		cell(row.Right, row.RightLine, rightStyle),
+	)
+}
+
+func (d *responseDiff) split(left, right string) string {
+	return "  " + left + styles.DiffSeparatorStyle.Render(" │ ") + right
+}
+
+func (d *responseDiff) columnWidth() int {
+	return max((d.width-5)/2, 1)
+}
+
+// fit truncates or pads text to exactly width cells
+func fit(text string, width int) string {
+	return runewidth.FillRight(runewidth.Truncate(text, width, "…"), width)
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
@@ -0,0 +1,498 @@
+package views
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/maniac-en/req/internal/backend/history"
+	"github.com/maniac-en/req/internal/backend/runner"
+	"github.com/maniac-en/req/internal/diff"
+	"github.com/maniac-en/req/internal/format"
+	bodyViewer "github.com/maniac-en/req/internal/tui/components/BodyViewer"
+	"github.com/maniac-en/req/internal/tui/keybinds"
+	"github.com/maniac-en/req/internal/tui/messages"
+	"github.com/maniac-en/req/internal/tui/styles"
+	"github.com/mattn/go-runewidth"
+)
+
+// historyLimit caps how many matches are listed at once
+const historyLimit = 200
+
+// HistoryView searches past executions, shows a selected one in full and
+// compares two entries, or an entry with a fresh run, side by side
+type HistoryView struct {
+	width   int
+	height  int
+	order   int
+	manager *history.HistoryManager
+	runner  *runner.Runner
+
+	search    textinput.Model
+	searching bool
+	searchErr error
+	searchGen int
+
+	results []history.HistoryEntity
+	total   int64
+	cursor  int
+	offset  int
+
+	// selected is the entry shown in the body viewer, nil while listing
+	selected *history.HistoryEntity
+	body     bodyViewer.BodyViewer
+
+	// marked is the entry the next comparison starts from
+	marked     *history.HistoryEntity
+	rerunning  bool
+	comparison *responseDiff
+	// notice confirms the last action until the next key press
+	notice string
+}
+
+// Init runs the current search so new executions show up
+func (h *HistoryView) Init() tea.Cmd {
+	return h.runSearch()
+}
+
+func (h *HistoryView) Name() string {
+	return "History"
+}
+
+func (h *HistoryView) Help() []key.Binding {
+	switch {
+	case h.searching:
+		return []key.Binding{keybinds.Keys.AcceptWhileFiltering, keybinds.Keys.CancelWhileFiltering}
+	case h.comparison != nil:
+		return []key.Binding{keybinds.Keys.Up, keybinds.Keys.Down, keybinds.Keys.PrevPage, keybinds.Keys.NextPage, keybinds.Keys.Back}
+	case h.selected != nil:
+		return append(h.body.Help(), keybinds.Keys.Back)
+	}
+	return []key.Binding{keybinds.Keys.Up, keybinds.Keys.Down, keybinds.Keys.Filter, keybinds.Keys.Choose, keybinds.Keys.Diff, keybinds.Keys.DiffRun, keybinds.Keys.Pin}
+}
+
+// IsCapturingInput also covers an open entry or comparison, which handles
+// back itself to return to the list
+func (h *HistoryView) IsCapturingInput() bool {
+	return h.searching || h.selected != nil || h.comparison != nil
+}
+
+func (h *HistoryView) GetFooterSegment() string {
+	if h.comparison != nil {
+		return "history/diff"
+	}
+	if h.selected != nil {
+		return fmt.Sprintf("history/%d", h.selected.ID)
+	}
+	return "history/"
+}
+
+func (h *HistoryView) Update(msg tea.Msg) (ViewInterface, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		h.width = msg.Width
+		h.height = msg.Height
+		h.resize()
+		return h, nil
+	case messages.HistorySearched:
+		if msg.Generation != h.searchGen {
+			return h, nil
+		}
+		h.searchErr = msg.Err
+		if msg.Err == nil {
+			h.results = msg.Result.Items
+			h.total = msg.Result.Total
+			h.cursor = 0
+			h.offset = 0
+		}
+		return h, nil
+	case messages.HistoryRerun:
+		h.rerunning = false
+		if msg.Err != nil {
+			return h, func() tea.Msg {
+				return messages.ShowError{Message: msg.Err.Error()}
+			}
+		}
+		rightTitle := "fresh run"
+		if msg.Result.HistoryID != 0 {
+			rightTitle = fmt.Sprintf("fresh run #%d", msg.Result.HistoryID)
+		}
+		h.compare(entryTitle(msg.Entry), msg.Entry.Response(), rightTitle, msg.Result.Response())
+		// the run was recorded, so list it
+		return h, h.runSearch()
+	case tea.KeyMsg:
+		if h.searching {
+			return h, h.updateSearch(msg)
+		}
+		if h.comparison != nil {
+			switch {
+			case key.Matches(msg, keybinds.Keys.Back):
+				h.comparison = nil
+			case key.Matches(msg, keybinds.Keys.Up):
+				h.comparison.scroll(-1)
+			case key.Matches(msg, keybinds.Keys.Down):
+				h.comparison.scroll(1)
+			case key.Matches(msg, keybinds.Keys.PrevPage):
+				h.comparison.page(-1)
+			case key.Matches(msg, keybinds.Keys.NextPage):
+				h.comparison.page(1)
+			}
+			return h, nil
+		}
+		h.notice = ""
+		if h.selected != nil {
+			if key.Matches(msg, keybinds.Keys.Back) {
+				h.selected = nil
+				return h, nil
+			}
+			break
+		}
+		switch {
+		case key.Matches(msg, keybinds.Keys.Up):
+			h.moveCursor(-1)
+		case key.Matches(msg, keybinds.Keys.Down):
+			h.moveCursor(1)
+		case key.Matches(msg, keybinds.Keys.Filter):
+			h.searching = true
+			return h, h.search.Focus()
+		case key.Matches(msg, keybinds.Keys.Choose):
+			return h, h.open()
+		case key.Matches(msg, keybinds.Keys.Diff):
+			return h, h.mark()
+		case key.Matches(msg, keybinds.Keys.DiffRun):
+			return h, h.rerun()
+		case key.Matches(msg, keybinds.Keys.Pin):
+			return h, h.pin()
+		}
+		return h, nil
+	}
+
+	if h.selected != nil {
+		h.body, cmd = h.body.Update(msg)
+	}
+	return h, cmd
+}
+
+func (h *HistoryView) View() string {
+	if h.comparison != nil {
+		return h.comparison.view()
+	}
+	if h.selected != nil {
+		return h.body.View()
+	}
+
+	lines := []string{styles.ResponseFilterStyle.Render(h.searchLine())}
+	rows := h.listHeight()
+	for i := h.offset; i < len(h.results) && i < h.offset+rows; i++ {
+		lines = append(lines, h.row(h.results[i], i == h.cursor))
+	}
+	if len(h.results) == 0 && h.searchErr == nil {
+		lines = append(lines, styles.HistoryRowStyle.Render(styles.HistoryMetaStyle.Render("no matching requests")))
+	}
+	for len(lines) < h.height {
+		lines = append(lines, "")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (h *HistoryView) Order() int {
+	return h.order
+}
+
+func (h *HistoryView) SetState(items ...any) error {
+	return errors.New("the history view takes no state")
+}
+
+func (h *HistoryView) OnFocus() {
+
+}
+
+func (h *HistoryView) OnBlur() {
+
+}
+
+func (h *HistoryView) updateSearch(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, keybinds.Keys.CancelWhileFiltering):
+		h.searching = false
+		h.search.Blur()
+		h.search.SetValue("")
+		return h.runSearch()
+	case key.Matches(msg, keybinds.Keys.AcceptWhileFiltering):
+		h.searching = false
+		h.search.Blur()
+		return nil
+	}
+
+	previous := h.search.Value()
+	var cmd tea.Cmd
+	h.search, cmd = h.search.Update(msg)
+	if h.search.Value() == previous {
+		return cmd
+	}
+	return tea.Batch(cmd, h.runSearch())
+}
+
+// runSearch queries history in the background. Results of superseded
+// searches are dropped.
+func (h *HistoryView) runSearch() tea.Cmd {
+	h.searchGen++
+	h.searchErr = nil
+	filters, err := history.ParseQuery(h.search.Value(), time.Now())
+	if err != nil {
+		h.searchErr = err
+		return nil
+	}
+
+	generation := h.searchGen
+	return func() tea.Msg {
+		result, err := h.manager.Search(context.Background(), filters, historyLimit, 0)
+		return messages.HistorySearched{Generation: generation, Result: result, Err: err}
+	}
+}
+
+// current loads the full entry under the cursor. Search results leave the
+// bodies out.
+func (h *HistoryView) current() (*history.HistoryEntity, tea.Cmd) {
+	if h.cursor >= len(h.results) {
+		return nil, nil
+	}
+	entry, err := h.manager.Read(context.Background(), h.results[h.cursor].ID)
+	if err != nil {
+		return nil, func() tea.Msg {
+			return messages.ShowError{Message: err.Error()}
+		}
+	}
+	return &entry, nil
+}
+
+// open shows the entry under the cursor in the body viewer
+func (h *HistoryView) open() tea.Cmd {
+	entry, cmd := h.current()
+	if entry == nil {
+		return cmd
+	}
+
+	h.selected = entry
+	responseHeaders := map[string][]string{}
+	json.Unmarshal([]byte(entry.ResponseHeaders.String), &responseHeaders)
+	return h.body.SetContent(entryPreamble(*entry, responseHeaders), entry.ResponseBody.String, format.ContentType(responseHeaders))
+}
+
+// mark marks the entry under the cursor, or compares it with the marked one.
+// Marking the marked entry again clears the mark.
+func (h *HistoryView) mark() tea.Cmd {
+	entry, cmd := h.current()
+	if entry == nil {
+		return cmd
+	}
+	switch {
+	case h.marked == nil:
+		h.marked = entry
+	case h.marked.ID == entry.ID:
+		h.marked = nil
+	default:
+		h.compare(entryTitle(*h.marked), h.marked.Response(), entryTitle(*entry), entry.Response())
+		h.marked = nil
+	}
+	return nil
+}
+
+// rerun runs the endpoint behind the entry under the cursor again so the
+// responses can be compared
+func (h *HistoryView) rerun() tea.Cmd {
+	if h.rerunning || h.runner == nil {
+		return nil
+	}
+	entry, cmd := h.current()
+	if entry == nil {
+		return cmd
+	}
+	h.rerunning = true
+	return func() tea.Msg {
+		result, err := h.runner.Rerun(context.Background(), *entry)
+		return messages.HistoryRerun{Entry: *entry, Result: result, Err: err}
+	}
+}
+
+// pin makes the entry under the cursor the snapshot later runs of its
+// endpoint are compared against
+func (h *HistoryView) pin() tea.Cmd {
+	if h.runner == nil || h.runner.Snapshots == nil {
+		return nil
+	}
+	entry, cmd := h.current()
+	if entry == nil {
+		return cmd
+	}
+	ctx := context.Background()
+	endpoint, err := h.runner.EndpointFor(ctx, *entry)
+	if err == nil {
+		_, err = h.runner.Snapshots.Pin(ctx, endpoint.ID, entry.Response())
+	}
+	if err != nil {
+		return func() tea.Msg {
+			return messages.ShowError{Message: err.Error()}
+		}
+	}
+	h.notice = fmt.Sprintf("pinned #%d as the snapshot of %s", entry.ID, endpoint.Name)
+	return nil
+}
+
+func (h *HistoryView) compare(leftTitle string, left diff.Response, rightTitle string, right diff.Response) {
+	h.comparison = newResponseDiff(leftTitle, left, rightTitle, right)
+	h.comparison.setSize(h.width, h.height)
+}
+
+func (h *HistoryView) moveCursor(delta int) {
+	h.cursor = max(0, min(h.cursor+delta, len(h.results)-1))
+	rows := h.listHeight()
+	if h.cursor < h.offset {
+		h.offset = h.cursor
+	} else if h.cursor >= h.offset+rows {
+		h.offset = h.cursor - rows + 1
+	}
+}
+
+func (h *HistoryView) listHeight() int {
+	return max(h.height-1, 1)
+}
+
+func (h *HistoryView) resize() {
+	h.body.SetSize(h.width, h.height)
+	if h.comparison != nil {
+		h.comparison.setSize(h.width, h.height)
+	}
+}
+
+// searchLine shows the search input followed by the result count and the
+// state of any comparison. The input shrinks so the status stays on screen.
+func (h *HistoryView) searchLine() string {
+	var status string
+	if h.searchErr != nil {
+		status = styles.StatusErrorStyle.Render(h.searchErr.Error())
+	} else {
+		meta := fmt.Sprintf("%d", h.total)
+		if int64(len(h.results)) < h.total {
+			meta = fmt.Sprintf("%d of %d", len(h.results), h.total)
+		}
+		switch {
+		case h.notice != "":
+			meta += "  " + h.notice
+		case h.rerunning:
+			meta += "  running…"
+		case h.marked != nil:
+			meta += fmt.Sprintf("  comparing #%d with…", h.marked.ID)
+		}
+		status = styles.ResponseMetaStyle.Render(meta)
+	}
+	h.search.Width = max(h.width-lipgloss.Width(h.search.Prompt)-lipgloss.Width(status)-3, 0)
+	return lipgloss.JoinHorizontal(lipgloss.Center, h.search.View(), status)
+}
+
+func (h *HistoryView) row(entry history.HistoryEntity, selected bool) string {
+	statusStyle := styles.HistoryStatusOKStyle
+	if entry.StatusCode >= 400 {
+		statusStyle = styles.HistoryStatusErrorStyle
+	}
+	name := entry.EndpointName.String
+	if entry.CollectionName.Valid {
+		name = entry.CollectionName.String + "/" + name
+	}
+	meta := fmt.Sprintf("%s  %s", name, formatAge(time.Since(entry.GetCreatedAt())))
+
+	line := fmt.Sprintf("%s %-7s %s  %s",
+		statusStyle.Render(fmt.Sprintf("%d", entry.StatusCode)),
+		entry.Method,
+		entry.Url,
+		styles.HistoryMetaStyle.Render(meta),
+	)
+	if width := h.width - 4; width > 0 && lipgloss.Width(line) > width {
+		line = runewidth.Truncate(fmt.Sprintf("%d %-7s %s  %s", entry.StatusCode, entry.Method, entry.Url, meta), width, "…")
+	}
+	if h.marked != nil && h.marked.ID == entry.ID {
+		line = styles.HistoryMarkStyle.Render("◆ ") + line
+	}
+	if selected {
+		return styles.HistorySelectedRowStyle.Render(line)
+	}
+	return styles.HistoryRowStyle.Render(line)
+}
+
+func entryTitle(entry history.HistoryEntity) string {
+	return fmt.Sprintf("#%d %s %s", entry.ID, entry.Method, entry.Url)
+}
+
+// entryPreamble lists the recorded request above the response body
+func entryPreamble(entry history.HistoryEntity, responseHeaders map[string][]string) []string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s\n", styles.ResponseTitleStyle.Render(entry.Method+" "+entry.Url), styles.ResponseMetaStyle.Render(fmt.Sprintf("%d  %dms  %s", entry.StatusCode, entry.Duration, entry.GetCreatedAt().Local().Format(time.DateTime))))
+	b.WriteString("\n")
+
+	if headers := singleValues(entry.RequestHeaders.String); len(headers) > 0 {
+		writeMetadata(&b, "Request Headers", headers)
+	}
+	if params := singleValues(entry.QueryParams.String); len(params) > 0 {
+		writeMetadata(&b, "Query", params)
+	}
+	if entry.RequestBody.String != "" {
+		b.WriteString(styles.ResponseSectionStyle.Render("Request Body"))
+		b.WriteString("\n")
+		b.WriteString(entry.RequestBody.String)
+		b.WriteString("\n\n")
+	}
+	writeMetadata(&b, "Response Headers", responseHeaders)
+	b.WriteString(styles.ResponseSectionStyle.Render("Body"))
+	return strings.Split(b.String(), "\n")
+}
+
+// singleValues decodes a stored name to value map into the shape writeMetadata takes
+func singleValues(data string) map[string][]string {
+	values := map[string]string{}
+	json.Unmarshal([]byte(data), &values)
+	out := make(map[string][]string, len(values))
+	for name, value := range values {
+		out[name] = []string{value}
+	}
+	return out
+}
+
+func formatAge(age time.Duration) string {
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age.Hours()))
+	}
+	return fmt.Sprintf("%dd ago", int(age.Hours()/24))
+}
+
+func NewHistoryView(manager *history.HistoryManager, runner *runner.Runner, order int) *HistoryView {
+	search := textinput.New()
+	search.Prompt = "search: "
+	search.Placeholder = "weird error status:5xx method:POST since:7d"
+
+	return &HistoryView{
+		order:   order,
+		manager: manager,
+		runner:  runner,
+		search:  search,
+		body: bodyViewer.NewBodyViewer(bodyViewer.KeyMap{
+			Up:       keybinds.Keys.Up,
+			Down:     keybinds.Keys.Down,
+			PageUp:   keybinds.Keys.PrevPage,
+			PageDown: keybinds.Keys.NextPage,
+			Toggle:   keybinds.Keys.ToggleRaw,
+		}),
+	}
+}
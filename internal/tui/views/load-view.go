@@ -0,0 +1,432 @@
+package views
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/maniac-en/req/internal/backend/load"
+	"github.com/maniac-en/req/internal/backend/runner"
+	optionsProvider "github.com/maniac-en/req/internal/tui/components/OptionsProvider"
+	"github.com/maniac-en/req/internal/tui/keybinds"
+	"github.com/maniac-en/req/internal/tui/messages"
+	"github.com/maniac-en/req/internal/tui/styles"
+)
+
+// load test settings, in the order they are edited
+const (
+	fieldRequests = iota
+	fieldDuration
+	fieldConcurrency
+	fieldRate
+	fieldCount
+)
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// LoadView sends the endpoint opened in the response view repeatedly and
+// shows throughput, errors and latency percentiles while it runs
+type LoadView struct {
+	width    int
+	height   int
+	order    int
+	runner   *runner.Runner
+	endpoint optionsProvider.Option
+
+	fields  []textinput.Model
+	field   int
+	editing bool
+
+	running    bool
+	cancel     context.CancelFunc
+	generation int
+	updates    chan tea.Msg
+	report     *load.Report
+	err        error
+	// rates holds the throughput of each progress interval for the sparkline
+	rates        []float64
+	lastRequests int
+	lastElapsed  time.Duration
+	// notice confirms the last action until the next key press
+	notice string
+}
+
+func (l *LoadView) Init() tea.Cmd {
+	return nil
+}
+
+func (l *LoadView) Name() string {
+	return "Load"
+}
+
+func (l *LoadView) Help() []key.Binding {
+	if l.editing {
+		return []key.Binding{keybinds.Keys.NextField, keybinds.Keys.PrevField, keybinds.Keys.StartStop, keybinds.Keys.CancelWhileFiltering}
+	}
+	help := []key.Binding{keybinds.Keys.StartStop}
+	if !l.running {
+		help = append(help, keybinds.Keys.EditItem)
+	}
+	if l.report != nil && !l.running {
+		help = append(help, keybinds.Keys.SaveReport)
+	}
+	return help
+}
+
+func (l *LoadView) IsCapturingInput() bool {
+	return l.editing
+}
+
+func (l *LoadView) GetFooterSegment() string {
+	return l.endpoint.Name + "/load"
+}
+
+func (l *LoadView) Update(msg tea.Msg) (ViewInterface, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		l.width = msg.Width
+		l.height = msg.Height
+	case messages.LoadProgress:
+		if msg.Generation != l.generation {
+			return l, nil
+		}
+		l.progress(msg.Report)
+		return l, l.wait()
+	case messages.LoadFinished:
+		if msg.Generation != l.generation {
+			return l, nil
+		}
+		l.running = false
+		l.cancel = nil
+		l.err = msg.Err
+		if msg.Err == nil {
+			l.report = &msg.Report
+		}
+	case tea.KeyMsg:
+		l.notice = ""
+		if l.editing {
+			return l, l.updateFields(msg)
+		}
+		switch {
+		case key.Matches(msg, keybinds.Keys.StartStop):
+			if l.running {
+				l.cancel()
+				return l, nil
+			}
+			return l, l.start()
+		case key.Matches(msg, keybinds.Keys.EditItem) && !l.running:
+			l.editing = true
+			return l, l.fields[l.field].Focus()
+		case key.Matches(msg, keybinds.Keys.SaveReport) && !l.running && l.report != nil:
+			l.save()
+		}
+	}
+	return l, nil
+}
+
+func (l *LoadView) View() string {
+	title := styles.ResponseTitleStyle.Render(l.endpoint.Name)
+	lines := []string{lipgloss.JoinHorizontal(lipgloss.Center, title, l.state())}
+	lines = append(lines, styles.ResponseFilterStyle.Render(l.settingsLine()), "")
+
+	if l.err != nil {
+		lines = append(lines, styles.ResponseFilterStyle.Render(styles.StatusErrorStyle.Render(l.err.Error())))
+	}
+	if l.report != nil {
+		lines = append(lines, l.reportLines()...)
+	} else if l.err == nil {
+		lines = append(lines, styles.ResponseFilterStyle.Render(styles.HistoryMetaStyle.Render(fmt.Sprintf("press %s to start, %s to change the settings", keybinds.Keys.StartStop.Help().Key, keybinds.Keys.EditItem.Help().Key))))
+	}
+	if l.notice != "" {
+		lines = append(lines, "", styles.ResponseFilterStyle.Render(styles.ResponseMetaStyle.Render(l.notice)))
+	}
+
+	for len(lines) < l.height {
+		lines = append(lines, "")
+	}
+	return strings.Join(lines[:min(len(lines), max(l.height, 1))], "\n")
+}
+
+func (l *LoadView) Order() int {
+	return l.order
+}
+
+func (l *LoadView) SetState(items ...any) error {
+	if len(items) == 1 {
+		if endpoint, ok := items[0].(optionsProvider.Option); ok {
+			if endpoint.ID == l.endpoint.ID {
+				return nil
+			}
+			if l.running {
+				l.cancel()
+			}
+			l.generation++
+			l.endpoint = endpoint
+			l.running = false
+			l.report = nil
+			l.err = nil
+			l.notice = ""
+			l.rates = nil
+			return nil
+		}
+	}
+	return errors.New("Invalid inputs, this function takes 1 input of type optionsProvider.Options")
+}
+
+func (l *LoadView) OnFocus() {
+
+}
+
+func (l *LoadView) OnBlur() {
+
+}
+
+func (l *LoadView) updateFields(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, keybinds.Keys.CancelWhileFiltering):
+		l.editing = false
+		l.fields[l.field].Blur()
+		return nil
+	case key.Matches(msg, keybinds.Keys.StartStop):
+		l.editing = false
+		l.fields[l.field].Blur()
+		return l.start()
+	case key.Matches(msg, keybinds.Keys.NextField), key.Matches(msg, keybinds.Keys.PrevField):
+		l.fields[l.field].Blur()
+		step := 1
+		if key.Matches(msg, keybinds.Keys.PrevField) {
+			step = fieldCount - 1
+		}
+		l.field = (l.field + step) % fieldCount
+		return l.fields[l.field].Focus()
+	}
+	var cmd tea.Cmd
+	l.fields[l.field], cmd = l.fields[l.field].Update(msg)
+	return cmd
+}
+
+func (l *LoadView) options() (load.Options, error) {
+	var options load.Options
+	var err error
+	value := func(field int) string {
+		return strings.TrimSpace(l.fields[field].Value())
+	}
+	if v := value(fieldRequests); v != "" {
+		if options.Requests, err = strconv.Atoi(v); err != nil {
+			return options, fmt.Errorf("requests: expected a number, got %q", v)
+		}
+	}
+	if v := value(fieldDuration); v != "" {
+		if options.Duration, err = time.ParseDuration(v); err != nil {
+			return options, fmt.Errorf("duration: expected a duration like 30s, got %q", v)
+		}
+	}
+	if options.Concurrency, err = strconv.Atoi(value(fieldConcurrency)); err != nil {
+		return options, fmt.Errorf("concurrency: expected a number, got %q", value(fieldConcurrency))
+	}
+	if v := value(fieldRate); v != "" {
+		if options.Rate, err = strconv.ParseFloat(v, 64); err != nil {
+			return options, fmt.Errorf("rate: expected requests per second, got %q", v)
+		}
+	}
+	return options, options.Validate()
+}
+
+// start runs the test in the background. Progress arrives as messages, one
+// at a time, so a slow redraw never holds the test up.
+func (l *LoadView) start() tea.Cmd {
+	l.err = nil
+	options, err := l.options()
+	if err != nil {
+		l.err = err
+		return nil
+	}
+	endpoint, err := l.runner.Endpoints.Read(context.Background(), l.endpoint.ID)
+	if err != nil {
+		l.err = err
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	l.generation++
+	l.running = true
+	l.cancel = cancel
+	l.report = &load.Report{Options: options}
+	l.rates = nil
+	l.lastRequests = 0
+	l.lastElapsed = 0
+
+	generation, updates := l.generation, make(chan tea.Msg, 1)
+	l.updates = updates
+	go func() {
+		defer cancel()
+		report, err := l.runner.Load(ctx, endpoint, options, func(report load.Report) {
+			select {
+			case updates <- messages.LoadProgress{Generation: generation, Report: report}:
+			default:
+			}
+		})
+		updates <- messages.LoadFinished{Generation: generation, Report: report, Err: err}
+	}()
+	return l.wait()
+}
+
+func (l *LoadView) wait() tea.Cmd {
+	updates := l.updates
+	return func() tea.Msg {
+		return <-updates
+	}
+}
+
+func (l *LoadView) progress(report load.Report) {
+	if interval := report.Elapsed - l.lastElapsed; interval > 0 {
+		l.rates = append(l.rates, float64(report.Requests-l.lastRequests)/interval.Seconds())
+	}
+	l.lastRequests = report.Requests
+	l.lastElapsed = report.Elapsed
+	l.report = &report
+}
+
+func (l *LoadView) save() {
+	path, err := filepath.Abs(load.FileName(l.endpoint.Name, time.Now()))
+	if err == nil {
+		err = l.report.WriteFile(path)
+	}
+	if err != nil {
+		l.err = err
+		return
+	}
+	l.notice = "report saved to " + path
+}
+
+func (l *LoadView) state() string {
+	switch {
+	case l.running:
+		return styles.StatusSuccessStyle.Render("running")
+	case l.report != nil && !l.report.Completed:
+		return styles.StatusErrorStyle.Render("stopped")
+	case l.report != nil:
+		return styles.StatusSuccessStyle.Render("done")
+	}
+	return styles.ResponseMetaStyle.Render("load test")
+}
+
+func (l *LoadView) settingsLine() string {
+	labels := []string{"requests", "duration", "concurrency", "rate/s"}
+	parts := make([]string, fieldCount)
+	for i, field := range l.fields {
+		value := field.Value()
+		if l.editing {
+			value = field.View()
+		} else if value == "" {
+			value = "-"
+		}
+		parts[i] = styles.HistoryMetaStyle.Render(labels[i]+" ") + value
+	}
+	return strings.Join(parts, "   ")
+}
+
+func (l *LoadView) reportLines() []string {
+	report := *l.report
+	label := func(text string) string {
+		return styles.ResponseSectionStyle.Render(fmt.Sprintf("%-12s", text))
+	}
+	row := func(name, value string) string {
+		return styles.ResponseFilterStyle.Render(label(name) + value)
+	}
+
+	errorText := fmt.Sprintf("%d (%.1f%%)", report.Failed, report.ErrorRate()*100)
+	if report.Failed > 0 {
+		errorText = styles.HistoryStatusErrorStyle.Render(errorText)
+	}
+	latency := report.Latency
+	lines := []string{
+		row("Requests", fmt.Sprintf("%d in %s", report.Requests, report.Elapsed.Round(100*time.Millisecond))),
+		row("Throughput", fmt.Sprintf("%.1f req/s  %s", report.Throughput(), l.sparkline())),
+		row("Errors", errorText),
+		row("Latency", fmt.Sprintf("p50 %s   p90 %s   p99 %s", roundLatency(latency.P50), roundLatency(latency.P90), roundLatency(latency.P99))),
+		row("", styles.HistoryMetaStyle.Render(fmt.Sprintf("min %s   mean %s   max %s", roundLatency(latency.Min), roundLatency(latency.Mean), roundLatency(latency.Max)))),
+	}
+
+	statuses := make([]int, 0, len(report.Statuses))
+	for status := range report.Statuses {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+	var counts []string
+	for _, status := range statuses {
+		style := styles.HistoryStatusOKStyle
+		if status >= 400 {
+			style = styles.HistoryStatusErrorStyle
+		}
+		counts = append(counts, style.Render(strconv.Itoa(status))+fmt.Sprintf(" × %d", report.Statuses[status]))
+	}
+	if len(counts) > 0 {
+		lines = append(lines, row("Statuses", strings.Join(counts, "   ")))
+	}
+
+	messages := make([]string, 0, len(report.Errors))
+	for message := range report.Errors {
+		messages = append(messages, message)
+	}
+	sort.Strings(messages)
+	for _, message := range messages {
+		lines = append(lines, row("", styles.HistoryStatusErrorStyle.Render(fmt.Sprintf("%d × %s", report.Errors[message], message))))
+	}
+	return lines
+}
+
+// sparkline draws recent throughput, scaled to the busiest interval shown
+func (l *LoadView) sparkline() string {
+	rates := l.rates
+	if width := max(l.width-40, 0); len(rates) > width {
+		rates = rates[len(rates)-width:]
+	}
+	peak := 0.0
+	for _, rate := range rates {
+		peak = max(peak, rate)
+	}
+	if peak == 0 {
+		return ""
+	}
+	var b strings.Builder
+	for _, rate := range rates {
+		b.WriteRune(sparkBlocks[int(rate/peak*float64(len(sparkBlocks)-1))])
+	}
+	return styles.HistoryStatusOKStyle.Render(b.String())
+}
+
+// roundLatency keeps latencies readable without hiding sub-millisecond ones
+func roundLatency(d time.Duration) time.Duration {
+	if d < 10*time.Millisecond {
+		return d.Round(10 * time.Microsecond)
+	}
+	return d.Round(time.Millisecond)
+}
+
+func NewLoadView(runner *runner.Runner, order int) *LoadView {
+	defaults := []string{"100", "", "10", ""}
+	placeholders := []string{"count", "e.g. 30s", "workers", "unlimited"}
+	fields := make([]textinput.Model, fieldCount)
+	for i := range fields {
+		fields[i] = textinput.New()
+		fields[i].Prompt = ""
+		fields[i].Placeholder = placeholders[i]
+		fields[i].Width = 10
+		fields[i].SetValue(defaults[i])
+	}
+	return &LoadView{
+		order:  order,
+		runner: runner,
+		fields: fields,
+	}
+}
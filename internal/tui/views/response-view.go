@@ -0,0 +1,517 @@
+package views
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atotto/clipboard"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/maniac-en/req/internal/backend/endpoints"
+	"github.com/maniac-en/req/internal/backend/runner"
+	"github.com/maniac-en/req/internal/codegen"
+	"github.com/maniac-en/req/internal/diff"
+	"github.com/maniac-en/req/internal/format"
+	"github.com/maniac-en/req/internal/query"
+	bodyViewer "github.com/maniac-en/req/internal/tui/components/BodyViewer"
+	optionsProvider "github.com/maniac-en/req/internal/tui/components/OptionsProvider"
+	"github.com/maniac-en/req/internal/tui/keybinds"
+	"github.com/maniac-en/req/internal/tui/messages"
+	"github.com/maniac-en/req/internal/tui/styles"
+)
+
+type ResponseView struct {
+	width    int
+	height   int
+	order    int
+	runner   *runner.Runner
+	endpoint optionsProvider.Option
+	body     bodyViewer.BodyViewer
+	result   *runner.Result
+	err      error
+	loading  bool
+	preamble []string
+
+	// the filter narrows a JSON body down with a JSONPath or jq expression
+	filter    textinput.Model
+	filtering bool
+	filterErr error
+	filterGen int
+	document  any
+
+	// choosing is set while picking the language to copy the request as
+	choosing bool
+	// notice confirms the last copy until the next key press
+	notice    string
+	noticeErr bool
+}
+
+// Init sends the request for the endpoint set through SetState
+func (r *ResponseView) Init() tea.Cmd {
+	if !r.loading {
+		return nil
+	}
+	return r.send()
+}
+
+func (r *ResponseView) Name() string {
+	return "Response"
+}
+
+func (r *ResponseView) Help() []key.Binding {
+	if r.filtering {
+		return []key.Binding{keybinds.Keys.AcceptWhileFiltering, keybinds.Keys.CancelWhileFiltering}
+	}
+	if r.choosing {
+		return []key.Binding{keybinds.Keys.CancelWhileFiltering}
+	}
+	return append(r.body.Help(), keybinds.Keys.Filter, keybinds.Keys.Send, keybinds.Keys.LoadTest, keybinds.Keys.CopyCode)
+}
+
+func (r *ResponseView) IsCapturingInput() bool {
+	return r.filtering || r.choosing
+}
+
+func (r *ResponseView) GetFooterSegment() string {
+	return r.endpoint.Name
+}
+
+func (r *ResponseView) Update(msg tea.Msg) (ViewInterface, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		r.width = msg.Width
+		r.height = msg.Height
+		r.resize()
+		return r, nil
+	case messages.RequestCompleted:
+		if msg.EndpointID != r.endpoint.ID {
+			return r, nil
+		}
+		r.loading = false
+		r.result = msg.Result
+		r.err = msg.Err
+		cmd = r.setContent()
+		r.resize()
+		return r, cmd
+	case messages.ResponseFiltered:
+		if msg.Generation != r.filterGen {
+			return r, nil
+		}
+		if msg.Document != nil {
+			r.document = msg.Document
+		}
+		if msg.Err != nil {
+			r.filterErr = msg.Err
+			r.resize()
+			return r, nil
+		}
+		return r, r.body.SetContent(r.preamble, msg.Body, "application/json")
+	case messages.CodeCopied:
+		if msg.EndpointID != r.endpoint.ID {
+			return r, nil
+		}
+		if msg.Err != nil {
+			r.notice, r.noticeErr = msg.Err.Error(), true
+		} else {
+			r.notice, r.noticeErr = "copied as "+msg.Language, false
+		}
+		r.resize()
+		return r, nil
+	case tea.KeyMsg:
+		if r.notice != "" {
+			r.notice = ""
+			r.resize()
+		}
+		if r.filtering {
+			return r, r.updateFilter(msg)
+		}
+		if r.choosing {
+			return r, r.chooseLanguage(msg)
+		}
+		switch {
+		case key.Matches(msg, keybinds.Keys.Send) && !r.loading:
+			r.loading = true
+			r.err = nil
+			return r, r.send()
+		case key.Matches(msg, keybinds.Keys.Filter):
+			r.filtering = true
+			r.resize()
+			return r, r.filter.Focus()
+		case key.Matches(msg, keybinds.Keys.LoadTest):
+			endpoint := r.endpoint
+			return r, func() tea.Msg {
+				return messages.NavigateToView{ViewName: "load", Data: endpoint}
+			}
+		case key.Matches(msg, keybinds.Keys.CopyCode):
+			r.choosing = true
+			r.resize()
+			return r, nil
+		}
+	}
+
+	r.body, cmd = r.body.Update(msg)
+	return r, cmd
+}
+
+func (r *ResponseView) View() string {
+	return lipgloss.JoinVertical(lipgloss.Left, r.header(), r.body.View())
+}
+
+func (r *ResponseView) Order() int {
+	return r.order
+}
+
+func (r *ResponseView) SetState(items ...any) error {
+	if len(items) == 1 {
+		if endpoint, ok := items[0].(optionsProvider.Option); ok {
+			r.endpoint = endpoint
+			r.result = nil
+			r.err = nil
+			r.loading = true
+			r.preamble = nil
+			r.document = nil
+			r.filter.SetValue("")
+			r.filterErr = nil
+			r.choosing = false
+			r.notice = ""
+			r.body.SetContent(nil, "", "")
+			return nil
+		}
+	}
+	return errors.New("Invalid inputs, this function takes 1 input of type optionsProvider.Options")
+}
+
+func (r *ResponseView) OnFocus() {
+
+}
+
+func (r *ResponseView) OnBlur() {
+
+}
+
+func (r *ResponseView) send() tea.Cmd {
+	endpointID := r.endpoint.ID
+	return func() tea.Msg {
+		result, err := r.runner.RunEndpoint(context.Background(), endpointID)
+		return messages.RequestCompleted{
+			EndpointID: endpointID,
+			Result:     result,
+			Err:        err,
+		}
+	}
+}
+
+func (r *ResponseView) resize() {
+	r.body.SetSize(r.width, max(r.height-lipgloss.Height(r.header()), 0))
+}
+
+func (r *ResponseView) header() string {
+	lines := []string{r.statusLine()}
+	if r.filtering || r.filter.Value() != "" {
+		line := r.filter.View()
+		if r.filterErr != nil {
+			line = lipgloss.JoinHorizontal(lipgloss.Center, line, styles.StatusErrorStyle.Render(r.filterErr.Error()))
+		}
+		lines = append(lines, styles.ResponseFilterStyle.Render(line))
+	}
+	switch {
+	case r.choosing:
+		options := make([]string, len(codegen.Languages))
+		for i, language := range codegen.Languages {
+			options[i] = fmt.Sprintf("%d %s", i+1, language.Label)
+		}
+		lines = append(lines, styles.ResponseFilterStyle.Render("copy as: "+strings.Join(options, "   ")))
+	case r.notice != "" && r.noticeErr:
+		lines = append(lines, styles.ResponseFilterStyle.Render(styles.StatusErrorStyle.Render(r.notice)))
+	case r.notice != "":
+		lines = append(lines, styles.ResponseFilterStyle.Render(styles.ResponseMetaStyle.Render(r.notice)))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
+
+// chooseLanguage copies the request as code in the language picked by
+// number, in the background since resolving it reads the database
+func (r *ResponseView) chooseLanguage(msg tea.KeyMsg) tea.Cmd {
+	defer r.resize()
+	if key.Matches(msg, keybinds.Keys.CancelWhileFiltering) {
+		r.choosing = false
+		return nil
+	}
+	choice, err := strconv.Atoi(msg.String())
+	if err != nil || choice < 1 || choice > len(codegen.Languages) {
+		return nil
+	}
+	r.choosing = false
+
+	language, endpointID := codegen.Languages[choice-1], r.endpoint.ID
+	return func() tea.Msg {
+		msg := messages.CodeCopied{EndpointID: endpointID, Language: language.Label}
+		ctx := context.Background()
+		endpoint, err := r.runner.Endpoints.Read(ctx, endpointID)
+		if err != nil {
+			msg.Err = err
+			return msg
+		}
+		request, err := r.runner.ClientRequest(ctx, endpoint)
+		if err != nil {
+			msg.Err = err
+			return msg
+		}
+		msg.Err = clipboard.WriteAll(language.Generate(request))
+		return msg
+	}
+}
+
+func (r *ResponseView) updateFilter(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, keybinds.Keys.CancelWhileFiltering):
+		r.filtering = false
+		r.filter.Blur()
+		r.filter.SetValue("")
+		r.resize()
+		return r.applyFilter()
+	case key.Matches(msg, keybinds.Keys.AcceptWhileFiltering):
+		r.filtering = false
+		r.filter.Blur()
+		r.resize()
+		return nil
+	}
+
+	previous := r.filter.Value()
+	var cmd tea.Cmd
+	r.filter, cmd = r.filter.Update(msg)
+	if r.filter.Value() == previous {
+		return cmd
+	}
+	return tea.Batch(cmd, r.applyFilter())
+}
+
+// applyFilter shows the part of the body matching the filter expression.
+// The body is decoded once, in the background, and reused for every
+// keystroke after that.
+func (r *ResponseView) applyFilter() tea.Cmd {
+	r.filterGen++
+	r.filterErr = nil
+	defer r.resize()
+	if r.result == nil {
+		return nil
+	}
+
+	expr := strings.TrimSpace(r.filter.Value())
+	if expr == "" {
+		return r.body.SetContent(r.preamble, r.result.Body, r.contentType())
+	}
+	q, err := query.Compile(expr)
+	if err != nil {
+		r.filterErr = err
+		return nil
+	}
+
+	generation, document, body := r.filterGen, r.document, r.result.Body
+	return func() tea.Msg {
+		if document == nil {
+			var err error
+			if document, err = query.Decode(body); err != nil {
+				return messages.ResponseFiltered{Generation: generation, Err: err}
+			}
+		}
+		msg := messages.ResponseFiltered{Generation: generation, Document: document}
+		results, err := q.Evaluate(document)
+		if err == nil {
+			msg.Body, err = query.Render(results)
+		}
+		msg.Err = err
+		return msg
+	}
+}
+
+func (r *ResponseView) statusLine() string {
+	title := styles.ResponseTitleStyle.Render(r.endpoint.Name)
+	switch {
+	case r.loading:
+		return lipgloss.JoinHorizontal(lipgloss.Center, title, styles.ResponseMetaStyle.Render("sending..."))
+	case r.err != nil:
+		return lipgloss.JoinHorizontal(lipgloss.Center, title, styles.StatusErrorStyle.Render("failed"))
+	case r.result == nil:
+		return title
+	}
+
+	statusStyle := styles.StatusSuccessStyle
+	if !r.result.Succeeded() {
+		statusStyle = styles.StatusErrorStyle
+	}
+	mode := "pretty"
+	if r.body.IsRaw() {
+		mode = "raw"
+	}
+	meta := fmt.Sprintf("%s  %s  %d bytes  %s (%s)  %3.f%%", strings.ToUpper(r.result.Protocol), r.result.Duration.Round(time.Millisecond), len(r.result.Body), r.body.Kind(), mode, r.body.ScrollPercent()*100)
+	return lipgloss.JoinHorizontal(lipgloss.Center, title, statusStyle.Render(r.result.Status), styles.ResponseMetaStyle.Render(meta))
+}
+
+// setContent hands the result to the body viewer, with headers and trailers
+// rendered as a preamble above the body.
+func (r *ResponseView) setContent() tea.Cmd {
+	r.preamble = nil
+	r.document = nil
+	if r.err != nil {
+		r.filterGen++
+		return r.body.SetContent(nil, r.err.Error(), "")
+	}
+	if r.result == nil {
+		r.filterGen++
+		return r.body.SetContent(nil, "", "")
+	}
+
+	var b strings.Builder
+	writeMetadata(&b, "Headers", r.result.Headers)
+	if len(r.result.Trailers) > 0 {
+		writeMetadata(&b, "Trailers", r.result.Trailers)
+	}
+	if len(r.result.Generated) > 0 {
+		writeValues(&b, "Generated", r.result.Generated)
+	}
+	if len(r.result.Extracted) > 0 || len(r.result.ExtractionErrors) > 0 {
+		writeExtractions(&b, r.result)
+	}
+	if len(r.result.Tests) > 0 || len(r.result.ScriptErrors) > 0 {
+		writeTests(&b, r.result)
+	}
+	if len(r.result.Logs) > 0 {
+		writeLogs(&b, r.result)
+	}
+	if r.result.Snapshot != nil {
+		writeSnapshot(&b, *r.result.Snapshot)
+	}
+	b.WriteString(styles.ResponseSectionStyle.Render("Body"))
+	r.preamble = strings.Split(b.String(), "\n")
+	return r.applyFilter()
+}
+
+func (r *ResponseView) contentType() string {
+	if r.result.Protocol == endpoints.ProtocolGRPC {
+		return "application/json"
+	}
+	return format.ContentType(r.result.Headers)
+}
+
+func writeMetadata(b *strings.Builder, title string, values map[string][]string) {
+	b.WriteString(styles.ResponseSectionStyle.Render(title))
+	b.WriteString("\n")
+
+	keys := make([]string, 0, len(values))
+	for name := range values {
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+	for _, name := range keys {
+		fmt.Fprintf(b, "%s: %s\n", name, strings.Join(values[name], ", "))
+	}
+	b.WriteString("\n")
+}
+
+func writeExtractions(b *strings.Builder, result *runner.Result) {
+	b.WriteString(styles.ResponseSectionStyle.Render("Extracted"))
+	b.WriteString("\n")
+	writeAssignments(b, result.Extracted)
+	for _, err := range result.ExtractionErrors {
+		b.WriteString(styles.StatusErrorStyle.Render(err.Error()))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+}
+
+func writeValues(b *strings.Builder, title string, values map[string]string) {
+	b.WriteString(styles.ResponseSectionStyle.Render(title))
+	b.WriteString("\n")
+	writeAssignments(b, values)
+	b.WriteString("\n")
+}
+
+func writeAssignments(b *strings.Builder, values map[string]string) {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(b, "%s = %s\n", name, values[name])
+	}
+}
+
+func writeTests(b *strings.Builder, result *runner.Result) {
+	b.WriteString(styles.ResponseSectionStyle.Render("Tests"))
+	b.WriteString("\n")
+
+	for _, test := range result.Tests {
+		if test.Passed {
+			fmt.Fprintf(b, "✓ %s\n", test.Name)
+			continue
+		}
+		b.WriteString(styles.StatusErrorStyle.Render(fmt.Sprintf("✗ %s: %s", test.Name, test.Error)))
+		b.WriteString("\n")
+	}
+	for _, err := range result.ScriptErrors {
+		b.WriteString(styles.StatusErrorStyle.Render(err.Error()))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+}
+
+// writeSnapshot reports whether the response drifted from its snapshot
+func writeSnapshot(b *strings.Builder, result diff.Result) {
+	b.WriteString(styles.ResponseSectionStyle.Render("Snapshot"))
+	b.WriteString("\n")
+	if result.Equal() {
+		b.WriteString("✓ matches\n\n")
+		return
+	}
+
+	b.WriteString(styles.StatusErrorStyle.Render("✗ drifted: " + plural(result.Count(), "difference")))
+	b.WriteString("\n")
+	if result.Status != nil {
+		fmt.Fprintf(b, "%s\n", result.Status)
+	}
+	for _, change := range result.Headers {
+		fmt.Fprintf(b, "%s\n", change)
+	}
+	for _, change := range result.Body {
+		fmt.Fprintf(b, "%s\n", change)
+	}
+	b.WriteString("\n")
+}
+
+func writeLogs(b *strings.Builder, result *runner.Result) {
+	b.WriteString(styles.ResponseSectionStyle.Render("Console"))
+	b.WriteString("\n")
+	for _, entry := range result.Logs {
+		fmt.Fprintf(b, "[%s] %s\n", entry.Level, entry.Message)
+	}
+	b.WriteString("\n")
+}
+
+func NewResponseView(runner *runner.Runner, order int) *ResponseView {
+	filter := textinput.New()
+	filter.Prompt = "filter: "
+	filter.Placeholder = "$.items[0].id or .items[] | select(.active)"
+
+	return &ResponseView{
+		order:  order,
+		runner: runner,
+		filter: filter,
+		body: bodyViewer.NewBodyViewer(bodyViewer.KeyMap{
+			Up:       keybinds.Keys.Up,
+			Down:     keybinds.Keys.Down,
+			PageUp:   keybinds.Keys.PrevPage,
+			PageDown: keybinds.Keys.NextPage,
+			Toggle:   keybinds.Keys.ToggleRaw,
+		}),
+	}
+}
@@ -0,0 +1,232 @@
+package views
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/maniac-en/req/internal/backend/endpoints"
+	"github.com/maniac-en/req/internal/diff"
+	optionsProvider "github.com/maniac-en/req/internal/tui/components/OptionsProvider"
+	"github.com/maniac-en/req/internal/tui/keybinds"
+	"github.com/maniac-en/req/internal/tui/styles"
+	"github.com/mattn/go-runewidth"
+)
+
+// RevisionsView lists the earlier versions of an endpoint. The selected
+// version is compared with the one that replaced it, or with the current
+// endpoint, and can be restored.
+type RevisionsView struct {
+	width   int
+	height  int
+	order   int
+	manager *endpoints.EndpointsManager
+
+	endpoint  endpoints.EndpointEntity
+	revisions []endpoints.Revision
+	cursor    int
+	offset    int
+	// compareCurrent compares the selected version with the endpoint as it is
+	// now instead of with the next version
+	compareCurrent bool
+	// notice confirms the last restore until the next key press
+	notice string
+}
+
+func (r *RevisionsView) Init() tea.Cmd {
+	return nil
+}
+
+func (r *RevisionsView) Name() string {
+	return "Revisions"
+}
+
+func (r *RevisionsView) Help() []key.Binding {
+	return []key.Binding{keybinds.Keys.Up, keybinds.Keys.Down, keybinds.Keys.Restore, keybinds.Keys.CompareCurrent}
+}
+
+func (r *RevisionsView) GetFooterSegment() string {
+	return r.endpoint.Name + "/revisions"
+}
+
+func (r *RevisionsView) Update(msg tea.Msg) (ViewInterface, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		r.width = msg.Width
+		r.height = msg.Height
+		r.moveCursor(0)
+	case tea.KeyMsg:
+		r.notice = ""
+		switch {
+		case key.Matches(msg, keybinds.Keys.Up):
+			r.moveCursor(-1)
+		case key.Matches(msg, keybinds.Keys.Down):
+			r.moveCursor(1)
+		case key.Matches(msg, keybinds.Keys.CompareCurrent):
+			r.compareCurrent = !r.compareCurrent
+		case key.Matches(msg, keybinds.Keys.Restore):
+			return r, r.restore()
+		}
+	}
+	return r, nil
+}
+
+func (r *RevisionsView) View() string {
+	status := fmt.Sprintf("%s of %s", plural(len(r.revisions), "earlier version"), r.endpoint.Name)
+	if r.notice != "" {
+		status += "  " + r.notice
+	}
+	lines := []string{styles.ResponseMetaStyle.Render(status)}
+
+	if len(r.revisions) == 0 {
+		lines = append(lines, styles.HistoryRowStyle.Render(styles.HistoryMetaStyle.Render("no earlier versions, the version replaced by each edit is kept here")))
+		return r.pad(lines)
+	}
+
+	rows := r.listHeight()
+	for i := r.offset; i < len(r.revisions) && i < r.offset+rows; i++ {
+		lines = append(lines, r.row(r.revisions[i], i == r.cursor))
+	}
+	lines = append(lines, "")
+
+	selected := r.revisions[r.cursor]
+	title := "Changes made by the next edit"
+	newer := r.endpoint.Version()
+	if r.compareCurrent {
+		title = "Changes from this version to the current one"
+	} else if r.cursor > 0 {
+		newer = r.revisions[r.cursor-1].Version()
+	}
+	lines = append(lines, styles.DiffTitleStyle.Render(fit(title, max(r.width-2, 1))))
+
+	changes := endpoints.CompareVersions(selected.Version(), newer)
+	if len(changes) == 0 {
+		lines = append(lines, styles.HistoryMetaStyle.Render("no differences"))
+	}
+	for i, change := range changes {
+		if len(lines) >= r.height-1 && i < len(changes)-1 {
+			lines = append(lines, styles.HistoryMetaStyle.Render(fmt.Sprintf("… %d more", len(changes)-i)))
+			break
+		}
+		lines = append(lines, changeStyle(change.Kind).Render(runewidth.Truncate(change.String(), max(r.width-2, 1), "…")))
+	}
+	return r.pad(lines)
+}
+
+func (r *RevisionsView) Order() int {
+	return r.order
+}
+
+// SetState opens the revisions of the endpoint in an optionsProvider.Option
+func (r *RevisionsView) SetState(items ...any) error {
+	if len(items) == 1 {
+		if option, ok := items[0].(optionsProvider.Option); ok {
+			endpoint, err := r.manager.Read(context.Background(), option.ID)
+			if err != nil {
+				return err
+			}
+			r.endpoint = endpoint
+			r.cursor = 0
+			r.offset = 0
+			r.compareCurrent = false
+			return r.load()
+		}
+	}
+	return errors.New("Invalid inputs, this function takes 1 input of type optionsProvider.Options")
+}
+
+func (r *RevisionsView) OnFocus() {
+
+}
+
+func (r *RevisionsView) OnBlur() {
+	r.notice = ""
+}
+
+func (r *RevisionsView) load() error {
+	revisions, err := r.manager.ListRevisions(context.Background(), r.endpoint.ID)
+	if err != nil {
+		return err
+	}
+	r.revisions = revisions
+	r.moveCursor(0)
+	return nil
+}
+
+func (r *RevisionsView) restore() tea.Cmd {
+	if len(r.revisions) == 0 {
+		return nil
+	}
+	revision := r.revisions[r.cursor]
+	endpoint, err := r.manager.RestoreRevision(context.Background(), revision.ID)
+	if err != nil {
+		return showError(err)
+	}
+	r.endpoint = endpoint
+	// the replaced version is now the newest revision
+	r.cursor = 0
+	if err := r.load(); err != nil {
+		return showError(err)
+	}
+	r.notice = "restored the version saved " + formatAge(time.Since(revision.GetCreatedAt()))
+	return nil
+}
+
+func (r *RevisionsView) moveCursor(delta int) {
+	r.cursor = max(0, min(r.cursor+delta, len(r.revisions)-1))
+	rows := r.listHeight()
+	if r.cursor < r.offset {
+		r.offset = r.cursor
+	} else if r.cursor >= r.offset+rows {
+		r.offset = r.cursor - rows + 1
+	}
+}
+
+// listHeight leaves two thirds of the view for the changes
+func (r *RevisionsView) listHeight() int {
+	return max(min(len(r.revisions), r.height/3), 1)
+}
+
+func (r *RevisionsView) row(revision endpoints.Revision, selected bool) string {
+	meta := formatAge(time.Since(revision.GetCreatedAt()))
+	if revision.Name != r.endpoint.Name {
+		meta = revision.Name + "  " + meta
+	}
+	line := fmt.Sprintf("%-7s %s  %s", revision.Method, revision.Url, styles.HistoryMetaStyle.Render(meta))
+	if plain := fmt.Sprintf("%-7s %s  %s", revision.Method, revision.Url, meta); r.width > 4 && runewidth.StringWidth(plain) > r.width-4 {
+		line = runewidth.Truncate(plain, r.width-4, "…")
+	}
+	if selected {
+		return styles.HistorySelectedRowStyle.Render(line)
+	}
+	return styles.HistoryRowStyle.Render(line)
+}
+
+func (r *RevisionsView) pad(lines []string) string {
+	for len(lines) < r.height {
+		lines = append(lines, "")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func changeStyle(kind diff.Kind) lipgloss.Style {
+	switch kind {
+	case diff.Added:
+		return styles.DiffAddedStyle
+	case diff.Removed:
+		return styles.DiffRemovedStyle
+	}
+	return styles.HistoryMarkStyle
+}
+
+func NewRevisionsView(manager *endpoints.EndpointsManager, order int) *RevisionsView {
+	return &RevisionsView{
+		order:   order,
+		manager: manager,
+	}
+}
@@ -0,0 +1,201 @@
+package views
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/maniac-en/req/internal/backend/trash"
+	"github.com/maniac-en/req/internal/tui/keybinds"
+	"github.com/maniac-en/req/internal/tui/messages"
+	"github.com/maniac-en/req/internal/tui/styles"
+	"github.com/mattn/go-runewidth"
+)
+
+// TrashView lists deleted collections, folders and endpoints so they can be
+// restored, or deleted for good
+type TrashView struct {
+	width   int
+	height  int
+	order   int
+	manager *trash.TrashManager
+
+	items  []trash.Item
+	cursor int
+	offset int
+	// confirming is set while emptying the trash waits for confirmation
+	confirming bool
+	// notice confirms the last action until the next key press
+	notice string
+}
+
+// Init loads the trash, which changes whenever something is deleted
+func (t *TrashView) Init() tea.Cmd {
+	return t.refresh()
+}
+
+func (t *TrashView) Name() string {
+	return "Trash"
+}
+
+func (t *TrashView) Help() []key.Binding {
+	if t.confirming {
+		return []key.Binding{keybinds.Keys.Confirm}
+	}
+	return []key.Binding{keybinds.Keys.Up, keybinds.Keys.Down, keybinds.Keys.Choose, keybinds.Keys.EmptyTrash}
+}
+
+func (t *TrashView) IsCapturingInput() bool {
+	return t.confirming
+}
+
+func (t *TrashView) GetFooterSegment() string {
+	return "trash/"
+}
+
+func (t *TrashView) Update(msg tea.Msg) (ViewInterface, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		t.width = msg.Width
+		t.height = msg.Height
+	case tea.KeyMsg:
+		t.notice = ""
+		if t.confirming {
+			t.confirming = false
+			if key.Matches(msg, keybinds.Keys.Confirm) {
+				return t, t.empty()
+			}
+			return t, nil
+		}
+		switch {
+		case key.Matches(msg, keybinds.Keys.Up):
+			t.moveCursor(-1)
+		case key.Matches(msg, keybinds.Keys.Down):
+			t.moveCursor(1)
+		case key.Matches(msg, keybinds.Keys.Choose):
+			return t, t.restore()
+		case key.Matches(msg, keybinds.Keys.EmptyTrash):
+			t.confirming = len(t.items) > 0
+		}
+	}
+	return t, nil
+}
+
+func (t *TrashView) View() string {
+	status := fmt.Sprintf("%d deleted items", len(t.items))
+	switch {
+	case t.confirming:
+		status = fmt.Sprintf("Permanently delete all %d items? Press %s to confirm, any other key to cancel", len(t.items), keybinds.Keys.Confirm.Help().Key)
+	case t.notice != "":
+		status += "  " + t.notice
+	}
+
+	lines := []string{styles.ResponseMetaStyle.Render(status)}
+	rows := t.listHeight()
+	for i := t.offset; i < len(t.items) && i < t.offset+rows; i++ {
+		lines = append(lines, t.row(t.items[i], i == t.cursor))
+	}
+	if len(t.items) == 0 {
+		lines = append(lines, styles.HistoryRowStyle.Render(styles.HistoryMetaStyle.Render("the trash is empty")))
+	}
+	for len(lines) < t.height {
+		lines = append(lines, "")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (t *TrashView) Order() int {
+	return t.order
+}
+
+func (t *TrashView) SetState(items ...any) error {
+	return errors.New("the trash view takes no state")
+}
+
+func (t *TrashView) OnFocus() {
+
+}
+
+func (t *TrashView) OnBlur() {
+	t.confirming = false
+	t.notice = ""
+}
+
+func (t *TrashView) refresh() tea.Cmd {
+	items, err := t.manager.List(context.Background())
+	if err != nil {
+		return func() tea.Msg {
+			return messages.ShowError{Message: err.Error()}
+		}
+	}
+	t.items = items
+	t.moveCursor(0)
+	return nil
+}
+
+func (t *TrashView) restore() tea.Cmd {
+	if len(t.items) == 0 {
+		return nil
+	}
+	item := t.items[t.cursor]
+	if err := t.manager.Restore(context.Background(), item.Kind, item.ID); err != nil {
+		return func() tea.Msg {
+			return messages.ShowError{Message: err.Error()}
+		}
+	}
+	t.notice = fmt.Sprintf("restored %s %s", item.Kind, item.Name)
+	return t.refresh()
+}
+
+func (t *TrashView) empty() tea.Cmd {
+	deleted, err := t.manager.Empty(context.Background(), 0)
+	if err != nil {
+		return func() tea.Msg {
+			return messages.ShowError{Message: err.Error()}
+		}
+	}
+	cmd := t.refresh()
+	t.notice = fmt.Sprintf("permanently deleted %d items", deleted)
+	return cmd
+}
+
+func (t *TrashView) moveCursor(delta int) {
+	t.cursor = max(0, min(t.cursor+delta, len(t.items)-1))
+	rows := t.listHeight()
+	if t.cursor < t.offset {
+		t.offset = t.cursor
+	} else if t.cursor >= t.offset+rows {
+		t.offset = t.cursor - rows + 1
+	}
+}
+
+func (t *TrashView) listHeight() int {
+	return max(t.height-1, 1)
+}
+
+func (t *TrashView) row(item trash.Item, selected bool) string {
+	meta := formatAge(time.Since(item.DeletedAt))
+	if item.Location != "" {
+		meta = item.Location + "  " + meta
+	}
+	line := fmt.Sprintf("%-10s %s  %s", item.Kind, item.Name, styles.HistoryMetaStyle.Render(meta))
+	if width := t.width - 4; width > 0 && lipgloss.Width(line) > width {
+		line = runewidth.Truncate(fmt.Sprintf("%-10s %s  %s", item.Kind, item.Name, meta), width, "…")
+	}
+	if selected {
+		return styles.HistorySelectedRowStyle.Render(line)
+	}
+	return styles.HistoryRowStyle.Render(line)
+}
+
+func NewTrashView(manager *trash.TrashManager, order int) *TrashView {
+	return &TrashView{
+		order:   order,
+		manager: manager,
+	}
+}
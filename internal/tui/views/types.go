@@ -17,3 +17,15 @@ type ViewInterface interface {
 	OnFocus()
 	OnBlur()
 }
+
+// InputCapturer is implemented by views that accept free text. While a view
+// is capturing input, app-wide keys such as back are left to the view.
+type InputCapturer interface {
+	IsCapturingInput() bool
+}
+
+// BackHandler is implemented by views with their own levels, such as
+// folders. Back reports whether the view handled the key itself.
+type BackHandler interface {
+	Back() bool
+}
@@ -0,0 +1,191 @@
+package views
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/maniac-en/req/internal/tui/keybinds"
+	"github.com/maniac-en/req/internal/tui/messages"
+	"github.com/maniac-en/req/internal/tui/styles"
+	"github.com/maniac-en/req/internal/workspace"
+)
+
+// WorkspacesView lists the workspaces in the data directory. Choosing one,
+// or naming a new one, restarts the UI on its database.
+type WorkspacesView struct {
+	width   int
+	height  int
+	order   int
+	dataDir string
+	current string
+
+	names  []string
+	cursor int
+	offset int
+	// adding is set while a new workspace is being named
+	adding bool
+	input  textinput.Model
+}
+
+// Init lists the workspaces again, other instances of req may have added some
+func (w *WorkspacesView) Init() tea.Cmd {
+	names, err := workspace.List(w.dataDir)
+	if err != nil {
+		return showError(err)
+	}
+	w.names = names
+	w.moveCursor(0)
+	return nil
+}
+
+func (w *WorkspacesView) Name() string {
+	return "Workspaces"
+}
+
+func (w *WorkspacesView) Help() []key.Binding {
+	if w.adding {
+		return []key.Binding{keybinds.Keys.Choose, keybinds.Keys.CancelWhileFiltering}
+	}
+	return []key.Binding{keybinds.Keys.Up, keybinds.Keys.Down, keybinds.Keys.Choose, keybinds.Keys.InsertItem}
+}
+
+func (w *WorkspacesView) IsCapturingInput() bool {
+	return w.adding
+}
+
+func (w *WorkspacesView) GetFooterSegment() string {
+	return "workspaces/"
+}
+
+func (w *WorkspacesView) Update(msg tea.Msg) (ViewInterface, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		w.width = msg.Width
+		w.height = msg.Height
+		w.input.Width = max(w.width-lipgloss.Width(w.input.Prompt)-3, 0)
+	case tea.KeyMsg:
+		if w.adding {
+			return w, w.updateInput(msg)
+		}
+		switch {
+		case key.Matches(msg, keybinds.Keys.Up):
+			w.moveCursor(-1)
+		case key.Matches(msg, keybinds.Keys.Down):
+			w.moveCursor(1)
+		case key.Matches(msg, keybinds.Keys.Choose):
+			if len(w.names) > 0 {
+				return w, w.switchTo(w.names[w.cursor])
+			}
+		case key.Matches(msg, keybinds.Keys.InsertItem):
+			w.adding = true
+			return w, w.input.Focus()
+		}
+	}
+	return w, nil
+}
+
+func (w *WorkspacesView) View() string {
+	lines := []string{styles.ResponseMetaStyle.Render(fmt.Sprintf("%s, in use: %s", plural(len(w.names), "workspace"), w.current))}
+	if w.adding {
+		lines[0] = styles.ResponseFilterStyle.Render(w.input.View())
+	}
+
+	rows := w.listHeight()
+	for i := w.offset; i < len(w.names) && i < w.offset+rows; i++ {
+		line := w.names[i]
+		if line == w.current {
+			line += "  " + styles.HistoryMetaStyle.Render("in use")
+		}
+		if i == w.cursor && !w.adding {
+			lines = append(lines, styles.HistorySelectedRowStyle.Render(line))
+		} else {
+			lines = append(lines, styles.HistoryRowStyle.Render(line))
+		}
+	}
+	for len(lines) < w.height {
+		lines = append(lines, "")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (w *WorkspacesView) Order() int {
+	return w.order
+}
+
+func (w *WorkspacesView) SetState(items ...any) error {
+	return errors.New("the workspaces view takes no state")
+}
+
+func (w *WorkspacesView) OnFocus() {
+
+}
+
+func (w *WorkspacesView) OnBlur() {
+	w.adding = false
+	w.input.Blur()
+	w.input.SetValue("")
+}
+
+func (w *WorkspacesView) updateInput(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, keybinds.Keys.CancelWhileFiltering):
+		w.adding = false
+		w.input.Blur()
+		w.input.SetValue("")
+		return nil
+	case key.Matches(msg, keybinds.Keys.Choose):
+		name := strings.TrimSpace(w.input.Value())
+		if err := workspace.ValidateName(name); err != nil {
+			return showError(err)
+		}
+		w.adding = false
+		w.input.Blur()
+		w.input.SetValue("")
+		return w.switchTo(name)
+	}
+
+	var cmd tea.Cmd
+	w.input, cmd = w.input.Update(msg)
+	return cmd
+}
+
+func (w *WorkspacesView) switchTo(name string) tea.Cmd {
+	if name == w.current {
+		return nil
+	}
+	return func() tea.Msg {
+		return messages.SwitchWorkspace{Name: name}
+	}
+}
+
+func (w *WorkspacesView) moveCursor(delta int) {
+	w.cursor = max(0, min(w.cursor+delta, len(w.names)-1))
+	rows := w.listHeight()
+	if w.cursor < w.offset {
+		w.offset = w.cursor
+	} else if w.cursor >= w.offset+rows {
+		w.offset = w.cursor - rows + 1
+	}
+}
+
+func (w *WorkspacesView) listHeight() int {
+	return max(w.height-1, 1)
+}
+
+func NewWorkspacesView(dataDir, current string, order int) *WorkspacesView {
+	input := textinput.New()
+	input.Prompt = "new workspace: "
+	input.Placeholder = "client-a"
+
+	return &WorkspacesView{
+		order:   order,
+		dataDir: dataDir,
+		current: current,
+		input:   input,
+	}
+}
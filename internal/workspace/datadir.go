@@ -0,0 +1,132 @@
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+)
+
+// DataDir returns the directory req keeps its data in: $XDG_DATA_HOME/req,
+// or ~/.local/share/req when that is unset. macOS and Windows use their
+// application data directory instead.
+func DataDir() (string, error) {
+	switch runtime.GOOS {
+	case "darwin", "windows", "ios", "plan9":
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(dir, "req"), nil
+	}
+
+	// the spec says relative paths are invalid and should be ignored
+	if dir := os.Getenv("XDG_DATA_HOME"); filepath.IsAbs(dir) {
+		return filepath.Join(dir, "req"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share", "req"), nil
+}
+
+// Migrate moves the data req kept in oldDir, the cache directory it used
+// before, into dataDir. Log files stay behind. It runs only while dataDir has
+// no databases yet and oldDir has the default one, so it happens once. It
+// returns the names of the entries moved.
+func Migrate(oldDir, dataDir string) ([]string, error) {
+	if oldDir == dataDir || exists(filepath.Join(dataDir, defaultFile)) || exists(filepath.Join(dataDir, dirName)) {
+		return nil, nil
+	}
+	if !exists(filepath.Join(oldDir, defaultFile)) {
+		return nil, nil
+	}
+
+	entries, err := os.ReadDir(oldDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+		return nil, err
+	}
+
+	var moved []string
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".log") {
+			continue
+		}
+		from, to := filepath.Join(oldDir, entry.Name()), filepath.Join(dataDir, entry.Name())
+		if exists(to) {
+			continue
+		}
+		if err := move(from, to); err != nil {
+			return moved, fmt.Errorf("moving %s to %s: %w", from, dataDir, err)
+		}
+		moved = append(moved, entry.Name())
+	}
+	return moved, nil
+}
+
+// move renames a file or directory, copying it when the rename crosses file
+// systems
+func move(from, to string) error {
+	err := os.Rename(from, to)
+	if err == nil || !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+	if err := copyTree(from, to); err != nil {
+		os.RemoveAll(to)
+		return err
+	}
+	return os.RemoveAll(from)
+}
+
+func copyTree(from, to string) error {
+	return filepath.WalkDir(from, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(from, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(to, rel)
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return os.MkdirAll(target, info.Mode().Perm())
+		}
+		return copyFile(path, target, info.Mode().Perm())
+	})
+}
+
+func copyFile(from, to string, perm fs.FileMode) error {
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
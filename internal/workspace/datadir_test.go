@@ -0,0 +1,113 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestDataDir(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("XDG directories apply on Linux")
+	}
+
+	t.Setenv("XDG_DATA_HOME", "/xdg/data")
+	if dir, err := DataDir(); err != nil || dir != "/xdg/data/req" {
+		t.Errorf("Expected /xdg/data/req, got %s (%v)", dir, err)
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	for _, value := range []string{"", "relative/data"} {
+		t.Setenv("XDG_DATA_HOME", value)
+		want := filepath.Join(home, ".local", "share", "req")
+		if dir, err := DataDir(); err != nil || dir != want {
+			t.Errorf("Expected %s for XDG_DATA_HOME=%q, got %s (%v)", want, value, dir, err)
+		}
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	write := func(t *testing.T, path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("Moves data and leaves logs", func(t *testing.T) {
+		oldDir, dataDir := t.TempDir(), filepath.Join(t.TempDir(), "req")
+		write(t, filepath.Join(oldDir, "app.db"), "default")
+		write(t, filepath.Join(oldDir, "workspaces", "client.db"), "client")
+		write(t, filepath.Join(oldDir, "ca", "ca.pem"), "cert")
+		write(t, filepath.Join(oldDir, "req.log"), "log")
+
+		moved, err := Migrate(oldDir, dataDir)
+		if err != nil {
+			t.Fatalf("Migrate failed: %v", err)
+		}
+		if want := []string{"app.db", "ca", "workspaces"}; !reflect.DeepEqual(moved, want) {
+			t.Errorf("Expected %v to be moved, got %v", want, moved)
+		}
+		for _, file := range []string{"app.db", "workspaces/client.db", "ca/ca.pem"} {
+			if !exists(filepath.Join(dataDir, file)) {
+				t.Errorf("Expected %s in the data directory", file)
+			}
+			if exists(filepath.Join(oldDir, file)) {
+				t.Errorf("Expected %s to be gone from the old directory", file)
+			}
+		}
+		if !exists(filepath.Join(oldDir, "req.log")) || exists(filepath.Join(dataDir, "req.log")) {
+			t.Error("Expected the log to stay behind")
+		}
+
+		// a second run finds the data already moved
+		write(t, filepath.Join(oldDir, "app.db"), "recreated")
+		if moved, err := Migrate(oldDir, dataDir); err != nil || len(moved) != 0 {
+			t.Errorf("Expected nothing to move again, got %v (%v)", moved, err)
+		}
+	})
+
+	t.Run("Nothing to migrate", func(t *testing.T) {
+		oldDir, dataDir := t.TempDir(), t.TempDir()
+		write(t, filepath.Join(oldDir, "req.log"), "log")
+		if moved, err := Migrate(oldDir, dataDir); err != nil || len(moved) != 0 {
+			t.Errorf("Expected nothing to move, got %v (%v)", moved, err)
+		}
+	})
+
+	t.Run("Existing data wins", func(t *testing.T) {
+		oldDir, dataDir := t.TempDir(), t.TempDir()
+		write(t, filepath.Join(oldDir, "app.db"), "old")
+		write(t, filepath.Join(dataDir, "app.db"), "new")
+		if moved, err := Migrate(oldDir, dataDir); err != nil || len(moved) != 0 {
+			t.Errorf("Expected nothing to move, got %v (%v)", moved, err)
+		}
+		if content, _ := os.ReadFile(filepath.Join(dataDir, "app.db")); string(content) != "new" {
+			t.Errorf("Expected the existing database to be kept, got %q", content)
+		}
+	})
+}
+
+func TestCopyTree(t *testing.T) {
+	from, to := t.TempDir(), filepath.Join(t.TempDir(), "copy")
+	if err := os.MkdirAll(filepath.Join(from, "nested"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(from, "nested", "file"), []byte("content"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := copyTree(from, to); err != nil {
+		t.Fatalf("copyTree failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(to, "nested", "file"))
+	if err != nil || string(content) != "content" {
+		t.Errorf("Expected the file to be copied, got %q (%v)", content, err)
+	}
+}
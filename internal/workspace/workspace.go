@@ -0,0 +1,125 @@
+// Package workspace maps workspace names to database files in the data
+// directory. Each workspace is an independent database, so personal, team and
+// client projects don't mix. The default workspace keeps the database req has
+// always used.
+package workspace
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Default is the workspace used when none is picked
+const Default = "default"
+
+const (
+	// defaultFile is the database of the default workspace
+	defaultFile = "app.db"
+	// dirName holds the databases of the other workspaces
+	dirName = "workspaces"
+	// collectionDirsName holds the databases of collection directories
+	collectionDirsName = "dirs"
+	ext                = ".db"
+)
+
+var (
+	validName   = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+	unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+)
+
+type Workspace struct {
+	Name string
+	// Path is the database file, created on first use
+	Path string
+	// Dir is the collection directory the workspace was opened for, if any
+	Dir string
+}
+
+// ValidateName accepts names that are safe to use as file names
+func ValidateName(name string) error {
+	if !validName.MatchString(name) || len(name) > 64 {
+		return fmt.Errorf("invalid workspace name %q: use up to 64 letters, digits, dots, dashes and underscores", name)
+	}
+	return nil
+}
+
+// Resolve returns the workspace to open. An explicit database path wins over
+// a name, and an empty name is the default workspace. The directory holding
+// the database is created if needed.
+func Resolve(dataDir, name, path string) (Workspace, error) {
+	var ws Workspace
+	switch {
+	case path != "":
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return Workspace{}, fmt.Errorf("resolving %s: %w", path, err)
+		}
+		ws = Workspace{Name: strings.TrimSuffix(filepath.Base(abs), ext), Path: abs}
+	case name == "" || name == Default:
+		ws = Workspace{Name: Default, Path: filepath.Join(dataDir, defaultFile)}
+	default:
+		if err := ValidateName(name); err != nil {
+			return Workspace{}, err
+		}
+		ws = Workspace{Name: name, Path: filepath.Join(dataDir, dirName, name+ext)}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(ws.Path), 0o755); err != nil {
+		return Workspace{}, fmt.Errorf("creating workspace directory: %w", err)
+	}
+	return ws, nil
+}
+
+// ForDir returns the workspace of a collection directory. The collections
+// live in the directory, and the database keeps what the files don't, such as
+// history and environments, apart for each directory.
+func ForDir(dataDir, dir string) (Workspace, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return Workspace{}, fmt.Errorf("resolving %s: %w", dir, err)
+	}
+	if info, err := os.Stat(abs); err == nil && !info.IsDir() {
+		return Workspace{}, fmt.Errorf("%s is not a directory", dir)
+	}
+
+	name := filepath.Base(abs)
+	// directories with the same name in different projects get their own
+	// database
+	sum := sha256.Sum256([]byte(abs))
+	file := fmt.Sprintf("%s-%x%s", strings.Trim(unsafeChars.ReplaceAllString(name, "-"), "-"), sum[:4], ext)
+	ws := Workspace{Name: name, Path: filepath.Join(dataDir, collectionDirsName, file), Dir: abs}
+
+	if err := os.MkdirAll(filepath.Dir(ws.Path), 0o755); err != nil {
+		return Workspace{}, fmt.Errorf("creating workspace directory: %w", err)
+	}
+	return ws, nil
+}
+
+// List returns the names of the workspaces in dataDir, the default first and
+// the others sorted
+func List(dataDir string) ([]string, error) {
+	names := []string{Default}
+	entries, err := os.ReadDir(filepath.Join(dataDir, dirName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return names, nil
+		}
+		return nil, err
+	}
+
+	var others []string
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ext)
+		if entry.IsDir() || !ok || ValidateName(name) != nil || name == Default {
+			continue
+		}
+		others = append(others, name)
+	}
+	sort.Strings(others)
+	return append(names, others...), nil
+}
@@ -0,0 +1,120 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	dataDir := t.TempDir()
+
+	t.Run("Default workspace keeps app.db", func(t *testing.T) {
+		for _, name := range []string{"", Default} {
+			ws, err := Resolve(dataDir, name, "")
+			if err != nil {
+				t.Fatalf("Resolve failed: %v", err)
+			}
+			if ws.Name != Default || ws.Path != filepath.Join(dataDir, "app.db") {
+				t.Errorf("Expected the default workspace for %q, got %+v", name, ws)
+			}
+		}
+	})
+
+	t.Run("Named workspace", func(t *testing.T) {
+		ws, err := Resolve(dataDir, "client-a", "")
+		if err != nil {
+			t.Fatalf("Resolve failed: %v", err)
+		}
+		want := filepath.Join(dataDir, "workspaces", "client-a.db")
+		if ws.Name != "client-a" || ws.Path != want {
+			t.Errorf("Expected client-a at %s, got %+v", want, ws)
+		}
+		if info, err := os.Stat(filepath.Dir(want)); err != nil || !info.IsDir() {
+			t.Errorf("Expected the workspaces directory to be created, got %v", err)
+		}
+	})
+
+	t.Run("Path wins over name", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "nested", "team.db")
+		ws, err := Resolve(dataDir, "client-a", path)
+		if err != nil {
+			t.Fatalf("Resolve failed: %v", err)
+		}
+		if ws.Name != "team" || ws.Path != path {
+			t.Errorf("Expected team at %s, got %+v", path, ws)
+		}
+	})
+
+	t.Run("Invalid names", func(t *testing.T) {
+		for _, name := range []string{"../escape", ".hidden", "a/b", "with space"} {
+			if _, err := Resolve(dataDir, name, ""); err == nil {
+				t.Errorf("Expected an error for %q", name)
+			}
+		}
+	})
+}
+
+func TestForDir(t *testing.T) {
+	dataDir := t.TempDir()
+	projects := t.TempDir()
+
+	first, err := ForDir(dataDir, filepath.Join(projects, "one", "my api"))
+	if err != nil {
+		t.Fatalf("ForDir failed: %v", err)
+	}
+	second, err := ForDir(dataDir, filepath.Join(projects, "two", "my api"))
+	if err != nil {
+		t.Fatalf("ForDir failed: %v", err)
+	}
+	if first.Name != "my api" || first.Dir != filepath.Join(projects, "one", "my api") {
+		t.Errorf("Expected the workspace to be named after the directory, got %+v", first)
+	}
+	if first.Path == second.Path || filepath.Dir(first.Path) != filepath.Join(dataDir, "dirs") {
+		t.Errorf("Expected separate databases in dirs, got %s and %s", first.Path, second.Path)
+	}
+	if base := filepath.Base(first.Path); !strings.HasPrefix(base, "my-api-") {
+		t.Errorf("Expected a file name safe for any platform, got %s", base)
+	}
+
+	file := filepath.Join(projects, "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ForDir(dataDir, file); err == nil {
+		t.Error("Expected an error for a file")
+	}
+}
+
+func TestList(t *testing.T) {
+	dataDir := t.TempDir()
+
+	names, err := List(dataDir)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if !reflect.DeepEqual(names, []string{Default}) {
+		t.Errorf("Expected only the default workspace, got %v", names)
+	}
+
+	dir := filepath.Join(dataDir, "workspaces")
+	if err := os.MkdirAll(filepath.Join(dir, "ignored.db"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range []string{"work.db", "personal.db", "notes.txt", "work.db-journal"} {
+		if err := os.WriteFile(filepath.Join(dir, file), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names, err = List(dataDir)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	want := []string{Default, "personal", "work"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("Expected %v, got %v", want, names)
+	}
+}
@@ -4,23 +4,41 @@ import (
 	"context"
 	"database/sql"
 	"embed"
+	"errors"
 	"fmt"
 	"io/fs"
 	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
 	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/maniac-en/req/internal/backend/collections"
 	"github.com/maniac-en/req/internal/backend/database"
 	"github.com/maniac-en/req/internal/backend/demo"
 	"github.com/maniac-en/req/internal/backend/endpoints"
+	"github.com/maniac-en/req/internal/backend/environments"
+	"github.com/maniac-en/req/internal/backend/folders"
+	"github.com/maniac-en/req/internal/backend/grpc"
 	"github.com/maniac-en/req/internal/backend/history"
 	"github.com/maniac-en/req/internal/backend/http"
+	"github.com/maniac-en/req/internal/backend/scripting"
+	"github.com/maniac-en/req/internal/backend/snapshots"
+	"github.com/maniac-en/req/internal/backend/storage"
+	"github.com/maniac-en/req/internal/backend/trash"
+	"github.com/maniac-en/req/internal/cli"
+	"github.com/maniac-en/req/internal/config"
 	"github.com/maniac-en/req/internal/log"
 	"github.com/maniac-en/req/internal/tui/app"
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/maniac-en/req/internal/tui/keybinds"
+	"github.com/maniac-en/req/internal/tui/styles"
+	"github.com/maniac-en/req/internal/workspace"
+	"github.com/mattn/go-sqlite3"
 	"github.com/pressly/goose/v3"
 )
 
@@ -32,6 +50,7 @@ var migrationsFS embed.FS
 var (
 	USERHOMEDIR string
 	APPDIR      string
+	CACHEDIR    string
 	DBPATH      string
 	LOGPATH     string
 	DB          *sql.DB
@@ -39,6 +58,28 @@ var (
 
 var Version = "dev"
 
+// driverName is the sqlite3 driver that reports writes to the collection
+// tables to the sync of a collection directory, if there is one
+const driverName = "sqlite3_req"
+
+var collectionsSync atomic.Pointer[storage.Sync]
+
+func init() {
+	sql.Register(driverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			conn.RegisterUpdateHook(func(_ int, _ string, table string, _ int64) {
+				switch table {
+				case "collections", "folders", "endpoints":
+					if sync := collectionsSync.Load(); sync != nil {
+						sync.Changed()
+					}
+				}
+			})
+			return nil
+		},
+	})
+}
+
 func getVersion() string {
 	// Try to get version from build info (works with go install)
 	if info, ok := debug.ReadBuildInfo(); ok {
@@ -51,28 +92,135 @@ func getVersion() string {
 	return Version
 }
 
-func initPaths() error {
-	// setup paths using OS-appropriate cache directory
+// initPaths sets up the directories, using those of the config file when it
+// names them
+func initPaths(paths config.Paths) error {
+	// setup paths using OS-appropriate data and cache directories
 	userHomeDir, err := os.UserHomeDir()
 	if err != nil {
 		return fmt.Errorf("error reading user's home path: %w", err)
 	}
 	USERHOMEDIR = userHomeDir
 
-	// use OS-appropriate cache directory
-	userCacheDir, err := os.UserCacheDir()
+	// collections and history are user data, kept where cache cleaners
+	// leave them alone
+	dataDir, err := workspace.DataDir()
+	if paths.Data != "" {
+		dataDir, err = config.ExpandPath(paths.Data)
+	}
 	if err != nil {
-		return fmt.Errorf("error reading user's cache path: %w", err)
+		return fmt.Errorf("error reading user's data path: %w", err)
 	}
-	APPDIR = filepath.Join(userCacheDir, "req")
+	APPDIR = dataDir
 	if err := os.MkdirAll(APPDIR, 0o755); err != nil {
 		return fmt.Errorf("error creating app directory: %w", err)
 	}
-	DBPATH = filepath.Join(APPDIR, "app.db")
-	LOGPATH = filepath.Join(APPDIR, "req.log")
+
+	// logs can be thrown away, so they stay in the cache directory
+	userCacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return fmt.Errorf("error reading user's cache path: %w", err)
+	}
+	CACHEDIR = filepath.Join(userCacheDir, "req")
+	if err := os.MkdirAll(CACHEDIR, 0o755); err != nil {
+		return fmt.Errorf("error creating cache directory: %w", err)
+	}
+	LOGPATH = filepath.Join(CACHEDIR, "req.log")
+	if paths.Log != "" {
+		if LOGPATH, err = config.ExpandPath(paths.Log); err != nil {
+			return fmt.Errorf("error reading user's log path: %w", err)
+		}
+	}
 	return nil
 }
 
+// globalFlags picks the workspace and the config file. They come before the
+// subcommand, as in "req --workspace client run API".
+type globalFlags struct {
+	db        string
+	workspace string
+	// dir is a directory of collection files to work on
+	dir    string
+	config string
+}
+
+// parseGlobalFlags strips the leading --db, --workspace, --dir and --config
+// flags from args, falling back to REQ_DB, REQ_WORKSPACE and REQ_CONFIG when
+// they are not given
+func parseGlobalFlags(args []string) (globalFlags, []string, error) {
+	var flags globalFlags
+	for len(args) > 0 {
+		name, value, hasValue := strings.Cut(args[0], "=")
+		var target *string
+		switch name {
+		case "--db":
+			target = &flags.db
+		case "--workspace", "-w":
+			target = &flags.workspace
+		case "--dir":
+			target = &flags.dir
+		case "--config":
+			target = &flags.config
+		default:
+			return withEnv(flags), args, nil
+		}
+		if !hasValue {
+			if len(args) < 2 {
+				return flags, nil, fmt.Errorf("flag %s needs a value", name)
+			}
+			value, args = args[1], args[1:]
+		}
+		*target = value
+		args = args[1:]
+		if flags.dir != "" && (flags.db != "" || flags.workspace != "") {
+			return flags, nil, errors.New("--dir can't be combined with --db or --workspace")
+		}
+	}
+	return withEnv(flags), args, nil
+}
+
+func withEnv(flags globalFlags) globalFlags {
+	if flags.config == "" {
+		flags.config = os.Getenv("REQ_CONFIG")
+	}
+	if flags.db != "" || flags.workspace != "" || flags.dir != "" {
+		return flags
+	}
+	flags.db, flags.workspace = os.Getenv("REQ_DB"), os.Getenv("REQ_WORKSPACE")
+	return flags
+}
+
+// loadConfig reads the config file named by --config or REQ_CONFIG, or the
+// one in the user's config directory if there is one, and applies its theme,
+// keymap preset and key bindings
+func loadConfig(path string) (config.Config, error) {
+	required := path != ""
+	if !required {
+		defaultPath, err := config.DefaultPath()
+		if err != nil {
+			// without a config directory there is no file to read
+			return config.Default(), nil
+		}
+		path = defaultPath
+	}
+	cfg, err := config.Load(path, required)
+	if err != nil {
+		return cfg, err
+	}
+	if err := styles.SetTheme(cfg.Theme, cfg.Themes, cfg.Colors); err != nil {
+		return cfg, fmt.Errorf("config %s: %w", cfg.Path, err)
+	}
+	keys, err := keybinds.Preset(cfg.Keymap)
+	if err != nil {
+		return cfg, fmt.Errorf("config %s: keymap: %w", cfg.Path, err)
+	}
+	maps.Copy(keys, cfg.Keys)
+	if err := keybinds.Remap(keys); err != nil {
+		return cfg, fmt.Errorf("config %s: keys: %w", cfg.Path, err)
+	}
+	return cfg, nil
+}
+
 func runMigrations() error {
 	// connect to database
 	db, err := sql.Open("sqlite3", DBPATH)
@@ -104,7 +252,7 @@ func runMigrations() error {
 	}
 
 	// open a new connection for the global DB
-	globalDB, err := sql.Open("sqlite3", DBPATH)
+	globalDB, err := sql.Open(driverName, DBPATH)
 	if err != nil {
 		return fmt.Errorf("error opening global database connection: %w", err)
 	}
@@ -112,67 +260,245 @@ func runMigrations() error {
 	return nil
 }
 
+// historyRetention applies the REQ_HISTORY_* variables to the retention
+// policy of the config file. Unset or invalid variables keep its values.
+func historyRetention(retention history.Retention) (history.Retention, error) {
+	var errs []error
+	if value := os.Getenv("REQ_HISTORY_MAX_AGE"); value != "" {
+		age, err := history.ParseAge(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("REQ_HISTORY_MAX_AGE: %w", err))
+		} else {
+			retention.MaxAge = age
+		}
+	}
+	if value := os.Getenv("REQ_HISTORY_MAX_ENTRIES"); value != "" {
+		entries, err := strconv.Atoi(value)
+		if err != nil || entries < 0 {
+			errs = append(errs, fmt.Errorf("REQ_HISTORY_MAX_ENTRIES: expected a number, got %q", value))
+		} else {
+			retention.MaxEntriesPerCollection = entries
+		}
+	}
+	if value := os.Getenv("REQ_HISTORY_MAX_SIZE"); value != "" {
+		size, err := history.ParseSize(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("REQ_HISTORY_MAX_SIZE: %w", err))
+		} else {
+			retention.MaxTotalBytes = size
+		}
+	}
+	if value := os.Getenv("REQ_HISTORY_FAILED_BODIES_ONLY"); value != "" {
+		failedOnly, err := strconv.ParseBool(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("REQ_HISTORY_FAILED_BODIES_ONLY: %w", err))
+		} else {
+			retention.FailedBodiesOnly = failedOnly
+		}
+	}
+	return retention, errors.Join(errs...)
+}
+
+// copyCollections replaces the collections in one store with those of another
+func copyCollections(from, to storage.Store) error {
+	ctx := context.Background()
+	loaded, err := from.Load(ctx)
+	if err != nil {
+		return err
+	}
+	return to.Save(ctx, loaded)
+}
+
 func main() {
+	flags, args, err := parseGlobalFlags(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "req: %v\n", err)
+		os.Exit(2)
+	}
+	cfg, err := loadConfig(flags.config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "req: %v\n", err)
+		os.Exit(2)
+	}
+
 	// initialize paths first
-	if err := initPaths(); err != nil {
+	if err := initPaths(cfg.Paths); err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to initialize: %v\n", err)
 		os.Exit(1)
 	}
 
-	// initialize logging
-	logLevel := slog.LevelInfo
-	if os.Getenv("REQ_DEBUG") == "1" || os.Getenv("REQ_LOG_LEVEL") == "debug" {
+	// initialize logging, the environment overrides the config file
+	logLevel, _ := cfg.LogLevel()
+	if level, err := config.ParseLogLevel(os.Getenv("REQ_LOG_LEVEL")); err == nil {
+		logLevel = level
+	}
+	if os.Getenv("REQ_DEBUG") == "1" {
 		logLevel = slog.LevelDebug
 	}
+	rotation := cfg.Rotation()
 	log.Initialize(log.Config{
 		Level:       logLevel,
 		LogFilePath: LOGPATH,
+		Rotation:    &rotation,
 	})
-	defer func() {
+	closeLog := func() {
 		if err := log.Global().Close(); err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to close logger: %v\n", err)
 		}
-	}()
+	}
 
-	log.Info("starting req application")
+	log.Info("starting req application", "config", cfg.Path)
+
+	// earlier versions kept everything in the cache directory
+	moved, err := workspace.Migrate(CACHEDIR, APPDIR)
+	if err != nil {
+		// starting on an empty database would hide the user's data
+		log.Error("failed to move data out of the cache directory", "from", CACHEDIR, "to", APPDIR, "error", err)
+		fmt.Fprintf(os.Stderr, "req: moving data from %s to %s: %v\n", CACHEDIR, APPDIR, err)
+		closeLog()
+		os.Exit(1)
+	} else if len(moved) > 0 {
+		log.Info("moved data out of the cache directory", "from", CACHEDIR, "to", APPDIR, "entries", moved)
+	}
+
+	var current workspace.Workspace
+	if flags.dir != "" {
+		current, err = workspace.ForDir(APPDIR, flags.dir)
+	} else {
+		current, err = workspace.Resolve(APPDIR, flags.workspace, flags.db)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "req: %v\n", err)
+		closeLog()
+		os.Exit(2)
+	}
+
+	// the UI quits to switch workspaces, and starts again on the new one
+	for {
+		next, code := run(cfg, current, args)
+		if next == "" {
+			closeLog()
+			os.Exit(code)
+		}
+		if current, err = workspace.Resolve(APPDIR, next, ""); err != nil {
+			log.Fatal("failed to switch workspace", "workspace", next, "error", err)
+		}
+	}
+}
+
+// run opens a workspace and runs the subcommand in args, or the UI when there
+// is none. It returns the workspace the UI switched to, if any, and the exit
+// code.
+func run(cfg config.Config, current workspace.Workspace, args []string) (next string, code int) {
+	DBPATH = current.Path
+	log.Info("opening workspace", "workspace", current.Name, "path", current.Path)
 
 	// run database migrations
 	if err := runMigrations(); err != nil {
 		log.Fatal("failed to run migrations", "error", err)
 	}
+	defer func() {
+		if err := DB.Close(); err != nil {
+			log.Error("failed to close database", "error", err)
+		}
+	}()
 
 	// create database client and managers
 	db := database.New(DB)
 	collectionsManager := collections.NewCollectionsManager(db)
 	endpointsManager := endpoints.NewEndpointsManager(db)
+	environmentsManager := environments.NewEnvironmentsManager(db)
 	httpManager := http.NewHTTPManager()
+	httpManager.Client.Timeout = cfg.HTTP.Timeout.Duration
+	grpcManager := grpc.NewGRPCManager()
+	grpcManager.Timeout = cfg.HTTP.Timeout.Duration
+	scriptManager := scripting.NewScriptManager()
+	snapshotsManager := snapshots.NewSnapshotsManager(db)
 	historyManager := history.NewHistoryManager(db)
+	foldersManager := folders.NewFoldersManager(db)
+	trashManager := trash.NewTrashManager(db)
+	// validated when the config was loaded
+	retention, _ := cfg.Retention()
+	retention, err := historyRetention(retention)
+	if err != nil {
+		log.Error("invalid history retention, using defaults", "error", err)
+	}
+	historyManager.Retention = retention
+	if _, err := historyManager.Prune(context.Background(), retention); err != nil {
+		log.Error("failed to prune history", "error", err)
+	}
+
+	// the collections of a directory are read into the database on start and
+	// written back shortly after every change
+	if current.Dir != "" {
+		files := storage.NewDirectory(current.Dir)
+		store := storage.NewDatabase(collectionsManager, foldersManager, endpointsManager)
+		if err := copyCollections(files, store); err != nil {
+			log.Error("failed to load collection directory", "dir", current.Dir, "error", err)
+			fmt.Fprintf(os.Stderr, "req: loading %s: %v\n", current.Dir, err)
+			return "", 1
+		}
+		sync := storage.NewSync(store, files, 200*time.Millisecond)
+		sync.Start()
+		collectionsSync.Store(sync)
+		defer func() {
+			collectionsSync.Store(nil)
+			if err := sync.Close(); err != nil {
+				log.Error("failed to save collection directory", "dir", current.Dir, "error", err)
+				fmt.Fprintf(os.Stderr, "req: saving %s: %v\n", current.Dir, err)
+				code = 1
+			}
+		}()
+	}
 
 	// create clean context for dependency injection
 	appContext := app.NewContext(
 		collectionsManager,
 		endpointsManager,
+		environmentsManager,
 		httpManager,
+		grpcManager,
+		scriptManager,
+		snapshotsManager,
 		historyManager,
+		foldersManager,
+		trashManager,
 		getVersion(),
 	)
+	appContext.Workspace = current.Name
+	appContext.DataDir = APPDIR
 
-	// populate dummy data for demo
-	demoGenerator := demo.NewDemoGenerator(collectionsManager, endpointsManager)
-	dummyDataCreated, err := demoGenerator.PopulateDummyData(context.Background())
-	if err != nil {
-		log.Error("failed to populate dummy data", "error", err)
-	} else if dummyDataCreated {
-		// appContext.SetDummyDataCreated(true)
+	// populate dummy data for demo, other workspaces start empty
+	if current.Name == workspace.Default && current.Dir == "" {
+		demoGenerator := demo.NewDemoGenerator(collectionsManager, endpointsManager)
+		dummyDataCreated, err := demoGenerator.PopulateDummyData(context.Background())
+		if err != nil {
+			log.Error("failed to populate dummy data", "error", err)
+		} else if dummyDataCreated {
+			// appContext.SetDummyDataCreated(true)
+		}
 	}
 
-	log.Info("application initialized", "components", []string{"database", "collections", "endpoints", "http", "history", "logging", "demo"})
-	log.Debug("configuration loaded", "collections_manager", collectionsManager != nil, "endpoints", endpointsManager != nil, "database", db != nil, "http_manager", httpManager != nil, "history_manager", historyManager != nil)
+	log.Info("application initialized", "components", []string{"database", "collections", "endpoints", "environments", "http", "grpc", "scripting", "snapshots", "history", "folders", "trash", "logging", "demo"})
+	log.Debug("configuration loaded", "collections_manager", collectionsManager != nil, "endpoints", endpointsManager != nil, "database", db != nil, "http_manager", httpManager != nil, "grpc_manager", grpcManager != nil, "script_manager", scriptManager != nil, "snapshots_manager", snapshotsManager != nil, "history_manager", historyManager != nil)
 	log.Info("application started successfully")
 
+	// subcommands run without the UI
+	if len(args) > 0 {
+		command := cli.New(collectionsManager, environmentsManager, historyManager, snapshotsManager, trashManager, appContext.Runner)
+		command.DataDir = APPDIR
+		command.Workspace = current.Name
+		return "", command.Run(context.Background(), args)
+	}
+
 	// Entry point for UI
-	program := tea.NewProgram(app.NewAppModel(appContext), tea.WithAltScreen())
-	if _, err := program.Run(); err != nil {
+	program := tea.NewProgram(app.NewAppModel(appContext), tea.WithAltScreen(), tea.WithOutput(styles.Output(os.Stdout)))
+	final, err := program.Run()
+	if err != nil {
 		log.Fatal("Fatal error:", err)
 	}
+	if model, ok := final.(app.AppModel); ok {
+		return model.SwitchTo(), 0
+	}
+	return "", 0
 }